@@ -0,0 +1,547 @@
+package tview
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// The interval in which an animated accordion is redrawn.
+const accordionFrameInterval = 20 * time.Millisecond
+
+// accordionSection is one section of an Accordion.
+type accordionSection struct {
+	name  string    // The section's name which identifies it.
+	title string    // The text shown in the section's title row.
+	item  Primitive // The section's primitive.
+
+	// The height of the expanded section's primitive, 0 to share the space
+	// left by the other sections.
+	height int
+
+	// Whether the section is expanded.
+	expanded bool
+
+	// The expanded fraction of the section (0 to 1) when it was last expanded
+	// or collapsed, and the time when that happened.
+	from      float64
+	changedAt time.Time
+}
+
+// Accordion is a container of sections stacked on top of each other. Each
+// section has a title row and a primitive which is only shown while the
+// section is expanded. Expanded sections either have a fixed height or share
+// the space left by the other sections. If the accordion is exclusive (see
+// [Accordion.SetExclusive]), expanding a section collapses all others.
+//
+// If an application was provided with [Accordion.SetApplication], sections
+// expand and collapse with an animation (see [Accordion.SetAnimation]).
+// Otherwise, they change their height instantly. The expansion state can be
+// saved with [Accordion.GetExpandedSections] and restored with
+// [Accordion.SetExpandedSections].
+//
+// The following keys are available while the accordion (rather than one of
+// its sections' primitives) has focus:
+//
+//   - Up arrow / down arrow, Home, End: Move to another section title.
+//   - Space: Expand or collapse the current section.
+//   - Right arrow / left arrow: Expand / collapse the current section.
+//   - Enter: Expand the current section and give focus to its primitive.
+//
+// While the primitive of a section has focus, Ctrl+Up returns focus to the
+// section's title. Clicking on a title expands or collapses the section.
+type Accordion struct {
+	*Box
+
+	// The sections, from top to bottom.
+	sections []*accordionSection
+
+	// The index of the current section.
+	current int
+
+	// Whether expanding a section collapses all others.
+	exclusive bool
+
+	// The duration of the expand and collapse animation.
+	animation time.Duration
+
+	// The styles of the title rows and of the current title row.
+	titleStyle, currentTitleStyle tcell.Style
+
+	// The y-coordinates of the title rows when last drawn, -1 for hidden rows.
+	titleY []int
+
+	// We keep a reference to the function which allows us to set the focus
+	// when sections are collapsed.
+	setFocus func(p Primitive)
+
+	// An optional handler which is called when a section is expanded or
+	// collapsed.
+	changed func(name string, expanded bool)
+
+	// Protects the fields below which are accessed by the animation timers.
+	mu sync.Mutex
+
+	// The application which animates the accordion, if any.
+	app *Application
+
+	// The time when the last animation ends.
+	animationEnd time.Time
+}
+
+// NewAccordion returns a new accordion without any sections.
+func NewAccordion() *Accordion {
+	return &Accordion{
+		Box:               NewBox(),
+		animation:         150 * time.Millisecond,
+		titleStyle:        tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		currentTitleStyle: tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.ContrastBackgroundColor),
+	}
+}
+
+// AddSection adds a section with the given name, title, and primitive to the
+// bottom of the accordion. If there was previously a section with the same
+// name, it is replaced but keeps its expansion state. The height is that of
+// the primitive while the section is expanded. If it is 0, the section shares
+// the space left by the sections with a fixed height.
+func (a *Accordion) AddSection(name, title string, item Primitive, height int, expanded bool) *Accordion {
+	for _, section := range a.sections {
+		if section.name == name {
+			section.title, section.item, section.height = title, item, height
+			return a
+		}
+	}
+	section := &accordionSection{
+		name:   name,
+		title:  title,
+		item:   item,
+		height: height,
+	}
+	a.sections = append(a.sections, section)
+	if expanded {
+		a.setExpanded(section, true, false)
+	}
+	return a
+}
+
+// RemoveSection removes the section with the given name.
+func (a *Accordion) RemoveSection(name string) *Accordion {
+	for index, section := range a.sections {
+		if section.name == name {
+			hasFocus := a.HasFocus()
+			a.sections = append(a.sections[:index], a.sections[index+1:]...)
+			if a.current >= len(a.sections) && a.current > 0 {
+				a.current--
+			}
+			if hasFocus && !a.HasFocus() && a.setFocus != nil {
+				a.setFocus(a)
+			}
+			break
+		}
+	}
+	return a
+}
+
+// HasSection returns true if a section with the given name exists.
+func (a *Accordion) HasSection(name string) bool {
+	return a.section(name) != nil
+}
+
+// GetSectionCount returns the number of sections.
+func (a *Accordion) GetSectionCount() int {
+	return len(a.sections)
+}
+
+// SetSectionTitle changes the title of the section with the given name.
+func (a *Accordion) SetSectionTitle(name, title string) *Accordion {
+	if section := a.section(name); section != nil {
+		section.title = title
+	}
+	return a
+}
+
+// Expand expands the section with the given name. If the accordion is
+// exclusive, all other sections are collapsed.
+func (a *Accordion) Expand(name string) *Accordion {
+	if section := a.section(name); section != nil {
+		a.setExpanded(section, true, true)
+	}
+	return a
+}
+
+// Collapse collapses the section with the given name.
+func (a *Accordion) Collapse(name string) *Accordion {
+	if section := a.section(name); section != nil {
+		a.setExpanded(section, false, true)
+	}
+	return a
+}
+
+// Toggle expands the section with the given name if it is collapsed and
+// collapses it otherwise.
+func (a *Accordion) Toggle(name string) *Accordion {
+	if section := a.section(name); section != nil {
+		a.setExpanded(section, !section.expanded, true)
+	}
+	return a
+}
+
+// IsExpanded returns whether the section with the given name is expanded.
+func (a *Accordion) IsExpanded(name string) bool {
+	section := a.section(name)
+	return section != nil && section.expanded
+}
+
+// GetExpandedSections returns the names of all expanded sections, e.g. to
+// restore the expansion state later with [Accordion.SetExpandedSections].
+func (a *Accordion) GetExpandedSections() []string {
+	var names []string
+	for _, section := range a.sections {
+		if section.expanded {
+			names = append(names, section.name)
+		}
+	}
+	return names
+}
+
+// SetExpandedSections expands the sections with the given names and collapses
+// all others, without an animation and without calling the "changed" handler.
+// Names of sections which don't exist are ignored.
+func (a *Accordion) SetExpandedSections(names []string) *Accordion {
+	expand := make(map[string]bool)
+	for _, name := range names {
+		expand[name] = true
+	}
+	for _, section := range a.sections {
+		section.expanded = expand[section.name]
+		section.from, section.changedAt = 0, time.Time{}
+		if section.expanded {
+			section.from = 1
+		}
+	}
+	return a
+}
+
+// SetExclusive sets whether expanding a section collapses all others.
+func (a *Accordion) SetExclusive(exclusive bool) *Accordion {
+	a.exclusive = exclusive
+	return a
+}
+
+// SetAnimation sets the duration of the animation with which sections expand
+// and collapse. Set to 0 to change their height instantly. The animation is
+// only shown if an application was set with [Accordion.SetApplication].
+func (a *Accordion) SetAnimation(duration time.Duration) *Accordion {
+	a.animation = duration
+	return a
+}
+
+// SetApplication sets the application which redraws the screen while
+// sections are expanding or collapsing. Set to nil to change the height of
+// sections instantly.
+func (a *Accordion) SetApplication(app *Application) *Accordion {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.app = app
+	return a
+}
+
+// SetTitleStyles sets the styles of the title rows and of the current section's
+// title row while the accordion has focus.
+func (a *Accordion) SetTitleStyles(title, current tcell.Style) *Accordion {
+	a.titleStyle, a.currentTitleStyle = title, current
+	return a
+}
+
+// SetChangedFunc sets a handler which is called when a section is expanded or
+// collapsed, e.g. to persist the expansion state.
+func (a *Accordion) SetChangedFunc(handler func(name string, expanded bool)) *Accordion {
+	a.changed = handler
+	return a
+}
+
+// section returns the section with the given name or nil if there is none.
+func (a *Accordion) section(name string) *accordionSection {
+	for _, section := range a.sections {
+		if section.name == name {
+			return section
+		}
+	}
+	return nil
+}
+
+// animated returns whether sections currently change their height with an
+// animation.
+func (a *Accordion) animated() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.app != nil && a.animation > 0
+}
+
+// fraction returns the expanded fraction of the given section (0 to 1) at
+// the given time.
+func (a *Accordion) fraction(section *accordionSection, now time.Time) float64 {
+	to := 0.0
+	if section.expanded {
+		to = 1
+	}
+	if a.animation <= 0 || section.changedAt.IsZero() {
+		return to
+	}
+	progress := float64(now.Sub(section.changedAt)) / float64(a.animation)
+	if progress >= 1 {
+		return to
+	}
+	return section.from + (to-section.from)*progress
+}
+
+// setExpanded expands or collapses the given section, collapsing all other
+// sections if the accordion is exclusive. If notify is true, the "changed"
+// handler is called for all sections which changed.
+func (a *Accordion) setExpanded(section *accordionSection, expanded, notify bool) {
+	var changed []*accordionSection
+	for _, s := range a.sections {
+		target := s.expanded
+		if s == section {
+			target = expanded
+		} else if expanded && a.exclusive {
+			target = false
+		}
+		if target != s.expanded {
+			changed = append(changed, s)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	// Change the sections.
+	now, animated := time.Now(), a.animated()
+	for _, s := range changed {
+		if animated {
+			s.from, s.changedAt = a.fraction(s, now), now
+		} else {
+			s.changedAt = time.Time{}
+		}
+		s.expanded = !s.expanded
+		if !s.expanded && s.item.HasFocus() && a.setFocus != nil {
+			a.setFocus(a) // Don't keep the focus in a hidden primitive.
+		}
+	}
+
+	// Start the animation.
+	if animated {
+		a.mu.Lock()
+		app := a.app
+		if end := now.Add(a.animation); end.After(a.animationEnd) {
+			a.animationEnd = end
+		}
+		a.mu.Unlock()
+		app.startAnimation(a, accordionFrameInterval)
+		time.AfterFunc(a.animation, func() {
+			a.mu.Lock()
+			done := !time.Now().Before(a.animationEnd)
+			a.mu.Unlock()
+			if done {
+				app.stopAnimation(a)
+				app.requestDraw() // Draw the final state.
+			}
+		})
+	}
+
+	if notify && a.changed != nil {
+		for _, s := range changed {
+			a.changed(s.name, s.expanded)
+		}
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (a *Accordion) Focus(delegate func(p Primitive)) {
+	a.setFocus = delegate
+	a.Box.Focus(delegate)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (a *Accordion) HasFocus() bool {
+	for _, section := range a.sections {
+		if section.item.HasFocus() {
+			return true
+		}
+	}
+	return a.Box.HasFocus()
+}
+
+// Draw draws this primitive onto the screen.
+func (a *Accordion) Draw(screen tcell.Screen) {
+	a.Box.DrawForSubclass(screen, a)
+
+	x, y, width, height := a.GetInnerRect()
+	a.titleY = a.titleY[:0]
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Calculate the heights of the expanded sections. Flexible sections share
+	// the remaining space according to how far they are expanded.
+	now := time.Now()
+	fractions := make([]float64, len(a.sections))
+	available, weights := float64(height-len(a.sections)), 0.0
+	for index, section := range a.sections {
+		fractions[index] = a.fraction(section, now)
+		if section.height > 0 {
+			available -= float64(section.height) * fractions[index]
+		} else {
+			weights += fractions[index]
+		}
+	}
+	if available < 0 {
+		available = 0
+	}
+	heights := make([]int, len(a.sections))
+	var flexibleSum float64
+	var flexibleHeight int
+	for index, section := range a.sections {
+		if section.height > 0 {
+			heights[index] = int(math.Round(float64(section.height) * fractions[index]))
+		} else if weights > 0 {
+			flexibleSum += available * fractions[index] / weights
+			heights[index] = int(math.Round(flexibleSum)) - flexibleHeight // Distribute rounding errors.
+			flexibleHeight += heights[index]
+		}
+	}
+
+	// Draw the sections.
+	bottom := y + height
+	for index, section := range a.sections {
+		if y >= bottom {
+			a.titleY = append(a.titleY, -1)
+			continue
+		}
+
+		// Draw the title row.
+		a.titleY = append(a.titleY, y)
+		style := a.titleStyle
+		if index == a.current && a.Box.HasFocus() {
+			style = a.currentTitleStyle
+		}
+		for offset := 0; offset < width; offset++ {
+			screen.SetContent(x+offset, y, ' ', nil, style)
+		}
+		arrow := '▶'
+		if section.expanded {
+			arrow = '▼'
+		}
+		screen.SetContent(x+1, y, arrow, nil, style)
+		printWithStyle(screen, section.title, x+3, y, 0, width-4, AlignLeft, style, false)
+		y++
+
+		// Draw the primitive.
+		itemHeight := heights[index]
+		if y+itemHeight > bottom {
+			itemHeight = bottom - y
+		}
+		if itemHeight > 0 {
+			section.item.SetRect(x, y, width, itemHeight)
+			if section.item.HasFocus() {
+				defer section.item.Draw(screen)
+			} else {
+				section.item.Draw(screen)
+			}
+			y += itemHeight
+		}
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (a *Accordion) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return a.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		// Pass events on to the focused section's primitive.
+		for index, section := range a.sections {
+			if section.item.HasFocus() {
+				if event.Key() == tcell.KeyUp && event.Modifiers()&tcell.ModCtrl != 0 {
+					a.current = index
+					setFocus(a)
+					return
+				}
+				if handler := section.item.InputHandler(); handler != nil {
+					handler(event, setFocus)
+				}
+				return
+			}
+		}
+		if len(a.sections) == 0 {
+			return
+		}
+
+		// Navigate the section titles.
+		section := a.sections[a.current]
+		switch event.Key() {
+		case tcell.KeyUp:
+			if a.current > 0 {
+				a.current--
+			}
+		case tcell.KeyDown:
+			if a.current < len(a.sections)-1 {
+				a.current++
+			}
+		case tcell.KeyHome:
+			a.current = 0
+		case tcell.KeyEnd:
+			a.current = len(a.sections) - 1
+		case tcell.KeyRight:
+			a.setExpanded(section, true, true)
+		case tcell.KeyLeft:
+			a.setExpanded(section, false, true)
+		case tcell.KeyEnter:
+			a.setExpanded(section, true, true)
+			setFocus(section.item)
+		case tcell.KeyRune:
+			if event.Rune() == ' ' {
+				a.setExpanded(section, !section.expanded, true)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (a *Accordion) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return a.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !a.InRect(x, y) {
+			return false, nil
+		}
+
+		// Clicking on a title expands or collapses its section.
+		for index, titleY := range a.titleY {
+			if titleY == y && index < len(a.sections) {
+				if action == MouseLeftClick {
+					a.setFocus = setFocus
+					a.current = index
+					setFocus(a)
+					a.setExpanded(a.sections[index], !a.sections[index].expanded, true)
+				}
+				return true, nil
+			}
+		}
+
+		// Pass other events on to the sections' primitives.
+		for _, section := range a.sections {
+			if section.expanded {
+				consumed, capture = section.item.MouseHandler()(action, event, setFocus)
+				if consumed {
+					return
+				}
+			}
+		}
+
+		// Clicking elsewhere focuses the accordion.
+		if action == MouseLeftDown {
+			setFocus(a)
+			consumed = true
+		}
+		return
+	})
+}
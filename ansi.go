@@ -34,6 +34,12 @@ type ansi struct {
 // written to it into tview style tags. Other escape codes don't have an effect
 // and are simply removed. The translated text is written to the provided
 // writer.
+//
+// All SGR (Select Graphic Rendition) attributes which have an equivalent in
+// tview are supported: bold, dim, italic, underline (including double
+// underline), blink, reverse, and strike-through, as well as the standard,
+// 8-bit (256 colors), and 24-bit foreground and background colors, with
+// parameters separated by semicolons or colons.
 func ANSIWriter(writer io.Writer) io.Writer {
 	return &ansi{
 		Writer:          writer,
@@ -62,6 +68,7 @@ func (a *ansi) Write(text []byte) (int, error) {
 				a.csiIntermediate.Reset()
 				a.state = ansiControlSequence
 			case 'c': // Reset.
+				a.attributes = ""
 				fmt.Fprint(a.buffer, "[-:-:-]")
 				a.state = ansiText
 			case 'P', ']', 'X', '^', '_': // Substrings and commands.
@@ -90,129 +97,8 @@ func (a *ansi) Write(text []byte) (int, error) {
 					}
 					fmt.Fprint(a.buffer, strings.Repeat("\n", count))
 				case 'm': // Select Graphic Rendition.
-					var background, foreground string
-					params := a.csiParameter.String()
-					fields := strings.Split(params, ";")
-					if len(params) == 0 || len(fields) == 1 && fields[0] == "0" {
-						// Reset.
-						a.attributes = ""
-						if _, err := a.buffer.WriteString("[-:-:-]"); err != nil {
-							return 0, err
-						}
-						break
-					}
-					lookupColor := func(colorNumber int) string {
-						if colorNumber < 0 || colorNumber > 15 {
-							return "black"
-						}
-						return []string{
-							"black",
-							"maroon",
-							"green",
-							"olive",
-							"navy",
-							"purple",
-							"teal",
-							"silver",
-							"gray",
-							"red",
-							"lime",
-							"yellow",
-							"blue",
-							"fuchsia",
-							"aqua",
-							"white",
-						}[colorNumber]
-					}
-				FieldLoop:
-					for index, field := range fields {
-						switch field {
-						case "1", "01":
-							if !strings.ContainsRune(a.attributes, 'b') {
-								a.attributes += "b"
-							}
-						case "2", "02":
-							if !strings.ContainsRune(a.attributes, 'd') {
-								a.attributes += "d"
-							}
-						case "4", "04":
-							if !strings.ContainsRune(a.attributes, 'u') {
-								a.attributes += "u"
-							}
-						case "5", "05":
-							if !strings.ContainsRune(a.attributes, 'l') {
-								a.attributes += "l"
-							}
-						case "22":
-							if i := strings.IndexRune(a.attributes, 'b'); i >= 0 {
-								a.attributes = a.attributes[:i] + a.attributes[i+1:]
-							}
-							if i := strings.IndexRune(a.attributes, 'd'); i >= 0 {
-								a.attributes = a.attributes[:i] + a.attributes[i+1:]
-							}
-						case "24":
-							if i := strings.IndexRune(a.attributes, 'u'); i >= 0 {
-								a.attributes = a.attributes[:i] + a.attributes[i+1:]
-							}
-						case "25":
-							if i := strings.IndexRune(a.attributes, 'l'); i >= 0 {
-								a.attributes = a.attributes[:i] + a.attributes[i+1:]
-							}
-						case "30", "31", "32", "33", "34", "35", "36", "37":
-							colorNumber, _ := strconv.Atoi(field)
-							foreground = lookupColor(colorNumber - 30)
-						case "39":
-							foreground = "-"
-						case "40", "41", "42", "43", "44", "45", "46", "47":
-							colorNumber, _ := strconv.Atoi(field)
-							background = lookupColor(colorNumber - 40)
-						case "49":
-							background = "-"
-						case "90", "91", "92", "93", "94", "95", "96", "97":
-							colorNumber, _ := strconv.Atoi(field)
-							foreground = lookupColor(colorNumber - 82)
-						case "100", "101", "102", "103", "104", "105", "106", "107":
-							colorNumber, _ := strconv.Atoi(field)
-							background = lookupColor(colorNumber - 92)
-						case "38", "48":
-							var color string
-							if len(fields) > index+1 {
-								if fields[index+1] == "5" && len(fields) > index+2 { // 8-bit colors.
-									colorNumber, _ := strconv.Atoi(fields[index+2])
-									if colorNumber <= 15 {
-										color = lookupColor(colorNumber)
-									} else if colorNumber <= 231 {
-										red := (colorNumber - 16) / 36
-										green := ((colorNumber - 16) / 6) % 6
-										blue := (colorNumber - 16) % 6
-										color = fmt.Sprintf("#%02x%02x%02x", 255*red/5, 255*green/5, 255*blue/5)
-									} else if colorNumber <= 255 {
-										grey := 255 * (colorNumber - 232) / 23
-										color = fmt.Sprintf("#%02x%02x%02x", grey, grey, grey)
-									}
-								} else if fields[index+1] == "2" && len(fields) > index+4 { // 24-bit colors.
-									red, _ := strconv.Atoi(fields[index+2])
-									green, _ := strconv.Atoi(fields[index+3])
-									blue, _ := strconv.Atoi(fields[index+4])
-									color = fmt.Sprintf("#%02x%02x%02x", red, green, blue)
-								}
-							}
-							if len(color) > 0 {
-								if field == "38" {
-									foreground = color
-								} else {
-									background = color
-								}
-							}
-							break FieldLoop
-						}
-					}
-					var colon string
-					if len(a.attributes) > 0 {
-						colon = ":"
-					}
-					if len(foreground) > 0 || len(background) > 0 || len(a.attributes) > 0 {
-						fmt.Fprintf(a.buffer, "[%s:%s%s%s]", foreground, background, colon, a.attributes)
+					if err := a.selectGraphicRendition(a.csiParameter.String()); err != nil {
+						return 0, err
 					}
 				}
 				a.state = ansiText
@@ -248,6 +134,192 @@ func (a *ansi) Write(text []byte) (int, error) {
 	return len(text), nil
 }
 
+// ansiAttributes maps SGR parameters to the tview attributes they switch on
+// (lowercase) or off (uppercase).
+var ansiAttributes = map[int]string{
+	1:  "b",
+	2:  "d",
+	3:  "i",
+	4:  "u",
+	5:  "l",
+	6:  "l",
+	7:  "r",
+	9:  "s",
+	21: "u", // Double underline.
+	22: "BD",
+	23: "I",
+	24: "U",
+	25: "L",
+	27: "R",
+	29: "S",
+}
+
+// ansiColors are the names of the 16 standard ANSI colors.
+var ansiColors = []string{
+	"black",
+	"maroon",
+	"green",
+	"olive",
+	"navy",
+	"purple",
+	"teal",
+	"silver",
+	"gray",
+	"red",
+	"lime",
+	"yellow",
+	"blue",
+	"fuchsia",
+	"aqua",
+	"white",
+}
+
+// selectGraphicRendition translates the parameters of an SGR control sequence
+// into a style tag and writes it to the buffer. Parameters may be separated by
+// semicolons or, for extended colors, sub-parameters by colons (e.g.
+// "38:2::255:128:0").
+func (a *ansi) selectGraphicRendition(params string) error {
+	// lookupColor returns the tag color for an 8-bit color number.
+	lookupColor := func(colorNumber int) string {
+		switch {
+		case colorNumber < 0 || colorNumber > 255:
+			return ""
+		case colorNumber <= 15:
+			return ansiColors[colorNumber]
+		case colorNumber <= 231:
+			levels := []int{0, 95, 135, 175, 215, 255}
+			colorNumber -= 16
+			return fmt.Sprintf("#%02x%02x%02x", levels[colorNumber/36], levels[(colorNumber/6)%6], levels[colorNumber%6])
+		default:
+			grey := 8 + 10*(colorNumber-232)
+			return fmt.Sprintf("#%02x%02x%02x", grey, grey, grey)
+		}
+	}
+
+	// extendedColor parses an extended color (the parameters following 38 or
+	// 48) and returns the tag color and the number of parameters used.
+	extendedColor := func(params []string) (color string, used int) {
+		if len(params) == 0 {
+			return "", 0
+		}
+		switch params[0] {
+		case "5": // 8-bit colors.
+			if len(params) < 2 {
+				return "", len(params)
+			}
+			colorNumber, err := strconv.Atoi(params[1])
+			if err != nil {
+				return "", 2
+			}
+			return lookupColor(colorNumber), 2
+		case "2": // 24-bit colors.
+			if len(params) < 4 {
+				return "", len(params)
+			}
+			red, _ := strconv.Atoi(params[1])
+			green, _ := strconv.Atoi(params[2])
+			blue, _ := strconv.Atoi(params[3])
+			if red < 0 || red > 255 || green < 0 || green > 255 || blue < 0 || blue > 255 {
+				return "", 4
+			}
+			return fmt.Sprintf("#%02x%02x%02x", red, green, blue), 4
+		}
+		return "", 1
+	}
+
+	var foreground, background string
+	previous := a.attributes
+	flush := func() error {
+		// Determine which attributes were switched on or off.
+		var attributes string
+		for _, attribute := range a.attributes {
+			if !strings.ContainsRune(previous, attribute) {
+				attributes += string(attribute)
+			}
+		}
+		for _, attribute := range previous {
+			if !strings.ContainsRune(a.attributes, attribute) {
+				attributes += strings.ToUpper(string(attribute))
+			}
+		}
+		if foreground == "" && background == "" && attributes == "" {
+			return nil
+		}
+		var colon string
+		if len(attributes) > 0 {
+			colon = ":"
+		}
+		_, err := fmt.Fprintf(a.buffer, "[%s:%s%s%s]", foreground, background, colon, attributes)
+		return err
+	}
+
+	fields := strings.Split(params, ";")
+	for index := 0; index < len(fields); index++ {
+		subParams := strings.Split(fields[index], ":")
+		code, err := strconv.Atoi(subParams[0])
+		if err != nil && subParams[0] != "" {
+			continue // Ignore invalid parameters.
+		}
+		switch {
+		case code == 0: // Reset.
+			foreground, background, a.attributes, previous = "", "", "", ""
+			if _, err := a.buffer.WriteString("[-:-:-]"); err != nil {
+				return err
+			}
+		case ansiAttributes[code] != "":
+			for _, attribute := range ansiAttributes[code] {
+				if attribute >= 'a' && attribute <= 'z' {
+					if !strings.ContainsRune(a.attributes, attribute) {
+						a.attributes += string(attribute)
+					}
+				} else {
+					a.attributes = strings.ReplaceAll(a.attributes, strings.ToLower(string(attribute)), "")
+				}
+			}
+			if code == 4 && len(subParams) > 1 && subParams[1] == "0" { // "4:0" means no underline.
+				a.attributes = strings.ReplaceAll(a.attributes, "u", "")
+			}
+		case code >= 30 && code <= 37:
+			foreground = lookupColor(code - 30)
+		case code == 39:
+			foreground = "-"
+		case code >= 40 && code <= 47:
+			background = lookupColor(code - 40)
+		case code == 49:
+			background = "-"
+		case code >= 90 && code <= 97:
+			foreground = lookupColor(code - 82)
+		case code >= 100 && code <= 107:
+			background = lookupColor(code - 92)
+		case code == 38 || code == 48 || code == 58:
+			var color string
+			if len(subParams) > 1 {
+				// Colon-separated sub-parameters. 24-bit colors may include a
+				// color space ID.
+				colorParams := subParams[1:]
+				if colorParams[0] == "2" && len(colorParams) >= 5 {
+					colorParams = append([]string{"2"}, colorParams[2:]...)
+				}
+				color, _ = extendedColor(colorParams)
+			} else {
+				var used int
+				color, used = extendedColor(fields[index+1:])
+				index += used
+			}
+			if color != "" {
+				switch code {
+				case 38:
+					foreground = color
+				case 48:
+					background = color
+				} // Underline colors are not supported.
+			}
+		}
+	}
+
+	return flush()
+}
+
 // TranslateANSI replaces ANSI escape sequences found in the provided string
 // with tview's style tags and returns the resulting string.
 func TranslateANSI(text string) string {
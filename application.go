@@ -1,6 +1,10 @@
 package tview
 
 import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
 	"sync"
 	"time"
 
@@ -118,15 +122,38 @@ type Application struct {
 	mouseDownX, mouseDownY  int              // The position of the mouse when its button was last pressed.
 	lastMouseClick          time.Time        // The time when a mouse button was last clicked.
 	lastMouseButtons        tcell.ButtonMask // The last mouse button state.
+
+	// Animated primitives mapped to the time between their frames. As long as
+	// there are any, the screen is redrawn periodically.
+	animations map[Primitive]time.Duration
+
+	// Signals the event loop that the next animation frame is due.
+	animationFrame chan struct{}
+
+	// Whether the next animation frame has already been scheduled.
+	animationScheduled bool
+
+	// Signals the event loop that the screen needs to be redrawn.
+	drawRequests chan struct{}
+
+	// The toasts shown on top of the root primitive.
+	notifications *Notifications
+
+	// The images drawn with graphics protocols.
+	graphics imageGraphics
 }
 
 // NewApplication creates and returns a new application.
 func NewApplication() *Application {
-	return &Application{
+	a := &Application{
 		events:            make(chan tcell.Event, queueSize),
 		updates:           make(chan queuedUpdate, queueSize),
 		screenReplacement: make(chan tcell.Screen, 1),
+		animationFrame:    make(chan struct{}, 1),
+		drawRequests:      make(chan struct{}, 1),
 	}
+	a.notifications = newNotifications(a)
+	return a
 }
 
 // SetInputCapture sets a function which captures all key events before they are
@@ -368,6 +395,7 @@ EventLoop:
 					continue
 				}
 				lastRedraw = time.Now()
+				a.graphics.invalidate(screen)
 				screen.Clear()
 				a.draw()
 			case *tcell.EventMouse:
@@ -390,6 +418,18 @@ EventLoop:
 			if update.done != nil {
 				update.done <- struct{}{}
 			}
+
+		// Draw the next animation frame.
+		case <-a.animationFrame:
+			a.Lock()
+			a.animationScheduled = false
+			a.Unlock()
+			a.draw()
+			a.scheduleAnimationFrame()
+
+		// A primitive requested a redraw.
+		case <-a.drawRequests:
+			a.draw()
 		}
 	}
 
@@ -422,6 +462,14 @@ func (a *Application) fireMouseActions(event *tcell.EventMouse) (consumed, isMou
 			}
 		}
 
+		// Clicks on notifications don't reach the primitives below them.
+		if a.mouseCapturingPrimitive == nil && targetPrimitive == nil {
+			if x, y := event.Position(); a.notifications.mouseAction(action, x, y) {
+				consumed = true
+				return
+			}
+		}
+
 		// Determine the target primitive.
 		var primitive, capturingPrimitive Primitive
 		if a.mouseCapturingPrimitive != nil {
@@ -554,6 +602,77 @@ func (a *Application) Suspend(f func()) bool {
 	return true
 }
 
+// EditInExternalEditor suspends the application (see [Application.Suspend])
+// and lets the user edit the text of the given text area in an external
+// editor. The editor command is taken from the VISUAL or EDITOR environment
+// variables, in that order, and defaults to "vi". It is invoked with the name
+// of a temporary file containing the text which is removed afterwards.
+//
+// When the editor exits successfully, the text area's text is replaced with
+// the file's contents as one change which the user can undo. Many editors add
+// a newline to the end of the file. If the original text did not end with a
+// newline, this final newline is removed again.
+//
+// An error is returned if the application could not be suspended, if the
+// temporary file could not be written or read, or if the editor failed. The
+// text area is not changed in that case. This function blocks until the editor
+// exits. It is typically called from an event handler, e.g. one set with
+// [Box.SetInputCapture].
+func (a *Application) EditInExternalEditor(textArea *TextArea) error {
+	// Write the text to a temporary file.
+	file, err := os.CreateTemp("", "tview-*.txt")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	text := textArea.GetText()
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	// Determine the editor.
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+
+	// Run it.
+	var runErr error
+	if !a.Suspend(func() {
+		cmd := exec.Command(args[0], append(args[1:], file.Name())...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		runErr = cmd.Run()
+	}) {
+		return errors.New("application could not be suspended")
+	}
+	if runErr != nil {
+		return runErr
+	}
+
+	// Load the result.
+	contents, err := os.ReadFile(file.Name())
+	if err != nil {
+		return err
+	}
+	newText := string(contents)
+	if !strings.HasSuffix(text, "\n") {
+		newText = strings.TrimSuffix(strings.TrimSuffix(newText, "\n"), "\r")
+	}
+	if newText != text {
+		textArea.Replace(0, textArea.GetTextLength(), newText)
+	}
+
+	return nil
+}
+
 // Draw refreshes the screen (during the next update cycle). It calls the Draw()
 // function of the application's root primitive and then syncs the screen
 // buffer. It is almost never necessary to call this function. It can actually
@@ -612,19 +731,80 @@ func (a *Application) draw() *Application {
 	}
 
 	// Draw all primitives.
+	a.graphics.begin(screen)
 	root.Draw(screen)
 
+	// Draw notifications on top of them.
+	a.notifications.draw(screen)
+
 	// Call after handler if there is one.
 	if after != nil {
 		after(screen)
 	}
 
-	// Sync screen.
-	screen.Show()
+	// Sync screen, then draw images using graphics protocols on top of it.
+	a.graphics.show(screen)
 
 	return a
 }
 
+// requestDraw asks the event loop to redraw the screen. Unlike Draw(), this
+// function never blocks and multiple requests made before the next redraw
+// result in a single redraw. It may be called from any goroutine.
+func (a *Application) requestDraw() {
+	select {
+	case a.drawRequests <- struct{}{}:
+	default: // A redraw is already pending.
+	}
+}
+
+// startAnimation registers the given primitive as animated. The screen will be
+// redrawn in the given interval (or more often if other animated primitives
+// require it) until stopAnimation() is called for the primitive. This function
+// may be called from any goroutine but not while drawing.
+func (a *Application) startAnimation(p Primitive, interval time.Duration) {
+	a.Lock()
+	if a.animations == nil {
+		a.animations = make(map[Primitive]time.Duration)
+	}
+	a.animations[p] = interval
+	a.Unlock()
+	a.scheduleAnimationFrame()
+}
+
+// stopAnimation removes the given primitive from the animated primitives.
+func (a *Application) stopAnimation(p Primitive) {
+	a.Lock()
+	defer a.Unlock()
+	delete(a.animations, p)
+}
+
+// scheduleAnimationFrame schedules the next animation frame if there are
+// animated primitives and no frame has been scheduled yet.
+func (a *Application) scheduleAnimationFrame() {
+	a.Lock()
+	defer a.Unlock()
+	if a.animationScheduled || len(a.animations) == 0 {
+		return
+	}
+	var interval time.Duration
+	for _, d := range a.animations {
+		if interval == 0 || d < interval {
+			interval = d
+		}
+	}
+	if interval < redrawPause {
+		interval = redrawPause
+	}
+	a.animationScheduled = true
+	time.AfterFunc(interval, func() {
+		select {
+		case a.animationFrame <- struct{}{}:
+		default: // A frame is already pending.
+		}
+	})
+}
+
 // Sync forces a full re-sync of the screen buffer with the actual screen during
 // the next event cycle. This is useful for when the terminal screen is
 // corrupted so you may want to offer your users a keyboard shortcut to refresh
@@ -637,6 +817,7 @@ func (a *Application) Sync() *Application {
 		if screen == nil {
 			return
 		}
+		a.graphics.invalidate(screen)
 		screen.Sync()
 	}}
 	return a
@@ -741,6 +922,12 @@ func (a *Application) GetFocus() Primitive {
 	return a.focus
 }
 
+// GetNotifications returns the object which shows transient messages on top
+// of the application's primitives.
+func (a *Application) GetNotifications() *Notifications {
+	return a.notifications
+}
+
 // QueueUpdate is used to synchronize access to primitives from non-main
 // goroutines. The provided function will be executed as part of the event loop
 // and thus will not cause race conditions with other such update functions or
@@ -0,0 +1,355 @@
+package tview
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// barChartBar is one bar of a bar chart.
+type barChartBar struct {
+	label string
+	value float64
+	color tcell.Color
+}
+
+// BarChart shows a set of labeled values as bars. By default, the bars are
+// vertical, growing from the bottom upwards with their labels below them. In
+// horizontal mode (see [BarChart.SetHorizontal]), the bars grow from left to
+// right with their labels to their left.
+//
+// The bars are scaled to fit the chart's inner rectangle, such that the largest
+// value fills all available space (see [BarChart.SetMaxValue] for a fixed
+// scale). Negative values are shown as empty bars.
+type BarChart struct {
+	*Box
+
+	// The bars, in the order in which they are shown.
+	bars []barChartBar
+
+	// Whether the bars grow from left to right.
+	horizontal bool
+
+	// The fixed value of a full bar or 0 to use the largest value.
+	maxValue float64
+
+	// The number of cells across a bar or 0 to choose a default.
+	barWidth int
+
+	// The number of cells between bars.
+	barGap int
+
+	// Whether values are shown next to the bars.
+	showValues bool
+
+	// The format string used to show values.
+	valueFormat string
+
+	// The color of bars which were added without a color.
+	barColor tcell.Color
+
+	// The style of the labels.
+	labelStyle tcell.Style
+
+	// The style of the values.
+	valueStyle tcell.Style
+}
+
+// NewBarChart returns a new, empty bar chart.
+func NewBarChart() *BarChart {
+	return &BarChart{
+		Box:         NewBox(),
+		barGap:      1,
+		valueFormat: "%g",
+		barColor:    Styles.ContrastBackgroundColor,
+		labelStyle:  tcell.StyleDefault.Foreground(Styles.PrimaryTextColor),
+		valueStyle:  tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
+	}
+}
+
+// AddBar adds a bar with the given label, value, and color to the end of the
+// chart. If the color is [tcell.ColorDefault], the color set with
+// [BarChart.SetBarColor] is used.
+func (b *BarChart) AddBar(label string, value float64, color tcell.Color) *BarChart {
+	b.bars = append(b.bars, barChartBar{label: label, value: value, color: color})
+	return b
+}
+
+// SetBarValue changes the value of the bar with the given index. Invalid
+// indices are ignored.
+func (b *BarChart) SetBarValue(index int, value float64) *BarChart {
+	if index >= 0 && index < len(b.bars) {
+		b.bars[index].value = value
+	}
+	return b
+}
+
+// GetBar returns the label and the value of the bar with the given index. If
+// the index is invalid, an empty string and 0 are returned.
+func (b *BarChart) GetBar(index int) (label string, value float64) {
+	if index < 0 || index >= len(b.bars) {
+		return "", 0
+	}
+	return b.bars[index].label, b.bars[index].value
+}
+
+// RemoveBar removes the bar with the given index. Invalid indices are ignored.
+func (b *BarChart) RemoveBar(index int) *BarChart {
+	if index >= 0 && index < len(b.bars) {
+		b.bars = append(b.bars[:index], b.bars[index+1:]...)
+	}
+	return b
+}
+
+// GetBarCount returns the number of bars.
+func (b *BarChart) GetBarCount() int {
+	return len(b.bars)
+}
+
+// Clear removes all bars.
+func (b *BarChart) Clear() *BarChart {
+	b.bars = nil
+	return b
+}
+
+// SetHorizontal sets whether the bars grow from left to right instead of from
+// bottom to top.
+func (b *BarChart) SetHorizontal(horizontal bool) *BarChart {
+	b.horizontal = horizontal
+	return b
+}
+
+// SetMaxValue sets the value of a completely filled bar. Larger values are
+// clamped. A value of 0 (the default) scales the bars to the largest value.
+func (b *BarChart) SetMaxValue(max float64) *BarChart {
+	if max < 0 {
+		max = 0
+	}
+	b.maxValue = max
+	return b
+}
+
+// SetBarWidth sets the number of cells across each bar, i.e. the number of
+// columns for vertical bars and the number of rows for horizontal bars. A value
+// of 0 (the default) results in a width of 3 for vertical bars and 1 for
+// horizontal bars.
+func (b *BarChart) SetBarWidth(width int) *BarChart {
+	if width < 0 {
+		width = 0
+	}
+	b.barWidth = width
+	return b
+}
+
+// SetBarGap sets the number of cells between two bars. The default is 1.
+func (b *BarChart) SetBarGap(gap int) *BarChart {
+	if gap < 0 {
+		gap = 0
+	}
+	b.barGap = gap
+	return b
+}
+
+// ShowValues sets whether each bar's value is shown above a vertical bar or to
+// the right of a horizontal bar.
+func (b *BarChart) ShowValues(show bool) *BarChart {
+	b.showValues = show
+	return b
+}
+
+// SetValueFormat sets the format string (as used by [fmt.Sprintf]) with which
+// values are shown, see [BarChart.ShowValues]. The default is "%g".
+func (b *BarChart) SetValueFormat(format string) *BarChart {
+	b.valueFormat = format
+	return b
+}
+
+// SetBarColor sets the color of bars which were added without a color.
+func (b *BarChart) SetBarColor(color tcell.Color) *BarChart {
+	b.barColor = color
+	return b
+}
+
+// SetLabelStyle sets the style of the bar labels.
+func (b *BarChart) SetLabelStyle(style tcell.Style) *BarChart {
+	b.labelStyle = style
+	return b
+}
+
+// SetValueStyle sets the style of the values shown next to the bars.
+func (b *BarChart) SetValueStyle(style tcell.Style) *BarChart {
+	b.valueStyle = style
+	return b
+}
+
+// scale returns the value of a completely filled bar.
+func (b *BarChart) scale() float64 {
+	if b.maxValue > 0 {
+		return b.maxValue
+	}
+	var max float64
+	for _, bar := range b.bars {
+		if bar.value > max {
+			max = bar.value
+		}
+	}
+	return max
+}
+
+// eighths returns the length of a bar with the given value in eighths of a
+// cell if a full bar has the given length in cells.
+func (b *BarChart) eighths(value float64, length int) int {
+	max := b.scale()
+	if max <= 0 || value <= 0 || length <= 0 {
+		return 0
+	}
+	if value > max {
+		value = max
+	}
+	return int(math.Round(value / max * float64(length*8)))
+}
+
+// valueText returns the formatted value of the given bar.
+func (b *BarChart) valueText(bar barChartBar) string {
+	return Escape(fmt.Sprintf(b.valueFormat, bar.value))
+}
+
+// Draw draws this primitive onto the screen.
+func (b *BarChart) Draw(screen tcell.Screen) {
+	b.Box.DrawForSubclass(screen, b)
+
+	x, y, width, height := b.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if b.horizontal {
+		b.drawHorizontal(screen, x, y, width, height)
+	} else {
+		b.drawVertical(screen, x, y, width, height)
+	}
+}
+
+// drawVertical draws bars which grow from bottom to top.
+func (b *BarChart) drawVertical(screen tcell.Screen, x, y, width, height int) {
+	barWidth := b.barWidth
+	if barWidth == 0 {
+		barWidth = 3
+	}
+
+	// Labels take up the bottom row, values the row above each bar.
+	length := height - 1
+	if b.showValues {
+		length--
+	}
+	_, labelBg, _ := b.labelStyle.Decompose()
+	_, valueBg, _ := b.valueStyle.Decompose()
+	for _, bar := range b.bars {
+		if barWidth > width {
+			barWidth = width
+		}
+		if barWidth <= 0 {
+			break
+		}
+
+		// Draw the bar.
+		color := bar.color
+		if color == tcell.ColorDefault {
+			color = b.barColor
+		}
+		style := tcell.StyleDefault.Background(b.backgroundColor).Foreground(color)
+		eighths := b.eighths(bar.value, length)
+		top := y + height - 1
+		for row := 0; eighths > 0 && row < length; row++ {
+			block := eighths
+			if block > 8 {
+				block = 8
+			}
+			top = y + height - 2 - row
+			for column := 0; column < barWidth; column++ {
+				screen.SetContent(x+column, top, gaugeVerticalBlocks[block], nil, style)
+			}
+			eighths -= block
+		}
+
+		// Draw the value and the label.
+		if b.showValues && top > y {
+			printWithStyle(screen, b.valueText(bar), x, top-1, 0, barWidth, AlignCenter, b.valueStyle, valueBg == tcell.ColorDefault)
+		}
+		printWithStyle(screen, bar.label, x, y+height-1, 0, barWidth, AlignCenter, b.labelStyle, labelBg == tcell.ColorDefault)
+
+		x += barWidth + b.barGap
+		width -= barWidth + b.barGap
+	}
+}
+
+// drawHorizontal draws bars which grow from left to right.
+func (b *BarChart) drawHorizontal(screen tcell.Screen, x, y, width, height int) {
+	barWidth := b.barWidth
+	if barWidth == 0 {
+		barWidth = 1
+	}
+
+	// Labels take up a column on the left, values follow each bar.
+	var labelWidth, valueWidth int
+	for _, bar := range b.bars {
+		if w := TaggedStringWidth(bar.label); w > labelWidth {
+			labelWidth = w
+		}
+		if w := TaggedStringWidth(b.valueText(bar)); b.showValues && w > valueWidth {
+			valueWidth = w
+		}
+	}
+	if labelWidth > 0 {
+		labelWidth++ // Add one space.
+	}
+	if valueWidth > 0 {
+		valueWidth++ // Add one space.
+	}
+	if labelWidth > width {
+		labelWidth = width
+	}
+	length := width - labelWidth - valueWidth
+	_, labelBg, _ := b.labelStyle.Decompose()
+	_, valueBg, _ := b.valueStyle.Decompose()
+	for _, bar := range b.bars {
+		if barWidth > height {
+			barWidth = height
+		}
+		if barWidth <= 0 {
+			break
+		}
+
+		// Draw the label.
+		middle := y + (barWidth-1)/2
+		printWithStyle(screen, bar.label, x, middle, 0, labelWidth-1, AlignRight, b.labelStyle, labelBg == tcell.ColorDefault)
+
+		// Draw the bar.
+		color := bar.color
+		if color == tcell.ColorDefault {
+			color = b.barColor
+		}
+		style := tcell.StyleDefault.Background(b.backgroundColor).Foreground(color)
+		eighths := b.eighths(bar.value, length)
+		end := x + labelWidth
+		for eighths > 0 {
+			block := eighths
+			if block > 8 {
+				block = 8
+			}
+			for row := 0; row < barWidth; row++ {
+				screen.SetContent(end, y+row, gaugeHorizontalBlocks[block], nil, style)
+			}
+			end++
+			eighths -= block
+		}
+
+		// Draw the value.
+		if b.showValues {
+			printWithStyle(screen, b.valueText(bar), end+1, middle, 0, x+width-end-1, AlignLeft, b.valueStyle, valueBg == tcell.ColorDefault)
+		}
+
+		y += barWidth + b.barGap
+		height -= barWidth + b.barGap
+	}
+}
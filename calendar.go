@@ -0,0 +1,408 @@
+package tview
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// The screen size of a calendar's month grid: seven columns of three cells
+// (minus the trailing gap) and eight rows (header, weekdays, and six weeks).
+const (
+	calendarWidth  = 7*3 - 1
+	calendarHeight = 8
+)
+
+// Calendar shows the days of one month in a grid, one week per row. The user
+// navigates between days with a cursor and selects a day by pressing Enter or
+// by clicking on it. The range of selectable dates may be restricted with
+// [Calendar.SetDateRange]. The first day of the week is configured with
+// [Calendar.SetWeekStart].
+//
+// The following keys are available:
+//
+//   - Left arrow / right arrow: Move the cursor to the previous / next day.
+//   - Up arrow / down arrow: Move the cursor to the previous / next week.
+//   - Page up / page down: Move the cursor to the previous / next month.
+//   - Home / end: Move the cursor to the first / last day of the month.
+//   - Enter: Select the day under the cursor.
+//
+// The arrows in the header change the month when clicked, as does the mouse
+// wheel.
+//
+// Dates are handled as calendar days in their own location. The time of day
+// is ignored.
+type Calendar struct {
+	*Box
+
+	// The day under the cursor. Its month is the one shown.
+	cursor time.Time
+
+	// The selected day or the zero time if no day is selected.
+	selected time.Time
+
+	// The range of selectable days. A zero time means no restriction.
+	minDate, maxDate time.Time
+
+	// The first day of the week.
+	weekStart time.Weekday
+
+	// The style of the month and year in the header.
+	headerStyle tcell.Style
+
+	// The style of the weekday names.
+	weekdayStyle tcell.Style
+
+	// The style of selectable days.
+	dayStyle tcell.Style
+
+	// The style of today's day.
+	todayStyle tcell.Style
+
+	// The style of the selected day.
+	selectedStyle tcell.Style
+
+	// The style of the day under the cursor when the calendar has focus.
+	cursorStyle tcell.Style
+
+	// The style of days outside the selectable range.
+	disabledStyle tcell.Style
+
+	// An optional function which is called when the user selects a day.
+	selectedFunc func(date time.Time)
+
+	// An optional function which is called when the cursor moves to another
+	// day.
+	changed func(date time.Time)
+
+	// An optional function which is called when the user presses Escape, Tab,
+	// or Backtab.
+	done func(key tcell.Key)
+}
+
+// NewCalendar returns a new calendar showing the current month with the
+// cursor on today's day. No day is selected. Weeks start on Monday.
+func NewCalendar() *Calendar {
+	return &Calendar{
+		Box:           NewBox(),
+		cursor:        calendarDay(time.Now()),
+		weekStart:     time.Monday,
+		headerStyle:   tcell.StyleDefault.Foreground(Styles.TitleColor).Bold(true),
+		weekdayStyle:  tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
+		dayStyle:      tcell.StyleDefault.Foreground(Styles.PrimaryTextColor),
+		todayStyle:    tcell.StyleDefault.Foreground(Styles.PrimaryTextColor).Underline(true),
+		selectedStyle: tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		cursorStyle:   tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.PrimitiveBackgroundColor),
+		disabledStyle: tcell.StyleDefault.Foreground(Styles.TertiaryTextColor).Dim(true),
+	}
+}
+
+// calendarDay returns midnight of the given time's day.
+func calendarDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// SetSelectedDate selects the given day and moves the cursor to it. A zero
+// time deselects the current day and leaves the cursor where it is. This does
+// not trigger the "selected" callback.
+func (c *Calendar) SetSelectedDate(date time.Time) *Calendar {
+	if date.IsZero() {
+		c.selected = time.Time{}
+		return c
+	}
+	c.selected = calendarDay(date)
+	c.cursor = c.selected
+	return c
+}
+
+// GetSelectedDate returns the selected day (at midnight) or the zero time if
+// no day is selected.
+func (c *Calendar) GetSelectedDate() time.Time {
+	return c.selected
+}
+
+// SetCursorDate moves the cursor to the given day, showing its month. This
+// does not trigger the "changed" callback.
+func (c *Calendar) SetCursorDate(date time.Time) *Calendar {
+	if !date.IsZero() {
+		c.cursor = calendarDay(date)
+	}
+	return c
+}
+
+// GetCursorDate returns the day (at midnight) under the cursor.
+func (c *Calendar) GetCursorDate() time.Time {
+	return c.cursor
+}
+
+// SetDateRange restricts the days which can be selected to the range from min
+// to max, inclusively. A zero time for either value removes that side's
+// restriction. The cursor is moved into the range if necessary.
+func (c *Calendar) SetDateRange(min, max time.Time) *Calendar {
+	c.minDate, c.maxDate = time.Time{}, time.Time{}
+	if !min.IsZero() {
+		c.minDate = calendarDay(min)
+	}
+	if !max.IsZero() {
+		c.maxDate = calendarDay(max)
+	}
+	c.cursor = c.clamp(c.cursor)
+	return c
+}
+
+// GetDateRange returns the range of selectable days. A zero time means there
+// is no restriction on that side.
+func (c *Calendar) GetDateRange() (min, max time.Time) {
+	return c.minDate, c.maxDate
+}
+
+// InRange returns whether the given day can be selected.
+func (c *Calendar) InRange(date time.Time) bool {
+	date = calendarDay(date)
+	return (c.minDate.IsZero() || !date.Before(c.minDate)) && (c.maxDate.IsZero() || !date.After(c.maxDate))
+}
+
+// clamp returns the given day, moved into the selectable range.
+func (c *Calendar) clamp(date time.Time) time.Time {
+	if !c.minDate.IsZero() && date.Before(c.minDate) {
+		return c.minDate
+	}
+	if !c.maxDate.IsZero() && date.After(c.maxDate) {
+		return c.maxDate
+	}
+	return date
+}
+
+// SetWeekStart sets the first day of the week, i.e. the day in the leftmost
+// column. The default is Monday.
+func (c *Calendar) SetWeekStart(day time.Weekday) *Calendar {
+	c.weekStart = day % 7
+	return c
+}
+
+// SetHeaderStyle sets the style of the month and year shown in the header.
+func (c *Calendar) SetHeaderStyle(style tcell.Style) *Calendar {
+	c.headerStyle = style
+	return c
+}
+
+// SetWeekdayStyle sets the style of the weekday names.
+func (c *Calendar) SetWeekdayStyle(style tcell.Style) *Calendar {
+	c.weekdayStyle = style
+	return c
+}
+
+// SetDayStyles sets the styles of the days: selectable days in general,
+// today's day, the selected day, the day under the cursor when the calendar
+// has focus, and days outside the selectable range.
+func (c *Calendar) SetDayStyles(day, today, selected, cursor, disabled tcell.Style) *Calendar {
+	c.dayStyle, c.todayStyle, c.selectedStyle, c.cursorStyle, c.disabledStyle = day, today, selected, cursor, disabled
+	return c
+}
+
+// SetSelectedFunc sets a handler which is called when the user selects a day.
+// The handler receives the day at midnight.
+func (c *Calendar) SetSelectedFunc(handler func(date time.Time)) *Calendar {
+	c.selectedFunc = handler
+	return c
+}
+
+// SetChangedFunc sets a handler which is called when the cursor moves to
+// another day. The handler receives the day at midnight.
+func (c *Calendar) SetChangedFunc(handler func(date time.Time)) *Calendar {
+	c.changed = handler
+	return c
+}
+
+// SetDoneFunc sets a handler which is called when the user presses the Escape,
+// Tab, or Backtab key.
+func (c *Calendar) SetDoneFunc(handler func(key tcell.Key)) *Calendar {
+	c.done = handler
+	return c
+}
+
+// moveCursor moves the cursor to the given day, restricted to the selectable
+// range, and triggers the "changed" callback.
+func (c *Calendar) moveCursor(date time.Time) {
+	date = c.clamp(calendarDay(date))
+	if date.Equal(c.cursor) {
+		return
+	}
+	c.cursor = date
+	if c.changed != nil {
+		c.changed(date)
+	}
+}
+
+// addMonths returns the given day moved by the given number of months. If the
+// day doesn't exist in the target month, the last day of that month is used.
+func addMonths(date time.Time, months int) time.Time {
+	year, month, day := date.Date()
+	first := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, date.Location())
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// selectDay selects the given day on behalf of the user if it is in range.
+func (c *Calendar) selectDay(date time.Time) {
+	if !c.InRange(date) {
+		return
+	}
+	c.selected = calendarDay(date)
+	c.moveCursor(c.selected)
+	if c.selectedFunc != nil {
+		c.selectedFunc(c.selected)
+	}
+}
+
+// firstCell returns the day shown in the top left cell of the grid.
+func (c *Calendar) firstCell() time.Time {
+	first := c.cursor.AddDate(0, 0, 1-c.cursor.Day())
+	offset := (int(first.Weekday()) - int(c.weekStart) + 7) % 7
+	return first.AddDate(0, 0, -offset)
+}
+
+// gridRect returns the screen rectangle of the month grid which is centered
+// horizontally within the inner rectangle.
+func (c *Calendar) gridRect() (x, y, width, height int) {
+	x, y, width, height = c.GetInnerRect()
+	if width > calendarWidth {
+		x += (width - calendarWidth) / 2
+		width = calendarWidth
+	}
+	return
+}
+
+// dayAt returns the day shown at the given screen coordinates and whether
+// there is one. Days of neighbouring months are not shown.
+func (c *Calendar) dayAt(x, y int) (time.Time, bool) {
+	rectX, rectY, _, _ := c.gridRect()
+	column, row := (x-rectX)/3, y-rectY-2
+	if x < rectX || (x-rectX)%3 == 2 || column >= 7 || row < 0 || row >= 6 {
+		return time.Time{}, false
+	}
+	date := c.firstCell().AddDate(0, 0, row*7+column)
+	return date, date.Month() == c.cursor.Month()
+}
+
+// Draw draws this primitive onto the screen.
+func (c *Calendar) Draw(screen tcell.Screen) {
+	c.Box.DrawForSubclass(screen, c)
+
+	x, y, width, height := c.gridRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Draw the header.
+	title := fmt.Sprintf("%s %d", c.cursor.Month(), c.cursor.Year())
+	_, headerBg, _ := c.headerStyle.Decompose()
+	printWithStyle(screen, "◀", x, y, 0, width, AlignLeft, c.headerStyle, headerBg == tcell.ColorDefault)
+	printWithStyle(screen, title, x, y, 0, width, AlignCenter, c.headerStyle, headerBg == tcell.ColorDefault)
+	printWithStyle(screen, "▶", x, y, 0, width, AlignRight, c.headerStyle, headerBg == tcell.ColorDefault)
+	if height < 2 {
+		return
+	}
+
+	// Draw the weekday names.
+	_, weekdayBg, _ := c.weekdayStyle.Decompose()
+	for column := 0; column < 7; column++ {
+		name := (c.weekStart + time.Weekday(column)) % 7
+		printWithStyle(screen, name.String()[:2], x+column*3, y+1, 0, width-column*3, AlignLeft, c.weekdayStyle, weekdayBg == tcell.ColorDefault)
+	}
+
+	// Draw the days.
+	today := calendarDay(time.Now())
+	date := c.firstCell()
+	for row := 0; row < 6 && row+2 < height; row++ {
+		for column := 0; column < 7; column, date = column+1, date.AddDate(0, 0, 1) {
+			if date.Month() != c.cursor.Month() {
+				continue
+			}
+			style := c.dayStyle
+			switch {
+			case !c.InRange(date):
+				style = c.disabledStyle
+			case date.Equal(c.cursor) && c.HasFocus():
+				style = c.cursorStyle
+			case date.Equal(c.selected):
+				style = c.selectedStyle
+			case date.Equal(today):
+				style = c.todayStyle
+			}
+			_, bg, _ := style.Decompose()
+			printWithStyle(screen, fmt.Sprintf("%2d", date.Day()), x+column*3, y+row+2, 0, width-column*3, AlignLeft, style, bg == tcell.ColorDefault)
+		}
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (c *Calendar) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return c.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		switch key := event.Key(); key {
+		case tcell.KeyLeft:
+			c.moveCursor(c.cursor.AddDate(0, 0, -1))
+		case tcell.KeyRight:
+			c.moveCursor(c.cursor.AddDate(0, 0, 1))
+		case tcell.KeyUp:
+			c.moveCursor(c.cursor.AddDate(0, 0, -7))
+		case tcell.KeyDown:
+			c.moveCursor(c.cursor.AddDate(0, 0, 7))
+		case tcell.KeyPgUp:
+			c.moveCursor(addMonths(c.cursor, -1))
+		case tcell.KeyPgDn:
+			c.moveCursor(addMonths(c.cursor, 1))
+		case tcell.KeyHome:
+			c.moveCursor(c.cursor.AddDate(0, 0, 1-c.cursor.Day()))
+		case tcell.KeyEnd:
+			c.moveCursor(addMonths(c.cursor.AddDate(0, 0, 1-c.cursor.Day()), 1).AddDate(0, 0, -1))
+		case tcell.KeyEnter:
+			c.selectDay(c.cursor)
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			if c.done != nil {
+				c.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (c *Calendar) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return c.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !c.InRect(x, y) {
+			return false, nil
+		}
+
+		// Process mouse event.
+		switch action {
+		case MouseLeftDown:
+			setFocus(c)
+			consumed = true
+		case MouseLeftClick:
+			consumed = true
+			rectX, rectY, width, _ := c.gridRect()
+			if y == rectY {
+				if x == rectX {
+					c.moveCursor(addMonths(c.cursor, -1))
+				} else if x == rectX+width-1 {
+					c.moveCursor(addMonths(c.cursor, 1))
+				}
+			} else if date, ok := c.dayAt(x, y); ok {
+				c.selectDay(date)
+			}
+		case MouseScrollUp:
+			c.moveCursor(addMonths(c.cursor, -1))
+			consumed = true
+		case MouseScrollDown:
+			c.moveCursor(addMonths(c.cursor, 1))
+			consumed = true
+		}
+
+		return
+	})
+}
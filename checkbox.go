@@ -4,9 +4,24 @@ import (
 	"github.com/gdamore/tcell/v2"
 )
 
+// CheckState is the state of a checkbox which may also be partially checked.
+type CheckState int
+
+// Checkbox states.
+const (
+	CheckStateUnchecked CheckState = iota
+	CheckStateChecked
+	CheckStatePartial // Indeterminate, e.g. only some of the items it stands for are checked.
+)
+
 // Checkbox implements a simple box for boolean values which can be checked and
 // unchecked.
 //
+// A checkbox may also be partially checked (see [Checkbox.SetCheckState]),
+// for example when it selects all items of a list and only some of them are
+// selected. By default, the user can only switch between checked and
+// unchecked. See [Checkbox.SetCycleOrder] to change this.
+//
 // See https://github.com/rivo/tview/wiki/Checkbox for an example.
 type Checkbox struct {
 	*Box
@@ -14,8 +29,11 @@ type Checkbox struct {
 	// Whether or not this checkbox is disabled/read-only.
 	disabled bool
 
-	// Whether or not this box is checked.
-	checked bool
+	// The state of this box.
+	state CheckState
+
+	// The states the user cycles through.
+	cycleOrder []CheckState
 
 	// The text to be displayed before the input area.
 	label string
@@ -33,6 +51,9 @@ type Checkbox struct {
 	// The style of the checked checkbox.
 	checkedStyle tcell.Style
 
+	// The style of the partially checked checkbox.
+	partialStyle tcell.Style
+
 	// Teh style of the checkbox when it is currently focused.
 	focusStyle tcell.Style
 
@@ -42,10 +63,17 @@ type Checkbox struct {
 	// The string used to display a checked box.
 	checkedString string
 
+	// The string used to display a partially checked box.
+	partialString string
+
 	// An optional function which is called when the user changes the checked
 	// state of this checkbox.
 	changed func(checked bool)
 
+	// An optional function which is called when the user changes the state of
+	// this checkbox.
+	stateChanged func(state CheckState)
+
 	// An optional function which is called when the user indicated that they
 	// are done entering text. The key which was pressed is provided (tab,
 	// shift-tab, or escape).
@@ -63,27 +91,90 @@ func NewCheckbox() *Checkbox {
 		labelStyle:      tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
 		uncheckedStyle:  tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
 		checkedStyle:    tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		partialStyle:    tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
 		focusStyle:      tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.ContrastBackgroundColor),
 		uncheckedString: " ",
 		checkedString:   "X",
+		partialString:   "-",
+		cycleOrder:      []CheckState{CheckStateUnchecked, CheckStateChecked},
 	}
 }
 
 // SetChecked sets the state of the checkbox. This also triggers the "changed"
 // callback if the state changes with this call.
 func (c *Checkbox) SetChecked(checked bool) *Checkbox {
-	if c.checked != checked {
-		if c.changed != nil {
-			c.changed(checked)
-		}
-		c.checked = checked
+	if checked {
+		return c.SetCheckState(CheckStateChecked)
 	}
-	return c
+	return c.SetCheckState(CheckStateUnchecked)
 }
 
-// IsChecked returns whether or not the box is checked.
+// IsChecked returns whether or not the box is checked. A partially checked
+// box is not checked.
 func (c *Checkbox) IsChecked() bool {
-	return c.checked
+	return c.state == CheckStateChecked
+}
+
+// SetCheckState sets the state of the checkbox, which may also be
+// [CheckStatePartial]. This also triggers the "changed" callbacks if the state
+// changes with this call.
+func (c *Checkbox) SetCheckState(state CheckState) *Checkbox {
+	if c.state != state {
+		c.setState(state)
+	}
+	return c
+}
+
+// GetCheckState returns the state of the checkbox.
+func (c *Checkbox) GetCheckState() CheckState {
+	return c.state
+}
+
+// SetCycleOrder sets the states the user cycles through when toggling the
+// checkbox, in this order. The default is [CheckStateUnchecked] followed by
+// [CheckStateChecked]. To let the user choose the partial state, too, include
+// [CheckStatePartial]. If the current state is not part of the order (e.g. a
+// partial state set with [Checkbox.SetCheckState]), toggling the checkbox
+// checks it, or moves it to the first state of the order if checking it is not
+// part of the order either. Calls with fewer than two states are ignored.
+func (c *Checkbox) SetCycleOrder(states ...CheckState) *Checkbox {
+	if len(states) >= 2 {
+		c.cycleOrder = states
+	}
+	return c
+}
+
+// toggle moves the checkbox to the next state of the cycle order on behalf of
+// the user.
+func (c *Checkbox) toggle() {
+	next := -1
+	for index, state := range c.cycleOrder {
+		if state == CheckStateChecked && next < 0 {
+			next = index
+		}
+		if state == c.state {
+			next = index + 1
+			break
+		}
+	}
+	if next < 0 || next >= len(c.cycleOrder) {
+		next = 0
+	}
+	c.setState(c.cycleOrder[next])
+}
+
+// setState sets the state of the checkbox and triggers the "changed"
+// callbacks. The "changed" callback receiving a boolean is only called if the
+// checked state actually changes.
+func (c *Checkbox) setState(state CheckState) {
+	wasChecked := c.state == CheckStateChecked
+	c.state = state
+	if c.changed != nil && wasChecked != (state == CheckStateChecked) {
+		c.changed(state == CheckStateChecked)
+	}
+	if c.stateChanged != nil {
+		c.stateChanged(state)
+	}
 }
 
 // SetLabel sets the text to be displayed before the input area.
@@ -120,6 +211,7 @@ func (c *Checkbox) SetLabelStyle(style tcell.Style) *Checkbox {
 func (c *Checkbox) SetFieldBackgroundColor(color tcell.Color) *Checkbox {
 	c.uncheckedStyle = c.uncheckedStyle.Background(color)
 	c.checkedStyle = c.checkedStyle.Background(color)
+	c.partialStyle = c.partialStyle.Background(color)
 	c.focusStyle = c.focusStyle.Foreground(color)
 	return c
 }
@@ -128,6 +220,7 @@ func (c *Checkbox) SetFieldBackgroundColor(color tcell.Color) *Checkbox {
 func (c *Checkbox) SetFieldTextColor(color tcell.Color) *Checkbox {
 	c.uncheckedStyle = c.uncheckedStyle.Foreground(color)
 	c.checkedStyle = c.checkedStyle.Foreground(color)
+	c.partialStyle = c.partialStyle.Foreground(color)
 	c.focusStyle = c.focusStyle.Background(color)
 	return c
 }
@@ -144,6 +237,12 @@ func (c *Checkbox) SetCheckedStyle(style tcell.Style) *Checkbox {
 	return c
 }
 
+// SetPartialStyle sets the style of the partially checked checkbox.
+func (c *Checkbox) SetPartialStyle(style tcell.Style) *Checkbox {
+	c.partialStyle = style
+	return c
+}
+
 // SetActivatedStyle sets the style of the checkbox when it is currently
 // focused.
 func (c *Checkbox) SetActivatedStyle(style tcell.Style) *Checkbox {
@@ -169,6 +268,14 @@ func (c *Checkbox) SetUncheckedString(unchecked string) *Checkbox {
 	return c
 }
 
+// SetPartialString sets the string to be displayed when the checkbox is
+// partially checked (defaults to "-"). The string may contain color tags, see
+// [Checkbox.SetCheckedString].
+func (c *Checkbox) SetPartialString(partial string) *Checkbox {
+	c.partialString = partial
+	return c
+}
+
 // SetFormAttributes sets attributes shared by all form items.
 func (c *Checkbox) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
 	c.labelWidth = labelWidth
@@ -205,6 +312,14 @@ func (c *Checkbox) SetChangedFunc(handler func(checked bool)) *Checkbox {
 	return c
 }
 
+// SetCheckStateChangedFunc sets a handler which is called when the state of
+// this checkbox was changed, including changes to and from the partial state.
+// The handler function receives the new state.
+func (c *Checkbox) SetCheckStateChangedFunc(handler func(state CheckState)) *Checkbox {
+	c.stateChanged = handler
+	return c
+}
+
 // SetDoneFunc sets a handler which is called when the user is done using the
 // checkbox. The callback function is provided with the key that was pressed,
 // which is one of the following:
@@ -265,9 +380,13 @@ func (c *Checkbox) Draw(screen tcell.Screen) {
 	// Draw checkbox.
 	str := c.uncheckedString
 	style := c.uncheckedStyle
-	if c.checked {
+	switch c.state {
+	case CheckStateChecked:
 		str = c.checkedString
 		style = c.checkedStyle
+	case CheckStatePartial:
+		str = c.partialString
+		style = c.partialStyle
 	}
 	if c.disabled {
 		style = style.Background(c.backgroundColor)
@@ -291,10 +410,7 @@ func (c *Checkbox) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			if key == tcell.KeyRune && event.Rune() != ' ' {
 				break
 			}
-			c.checked = !c.checked
-			if c.changed != nil {
-				c.changed(c.checked)
-			}
+			c.toggle()
 		case tcell.KeyTab, tcell.KeyBacktab, tcell.KeyEscape: // We're done.
 			if c.done != nil {
 				c.done(key)
@@ -325,10 +441,7 @@ func (c *Checkbox) MouseHandler() func(action MouseAction, event *tcell.EventMou
 				setFocus(c)
 				consumed = true
 			} else if action == MouseLeftClick {
-				c.checked = !c.checked
-				if c.changed != nil {
-					c.changed(c.checked)
-				}
+				c.toggle()
 				consumed = true
 			}
 		}
@@ -0,0 +1,447 @@
+package tview
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Palettes from which colors are picked, see [ColorPicker.SetPalette].
+const (
+	ColorPickerPalette16  = iota // The 16 standard terminal colors.
+	ColorPickerPalette256        // All 256 palette colors.
+	ColorPickerTrueColor         // A range of RGB colors.
+)
+
+// The names of the palettes as shown in the tabs.
+var colorPickerTabs = []string{"16", "256", "True color"}
+
+// The number of columns of each palette's grid.
+var colorPickerColumns = []int{8, 16, 16}
+
+// The number of lightness levels of the true color palette. A row of gray
+// colors follows the rows of hues.
+const colorPickerLightness = 8
+
+// ColorPicker lets the user choose a color. It shows tabs to switch between
+// palettes (the 16 standard colors, all 256 palette colors, and a range of
+// true colors), a grid of the current palette's colors, and a field in which
+// a color can be entered as a hexadecimal value (e.g. "#ff8000") or by name
+// (e.g. "orange").
+//
+// The following keys are available:
+//
+//   - Arrow keys, Home, End: Move the cursor within the grid.
+//   - Page up / page down: Switch to the previous / next palette.
+//   - Enter: Select the color under the cursor.
+//   - Tab / Backtab: Switch between the grid and the hex field.
+//
+// In the hex field, Enter selects the entered color if it is valid. Clicking
+// on a tab switches to its palette, clicking on a color selects it.
+type ColorPicker struct {
+	*Box
+
+	// The current palette.
+	palette int
+
+	// The index of the color under the cursor within the current palette.
+	cursor int
+
+	// The current color.
+	color tcell.Color
+
+	// The field for entering colors as text.
+	hex *InputField
+
+	// Whether the hex field has focus.
+	hexFocused bool
+
+	// The style of the tabs and of the current tab.
+	tabStyle, activeTabStyle tcell.Style
+
+	// An optional function which is called when the user selects a color.
+	selected func(color tcell.Color)
+
+	// An optional function which is called when the current color changes.
+	changed func(color tcell.Color)
+
+	// An optional function which is called when the user presses Escape.
+	done func(key tcell.Key)
+}
+
+// NewColorPicker returns a new color picker showing the 16 standard colors.
+func NewColorPicker() *ColorPicker {
+	c := &ColorPicker{
+		Box:            NewBox(),
+		color:          tcell.PaletteColor(0),
+		hex:            NewInputField().SetLabel("Hex ").SetFieldWidth(12),
+		tabStyle:       tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
+		activeTabStyle: tcell.StyleDefault.Background(Styles.SecondaryTextColor).Foreground(Styles.PrimitiveBackgroundColor),
+	}
+	c.updateHex()
+	return c
+}
+
+// colorPickerHex returns the given color as a hexadecimal string.
+func colorPickerHex(color tcell.Color) string {
+	if hex := color.Hex(); hex >= 0 {
+		return fmt.Sprintf("#%06x", hex)
+	}
+	return ""
+}
+
+// SetColor sets the current color. If the color is part of the current
+// palette, the cursor is moved to it. This triggers the "changed" callback if
+// the color changes with this call but not the "selected" callback.
+func (c *ColorPicker) SetColor(color tcell.Color) *ColorPicker {
+	for index := 0; index < c.colorCount(); index++ {
+		if c.colorAt(index).Hex() == color.Hex() {
+			c.cursor = index
+			break
+		}
+	}
+	c.setColor(color)
+	return c
+}
+
+// GetColor returns the current color.
+func (c *ColorPicker) GetColor() tcell.Color {
+	return c.color
+}
+
+// setColor sets the current color and updates the hex field.
+func (c *ColorPicker) setColor(color tcell.Color) {
+	changed := color != c.color
+	c.color = color
+	c.updateHex()
+	if changed && c.changed != nil {
+		c.changed(color)
+	}
+}
+
+// updateHex replaces the text of the hex field with the current color.
+func (c *ColorPicker) updateHex() {
+	c.hex.SetText(colorPickerHex(c.color))
+}
+
+// SetPalette sets the palette from which colors are picked, one of
+// [ColorPickerPalette16], [ColorPickerPalette256], or [ColorPickerTrueColor].
+func (c *ColorPicker) SetPalette(palette int) *ColorPicker {
+	if palette < 0 || palette >= len(colorPickerTabs) {
+		return c
+	}
+	c.palette = palette
+	if c.cursor >= c.colorCount() {
+		c.cursor = c.colorCount() - 1
+	}
+	return c
+}
+
+// GetPalette returns the palette from which colors are picked.
+func (c *ColorPicker) GetPalette() int {
+	return c.palette
+}
+
+// SetTabStyles sets the styles of the palette tabs and of the current tab.
+func (c *ColorPicker) SetTabStyles(tab, activeTab tcell.Style) *ColorPicker {
+	c.tabStyle, c.activeTabStyle = tab, activeTab
+	return c
+}
+
+// GetHexField returns the field in which colors are entered as text, e.g. to
+// change its styles.
+func (c *ColorPicker) GetHexField() *InputField {
+	return c.hex
+}
+
+// SetSelectedFunc sets a handler which is called when the user selects a
+// color, either from the grid or in the hex field.
+func (c *ColorPicker) SetSelectedFunc(handler func(color tcell.Color)) *ColorPicker {
+	c.selected = handler
+	return c
+}
+
+// SetChangedFunc sets a handler which is called when the current color
+// changes, e.g. when the cursor is moved.
+func (c *ColorPicker) SetChangedFunc(handler func(color tcell.Color)) *ColorPicker {
+	c.changed = handler
+	return c
+}
+
+// SetDoneFunc sets a handler which is called when the user presses the Escape
+// key.
+func (c *ColorPicker) SetDoneFunc(handler func(key tcell.Key)) *ColorPicker {
+	c.done = handler
+	return c
+}
+
+// colorCount returns the number of colors of the current palette.
+func (c *ColorPicker) colorCount() int {
+	switch c.palette {
+	case ColorPickerPalette16:
+		return 16
+	case ColorPickerPalette256:
+		return 256
+	}
+	return (colorPickerLightness + 1) * colorPickerColumns[ColorPickerTrueColor]
+}
+
+// colorAt returns the color with the given index in the current palette.
+func (c *ColorPicker) colorAt(index int) tcell.Color {
+	if c.palette != ColorPickerTrueColor {
+		return tcell.PaletteColor(index)
+	}
+	columns := colorPickerColumns[ColorPickerTrueColor]
+	row, column := index/columns, index%columns
+	if row == colorPickerLightness {
+		gray := int32(math.Round(float64(column) / float64(columns-1) * 255))
+		return tcell.NewRGBColor(gray, gray, gray)
+	}
+	return hslColor(float64(column)/float64(columns)*360, 1, 0.85-float64(row)*0.7/float64(colorPickerLightness-1))
+}
+
+// hslColor converts the given hue (0-360), saturation (0-1) and lightness (0-1)
+// into an RGB color.
+func hslColor(hue, saturation, lightness float64) tcell.Color {
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case hue < 60:
+		r, g = chroma, x
+	case hue < 120:
+		r, g = x, chroma
+	case hue < 180:
+		g, b = chroma, x
+	case hue < 240:
+		g, b = x, chroma
+	case hue < 300:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+	m := lightness - chroma/2
+	return tcell.NewRGBColor(int32(math.Round((r+m)*255)), int32(math.Round((g+m)*255)), int32(math.Round((b+m)*255)))
+}
+
+// moveCursor moves the cursor to the color with the given index, if it
+// exists, making it the current color.
+func (c *ColorPicker) moveCursor(index int) {
+	if index < 0 || index >= c.colorCount() {
+		return
+	}
+	c.cursor = index
+	c.setColor(c.colorAt(index))
+}
+
+// selectColor makes the given color the current color and triggers the
+// "selected" callback.
+func (c *ColorPicker) selectColor(color tcell.Color) {
+	c.setColor(color)
+	if c.selected != nil {
+		c.selected(color)
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (c *ColorPicker) Focus(delegate func(p Primitive)) {
+	if c.hexFocused {
+		delegate(c.hex)
+		return
+	}
+	c.Box.Focus(delegate)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (c *ColorPicker) HasFocus() bool {
+	return c.hex.HasFocus() || c.Box.HasFocus()
+}
+
+// tabX returns the screen x-coordinates of the tabs' left edges, followed by
+// the right edge of the last tab.
+func (c *ColorPicker) tabX() []int {
+	x, _, _, _ := c.GetInnerRect()
+	positions := make([]int, 0, len(colorPickerTabs)+1)
+	for _, tab := range colorPickerTabs {
+		positions = append(positions, x)
+		x += len(tab) + 3 // Padding and separator.
+	}
+	return append(positions, x)
+}
+
+// Draw draws this primitive onto the screen.
+func (c *ColorPicker) Draw(screen tcell.Screen) {
+	c.Box.DrawForSubclass(screen, c)
+
+	x, y, width, height := c.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Draw the tabs.
+	for index, tabX := range c.tabX()[:len(colorPickerTabs)] {
+		style := c.tabStyle
+		if index == c.palette {
+			style = c.activeTabStyle
+		}
+		_, bg, _ := style.Decompose()
+		if tabX < x+width {
+			printWithStyle(screen, " "+colorPickerTabs[index]+" ", tabX, y, 0, x+width-tabX, AlignLeft, style, bg == tcell.ColorDefault)
+		}
+	}
+
+	// Draw the grid.
+	columns := colorPickerColumns[c.palette]
+	rows := c.colorCount() / columns
+	for index := 0; index < c.colorCount(); index++ {
+		row, column := index/columns, index%columns
+		cellX, cellY := x+column*2, y+1+row
+		if cellX+1 >= x+width || cellY >= y+height-1 {
+			continue
+		}
+		color := c.colorAt(index)
+		text, style := "  ", tcell.StyleDefault.Background(color)
+		if index == c.cursor && c.color.Hex() == color.Hex() {
+			text = "[]"
+			if r, g, b := color.RGB(); r*299+g*587+b*114 > 128000 {
+				style = style.Foreground(tcell.ColorBlack)
+			} else {
+				style = style.Foreground(tcell.ColorWhite)
+			}
+		}
+		printWithStyle(screen, text, cellX, cellY, 0, 2, AlignLeft, style, false)
+	}
+
+	// Draw the current color and the hex field.
+	fieldY := y + 1 + rows
+	if fieldY >= y+height {
+		fieldY = y + height - 1
+	}
+	screen.SetContent(x, fieldY, ' ', nil, tcell.StyleDefault.Background(c.color))
+	screen.SetContent(x+1, fieldY, ' ', nil, tcell.StyleDefault.Background(c.color))
+	if width > 3 {
+		c.hex.SetRect(x+3, fieldY, width-3, 1)
+		c.hex.Draw(screen)
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (c *ColorPicker) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return c.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		// Forward keys to the hex field while it has focus.
+		if c.hex.HasFocus() {
+			c.hex.SetDoneFunc(func(key tcell.Key) {
+				switch key {
+				case tcell.KeyEnter:
+					text := strings.TrimSpace(c.hex.GetText())
+					color := tcell.GetColor(text)
+					if color == tcell.ColorDefault && len(text) == 6 {
+						color = tcell.GetColor("#" + text) // Hex value without "#".
+					}
+					if color != tcell.ColorDefault {
+						c.selectColor(color)
+					}
+				case tcell.KeyTab, tcell.KeyBacktab:
+					c.hexFocused = false
+					c.updateHex()
+					setFocus(c)
+				case tcell.KeyEscape:
+					c.updateHex()
+					if c.done != nil {
+						c.done(key)
+					}
+				}
+			})
+			c.hex.InputHandler()(event, setFocus)
+			return
+		}
+
+		// Process key event.
+		columns := colorPickerColumns[c.palette]
+		switch key := event.Key(); key {
+		case tcell.KeyLeft:
+			if c.cursor%columns > 0 {
+				c.moveCursor(c.cursor - 1)
+			}
+		case tcell.KeyRight:
+			if c.cursor%columns < columns-1 {
+				c.moveCursor(c.cursor + 1)
+			}
+		case tcell.KeyUp:
+			c.moveCursor(c.cursor - columns)
+		case tcell.KeyDown:
+			c.moveCursor(c.cursor + columns)
+		case tcell.KeyHome:
+			c.moveCursor(0)
+		case tcell.KeyEnd:
+			c.moveCursor(c.colorCount() - 1)
+		case tcell.KeyPgUp, tcell.KeyPgDn:
+			palette := c.palette + 1
+			if key == tcell.KeyPgUp {
+				palette = c.palette + len(colorPickerTabs) - 1
+			}
+			c.SetPalette(palette % len(colorPickerTabs))
+			c.moveCursor(c.cursor)
+		case tcell.KeyEnter:
+			c.moveCursor(c.cursor)
+			c.selectColor(c.color)
+		case tcell.KeyTab, tcell.KeyBacktab:
+			c.hexFocused = true
+			setFocus(c.hex)
+		case tcell.KeyEscape:
+			if c.done != nil {
+				c.done(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (c *ColorPicker) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return c.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !c.InRect(x, y) {
+			return false, nil
+		}
+
+		// Pass events on to the hex field.
+		if c.hex.InRect(x, y) {
+			if action == MouseLeftDown {
+				c.hexFocused = true
+			}
+			return c.hex.MouseHandler()(action, event, setFocus)
+		}
+
+		// Process mouse event.
+		rectX, rectY, _, _ := c.GetInnerRect()
+		switch action {
+		case MouseLeftDown:
+			c.hexFocused = false
+			setFocus(c)
+			consumed = true
+		case MouseLeftClick:
+			consumed = true
+			if y == rectY {
+				tabs := c.tabX()
+				for index := range colorPickerTabs {
+					if x >= tabs[index] && x < tabs[index+1]-1 {
+						c.SetPalette(index)
+						c.moveCursor(c.cursor)
+						break
+					}
+				}
+				break
+			}
+			columns := colorPickerColumns[c.palette]
+			row, column := y-rectY-1, (x-rectX)/2
+			if column < columns && row < c.colorCount()/columns {
+				c.moveCursor(row*columns + column)
+				c.selectColor(c.color)
+			}
+		}
+
+		return
+	})
+}
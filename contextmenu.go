@@ -0,0 +1,389 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// ContextMenu is a wrapper which adds a popup menu to another primitive. The
+// menu is opened when the user right-clicks on the primitive or presses a key
+// (Shift+F10 by default, see [ContextMenu.SetOpenKey]) while it has focus. It
+// shows [MenuItem] objects which may contain submenus, separators, disabled
+// and checkable items, just like the menus of a [MenuBar].
+//
+// A right-click opens the menu at the mouse position. When opened with a key,
+// the menu is placed at the position returned by the function set with
+// [ContextMenu.SetAnchorFunc], e.g. next to the cursor or the current
+// selection, or at the top-left corner of the primitive if there is no such
+// function. The function set with [ContextMenu.SetOpenFunc] may adjust the
+// menu's items before it opens, e.g. depending on what was clicked.
+//
+// While the menu is open, the following keys are available:
+//
+//   - Up arrow / down arrow, Home, End: Move the highlight.
+//   - Enter, Space: Select the highlighted item.
+//   - Right arrow: Open the highlighted item's submenu.
+//   - Left arrow: Close a submenu.
+//   - Letter: Select the item with this shortcut (see [MenuItem.SetShortcut]).
+//   - Escape: Close the menu.
+//
+// The menu is drawn on top of other primitives as long as the wrapped
+// primitive has focus, see [MenuBar] for details.
+type ContextMenu struct {
+	*Box
+
+	// The wrapped primitive.
+	primitive Primitive
+
+	// The item whose items make up the menu.
+	menu *MenuItem
+
+	// The open menus, from the context menu to the innermost submenu.
+	popups []*menuPopup
+
+	// The size of the screen as of the last call to Draw().
+	screenWidth, screenHeight int
+
+	// The key (and its modifiers) which opens the menu.
+	openKey       tcell.Key
+	openModifiers tcell.ModMask
+
+	// An optional function which returns the screen position at which the
+	// menu opens when the user presses the open key.
+	anchor func() (x, y int)
+
+	// An optional function which is called before the menu opens.
+	open func(x, y int) bool
+
+	// The style of the menus, of highlighted items, and of disabled items.
+	style, selectedStyle, disabledStyle tcell.Style
+
+	// An optional function which is called when the user selects an item.
+	selected func(item *MenuItem)
+
+	// An optional function which is called when the menu is closed without a
+	// selection.
+	canceled func()
+}
+
+// NewContextMenu returns a new context menu without items for the given
+// primitive. The primitive's size is changed to that of the context menu.
+func NewContextMenu(primitive Primitive) *ContextMenu {
+	return &ContextMenu{
+		Box:           NewBox(),
+		primitive:     primitive,
+		menu:          NewMenuItem(""),
+		openKey:       tcell.KeyF10,
+		openModifiers: tcell.ModShift,
+		style:         tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		selectedStyle: tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.ContrastBackgroundColor),
+		disabledStyle: tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.ContrastSecondaryTextColor),
+	}
+}
+
+// GetPrimitive returns the wrapped primitive.
+func (c *ContextMenu) GetPrimitive() Primitive {
+	return c.primitive
+}
+
+// AddItem adds an item to the menu.
+func (c *ContextMenu) AddItem(item *MenuItem) *ContextMenu {
+	c.menu.AddItem(item)
+	return c
+}
+
+// GetItems returns the items of the menu.
+func (c *ContextMenu) GetItems() []*MenuItem {
+	return c.menu.GetItems()
+}
+
+// ClearItems removes all items from the menu. An open menu is closed.
+func (c *ContextMenu) ClearItems() *ContextMenu {
+	c.menu.ClearItems()
+	c.popups = nil
+	return c
+}
+
+// SetOpenKey sets the key which opens the menu when pressed together with the
+// given modifiers while the wrapped primitive has focus. Set the key to
+// [tcell.KeyNUL] to open the menu only with the mouse.
+func (c *ContextMenu) SetOpenKey(key tcell.Key, modifiers tcell.ModMask) *ContextMenu {
+	c.openKey, c.openModifiers = key, modifiers
+	return c
+}
+
+// SetAnchorFunc sets a function which returns the screen position at which
+// the menu opens when the user presses the open key, e.g. the position of the
+// cursor or of the current selection. The menu's top-left corner is placed
+// below the returned position.
+func (c *ContextMenu) SetAnchorFunc(handler func() (x, y int)) *ContextMenu {
+	c.anchor = handler
+	return c
+}
+
+// SetOpenFunc sets a function which is called before the menu opens at the
+// given screen position. It may change the menu's items. If it returns false,
+// the menu is not opened.
+func (c *ContextMenu) SetOpenFunc(handler func(x, y int) bool) *ContextMenu {
+	c.open = handler
+	return c
+}
+
+// SetStyles sets the style of the menus, of highlighted items, and of disabled
+// items.
+func (c *ContextMenu) SetStyles(style, selected, disabled tcell.Style) *ContextMenu {
+	c.style, c.selectedStyle, c.disabledStyle = style, selected, disabled
+	return c
+}
+
+// SetSelectedFunc sets a handler which is called with the chosen item when the
+// user selects an item which doesn't have a submenu, after the item's own
+// "selected" callback.
+func (c *ContextMenu) SetSelectedFunc(handler func(item *MenuItem)) *ContextMenu {
+	c.selected = handler
+	return c
+}
+
+// SetCanceledFunc sets a handler which is called when the menu is closed
+// without a selection, e.g. when the user presses Escape or clicks outside of
+// the menu.
+func (c *ContextMenu) SetCanceledFunc(handler func()) *ContextMenu {
+	c.canceled = handler
+	return c
+}
+
+// Open opens the menu with its top-left corner at the given screen position.
+// Nothing happens if the menu has no items or if the function set with
+// [ContextMenu.SetOpenFunc] returns false.
+func (c *ContextMenu) Open(x, y int) *ContextMenu {
+	if c.open != nil && !c.open(x, y) {
+		return c
+	}
+	if len(c.menu.items) == 0 {
+		return c
+	}
+	popup := newMenuPopup(c.menu)
+	popup.x, popup.y = x, y
+	c.popups = []*menuPopup{popup}
+	placeMenuPopups(c.popups, c.screenWidth, c.screenHeight)
+	return c
+}
+
+// Close closes the menu without a selection.
+func (c *ContextMenu) Close() *ContextMenu {
+	if len(c.popups) > 0 {
+		c.popups = nil
+		if c.canceled != nil {
+			c.canceled()
+		}
+	}
+	return c
+}
+
+// IsOpen returns whether the menu is open.
+func (c *ContextMenu) IsOpen() bool {
+	return len(c.popups) > 0
+}
+
+// openAtAnchor opens the menu at the position returned by the anchor
+// function, or at the top-left corner of the wrapped primitive.
+func (c *ContextMenu) openAtAnchor() {
+	var x, y int
+	if c.anchor != nil {
+		x, y = c.anchor()
+		y++
+	} else if box, ok := c.primitive.(interface {
+		GetInnerRect() (int, int, int, int)
+	}); ok {
+		x, y, _, _ = box.GetInnerRect()
+	} else {
+		x, y, _, _ = c.GetRect()
+	}
+	c.Open(x, y)
+}
+
+// activate selects the given item. If it has a submenu, the submenu is opened.
+// Otherwise, the menu is closed and the item's callbacks are invoked.
+func (c *ContextMenu) activate(item *MenuItem) {
+	if item == nil || !item.selectable() {
+		return
+	}
+	if len(item.items) > 0 {
+		if c.popups[len(c.popups)-1].item != item {
+			c.popups = append(c.popups, newMenuPopup(item))
+			placeMenuPopups(c.popups, c.screenWidth, c.screenHeight)
+		}
+		return
+	}
+	if item.checkable {
+		item.checked = !item.checked
+	}
+	c.popups = nil
+	if item.selected != nil {
+		item.selected()
+	}
+	if c.selected != nil {
+		c.selected(item)
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (c *ContextMenu) Draw(screen tcell.Screen) {
+	c.Box.DrawForSubclass(screen, c)
+
+	if c.primitive != nil {
+		x, y, width, height := c.GetInnerRect()
+		c.primitive.SetRect(x, y, width, height)
+		c.primitive.Draw(screen)
+	}
+
+	// Draw the open menus.
+	if !c.HasFocus() {
+		c.popups = nil // Focus moved elsewhere.
+	}
+	c.screenWidth, c.screenHeight = screen.Size()
+	placeMenuPopups(c.popups, c.screenWidth, c.screenHeight)
+	for _, popup := range c.popups {
+		popup.draw(screen, c.style, c.selectedStyle, c.disabledStyle)
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (c *ContextMenu) Focus(delegate func(p Primitive)) {
+	if c.primitive != nil {
+		delegate(c.primitive)
+	} else {
+		c.Box.Focus(delegate)
+	}
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (c *ContextMenu) HasFocus() bool {
+	if c.primitive == nil {
+		return c.Box.HasFocus()
+	}
+	return c.primitive.HasFocus()
+}
+
+// InputHandler returns the handler for this primitive.
+func (c *ContextMenu) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return c.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		// Pass events on to the wrapped primitive while the menu is closed.
+		if len(c.popups) == 0 {
+			if c.openKey != tcell.KeyNUL && event.Key() == c.openKey && event.Modifiers() == c.openModifiers {
+				c.openAtAnchor()
+				return
+			}
+			if c.primitive != nil {
+				if handler := c.primitive.InputHandler(); handler != nil {
+					handler(event, setFocus)
+				}
+			}
+			return
+		}
+
+		// Keys for the innermost open menu.
+		popup := c.popups[len(c.popups)-1]
+		switch key := event.Key(); key {
+		case tcell.KeyUp:
+			popup.move(-1)
+		case tcell.KeyDown:
+			popup.move(1)
+		case tcell.KeyHome:
+			popup.current = -1
+			popup.move(1)
+		case tcell.KeyEnd:
+			popup.current = len(popup.item.items)
+			popup.move(-1)
+		case tcell.KeyLeft:
+			if len(c.popups) > 1 {
+				c.popups = c.popups[:len(c.popups)-1]
+			}
+		case tcell.KeyRight:
+			if item := popup.highlighted(); item != nil && len(item.items) > 0 {
+				c.activate(item)
+			}
+		case tcell.KeyEnter:
+			c.activate(popup.highlighted())
+		case tcell.KeyRune:
+			if event.Rune() == ' ' {
+				c.activate(popup.highlighted())
+				break
+			}
+			for index, item := range popup.item.items {
+				if item.selectable() && item.matchShortcut(event.Rune()) {
+					popup.current = index
+					c.activate(item)
+					break
+				}
+			}
+		case tcell.KeyEscape:
+			c.Close()
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (c *ContextMenu) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return c.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+
+		// Pass events on to the wrapped primitive while the menu is closed.
+		if len(c.popups) == 0 {
+			if !c.InRect(x, y) {
+				return false, nil
+			}
+			if action == MouseRightClick {
+				c.Focus(setFocus)
+				c.Open(x, y)
+				return true, nil
+			}
+			if c.primitive != nil {
+				return c.primitive.MouseHandler()(action, event, setFocus)
+			}
+			return false, nil
+		}
+
+		// As long as the menu is open, we capture all mouse events.
+		capture = c
+		consumed = true
+
+		// Find the open menu and the item under the mouse.
+		level, index := -1, -1
+		for popupIndex := len(c.popups) - 1; popupIndex >= 0; popupIndex-- {
+			if c.popups[popupIndex].contains(x, y) {
+				level, index = popupIndex, c.popups[popupIndex].indexAt(x, y)
+				break
+			}
+		}
+
+		switch action {
+		case MouseLeftDown, MouseRightDown:
+			if level < 0 {
+				c.Close() // Clicking outside the menus closes them.
+				capture = nil
+			}
+		case MouseLeftClick, MouseRightClick:
+			if level >= 0 && index >= 0 {
+				popup := c.popups[level]
+				if item := popup.item.items[index]; item.selectable() {
+					c.popups = c.popups[:level+1]
+					popup.current = index
+					c.activate(item)
+				}
+			}
+		case MouseMove:
+			if level >= 0 && index >= 0 {
+				popup := c.popups[level]
+				if item := popup.item.items[index]; item.selectable() {
+					c.popups = c.popups[:level+1]
+					popup.current = index
+					if len(item.items) > 0 {
+						c.activate(item) // Open submenus when hovering over their item.
+					}
+				}
+			}
+		}
+
+		return
+	})
+}
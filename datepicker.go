@@ -0,0 +1,421 @@
+package tview
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// DatePicker is a form item for entering a date. The date can be typed into an
+// input field, in the format set with [DatePicker.SetFormat], or chosen from a
+// [Calendar] which pops up below the field when the user presses the down
+// arrow key or clicks on the field. In the calendar, Enter selects a date and
+// Escape returns to the input field without changing the date.
+//
+// Text which is not a valid date (or outside the range set with
+// [DatePicker.SetDateRange]) does not change the date. When the user leaves
+// the input field, such text is replaced with the current date.
+type DatePicker struct {
+	*Box
+
+	// Whether or not this date picker is disabled/read-only.
+	disabled bool
+
+	// The input field for entering the date as text.
+	field *InputField
+
+	// The calendar which pops up below the input field.
+	calendar *Calendar
+
+	// Whether the calendar is currently shown.
+	open bool
+
+	// The layout (as used by [time.Time.Format]) of dates in the input field.
+	format string
+
+	// The current date or the zero time if there is none.
+	date time.Time
+
+	// The screen width of the input field. A value of 0 means the width of a
+	// date in the current format.
+	fieldWidth int
+
+	// An optional function which is called when the date was changed.
+	changed func(date time.Time)
+
+	// An optional function which is called when the user indicated that they
+	// are done entering the date. The key which was pressed is provided (tab,
+	// shift-tab, enter, or escape).
+	done func(tcell.Key)
+
+	// A callback function set by the Form class and called when the user leaves
+	// this form item.
+	finished func(tcell.Key)
+}
+
+// NewDatePicker returns a new date picker without a date. Dates are formatted
+// as "2006-01-02".
+func NewDatePicker() *DatePicker {
+	calendar := NewCalendar()
+	calendar.SetHeaderStyle(tcell.StyleDefault.Foreground(Styles.PrimitiveBackgroundColor).Bold(true)).
+		SetWeekdayStyle(tcell.StyleDefault.Foreground(Styles.InverseTextColor)).
+		SetDayStyles(
+			tcell.StyleDefault.Foreground(Styles.PrimitiveBackgroundColor),
+			tcell.StyleDefault.Foreground(Styles.PrimitiveBackgroundColor).Underline(true),
+			tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
+			tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.PrimitiveBackgroundColor),
+			tcell.StyleDefault.Foreground(Styles.PrimitiveBackgroundColor).Dim(true),
+		).
+		SetBorderPadding(0, 0, 1, 1).
+		SetBackgroundColor(Styles.MoreContrastBackgroundColor)
+
+	p := &DatePicker{
+		Box:      NewBox(),
+		field:    NewInputField(),
+		calendar: calendar,
+		format:   "2006-01-02",
+	}
+	p.field.SetChangedFunc(p.parse)
+	p.field.SetDoneFunc(func(key tcell.Key) {
+		p.field.SetText(p.dateText())
+		p.finish(key)
+	})
+	return p
+}
+
+// SetDate sets the date. A zero time removes the date. This also triggers the
+// "changed" callback if the date changes with this call.
+func (p *DatePicker) SetDate(date time.Time) *DatePicker {
+	p.setDate(date)
+	p.field.SetText(p.dateText())
+	return p
+}
+
+// GetDate returns the date (at midnight) or the zero time if there is none.
+func (p *DatePicker) GetDate() time.Time {
+	return p.date
+}
+
+// GetText returns the text of the input field.
+func (p *DatePicker) GetText() string {
+	return p.field.GetText()
+}
+
+// setDate sets the date and triggers the "changed" callback if it changed.
+func (p *DatePicker) setDate(date time.Time) {
+	if !date.IsZero() {
+		date = calendarDay(date)
+	}
+	if date.Equal(p.date) {
+		return
+	}
+	p.date = date
+	if p.changed != nil {
+		p.changed(date)
+	}
+}
+
+// dateText returns the current date as text in the current format.
+func (p *DatePicker) dateText() string {
+	if p.date.IsZero() {
+		return ""
+	}
+	return p.date.Format(p.format)
+}
+
+// parse sets the date from the given text if it is a valid date.
+func (p *DatePicker) parse(text string) {
+	if text == p.dateText() {
+		return // Also covers formats which don't contain all date components.
+	}
+	if text == "" {
+		p.setDate(time.Time{})
+		return
+	}
+	date, err := time.ParseInLocation(p.format, text, time.Local)
+	if err != nil || !p.calendar.InRange(date) {
+		return
+	}
+	p.setDate(date)
+}
+
+// SetFormat sets the layout (as used by [time.Time.Format]) of dates in the
+// input field. The default is "2006-01-02".
+func (p *DatePicker) SetFormat(layout string) *DatePicker {
+	p.format = layout
+	p.field.SetText(p.dateText())
+	return p
+}
+
+// SetDateRange restricts the dates which can be entered to the range from min
+// to max, inclusively. A zero time for either value removes that side's
+// restriction. The current date is not changed.
+func (p *DatePicker) SetDateRange(min, max time.Time) *DatePicker {
+	p.calendar.SetDateRange(min, max)
+	return p
+}
+
+// SetWeekStart sets the first day of the week in the calendar. The default is
+// Monday.
+func (p *DatePicker) SetWeekStart(day time.Weekday) *DatePicker {
+	p.calendar.SetWeekStart(day)
+	return p
+}
+
+// GetCalendar returns the calendar which pops up below the input field, e.g.
+// to change its styles.
+func (p *DatePicker) GetCalendar() *Calendar {
+	return p.calendar
+}
+
+// SetLabel sets the text to be displayed before the input field.
+func (p *DatePicker) SetLabel(label string) *DatePicker {
+	p.field.SetLabel(label)
+	return p
+}
+
+// GetLabel returns the text to be displayed before the input field.
+func (p *DatePicker) GetLabel() string {
+	return p.field.GetLabel()
+}
+
+// SetLabelWidth sets the screen width of the label. A value of 0 will cause the
+// primitive to use the width of the label string.
+func (p *DatePicker) SetLabelWidth(width int) *DatePicker {
+	p.field.SetLabelWidth(width)
+	return p
+}
+
+// SetPlaceholder sets the text to be displayed when the input field is empty.
+func (p *DatePicker) SetPlaceholder(text string) *DatePicker {
+	p.field.SetPlaceholder(text)
+	return p
+}
+
+// SetFieldWidth sets the screen width of the input field. A value of 0 (the
+// default) uses the width of a date in the current format.
+func (p *DatePicker) SetFieldWidth(width int) *DatePicker {
+	p.fieldWidth = width
+	return p
+}
+
+// SetChangedFunc sets a handler which is called when the date was changed,
+// either by typing a valid date or by selecting one in the calendar. The
+// handler receives the new date at midnight or the zero time if the input
+// field was cleared.
+func (p *DatePicker) SetChangedFunc(handler func(date time.Time)) *DatePicker {
+	p.changed = handler
+	return p
+}
+
+// SetDoneFunc sets a handler which is called when the user is done entering
+// the date. The callback function is provided with the key that was pressed,
+// which is one of the following:
+//
+//   - KeyEnter: Done entering the date.
+//   - KeyEscape: Abort.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (p *DatePicker) SetDoneFunc(handler func(key tcell.Key)) *DatePicker {
+	p.done = handler
+	return p
+}
+
+// SetFinishedFunc sets a callback invoked when the user leaves this form item.
+func (p *DatePicker) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
+	p.finished = handler
+	return p
+}
+
+// finish calls the "done" and "finished" callbacks.
+func (p *DatePicker) finish(key tcell.Key) {
+	if p.done != nil {
+		p.done(key)
+	}
+	if p.finished != nil {
+		p.finished(key)
+	}
+}
+
+// SetFormAttributes sets attributes shared by all form items.
+func (p *DatePicker) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
+	p.field.SetFormAttributes(labelWidth, labelColor, bgColor, fieldTextColor, fieldBgColor)
+	p.backgroundColor = bgColor
+	return p
+}
+
+// GetFieldWidth returns this primitive's field width.
+func (p *DatePicker) GetFieldWidth() int {
+	if p.fieldWidth > 0 {
+		return p.fieldWidth
+	}
+	return TaggedStringWidth(Escape(time.Date(2006, 12, 31, 23, 59, 59, 0, time.Local).Format(p.format))) + 1
+}
+
+// GetFieldHeight returns this primitive's field height.
+func (p *DatePicker) GetFieldHeight() int {
+	return 1
+}
+
+// SetDisabled sets whether or not the item is disabled / read-only.
+func (p *DatePicker) SetDisabled(disabled bool) FormItem {
+	p.disabled = disabled
+	p.field.SetDisabled(disabled)
+	if p.finished != nil {
+		p.finished(-1)
+	}
+	return p
+}
+
+// Focus is called when this primitive receives focus.
+func (p *DatePicker) Focus(delegate func(p Primitive)) {
+	// If we're part of a form and this item is disabled, there's nothing the
+	// user can do here so we're finished.
+	if p.finished != nil && p.disabled {
+		p.finished(-1)
+		return
+	}
+
+	if p.open {
+		delegate(p.calendar)
+	} else {
+		delegate(p.field)
+	}
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (p *DatePicker) HasFocus() bool {
+	if p.open {
+		return p.calendar.HasFocus()
+	}
+	return p.field.HasFocus()
+}
+
+// IsOpen returns true if the calendar is currently shown.
+func (p *DatePicker) IsOpen() bool {
+	return p.open
+}
+
+// openCalendar shows the calendar and gives it focus.
+func (p *DatePicker) openCalendar(setFocus func(Primitive)) {
+	p.open = true
+	if p.date.IsZero() {
+		p.calendar.SetSelectedDate(time.Time{}).SetCursorDate(p.calendar.clamp(calendarDay(time.Now())))
+	} else {
+		p.calendar.SetSelectedDate(p.date)
+	}
+	p.calendar.SetSelectedFunc(func(date time.Time) {
+		p.SetDate(date)
+		p.closeCalendar(setFocus)
+	}).SetDoneFunc(func(key tcell.Key) {
+		p.closeCalendar(setFocus)
+		if key != tcell.KeyEscape {
+			p.finish(key)
+		}
+	})
+	setFocus(p.calendar)
+}
+
+// closeCalendar hides the calendar and returns focus to the input field.
+func (p *DatePicker) closeCalendar(setFocus func(Primitive)) {
+	p.open = false
+	if p.calendar.HasFocus() {
+		setFocus(p)
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (p *DatePicker) Draw(screen tcell.Screen) {
+	p.Box.DrawForSubclass(screen, p)
+
+	x, y, width, height := p.GetInnerRect()
+	if height < 1 || width < 1 {
+		return
+	}
+
+	// Draw the input field.
+	p.field.SetFieldWidth(p.GetFieldWidth())
+	p.field.SetRect(x, y, width, 1)
+	p.field.Draw(screen)
+
+	// Draw the calendar.
+	if !p.open || !p.HasFocus() {
+		return
+	}
+	labelWidth := p.field.textArea.GetLabelWidth()
+	if labelWidth == 0 {
+		labelWidth = TaggedStringWidth(p.field.GetLabel())
+	}
+	cx, cy := x+labelWidth, y+1
+	cwidth, cheight := calendarWidth+2, calendarHeight
+	swidth, sheight := screen.Size()
+	// We prefer to align the left sides of the calendar and the field, but if
+	// there is no space to the right, then shift the calendar to the left.
+	if cx+cwidth >= swidth {
+		cx = swidth - cwidth
+		if cx < 0 {
+			cx = 0
+		}
+	}
+	// We prefer to drop down but if there is no space, maybe drop up?
+	if cy+cheight >= sheight && y-cheight >= 0 {
+		cy = y - cheight
+	}
+	p.calendar.SetRect(cx, cy, cwidth, cheight)
+	p.calendar.Draw(screen)
+}
+
+// InputHandler returns the handler for this primitive.
+func (p *DatePicker) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return p.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if p.disabled {
+			return
+		}
+
+		// The calendar handles all keys while it is shown.
+		if p.open {
+			p.calendar.InputHandler()(event, setFocus)
+			return
+		}
+
+		// Process key event.
+		if event.Key() == tcell.KeyDown {
+			p.openCalendar(setFocus)
+			return
+		}
+		p.field.InputHandler()(event, setFocus)
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (p *DatePicker) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return p.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if p.disabled {
+			return false, nil
+		}
+
+		// As long as the calendar is shown, we capture all mouse events. Clicks
+		// outside of it close it again.
+		x, y := event.Position()
+		if p.open {
+			if p.calendar.InRect(x, y) {
+				p.calendar.MouseHandler()(action, event, setFocus)
+			} else if action == MouseLeftDown {
+				p.closeCalendar(setFocus)
+				return true, nil
+			}
+			return true, p
+		}
+
+		// Was the mouse event in the input field?
+		rectX, rectY, rectWidth, _ := p.GetInnerRect()
+		if y != rectY || x < rectX || x >= rectX+rectWidth {
+			return p.InRect(x, y), nil
+		}
+		if action == MouseLeftClick {
+			p.openCalendar(setFocus)
+			return true, nil
+		}
+		return p.field.MouseHandler()(action, event, setFocus)
+	})
+}
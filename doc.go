@@ -18,10 +18,32 @@ The package implements the following widgets:
   - [InputField]: One-line input fields to enter text.
   - [DropDown]: Drop-down selection fields.
   - [Checkbox]: Selectable checkbox for boolean values.
+  - [RadioButtons]: A group of mutually exclusive options.
+  - [Slider]: A track with a thumb for selecting a numeric value.
+  - [Spinner]: An animated activity indicator.
+  - [ProgressBar]: A bar showing the progress of an operation.
+  - [Gauge]: A meter for a value within a fixed range.
+  - [Sparkline]: A compact chart of a rolling series of values.
+  - [BarChart]: Labeled values shown as vertical or horizontal bars.
+  - [Plot]: Line and scatter plots of one or more data series.
+  - [Calendar]: A month grid for selecting a day.
+  - [DatePicker]: A date input field with a pop-up calendar.
+  - [TimePicker]: A segmented input for times of day and durations.
+  - [ColorPicker]: A palette grid and hex field for choosing colors.
+  - [MenuBar]: A bar of pull-down menus.
+  - [ContextMenu]: A popup menu for another primitive.
+  - [StatusBar]: A line of aligned text segments and flash messages.
+  - [Tabs]: A container switching between primitives with a tab strip.
+  - [Accordion]: A container of collapsible sections.
+  - [FileBrowser]: Lets the user navigate directories and choose a file.
+  - [FileDialog]: Dialogs to choose a file to open or a path to save to.
+  - [HexView]: Displays and edits binary data as a hex dump.
+  - [Terminal]: Runs a command on a pseudo terminal and shows its output (not
+    on Windows and some BSDs).
   - [Image]: Displays images.
   - [Button]: Buttons which get activated when the user selects them.
   - [Form]: Forms composed of input fields, drop down selections, checkboxes,
-    and buttons.
+    radio buttons, and buttons.
   - [Modal]: A centered window with a text message and one or more buttons.
   - [Grid]: A grid based layout manager.
   - [Flex]: A Flexbox based layout manager.
@@ -110,7 +132,14 @@ previously set.
 Setting a URL allows you to turn a piece of text into a hyperlink in some
 terminals. Specify a dash ("-") to specify the end of the hyperlink. Hyperlinks
 must only contain single-byte characters (e.g. ASCII) and they may not contain
-bracket characters ("[" or "]").
+bracket characters ("[" or "]"). Terminals which support OSC 8 escape sequences
+make hyperlinks clickable. Alternatively, hyperlinks can be specified with a
+"link" tag which only sets the URL. An empty URL ends the hyperlink:
+
+	Visit [link=https://example.com]example.com[link=] for more.
+
+A [TextView] can also report clicks on hyperlinks, see
+[TextView.SetLinkClickedFunc].
 
 Examples:
 
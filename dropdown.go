@@ -1,21 +1,59 @@
 package tview
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/uniseg"
 )
 
+// Filter modes of a drop-down, see [DropDown.SetFilterMode].
+const (
+	DropDownFilterNone = iota
+	DropDownFilterPrefix
+	DropDownFilterFuzzy
+)
+
 // dropDownOption is one option that can be selected in a drop-down primitive.
 type dropDownOption struct {
 	Text     string // The text to be displayed in the drop-down.
 	Selected func() // The (optional) callback for when this option was selected.
+
+	header    bool // Whether this is the heading of an option group.
+	separator bool // Whether this is a separator line.
+}
+
+// width returns the screen width of the option's text in the drop-down list,
+// not including the option prefix and suffix.
+func (o *dropDownOption) width() int {
+	if o.separator {
+		return 0
+	}
+	width := TaggedStringWidth(o.Text)
+	if o.header {
+		width += 2 // The collapse indicator.
+	}
+	return width
+}
+
+// selectable returns whether the option may be selected at all, i.e. whether
+// it is neither a heading nor a separator.
+func (o *dropDownOption) selectable() bool {
+	return !o.header && !o.separator
 }
 
 // DropDown implements a selection widget whose options become visible in a
 // drop-down list when activated.
 //
+// In multi-select mode (see [DropDown.SetMultiSelect]), the user may select
+// any number of options.
+//
+// Long option lists may be organized with group headings (see
+// [DropDown.AddOptionGroup]) and separator lines (see
+// [DropDown.AddSeparator]). Options may also be disabled (see
+// [DropDown.SetOptionDisabled]).
+//
 // See https://github.com/rivo/tview/wiki/DropDown for an example.
 type DropDown struct {
 	*Box
@@ -42,9 +80,13 @@ type DropDown struct {
 	// Set to true if the options are visible and selectable.
 	open bool
 
-	// The runes typed so far to directly access one of the list items.
+	// The runes typed so far to directly access one of the list items or, if
+	// a filter mode is set, to filter the list items.
 	prefix string
 
+	// The filter mode (one of the DropDownFilter constants).
+	filterMode int
+
 	// The list element for the options.
 	list *List
 
@@ -84,6 +126,29 @@ type DropDown struct {
 	// selection.
 	selected func(text string, index int)
 
+	// A callback function which is called when the user changes the selection
+	// in multi-select mode.
+	multiSelected func(texts []string, indices []int)
+
+	// The format of the text shown in multi-select mode if the texts of the
+	// selected options don't fit into the field.
+	selectedCountFormat string
+
+	// The current option and the options selected in multi-select mode when
+	// the list was opened.
+	optionBefore    int
+	selectionBefore []int
+
+	// An optional function which provides the options whenever the list is
+	// opened.
+	optionsFunc func(done func(texts []string))
+
+	// Whether the options are currently being provided by optionsFunc.
+	loading bool
+
+	// The text shown in the list while the options are loading.
+	loadingText string
+
 	dragging bool // Set to true when mouse dragging is in progress.
 }
 
@@ -96,6 +161,7 @@ func NewDropDown() *DropDown {
 		SetSelectedBackgroundColor(Styles.PrimaryTextColor).
 		SetHighlightFullLine(true).
 		SetBackgroundColor(Styles.MoreContrastBackgroundColor)
+	list.filterPromptHidden = true // The filter text is shown in the drop-down.
 
 	d := &DropDown{
 		Box:                  NewBox(),
@@ -105,6 +171,8 @@ func NewDropDown() *DropDown {
 		fieldBackgroundColor: Styles.ContrastBackgroundColor,
 		fieldTextColor:       Styles.PrimaryTextColor,
 		prefixTextColor:      Styles.ContrastSecondaryTextColor,
+		selectedCountFormat:  "%d selected",
+		loadingText:          "Loading...",
 	}
 
 	return d
@@ -114,7 +182,7 @@ func NewDropDown() *DropDown {
 // be a negative value to indicate that no option is currently selected. Calling
 // this function will also trigger the "selected" callback (if there is one).
 func (d *DropDown) SetCurrentOption(index int) *DropDown {
-	if index >= 0 && index < len(d.options) {
+	if index >= 0 && index < len(d.options) && d.options[index].selectable() {
 		d.currentOption = index
 		d.list.SetCurrentItem(index)
 		if d.selected != nil {
@@ -155,7 +223,9 @@ func (d *DropDown) SetTextOptions(prefix, suffix, currentPrefix, currentSuffix,
 	d.optionPrefix = prefix
 	d.optionSuffix = suffix
 	for index := 0; index < d.list.GetItemCount(); index++ {
-		d.list.SetItemText(index, prefix+d.options[index].Text+suffix, "")
+		if d.options[index].selectable() {
+			d.list.SetItemText(index, prefix+d.options[index].Text+suffix, "")
+		}
 	}
 	return d
 }
@@ -204,6 +274,92 @@ func (d *DropDown) SetPrefixTextColor(color tcell.Color) *DropDown {
 	return d
 }
 
+// SetFilterMode sets how the options are affected when the user types while
+// the drop-down list is open:
+//
+//   - DropDownFilterNone: The first option starting with the typed text is
+//     selected (the default).
+//   - DropDownFilterPrefix: Only options starting with the typed text are
+//     shown.
+//   - DropDownFilterFuzzy: Only options containing all typed characters in the
+//     same order (not necessarily adjacent) are shown.
+//
+// Case is ignored. The typed text is shown in the drop-down's field (see
+// [DropDown.SetPrefixTextColor]) and removed when the list is closed.
+func (d *DropDown) SetFilterMode(mode int) *DropDown {
+	d.filterMode = mode
+	if mode == DropDownFilterPrefix {
+		d.list.filterMatch = prefixMatch
+	} else {
+		d.list.filterMatch = nil
+	}
+	return d
+}
+
+// SetMultiSelect sets whether the user may select multiple options. In this
+// mode, each option in the drop-down list is prefixed with a marker (see
+// [DropDown.SetSelectionMarkers]). Space or a mouse click toggles whether an
+// option is selected, Enter (or a click outside the list) confirms the
+// selection, and Escape restores the previous selection.
+//
+// The drop-down field shows the comma-separated texts of the selected options
+// or, if they don't fit, the number of selected options (see
+// [DropDown.SetSelectedCountFormat]). Changes are reported to the handler set
+// with [DropDown.SetMultiSelectedFunc]. The current option (see
+// [DropDown.GetCurrentOption]) is not affected by the selection.
+func (d *DropDown) SetMultiSelect(multiSelect bool) *DropDown {
+	d.list.SetMultiSelect(multiSelect)
+	return d
+}
+
+// SetSelectionMarkers sets the texts printed in front of selected and
+// unselected options in multi-select mode. The defaults are "[x] " and "[ ] ".
+func (d *DropDown) SetSelectionMarkers(selected, unselected string) *DropDown {
+	d.list.SetSelectionMarkers(selected, unselected)
+	return d
+}
+
+// SetSelectedCountFormat sets the format string used to show the number of
+// selected options in multi-select mode if their texts don't fit into the
+// field. It must contain one "%d" verb. The default is "%d selected". If the
+// format is empty, the (truncated) texts are always shown.
+func (d *DropDown) SetSelectedCountFormat(format string) *DropDown {
+	d.selectedCountFormat = format
+	return d
+}
+
+// SetOptionSelected sets whether the option with the given index is selected
+// in multi-select mode. This does not trigger the "multi-selected" callback.
+// Panics if the index is out of range.
+func (d *DropDown) SetOptionSelected(index int, selected bool) *DropDown {
+	d.list.SetItemSelected(index, selected)
+	return d
+}
+
+// IsOptionSelected returns whether the option with the given index is
+// selected in multi-select mode. Panics if the index is out of range.
+func (d *DropDown) IsOptionSelected(index int) bool {
+	return d.list.IsItemSelected(index)
+}
+
+// GetSelectedOptions returns the indices and texts of the options selected in
+// multi-select mode, in the order of the options.
+func (d *DropDown) GetSelectedOptions() (indices []int, texts []string) {
+	indices = d.list.GetSelectedItems()
+	for _, index := range indices {
+		texts = append(texts, d.options[index].Text)
+	}
+	return
+}
+
+// SetMultiSelectedFunc sets a handler which is called when the user confirms
+// a changed selection in multi-select mode. The handler is provided with the
+// texts and indices of all selected options.
+func (d *DropDown) SetMultiSelectedFunc(handler func(texts []string, indices []int)) *DropDown {
+	d.multiSelected = handler
+	return d
+}
+
 // SetListStyles sets the styles of the items in the drop-down list (unselected
 // as well as selected items). Style attributes are currently ignored but may be
 // used in the future.
@@ -239,7 +395,7 @@ func (d *DropDown) GetFieldWidth() int {
 	}
 	fieldWidth := 0
 	for _, option := range d.options {
-		width := TaggedStringWidth(option.Text)
+		width := option.width()
 		if width > fieldWidth {
 			fieldWidth = width
 		}
@@ -285,6 +441,141 @@ func (d *DropDown) SetOptions(texts []string, selected func(text string, index i
 	return d
 }
 
+// SetOptionsFunc sets a function which provides the drop-down's options each
+// time the drop-down list is opened, for example by querying a remote API.
+// The function receives a "done" function which must be called with the
+// option texts once they are available. Until then, a placeholder text is
+// shown in the list (see [DropDown.SetLoadingText]). The new options replace
+// the current ones. The current option and, in multi-select mode, the
+// selected options are kept if their texts are still among the new options.
+//
+// The "done" function may be called right away or later, but it must be
+// called from the main goroutine. To load options in the background, use
+// [Application.QueueUpdateDraw]:
+//
+//	dropDown.SetOptionsFunc(func(done func([]string)) {
+//	  go func() {
+//	    options := fetchOptions()
+//	    app.QueueUpdateDraw(func() {
+//	      done(options)
+//	    })
+//	  }()
+//	})
+//
+// Set to nil to stop providing options. The function is not called again
+// while a previous call has not finished yet.
+func (d *DropDown) SetOptionsFunc(handler func(done func(texts []string))) *DropDown {
+	d.optionsFunc = handler
+	return d
+}
+
+// SetLoadingText sets the text shown in the drop-down list while the options
+// are provided by the function set with [DropDown.SetOptionsFunc]. The default
+// is "Loading...".
+func (d *DropDown) SetLoadingText(text string) *DropDown {
+	d.loadingText = text
+	return d
+}
+
+// IsLoading returns whether the drop-down is waiting for its options (see
+// [DropDown.SetOptionsFunc]).
+func (d *DropDown) IsLoading() bool {
+	return d.loading
+}
+
+// loadOptions calls the options function, unless a previous call has not
+// finished yet, and replaces the options once they are available.
+func (d *DropDown) loadOptions() {
+	if d.optionsFunc == nil || d.loading {
+		return
+	}
+	d.loading = true
+	var finished bool
+	d.optionsFunc(func(texts []string) {
+		if finished {
+			return
+		}
+		finished = true
+		d.loading = false
+
+		// Replace the options but keep the selection.
+		_, current := d.GetCurrentOption()
+		currentOption := -1
+		selected := make(map[string]bool)
+		if d.list.multiSelect {
+			_, selectedTexts := d.GetSelectedOptions()
+			for _, text := range selectedTexts {
+				selected[text] = true
+			}
+		}
+		d.list.Clear()
+		d.options = nil
+		for index, text := range texts {
+			d.AddOption(text, nil)
+			if selected[text] {
+				d.list.SetItemSelected(index, true)
+			}
+			if currentOption < 0 && d.currentOption >= 0 && text == current {
+				currentOption = index
+			}
+		}
+		d.currentOption = currentOption
+		if currentOption >= 0 {
+			d.list.SetCurrentItem(currentOption)
+		}
+		d.list.SetOffset(0, 0) // Adjusted when the list is drawn.
+		if d.open {
+			d.optionBefore = currentOption
+			d.selectionBefore = d.list.GetSelectedItems()
+		}
+	})
+}
+
+// AddOptionGroup adds a heading with the given title to the drop-down list.
+// The options added after it, up to the next heading, form a group. Headings
+// cannot be selected but they take up an option index.
+func (d *DropDown) AddOptionGroup(title string) *DropDown {
+	d.options = append(d.options, &dropDownOption{Text: title, header: true})
+	d.list.AddHeader(title)
+	return d
+}
+
+// AddSeparator adds a horizontal separator line to the drop-down list.
+// Separators cannot be selected but they take up an option index.
+func (d *DropDown) AddSeparator() *DropDown {
+	d.options = append(d.options, &dropDownOption{separator: true})
+	d.list.AddSeparator()
+	return d
+}
+
+// SetOptionDisabled sets whether the option with the given index is disabled.
+// Disabled options are shown in the list but they are skipped during
+// navigation and cannot be selected. Panics if the index is out of range.
+func (d *DropDown) SetOptionDisabled(index int, disabled bool) *DropDown {
+	d.list.SetItemDisabled(index, disabled)
+	return d
+}
+
+// IsOptionDisabled returns whether the option with the given index is
+// disabled. Headings and separators are always disabled. Panics if the index
+// is out of range.
+func (d *DropDown) IsOptionDisabled(index int) bool {
+	return !d.options[index].selectable() || d.list.IsItemDisabled(index)
+}
+
+// SetOptionGroupStyle sets the style of group headings (see
+// [DropDown.AddOptionGroup]).
+func (d *DropDown) SetOptionGroupStyle(style tcell.Style) *DropDown {
+	d.list.SetHeaderStyle(style)
+	return d
+}
+
+// SetDisabledOptionStyle sets the style of disabled options and separators.
+func (d *DropDown) SetDisabledOptionStyle(style tcell.Style) *DropDown {
+	d.list.SetDisabledStyle(style)
+	return d
+}
+
 // GetOptionCount returns the number of options in the drop-down.
 func (d *DropDown) GetOptionCount() int {
 	return len(d.options)
@@ -354,7 +645,10 @@ func (d *DropDown) Draw(screen tcell.Screen) {
 	maxWidth := 0
 	optionWrapWidth := TaggedStringWidth(d.optionPrefix + d.optionSuffix)
 	for _, option := range d.options {
-		strWidth := TaggedStringWidth(option.Text) + optionWrapWidth
+		strWidth := option.width()
+		if option.selectable() {
+			strWidth += optionWrapWidth
+		}
 		if strWidth > maxWidth {
 			maxWidth = strWidth
 		}
@@ -364,7 +658,14 @@ func (d *DropDown) Draw(screen tcell.Screen) {
 	fieldWidth := d.fieldWidth
 	if fieldWidth == 0 {
 		fieldWidth = maxWidth
-		if d.currentOption < 0 {
+		if d.list.multiSelect {
+			if selectionWidth := TaggedStringWidth(d.selectionText(maxWidth)); selectionWidth > fieldWidth {
+				fieldWidth = selectionWidth
+			}
+			if noSelectionWidth := TaggedStringWidth(d.noSelection); noSelectionWidth > fieldWidth {
+				fieldWidth = noSelectionWidth
+			}
+		} else if d.currentOption < 0 {
 			noSelectionWidth := TaggedStringWidth(d.noSelection)
 			if noSelectionWidth > fieldWidth {
 				fieldWidth = noSelectionWidth
@@ -391,7 +692,12 @@ func (d *DropDown) Draw(screen tcell.Screen) {
 	}
 
 	// Draw selected text.
-	if d.open && len(d.prefix) > 0 {
+	if d.open && len(d.prefix) > 0 && d.filterMode != DropDownFilterNone {
+		// Show the filter text.
+		currentOptionPrefixWidth := TaggedStringWidth(d.currentOptionPrefix)
+		Print(screen, d.currentOptionPrefix, x, y, fieldWidth, AlignLeft, d.fieldTextColor)
+		Print(screen, Escape(d.prefix), x+currentOptionPrefixWidth, y, fieldWidth-currentOptionPrefixWidth, AlignLeft, d.prefixTextColor)
+	} else if d.open && len(d.prefix) > 0 {
 		// Show the prefix.
 		currentOptionPrefixWidth := TaggedStringWidth(d.currentOptionPrefix)
 		prefixWidth := uniseg.StringWidth(d.prefix)
@@ -404,7 +710,11 @@ func (d *DropDown) Draw(screen tcell.Screen) {
 	} else {
 		color := d.fieldTextColor
 		text := d.noSelection
-		if d.currentOption >= 0 && d.currentOption < len(d.options) {
+		if d.list.multiSelect {
+			if selection := d.selectionText(fieldWidth); selection != "" {
+				text = selection
+			}
+		} else if d.currentOption >= 0 && d.currentOption < len(d.options) {
 			text = d.currentOptionPrefix + d.options[d.currentOption].Text + d.currentOptionSuffix
 		}
 		// Just show the current selection.
@@ -419,7 +729,16 @@ func (d *DropDown) Draw(screen tcell.Screen) {
 		lx := x
 		ly := y + 1
 		lwidth := maxWidth
-		lheight := len(d.options)
+		if d.list.multiSelect {
+			lwidth += uniseg.StringWidth(d.list.markerSelected)
+		}
+		lheight := d.list.visibleCount()
+		if lheight < 1 || d.loading {
+			lheight = 1 // Show an empty list if no options match the filter.
+		}
+		if loadingWidth := TaggedStringWidth(d.loadingText); d.loading && loadingWidth > lwidth {
+			lwidth = loadingWidth
+		}
 		swidth, sheight := screen.Size()
 		// We prefer to align the left sides of the list and the main widget, but
 		// if there is no space to the right, then shift the list to the left.
@@ -440,10 +759,31 @@ func (d *DropDown) Draw(screen tcell.Screen) {
 			lheight = sheight - ly
 		}
 		d.list.SetRect(lx, ly, lwidth, lheight)
-		d.list.Draw(screen)
+		if d.loading {
+			// Show the placeholder instead of the options.
+			d.list.Box.DrawForSubclass(screen, d.list)
+			printWithStyle(screen, d.loadingText, lx, ly, 0, lwidth, AlignLeft, d.list.disabledStyle, true)
+		} else {
+			d.list.adjustOffset()
+			d.list.Draw(screen)
+		}
 	}
 }
 
+// selectionText returns the text shown in the field in multi-select mode for
+// the given field width or an empty string if no option is selected.
+func (d *DropDown) selectionText(fieldWidth int) string {
+	_, texts := d.GetSelectedOptions()
+	if len(texts) == 0 {
+		return ""
+	}
+	text := d.currentOptionPrefix + strings.Join(texts, ", ") + d.currentOptionSuffix
+	if d.selectedCountFormat != "" && TaggedStringWidth(text) > fieldWidth {
+		text = d.currentOptionPrefix + fmt.Sprintf(d.selectedCountFormat, len(texts)) + d.currentOptionSuffix
+	}
+	return text
+}
+
 // InputHandler returns the handler for this primitive.
 func (d *DropDown) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return d.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
@@ -467,7 +807,11 @@ func (d *DropDown) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			// If the first key was a letter already, it becomes part of the prefix.
 			if r := event.Rune(); key == tcell.KeyRune && r != ' ' {
 				d.prefix += string(r)
-				d.evalPrefix()
+				if d.filterMode != DropDownFilterNone {
+					d.list.SetFilterText(d.prefix)
+				} else {
+					d.evalPrefix()
+				}
 			}
 
 			d.openList(setFocus)
@@ -486,7 +830,7 @@ func (d *DropDown) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 func (d *DropDown) evalPrefix() {
 	if len(d.prefix) > 0 {
 		for index, option := range d.options {
-			if strings.HasPrefix(strings.ToLower(option.Text), d.prefix) {
+			if option.selectable() && !d.list.IsItemDisabled(index) && strings.HasPrefix(strings.ToLower(option.Text), d.prefix) {
 				d.list.SetCurrentItem(index)
 				return
 			}
@@ -501,13 +845,21 @@ func (d *DropDown) evalPrefix() {
 // openList hands control over to the embedded List primitive.
 func (d *DropDown) openList(setFocus func(Primitive)) {
 	d.open = true
-	optionBefore := d.currentOption
+	d.optionBefore = d.currentOption
+	d.selectionBefore = d.list.GetSelectedItems()
 
 	d.list.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
 		if d.dragging {
 			return // If we're dragging the mouse, we don't want to trigger any events.
 		}
 
+		// In multi-select mode, the selection was confirmed.
+		if d.list.multiSelect {
+			d.closeList(setFocus)
+			d.confirmSelection()
+			return
+		}
+
 		// An option was selected. Close the list again.
 		d.currentOption = index
 		d.closeList(setFocus)
@@ -520,9 +872,45 @@ func (d *DropDown) openList(setFocus func(Primitive)) {
 			d.options[d.currentOption].Selected()
 		}
 	}).SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if d.loading {
+			// Wait for the options.
+			if event.Key() == tcell.KeyEscape {
+				d.currentOption = d.optionBefore
+				d.closeList(setFocus)
+			}
+			return nil
+		}
+
+		if d.filterMode != DropDownFilterNone {
+			// Typing filters the options.
+			switch event.Key() {
+			case tcell.KeyRune:
+				if d.list.multiSelect && event.Rune() == ' ' {
+					return event // Toggle the option.
+				}
+				d.prefix += string(event.Rune())
+				d.list.SetFilterText(d.prefix)
+				return nil
+			case tcell.KeyBackspace, tcell.KeyBackspace2:
+				if len(d.prefix) > 0 {
+					r := []rune(d.prefix)
+					d.prefix = string(r[:len(r)-1])
+					d.list.SetFilterText(d.prefix)
+				}
+				return nil
+			case tcell.KeyEscape:
+				d.currentOption = d.optionBefore
+				d.restoreSelection()
+				d.closeList(setFocus)
+			}
+			return event
+		}
+
 		if event.Key() == tcell.KeyRune {
-			d.prefix += string(event.Rune())
-			d.evalPrefix()
+			if !d.list.multiSelect || event.Rune() != ' ' { // Space toggles the option.
+				d.prefix += string(event.Rune())
+				d.evalPrefix()
+			}
 		} else if event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 {
 			if len(d.prefix) > 0 {
 				r := []rune(d.prefix)
@@ -530,7 +918,8 @@ func (d *DropDown) openList(setFocus func(Primitive)) {
 			}
 			d.evalPrefix()
 		} else if event.Key() == tcell.KeyEscape {
-			d.currentOption = optionBefore
+			d.currentOption = d.optionBefore
+			d.restoreSelection()
 			d.closeList(setFocus)
 		} else {
 			d.prefix = ""
@@ -540,12 +929,45 @@ func (d *DropDown) openList(setFocus func(Primitive)) {
 	})
 
 	setFocus(d.list)
+	d.loadOptions()
+}
+
+// restoreSelection restores the multi-selection as it was when the list was
+// opened.
+func (d *DropDown) restoreSelection() {
+	if !d.list.multiSelect {
+		return
+	}
+	d.list.ClearSelectedItems()
+	for _, index := range d.selectionBefore {
+		if index < len(d.options) {
+			d.list.SetItemSelected(index, true)
+		}
+	}
+}
+
+// confirmSelection invokes the "multi-selected" callback if the
+// multi-selection was changed since the list was opened.
+func (d *DropDown) confirmSelection() {
+	indices, texts := d.GetSelectedOptions()
+	changed := len(indices) != len(d.selectionBefore)
+	for index := 0; !changed && index < len(indices); index++ {
+		changed = indices[index] != d.selectionBefore[index]
+	}
+	d.selectionBefore = indices
+	if changed && d.multiSelected != nil {
+		d.multiSelected(texts, indices)
+	}
 }
 
 // closeList closes the embedded List element by hiding it and removing focus
 // from it.
 func (d *DropDown) closeList(setFocus func(Primitive)) {
 	d.open = false
+	if d.filterMode != DropDownFilterNone {
+		d.prefix = ""
+		d.list.SetFilterText("")
+	}
 	if d.list.HasFocus() {
 		setFocus(d)
 	}
@@ -606,12 +1028,22 @@ func (d *DropDown) MouseHandler() func(action MouseAction, event *tcell.EventMou
 			capture = d
 			if !d.open {
 				d.openList(setFocus)
-				d.dragging = true
+				d.dragging = !d.list.multiSelect
+			} else if d.loading {
+				d.closeList(setFocus)
+			} else if d.list.multiSelect {
+				// Clicks toggle options, clicks outside the list confirm.
+				if !d.list.InRect(x, y) {
+					d.closeList(setFocus)
+					d.confirmSelection()
+				} else if index := d.list.indexAtPoint(x, y); index >= 0 && !d.IsOptionDisabled(index) {
+					d.list.toggleItem(index)
+				}
 			} else if consumed, _ := d.list.MouseHandler()(MouseLeftClick, event, setFocus); !consumed {
 				d.closeList(setFocus) // Close drop-down if clicked outside of it.
 			}
 		case MouseMove:
-			if d.dragging {
+			if d.dragging && !d.loading {
 				// We pretend it's a left click so we can see the selection during
 				// dragging. Because we don't act upon it, it's not a problem.
 				d.list.MouseHandler()(MouseLeftClick, event, setFocus)
@@ -620,7 +1052,9 @@ func (d *DropDown) MouseHandler() func(action MouseAction, event *tcell.EventMou
 		case MouseLeftUp:
 			if d.dragging {
 				d.dragging = false
-				d.list.MouseHandler()(MouseLeftClick, event, setFocus)
+				if !d.loading {
+					d.list.MouseHandler()(MouseLeftClick, event, setFocus)
+				}
 				consumed = true
 			}
 		}
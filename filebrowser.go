@@ -0,0 +1,534 @@
+package tview
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// fileBrowserEntry is one entry of the directory shown by a FileBrowser.
+type fileBrowserEntry struct {
+	name string // The file name, ".." for the parent directory.
+	dir  bool   // Whether this entry is a directory.
+}
+
+// FileBrowser lets the user navigate the file system and choose a file. It
+// shows the path of the current directory as breadcrumbs, followed by a list
+// of the directory's subdirectories and files. Hidden files (whose names start
+// with a dot) are only shown if enabled with [FileBrowser.ShowHidden]. Files
+// may be restricted to those matching glob patterns, see
+// [FileBrowser.SetFilter].
+//
+// The following keys are available:
+//
+//   - Up arrow / down arrow, Page up / page down, Home, End: Move the cursor.
+//   - Enter: Open the directory under the cursor or choose the file.
+//   - Right arrow: Open the directory under the cursor.
+//   - Left arrow, Backspace: Go to the parent directory.
+//   - Alt+H: Show or hide hidden files.
+//   - Other characters: Move the cursor to the first entry starting with the
+//     typed text. Backspace removes the last character, Escape clears it.
+//   - Escape, Tab, Backtab: Leave the file browser (see
+//     [FileBrowser.SetDoneFunc]).
+//
+// Clicking on one of the breadcrumbs opens that directory. Double-clicking on
+// an entry opens it.
+type FileBrowser struct {
+	*Box
+
+	// The list showing the directory's entries.
+	list *List
+
+	// The absolute path of the current directory.
+	dir string
+
+	// The entries of the current directory, in the order of the list.
+	entries []fileBrowserEntry
+
+	// The error which occurred when the directory was last read, if any.
+	err error
+
+	// Whether hidden files are shown.
+	showHidden bool
+
+	// The glob patterns files must match to be shown. Empty for all files.
+	filter []string
+
+	// The text typed to search for an entry.
+	search string
+
+	// Whether the list's "selected" callback is triggered by a mouse click.
+	clicking bool
+
+	// The styles of the breadcrumbs and of the current directory.
+	breadcrumbStyle, currentStyle tcell.Style
+
+	// The breadcrumbs' positions and directories when last drawn.
+	breadcrumbX     []int
+	breadcrumbPaths []string
+	breadcrumbY     int
+
+	// An optional function which is called when the user chooses a file.
+	selected func(path string)
+
+	// An optional function which is called when the entry under the cursor
+	// changes.
+	changed func(path string)
+
+	// An optional function which is called when the current directory
+	// changes.
+	directoryChanged func(dir string)
+
+	// An optional function which is called when the user leaves the file
+	// browser.
+	done func(key tcell.Key)
+}
+
+// NewFileBrowser returns a new file browser showing the given directory. If
+// it is empty, the current working directory is shown.
+func NewFileBrowser(dir string) *FileBrowser {
+	f := &FileBrowser{
+		Box:             NewBox(),
+		list:            NewList(),
+		breadcrumbStyle: tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
+		currentStyle:    tcell.StyleDefault.Foreground(Styles.PrimaryTextColor).Bold(true),
+	}
+	f.list.ShowSecondaryText(false).
+		SetHighlightFullLine(true).
+		SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+			if !f.clicking {
+				f.open(index, false)
+			}
+		}).
+		SetChangedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+			if f.changed != nil && index >= 0 && index < len(f.entries) {
+				f.changed(f.entryPath(index))
+			}
+		})
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	f.SetDirectory(dir)
+	return f
+}
+
+// SetDirectory shows the given directory. If it cannot be read, the error is
+// shown instead of the entries, see also [FileBrowser.GetError].
+func (f *FileBrowser) SetDirectory(dir string) *FileBrowser {
+	if absolute, err := filepath.Abs(dir); err == nil {
+		dir = absolute
+	}
+	changed := dir != f.dir
+	f.dir = dir
+	f.search = ""
+	f.Refresh()
+	if changed && f.directoryChanged != nil {
+		f.directoryChanged(dir)
+	}
+	return f
+}
+
+// GetDirectory returns the absolute path of the current directory.
+func (f *FileBrowser) GetDirectory() string {
+	return f.dir
+}
+
+// GetError returns the error which occurred when the current directory was
+// last read or nil if there was none.
+func (f *FileBrowser) GetError() error {
+	return f.err
+}
+
+// GetCurrentPath returns the path of the entry under the cursor or an empty
+// string if the directory is empty.
+func (f *FileBrowser) GetCurrentPath() string {
+	index := f.list.GetCurrentItem()
+	if index < 0 || index >= len(f.entries) {
+		return ""
+	}
+	return f.entryPath(index)
+}
+
+// ShowHidden sets whether files and directories whose names start with a dot
+// are shown.
+func (f *FileBrowser) ShowHidden(show bool) *FileBrowser {
+	if show != f.showHidden {
+		f.showHidden = show
+		f.Refresh()
+	}
+	return f
+}
+
+// IsShowingHidden returns whether hidden files and directories are shown.
+func (f *FileBrowser) IsShowingHidden() bool {
+	return f.showHidden
+}
+
+// SetFilter restricts the files shown to those whose names match at least
+// one of the given glob patterns (see [filepath.Match]), e.g. "*.go".
+// Directories are always shown. Call without patterns to show all files.
+func (f *FileBrowser) SetFilter(patterns ...string) *FileBrowser {
+	f.filter = patterns
+	f.Refresh()
+	return f
+}
+
+// SetStyles sets the styles of the breadcrumbs and of the current directory's
+// breadcrumb.
+func (f *FileBrowser) SetStyles(breadcrumbs, current tcell.Style) *FileBrowser {
+	f.breadcrumbStyle, f.currentStyle = breadcrumbs, current
+	return f
+}
+
+// GetList returns the list which shows the directory's entries, e.g. to
+// change its styles.
+func (f *FileBrowser) GetList() *List {
+	return f.list
+}
+
+// SetSelectedFunc sets a handler which is called with the file's path when
+// the user chooses a file.
+func (f *FileBrowser) SetSelectedFunc(handler func(path string)) *FileBrowser {
+	f.selected = handler
+	return f
+}
+
+// SetChangedFunc sets a handler which is called with the entry's path when
+// the entry under the cursor changes.
+func (f *FileBrowser) SetChangedFunc(handler func(path string)) *FileBrowser {
+	f.changed = handler
+	return f
+}
+
+// SetDirectoryChangedFunc sets a handler which is called with the new
+// directory's path when the current directory changes.
+func (f *FileBrowser) SetDirectoryChangedFunc(handler func(dir string)) *FileBrowser {
+	f.directoryChanged = handler
+	return f
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the file
+// browser with the Escape, Tab, or Backtab key.
+func (f *FileBrowser) SetDoneFunc(handler func(key tcell.Key)) *FileBrowser {
+	f.done = handler
+	return f
+}
+
+// Refresh reads the current directory again.
+func (f *FileBrowser) Refresh() *FileBrowser {
+	current := ""
+	if index := f.list.GetCurrentItem(); index >= 0 && index < len(f.entries) {
+		current = f.entries[index].name
+	}
+
+	// Read the directory.
+	f.entries, f.err = nil, nil
+	if parent := filepath.Dir(f.dir); parent != f.dir {
+		f.entries = append(f.entries, fileBrowserEntry{name: "..", dir: true})
+	}
+	dirEntries, err := os.ReadDir(f.dir)
+	if err != nil {
+		f.err = err
+	}
+	var entries []fileBrowserEntry
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if !f.showHidden && strings.HasPrefix(name, ".") {
+			continue
+		}
+		isDir := dirEntry.IsDir()
+		if dirEntry.Type()&os.ModeSymlink != 0 {
+			if info, err := os.Stat(filepath.Join(f.dir, name)); err == nil {
+				isDir = info.IsDir()
+			}
+		}
+		if !isDir && !f.matchFilter(name) {
+			continue
+		}
+		entries = append(entries, fileBrowserEntry{name: name, dir: isDir})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].dir != entries[j].dir {
+			return entries[i].dir
+		}
+		return strings.ToLower(entries[i].name) < strings.ToLower(entries[j].name)
+	})
+	f.entries = append(f.entries, entries...)
+
+	// Fill the list.
+	changed := f.changed
+	f.changed = nil // Don't report the list being rebuilt.
+	f.list.Clear()
+	for _, entry := range f.entries {
+		text := Escape(entry.name)
+		if entry.dir {
+			text = "[::b]" + text + "/"
+		}
+		f.list.AddItem(text, "", 0, nil)
+	}
+	for index, entry := range f.entries {
+		if entry.name == current {
+			f.list.SetCurrentItem(index)
+			break
+		}
+	}
+	f.changed = changed
+	return f
+}
+
+// matchFilter returns whether the given file name matches the filter.
+func (f *FileBrowser) matchFilter(name string) bool {
+	if len(f.filter) == 0 {
+		return true
+	}
+	for _, pattern := range f.filter {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// entryPath returns the path of the entry with the given index.
+func (f *FileBrowser) entryPath(index int) string {
+	if f.entries[index].name == ".." {
+		return filepath.Dir(f.dir)
+	}
+	return filepath.Join(f.dir, f.entries[index].name)
+}
+
+// open opens the entry with the given index. Directories are shown, files are
+// chosen unless dirOnly is true.
+func (f *FileBrowser) open(index int, dirOnly bool) {
+	if index < 0 || index >= len(f.entries) {
+		return
+	}
+	entry := f.entries[index]
+	if entry.dir {
+		f.openDirectory(f.entryPath(index))
+		return
+	}
+	if !dirOnly && f.selected != nil {
+		f.selected(f.entryPath(index))
+	}
+}
+
+// openDirectory shows the given directory. If it is the parent of the current
+// directory, the cursor is placed on the directory which was left.
+func (f *FileBrowser) openDirectory(dir string) {
+	previous := f.dir
+	f.SetDirectory(dir)
+	for parent := previous; ; parent = filepath.Dir(parent) {
+		if filepath.Dir(parent) == f.dir {
+			for index, entry := range f.entries {
+				if entry.name == filepath.Base(parent) {
+					f.list.SetCurrentItem(index)
+					break
+				}
+			}
+			break
+		}
+		if filepath.Dir(parent) == parent {
+			break
+		}
+	}
+}
+
+// searchEntry moves the cursor to the first entry starting with the search
+// text.
+func (f *FileBrowser) searchEntry() {
+	search := strings.ToLower(f.search)
+	for index, entry := range f.entries {
+		if entry.name != ".." && strings.HasPrefix(strings.ToLower(entry.name), search) {
+			f.list.SetCurrentItem(index)
+			return
+		}
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (f *FileBrowser) Focus(delegate func(p Primitive)) {
+	delegate(f.list)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (f *FileBrowser) HasFocus() bool {
+	return f.list.HasFocus() || f.Box.HasFocus()
+}
+
+// Draw draws this primitive onto the screen.
+func (f *FileBrowser) Draw(screen tcell.Screen) {
+	f.Box.DrawForSubclass(screen, f)
+
+	x, y, width, height := f.GetInnerRect()
+	f.breadcrumbX, f.breadcrumbPaths = f.breadcrumbX[:0], f.breadcrumbPaths[:0]
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Collect the breadcrumbs, from the root to the current directory.
+	var paths []string
+	for path := f.dir; ; path = filepath.Dir(path) {
+		paths = append([]string{path}, paths...)
+		if filepath.Dir(path) == path {
+			break
+		}
+	}
+	labels := make([]string, len(paths))
+	var total int
+	for index, path := range paths {
+		labels[index] = filepath.Base(path)
+		if index == 0 {
+			labels[index] = path
+		} else if !strings.HasSuffix(labels[index-1], string(filepath.Separator)) {
+			labels[index] = string(filepath.Separator) + labels[index]
+		}
+		total += TaggedStringWidth(Escape(labels[index]))
+	}
+
+	// Drop leading breadcrumbs which don't fit.
+	first := 0
+	for total > width && first < len(paths)-1 {
+		total -= TaggedStringWidth(Escape(labels[first]))
+		first++
+		if first == 1 {
+			total++ // Ellipsis.
+		}
+	}
+
+	// Draw the breadcrumbs.
+	f.breadcrumbY = y
+	breadcrumbX := x
+	if first > 0 {
+		printWithStyle(screen, "…", breadcrumbX, y, 0, width, AlignLeft, f.breadcrumbStyle, true)
+		breadcrumbX++
+	}
+	for index := first; index < len(paths); index++ {
+		style := f.breadcrumbStyle
+		if index == len(paths)-1 {
+			style = f.currentStyle
+		}
+		f.breadcrumbX = append(f.breadcrumbX, breadcrumbX)
+		f.breadcrumbPaths = append(f.breadcrumbPaths, paths[index])
+		_, _, printed := printWithStyle(screen, Escape(labels[index]), breadcrumbX, y, 0, x+width-breadcrumbX, AlignLeft, style, true)
+		breadcrumbX += printed
+	}
+	f.breadcrumbX = append(f.breadcrumbX, breadcrumbX)
+
+	// Draw the entries.
+	listHeight := height - 1
+	if f.search != "" {
+		listHeight-- // Make room for the search text.
+	}
+	if listHeight <= 0 {
+		return
+	}
+	f.list.SetRect(x, y+1, width, listHeight)
+	f.list.Draw(screen)
+	if f.err != nil {
+		printWithStyle(screen, Escape(f.err.Error()), x, y+2, 0, width, AlignLeft, tcell.StyleDefault.Foreground(Styles.TertiaryTextColor), true)
+	}
+
+	// Draw the search text.
+	if f.search != "" {
+		printWithStyle(screen, "Search: "+Escape(f.search), x, y+1+listHeight, 0, width, AlignLeft, f.breadcrumbStyle, true)
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (f *FileBrowser) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return f.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		switch key := event.Key(); key {
+		case tcell.KeyRune:
+			if event.Modifiers()&tcell.ModAlt != 0 {
+				if r := event.Rune(); r == 'h' || r == 'H' {
+					f.ShowHidden(!f.showHidden)
+				}
+				return
+			}
+			f.search += string(event.Rune())
+			f.searchEntry()
+			return
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if f.search != "" {
+				runes := []rune(f.search)
+				f.search = string(runes[:len(runes)-1])
+				f.searchEntry()
+			} else {
+				f.openDirectory(filepath.Dir(f.dir))
+			}
+			return
+		case tcell.KeyEscape:
+			if f.search != "" {
+				f.search = ""
+			} else if f.done != nil {
+				f.done(key)
+			}
+			return
+		case tcell.KeyTab, tcell.KeyBacktab:
+			f.search = ""
+			if f.done != nil {
+				f.done(key)
+			}
+			return
+		case tcell.KeyLeft:
+			f.openDirectory(filepath.Dir(f.dir))
+			return
+		case tcell.KeyRight:
+			f.open(f.list.GetCurrentItem(), true)
+			return
+		}
+
+		// Pass other keys on to the list.
+		f.search = ""
+		if handler := f.list.InputHandler(); handler != nil {
+			handler(event, setFocus)
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (f *FileBrowser) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return f.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !f.InRect(x, y) {
+			return false, nil
+		}
+
+		// Clicking on a breadcrumb opens its directory.
+		if y == f.breadcrumbY {
+			if action == MouseLeftClick {
+				for index, path := range f.breadcrumbPaths {
+					if x >= f.breadcrumbX[index] && x < f.breadcrumbX[index+1] {
+						f.openDirectory(path)
+						break
+					}
+				}
+			}
+			if action == MouseLeftDown {
+				f.Focus(setFocus)
+			}
+			return true, nil
+		}
+
+		// Double-clicking opens an entry, single clicks only move the cursor.
+		if action == MouseLeftDoubleClick && f.list.InRect(x, y) {
+			f.open(f.list.GetCurrentItem(), false)
+			return true, nil
+		}
+		f.clicking = true
+		consumed, capture = f.list.MouseHandler()(action, event, setFocus)
+		f.clicking = false
+		if action == MouseLeftClick {
+			f.search = ""
+		}
+		if !consumed && action == MouseLeftDown {
+			f.Focus(setFocus)
+			consumed = true
+		}
+		return
+	})
+}
@@ -0,0 +1,312 @@
+package tview
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// FileDialog is a centered window which lets the user choose a file to open
+// or a path to save a file to. It consists of a [FileBrowser], an input field
+// for the file name (save dialogs only), and two buttons. Create it with
+// [OpenFileDialog] or [SaveFileDialog] and show it on top of other primitives,
+// e.g. as a page of a [Pages] object:
+//
+//	dialog := tview.OpenFileDialog("", func(path string, ok bool) {
+//		pages.RemovePage("open")
+//		if ok {
+//			load(path)
+//		}
+//	})
+//	pages.AddPage("open", dialog, true, true)
+//
+// The Tab and Backtab keys move the focus between the dialog's elements, the
+// Escape key cancels the dialog.
+type FileDialog struct {
+	*Box
+
+	// The file browser.
+	browser *FileBrowser
+
+	// The input field for the file name, nil for open dialogs.
+	name *InputField
+
+	// The button which confirms the dialog and the one which cancels it.
+	confirm, cancel *Button
+
+	// The dialog's size. 0 means two thirds of the screen's size.
+	width, height int
+
+	// The function with which the dialog last received focus.
+	delegate func(p Primitive)
+
+	// The function which is called when the dialog was confirmed or canceled.
+	done func(path string, ok bool)
+}
+
+// OpenFileDialog returns a new dialog which lets the user choose an existing
+// file, starting in the given directory (the current working directory if
+// empty). When the user chooses a file, the done function is called with its
+// path and ok set to true. If the user cancels the dialog, it is called with
+// an empty path and ok set to false.
+func OpenFileDialog(dir string, done func(path string, ok bool)) *FileDialog {
+	d := newFileDialog(dir, "Open", done)
+	d.SetTitle(" Open file ")
+	d.browser.SetSelectedFunc(func(path string) {
+		d.finish(path, true)
+	})
+	return d
+}
+
+// SaveFileDialog returns a new dialog which lets the user choose a path to
+// save a file to, starting in the given directory (the current working
+// directory if empty) with the given file name. When the user confirms the
+// dialog, the done function is called with the chosen path and ok set to
+// true. The file may or may not exist. If the user cancels the dialog, it is
+// called with an empty path and ok set to false.
+func SaveFileDialog(dir, name string, done func(path string, ok bool)) *FileDialog {
+	d := newFileDialog(dir, "Save", done)
+	d.SetTitle(" Save file ")
+	d.name = NewInputField().
+		SetLabel("Name: ").
+		SetDoneFunc(func(key tcell.Key) {
+			switch key {
+			case tcell.KeyEnter:
+				d.submit()
+			case tcell.KeyEscape:
+				d.finish("", false)
+			case tcell.KeyTab, tcell.KeyBacktab:
+				d.moveFocus(key)
+			}
+		})
+	d.name.SetText(name)
+	d.browser.SetSelectedFunc(func(path string) {
+		d.name.SetText(filepath.Base(path))
+		d.focusElement(1)
+	})
+	return d
+}
+
+// newFileDialog returns a new file dialog without the elements which differ
+// between open and save dialogs.
+func newFileDialog(dir, confirm string, done func(path string, ok bool)) *FileDialog {
+	d := &FileDialog{
+		Box:     NewBox(),
+		browser: NewFileBrowser(dir),
+		done:    done,
+	}
+	d.SetBorder(true)
+	d.browser.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			d.finish("", false)
+			return
+		}
+		d.moveFocus(key)
+	})
+	exit := func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			d.finish("", false)
+			return
+		}
+		d.moveFocus(key)
+	}
+	d.confirm = NewButton(confirm).SetSelectedFunc(d.submit).SetExitFunc(exit)
+	d.cancel = NewButton("Cancel").SetSelectedFunc(func() {
+		d.finish("", false)
+	}).SetExitFunc(exit)
+	return d
+}
+
+// GetFileBrowser returns the dialog's file browser, e.g. to set a filter.
+func (d *FileDialog) GetFileBrowser() *FileBrowser {
+	return d.browser
+}
+
+// SetSize sets the dialog's width and height, including its border. If a
+// value is 0 (the default), two thirds of the screen's width or height are
+// used.
+func (d *FileDialog) SetSize(width, height int) *FileDialog {
+	d.width, d.height = width, height
+	return d
+}
+
+// elements returns the dialog's elements in focus order.
+func (d *FileDialog) elements() []Primitive {
+	if d.name != nil {
+		return []Primitive{d.browser, d.name, d.confirm, d.cancel}
+	}
+	return []Primitive{d.browser, d.confirm, d.cancel}
+}
+
+// focusElement gives focus to the element with the given index.
+func (d *FileDialog) focusElement(index int) {
+	if d.delegate != nil {
+		d.delegate(d.elements()[index])
+	}
+}
+
+// moveFocus gives focus to the next element (for the Tab key) or the previous
+// one (for the Backtab key).
+func (d *FileDialog) moveFocus(key tcell.Key) {
+	elements := d.elements()
+	current := 0
+	for index, element := range elements {
+		if element.HasFocus() {
+			current = index
+			break
+		}
+	}
+	if key == tcell.KeyBacktab {
+		current += len(elements) - 1
+	} else {
+		current++
+	}
+	d.focusElement(current % len(elements))
+}
+
+// submit confirms the dialog with the entry under the browser's cursor (open
+// dialogs) or the entered name (save dialogs). Directories are opened instead.
+func (d *FileDialog) submit() {
+	var path string
+	if d.name != nil {
+		name := d.name.GetText()
+		if name == "" {
+			return
+		}
+		path = name
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(d.browser.GetDirectory(), path)
+		}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			d.browser.openDirectory(path)
+			d.name.SetText("")
+			return
+		}
+	} else {
+		path = d.browser.GetCurrentPath()
+		if path == "" {
+			return
+		}
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			d.browser.openDirectory(path)
+			return
+		}
+	}
+	d.finish(path, true)
+}
+
+// finish calls the done function.
+func (d *FileDialog) finish(path string, ok bool) {
+	if d.done != nil {
+		d.done(path, ok)
+	}
+}
+
+// Focus is called when this primitive receives focus.
+func (d *FileDialog) Focus(delegate func(p Primitive)) {
+	d.delegate = delegate
+	for _, element := range d.elements() {
+		if element.HasFocus() {
+			delegate(element)
+			return
+		}
+	}
+	delegate(d.browser)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (d *FileDialog) HasFocus() bool {
+	for _, element := range d.elements() {
+		if element.HasFocus() {
+			return true
+		}
+	}
+	return d.Box.HasFocus()
+}
+
+// Draw draws this primitive onto the screen.
+func (d *FileDialog) Draw(screen tcell.Screen) {
+	// Set the dialog's position and size.
+	screenWidth, screenHeight := screen.Size()
+	width, height := d.width, d.height
+	if width <= 0 {
+		width = screenWidth * 2 / 3
+	}
+	if height <= 0 {
+		height = screenHeight * 2 / 3
+	}
+	if width > screenWidth {
+		width = screenWidth
+	}
+	if height > screenHeight {
+		height = screenHeight
+	}
+	d.SetRect((screenWidth-width)/2, (screenHeight-height)/2, width, height)
+	d.Box.DrawForSubclass(screen, d)
+
+	x, y, width, height := d.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Draw the buttons in the last row, aligned to the right.
+	bottom := y + height - 1
+	cancelWidth := TaggedStringWidth(d.cancel.GetLabel()) + 4
+	confirmWidth := TaggedStringWidth(d.confirm.GetLabel()) + 4
+	d.cancel.SetRect(x+width-cancelWidth, bottom, cancelWidth, 1)
+	d.confirm.SetRect(x+width-cancelWidth-2-confirmWidth, bottom, confirmWidth, 1)
+	d.cancel.Draw(screen)
+	d.confirm.Draw(screen)
+
+	// Draw the name field above the buttons, separated by an empty row.
+	bottom -= 2
+	if d.name != nil {
+		d.name.SetRect(x, bottom, width, 1)
+		d.name.Draw(screen)
+		bottom -= 2
+	}
+
+	// The browser gets the remaining space.
+	if bottom >= y {
+		d.browser.SetRect(x, y, width, bottom-y+1)
+		d.browser.Draw(screen)
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (d *FileDialog) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return d.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		d.delegate = setFocus
+		for _, element := range d.elements() {
+			if element.HasFocus() {
+				if handler := element.InputHandler(); handler != nil {
+					handler(event, setFocus)
+				}
+				return
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (d *FileDialog) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return d.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		d.delegate = setFocus
+		for _, element := range d.elements() {
+			consumed, capture = element.MouseHandler()(action, event, setFocus)
+			if consumed {
+				return
+			}
+		}
+
+		// Clicks inside the dialog must not reach primitives behind it.
+		if d.InRect(event.Position()) {
+			if action == MouseLeftDown {
+				d.Focus(setFocus)
+			}
+			return true, nil
+		}
+		return false, nil
+	})
+}
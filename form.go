@@ -1,7 +1,13 @@
 package tview
 
 import (
+	"errors"
+	"fmt"
 	"image"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2"
 )
@@ -51,11 +57,315 @@ type FormItem interface {
 	SetDisabled(disabled bool) FormItem
 }
 
+// FormValidator checks the text of a form item (see [Form.SetItemValidators])
+// and returns an error describing the problem if the text is not valid. The
+// text of a [Checkbox] is "true" if it is checked and an empty string
+// otherwise, the text of a [DropDown] or [RadioButtons] is the text of its
+// current option (or the comma-separated texts of a drop-down's selected
+// options in multi-select mode).
+type FormValidator func(text string) error
+
+// ValidateRequired returns a validator which rejects empty texts with the
+// given error message (or a default message if it is empty).
+func ValidateRequired(message string) FormValidator {
+	if message == "" {
+		message = "This field is required"
+	}
+	return func(text string) error {
+		if text == "" {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// ValidateRegexp returns a validator which rejects non-empty texts that don't
+// match the given regular expression, with the given error message (or a
+// default message if it is empty).
+func ValidateRegexp(pattern *regexp.Regexp, message string) FormValidator {
+	if message == "" {
+		message = "Invalid format"
+	}
+	return func(text string) error {
+		if text != "" && !pattern.MatchString(text) {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// ValidateMinLength returns a validator which rejects non-empty texts with
+// fewer than the given number of characters (runes), with the given error
+// message (or a default message if it is empty).
+func ValidateMinLength(length int, message string) FormValidator {
+	if message == "" {
+		message = fmt.Sprintf("Must be at least %d characters long", length)
+	}
+	return func(text string) error {
+		if text != "" && utf8.RuneCountInString(text) < length {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// ValidateMaxLength returns a validator which rejects texts with more than the
+// given number of characters (runes), with the given error message (or a
+// default message if it is empty).
+func ValidateMaxLength(length int, message string) FormValidator {
+	if message == "" {
+		message = fmt.Sprintf("Must be at most %d characters long", length)
+	}
+	return func(text string) error {
+		if utf8.RuneCountInString(text) > length {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// FormError is a validation error of a form item, as returned by
+// [Form.Validate].
+type FormError struct {
+	// The index of the form item.
+	Index int
+
+	// The form item.
+	Item FormItem
+
+	// The error returned by the item's first failing validator.
+	Err error
+}
+
+// Error returns the item's label followed by the error message.
+func (e *FormError) Error() string {
+	return e.Item.GetLabel() + ": " + e.Err.Error()
+}
+
+// formItemOptions holds form-specific settings and state of a form item.
+type formItemOptions struct {
+	// The validators of the item's text.
+	validators []FormValidator
+
+	// The error of the last validation or nil if the item was valid.
+	err error
+
+	// The column of the item in multi-column layouts, or -1 if it was not set
+	// explicitly.
+	column int
+
+	// Whether the item is hidden.
+	hidden bool
+
+	// Whether the item was disabled with [Form.SetItemEnabled].
+	disabled bool
+
+	// The width of the item's label column or -1 if the form's uniform label
+	// width is used.
+	labelWidth int
+
+	// Whether the item spans the full width of the form.
+	fullWidth bool
+
+	// The help text shown while the item has focus.
+	help string
+}
+
+// formItemText returns the text of a form item for validation purposes.
+func formItemText(item FormItem) string {
+	switch item := item.(type) {
+	case *Checkbox:
+		if item.IsChecked() {
+			return "true"
+		}
+		return ""
+	case *DropDown:
+		if item.list.multiSelect {
+			_, texts := item.GetSelectedOptions()
+			return strings.Join(texts, ", ")
+		}
+		_, text := item.GetCurrentOption()
+		return text
+	case *RadioButtons:
+		_, text := item.GetCurrentOption()
+		return text
+	case *Slider:
+		return item.valueText(item.GetValue())
+	case interface{ GetText() string }:
+		return item.GetText()
+	}
+	return ""
+}
+
+// formSection is a form item which starts a section of a form. It is shown as
+// a header which may be collapsed to hide all items up to the next section.
+type formSection struct {
+	*Box
+
+	// The section's title.
+	title string
+
+	// The style of the header.
+	style tcell.Style
+
+	// Whether the section is collapsed.
+	collapsed bool
+
+	// Whether the header is disabled, i.e. cannot be collapsed or expanded by
+	// the user.
+	disabled bool
+
+	// An optional function which is called when the user leaves the header.
+	finished func(tcell.Key)
+}
+
+// newFormSection returns a new section header with the given title.
+func newFormSection(title string) *formSection {
+	return &formSection{
+		Box:   NewBox(),
+		title: title,
+	}
+}
+
+// GetLabel returns an empty string as section headers have no label.
+func (s *formSection) GetLabel() string {
+	return ""
+}
+
+// SetFormAttributes sets the header's background color. All other attributes
+// are ignored.
+func (s *formSection) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
+	s.backgroundColor = bgColor
+	return s
+}
+
+// GetFieldWidth returns the width of the header's title.
+func (s *formSection) GetFieldWidth() int {
+	return TaggedStringWidth(s.title) + 2
+}
+
+// GetFieldHeight returns the header's height.
+func (s *formSection) GetFieldHeight() int {
+	return 1
+}
+
+// SetFinishedFunc sets the function which is called when the user leaves the
+// header.
+func (s *formSection) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
+	s.finished = handler
+	return s
+}
+
+// SetDisabled sets whether the header can be collapsed or expanded by the
+// user.
+func (s *formSection) SetDisabled(disabled bool) FormItem {
+	s.disabled = disabled
+	if s.finished != nil {
+		s.finished(-1)
+	}
+	return s
+}
+
+// Focus is called when this primitive receives focus.
+func (s *formSection) Focus(delegate func(p Primitive)) {
+	// Disabled headers are skipped in forms.
+	if s.finished != nil && s.disabled {
+		s.finished(-1)
+		return
+	}
+
+	s.Box.Focus(delegate)
+}
+
+// Draw draws the header, followed by a horizontal line.
+func (s *formSection) Draw(screen tcell.Screen) {
+	s.Box.DrawForSubclass(screen, s)
+
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	style := s.style
+	if s.HasFocus() {
+		style = style.Reverse(true)
+	}
+	_, background, _ := style.Decompose()
+	maintainBackground := background == tcell.ColorDefault
+
+	expander := "▾ "
+	if s.collapsed {
+		expander = "▸ "
+	}
+	_, _, printed := printWithStyle(screen, expander+s.title, x, y, 0, width, AlignLeft, style, maintainBackground)
+	if printed < width {
+		printed++
+		line := strings.Repeat(string(Borders.Horizontal), width-printed)
+		printWithStyle(screen, line, x+printed, y, 0, width-printed, AlignLeft, s.style, true)
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (s *formSection) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return s.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		switch key := event.Key(); key {
+		case tcell.KeyRune, tcell.KeyEnter: // Collapse or expand.
+			if s.disabled || key == tcell.KeyRune && event.Rune() != ' ' {
+				break
+			}
+			s.collapsed = !s.collapsed
+		case tcell.KeyTab, tcell.KeyBacktab, tcell.KeyEscape: // We're done.
+			if s.finished != nil {
+				s.finished(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (s *formSection) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return s.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if !s.InRect(event.Position()) {
+			return false, nil
+		}
+		switch action {
+		case MouseLeftDown:
+			setFocus(s)
+			consumed = true
+		case MouseLeftClick:
+			if !s.disabled {
+				s.collapsed = !s.collapsed
+			}
+			consumed = true
+		}
+		return
+	})
+}
+
 // Form allows you to combine multiple one-line form elements into a vertical
 // or horizontal layout. Form elements include types such as InputField or
 // Checkbox. These elements can be optionally followed by one or more buttons
 // for which you can define form-wide actions (e.g. Save, Clear, Cancel).
 //
+// If the form's elements don't fit into the form's area, the form scrolls
+// vertically to keep the focused element visible. The user may also scroll
+// with the mouse wheel or, if enabled with ShowScrollBar(), the scroll bar.
+//
+// In vertical layouts, items may be arranged in multiple columns, see
+// [Form.SetColumns].
+//
+// Items may be grouped into sections which the user can collapse and expand,
+// see [Form.AddSection].
+//
+// Items may be hidden or disabled, see [Form.SetItemVisible] and
+// [Form.SetItemEnabled]. Use [Form.SetConditionFunc] to update them whenever
+// the user changes the value of a form item.
+//
+// Form items may be validated (see [Form.SetItemValidators]). Items are
+// validated when the user leaves them and when [Form.Validate] is called, for
+// example in a "Save" button's callback. Error messages are shown below the
+// offending items. Items may also have help texts which are shown while they
+// have focus, see [Form.SetItemHelp].
+//
 // See https://github.com/rivo/tview/wiki/Form for an example.
 type Form struct {
 	*Box
@@ -76,6 +386,9 @@ type Form struct {
 	// The number of empty cells between items.
 	itemPadding int
 
+	// The number of item columns in vertical layouts.
+	columns int
+
 	// The index of the item or button which has focus. (Items are counted first,
 	// buttons are counted last.) This is only used when the form itself receives
 	// focus so that the last element that had focus keeps it.
@@ -105,6 +418,51 @@ type Form struct {
 
 	// An optional function which is called when the user hits Escape.
 	cancel func()
+
+	// Additional settings and state of form items.
+	itemOptions map[FormItem]*formItemOptions
+
+	// The style of validation error messages.
+	errorStyle tcell.Style
+
+	// An optional function which updates the visibility and enablement of
+	// items when their values change.
+	condition func(form *Form)
+
+	// The item texts at the time the condition function was last called.
+	conditionTexts []string
+
+	// The number of rows the form is scrolled down by.
+	scrollOffset int
+
+	// Whether the form scrolls to keep the focused element visible. This is
+	// turned off when the user scrolls with the mouse.
+	followFocus bool
+
+	// Whether a scroll bar is shown when the form's elements don't fit.
+	showScrollBar bool
+
+	// The style of the scroll bar.
+	scrollBarStyle tcell.Style
+
+	// The scroll bar's position as of the last call to Draw().
+	bar scrollBar
+
+	// Whether the user is dragging the scroll bar's thumb.
+	scrollBarDragging bool
+
+	// The style of help texts.
+	helpStyle tcell.Style
+
+	// An optional text view which shows the help text of the focused item
+	// instead of showing it below the item.
+	helpView *TextView
+
+	// The help text last written to the help text view.
+	helpShown string
+
+	// The style of section headers.
+	sectionStyle tcell.Style
 }
 
 // NewForm returns a new form.
@@ -114,6 +472,7 @@ func NewForm() *Form {
 	f := &Form{
 		Box:                  box,
 		itemPadding:          1,
+		columns:              1,
 		labelColor:           Styles.SecondaryTextColor,
 		fieldBackgroundColor: Styles.ContrastBackgroundColor,
 		fieldTextColor:       Styles.PrimaryTextColor,
@@ -121,6 +480,12 @@ func NewForm() *Form {
 		buttonActivatedStyle: tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.ContrastBackgroundColor),
 		buttonDisabledStyle:  tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.ContrastSecondaryTextColor),
 		lastFinishedKey:      tcell.KeyTab, // To skip over inactive elements at the beginning of the form.
+		itemOptions:          make(map[FormItem]*formItemOptions),
+		errorStyle:           tcell.StyleDefault.Foreground(tcell.ColorRed),
+		followFocus:          true,
+		scrollBarStyle:       tcell.StyleDefault.Foreground(Styles.GraphicsColor),
+		sectionStyle:         tcell.StyleDefault.Foreground(Styles.TitleColor).Attributes(tcell.AttrBold),
+		helpStyle:            tcell.StyleDefault.Foreground(Styles.TertiaryTextColor),
 	}
 
 	return f
@@ -143,6 +508,172 @@ func (f *Form) SetHorizontal(horizontal bool) *Form {
 	return f
 }
 
+// SetColumns sets the number of columns into which form items are arranged in
+// vertical layouts. The available width is divided evenly among the columns
+// and labels align within each column. If no item has been assigned to a
+// column with [Form.SetItemColumn], the items are distributed evenly across
+// the columns, in the order they were added. Otherwise, items which were not
+// assigned to a column are placed in the column of the item before them (or
+// the first column). Buttons are placed below the longest column.
+//
+// The Tab key moves through the items of the first column from top to bottom,
+// then through those of the second column, and so on, and then through the
+// buttons. This function has no effect on horizontal layouts.
+func (f *Form) SetColumns(columns int) *Form {
+	if columns < 1 {
+		columns = 1
+	}
+	f.columns = columns
+	return f
+}
+
+// SetItemColumn sets the column (starting at 0) of the form item at the given
+// index in multi-column layouts (see [Form.SetColumns]).
+func (f *Form) SetItemColumn(index, column int) *Form {
+	if column < 0 {
+		column = 0
+	}
+	f.options(f.items[index]).column = column
+	return f
+}
+
+// SetItemLabelWidth overrides the width of the label column (including the
+// space between the label and the field) of the form item at the given index.
+// By default, the labels of all items (of a column) are given the same width
+// so that the fields align vertically. A width of 0 gives the item its label's
+// own width plus one space, or no label column at all if the item has no
+// label, i.e. the field starts at the left edge of the form. A negative width
+// restores the uniform label width.
+//
+// Items with an overridden label width are not considered when determining
+// the uniform label width.
+func (f *Form) SetItemLabelWidth(index, width int) *Form {
+	if width < 0 {
+		width = -1
+	}
+	f.options(f.items[index]).labelWidth = width
+	return f
+}
+
+// SetItemFullWidth sets whether the form item at the given index spans the
+// full width of the form, for example for a wide [TextArea]. In multi-column
+// layouts, such items are placed below all previous items, spanning all
+// columns. In horizontal layouts, they are placed on a line of their own.
+//
+// Unless overridden with [Form.SetItemLabelWidth], full-width items use their
+// label's own width (see there) instead of the uniform label width.
+func (f *Form) SetItemFullWidth(index int, fullWidth bool) *Form {
+	f.options(f.items[index]).fullWidth = fullWidth
+	return f
+}
+
+// itemColumns returns the column of each form item (see [Form.SetColumns]).
+func (f *Form) itemColumns() []int {
+	columns := make([]int, len(f.items))
+	if f.columns <= 1 || f.horizontal {
+		return columns
+	}
+
+	// Are there any explicit columns?
+	var explicit bool
+	for _, item := range f.items {
+		if options, ok := f.itemOptions[item]; ok && options.column >= 0 {
+			explicit = true
+			break
+		}
+	}
+
+	// Distribute evenly.
+	if !explicit {
+		perColumn := (len(f.items) + f.columns - 1) / f.columns
+		for index := range columns {
+			columns[index] = index / perColumn
+		}
+		return columns
+	}
+
+	// Follow explicit columns.
+	var column int
+	for index, item := range f.items {
+		if options, ok := f.itemOptions[item]; ok && options.column >= 0 {
+			column = options.column
+			if column >= f.columns {
+				column = f.columns - 1
+			}
+		}
+		columns[index] = column
+	}
+	return columns
+}
+
+// focusOrder returns the indices of all items and buttons (items counted
+// first, buttons counted last) in the order in which they receive focus when
+// the user presses Tab.
+func (f *Form) focusOrder() []int {
+	order := make([]int, 0, len(f.items)+len(f.buttons))
+	columns := f.itemColumns()
+	for column := 0; column < f.columns; column++ {
+		for index := range f.items {
+			if columns[index] == column {
+				order = append(order, index)
+			}
+		}
+	}
+	for index := range f.buttons {
+		order = append(order, len(f.items)+index)
+	}
+	return order
+}
+
+// nextElement returns the index of the element (items counted first, buttons
+// counted last) which receives focus after ("forward" is true) or before
+// ("forward" is false) the element with the given index, wrapping around at
+// the end and at the beginning.
+func (f *Form) nextElement(index int, forward bool) int {
+	order := f.focusOrder()
+	for position, element := range order {
+		if element != index {
+			continue
+		}
+		if forward {
+			return order[(position+1)%len(order)]
+		}
+		return order[(position+len(order)-1)%len(order)]
+	}
+	if forward {
+		return index + 1
+	}
+	return index - 1
+}
+
+// ShowScrollBar sets whether a vertical scroll bar is shown on the right side
+// of the form when its elements don't fit into its area. It is not shown by
+// default.
+func (f *Form) ShowScrollBar(show bool) *Form {
+	f.showScrollBar = show
+	return f
+}
+
+// SetScrollBarStyle sets the style of the scroll bar.
+func (f *Form) SetScrollBarStyle(style tcell.Style) *Form {
+	f.scrollBarStyle = style
+	return f
+}
+
+// GetScrollOffset returns the number of rows the form is scrolled down by.
+func (f *Form) GetScrollOffset() int {
+	return f.scrollOffset
+}
+
+// SetScrollOffset scrolls the form down by the given number of rows. The
+// form no longer scrolls to keep the focused element visible until the focus
+// changes or the user presses a key.
+func (f *Form) SetScrollOffset(offset int) *Form {
+	f.scrollOffset = offset
+	f.followFocus = false
+	return f
+}
+
 // SetLabelColor sets the color of the labels.
 func (f *Form) SetLabelColor(color tcell.Color) *Form {
 	f.labelColor = color
@@ -202,6 +733,279 @@ func (f *Form) SetButtonDisabledStyle(style tcell.Style) *Form {
 	return f
 }
 
+// SetErrorStyle sets the style of the validation error messages shown below
+// invalid form items.
+func (f *Form) SetErrorStyle(style tcell.Style) *Form {
+	f.errorStyle = style
+	return f
+}
+
+// SetSectionStyle sets the style of section headers (see [Form.AddSection]).
+// If the style's background color is the default color, the form's background
+// color is used. Headers which have focus are shown in reverse.
+func (f *Form) SetSectionStyle(style tcell.Style) *Form {
+	f.sectionStyle = style
+	return f
+}
+
+// options returns the additional settings of the given form item, creating
+// them if necessary.
+func (f *Form) options(item FormItem) *formItemOptions {
+	options, ok := f.itemOptions[item]
+	if !ok {
+		options = &formItemOptions{column: -1, labelWidth: -1}
+		f.itemOptions[item] = options
+	}
+	return options
+}
+
+// SetItemVisible sets whether the form item at the given index is visible.
+// Hidden items take up no space, are skipped when the user navigates the
+// form, and are not validated by [Form.Validate].
+func (f *Form) SetItemVisible(index int, visible bool) *Form {
+	f.options(f.items[index]).hidden = !visible
+	return f
+}
+
+// SetItemVisibleByLabel is like [Form.SetItemVisible] but refers to the first
+// form item with the given label. If there is no such item, nothing happens.
+func (f *Form) SetItemVisibleByLabel(label string, visible bool) *Form {
+	if index := f.GetFormItemIndex(label); index >= 0 {
+		f.SetItemVisible(index, visible)
+	}
+	return f
+}
+
+// IsItemVisible returns whether the form item at the given index is visible.
+func (f *Form) IsItemVisible(index int) bool {
+	return !f.isHidden(index)
+}
+
+// SetItemEnabled sets whether the form item at the given index is enabled. This
+// is the same as calling the item's SetDisabled() function except that the
+// form remembers the state: Disabled items are not validated by
+// [Form.Validate].
+func (f *Form) SetItemEnabled(index int, enabled bool) *Form {
+	item := f.items[index]
+	f.options(item).disabled = !enabled
+	item.SetDisabled(!enabled)
+	return f
+}
+
+// SetItemEnabledByLabel is like [Form.SetItemEnabled] but refers to the first
+// form item with the given label. If there is no such item, nothing happens.
+func (f *Form) SetItemEnabledByLabel(label string, enabled bool) *Form {
+	if index := f.GetFormItemIndex(label); index >= 0 {
+		f.SetItemEnabled(index, enabled)
+	}
+	return f
+}
+
+// IsItemEnabled returns whether the form item at the given index is enabled
+// (as set with [Form.SetItemEnabled]).
+func (f *Form) IsItemEnabled(index int) bool {
+	if options, ok := f.itemOptions[f.items[index]]; ok {
+		return !options.disabled
+	}
+	return true
+}
+
+// SetConditionFunc sets a function which is called whenever the value of any
+// form item has changed (and once, when the form is first drawn), typically to
+// show, hide, enable, or disable items depending on the values of other items.
+// For example:
+//
+//	form.SetConditionFunc(func(form *tview.Form) {
+//	  subscribe := form.GetFormItemByLabel("Subscribe").(*tview.Checkbox)
+//	  form.SetItemVisibleByLabel("Email", subscribe.IsChecked())
+//	})
+//
+// Changes are detected after each key or mouse event handled by the form and
+// when the form is drawn. If the item which has focus is hidden as a result of
+// a user action, the focus moves on to the next item.
+func (f *Form) SetConditionFunc(handler func(form *Form)) *Form {
+	f.condition = handler
+	f.conditionTexts = nil
+	return f
+}
+
+// evaluateConditions calls the condition function if the value of any item
+// has changed since it was last called. It returns whether the function was
+// called.
+func (f *Form) evaluateConditions() bool {
+	if f.condition == nil {
+		return false
+	}
+	changed := f.conditionTexts == nil || len(f.conditionTexts) != len(f.items)
+	texts := make([]string, len(f.items))
+	for index, item := range f.items {
+		texts[index] = formItemText(item)
+		if !changed && texts[index] != f.conditionTexts[index] {
+			changed = true
+		}
+	}
+	if !changed {
+		return false
+	}
+	f.conditionTexts = texts
+	f.condition(f)
+	return true
+}
+
+// updateConditions calls the condition function if the value of any item has
+// changed and moves the focus on if the focused item was hidden as a result.
+func (f *Form) updateConditions(setFocus func(p Primitive)) {
+	if !f.evaluateConditions() {
+		return
+	}
+	if index := f.focusIndex(); index >= 0 && index < len(f.items) && f.isHidden(index) {
+		f.focusedElement = index
+		f.Focus(setFocus)
+	}
+}
+
+// isHidden returns whether the form item at the given index is hidden, either
+// explicitly or because its section is collapsed or hidden.
+func (f *Form) isHidden(index int) bool {
+	item := f.items[index]
+	if options, ok := f.itemOptions[item]; ok && options.hidden {
+		return true
+	}
+	if _, ok := item.(*formSection); ok {
+		return false
+	}
+	if sectionIndex := f.sectionIndex(index); sectionIndex >= 0 {
+		return f.items[sectionIndex].(*formSection).collapsed || f.isHidden(sectionIndex)
+	}
+	return false
+}
+
+// sectionIndex returns the index of the section header the form item at the
+// given index belongs to or -1 if it does not belong to any section.
+func (f *Form) sectionIndex(index int) int {
+	for index--; index >= 0; index-- {
+		if _, ok := f.items[index].(*formSection); ok {
+			return index
+		}
+	}
+	return -1
+}
+
+// SetItemHelp sets a help text for the form item at the given index which is
+// shown while the item has focus, giving the user a hint about what to enter.
+// By default, the help text is shown below the item. Use [Form.SetHelpTextView]
+// to show it elsewhere. An empty text removes the help text.
+func (f *Form) SetItemHelp(index int, text string) *Form {
+	f.options(f.items[index]).help = text
+	return f
+}
+
+// GetItemHelp returns the help text of the form item at the given index.
+func (f *Form) GetItemHelp(index int) string {
+	if options, ok := f.itemOptions[f.items[index]]; ok {
+		return options.help
+	}
+	return ""
+}
+
+// SetHelpStyle sets the style of help texts shown below form items (see
+// [Form.SetItemHelp]).
+func (f *Form) SetHelpStyle(style tcell.Style) *Form {
+	f.helpStyle = style
+	return f
+}
+
+// SetHelpTextView sets a text view, for example a status line, in which the
+// help text of the focused item is shown instead of below the item (see
+// [Form.SetItemHelp]). The form replaces the text view's text whenever the
+// focus moves to another item. Set to nil to show help texts below the items
+// again.
+func (f *Form) SetHelpTextView(view *TextView) *Form {
+	f.helpView = view
+	f.helpShown = ""
+	return f
+}
+
+// updateHelp writes the help text of the focused item to the help text view,
+// if there is one.
+func (f *Form) updateHelp() {
+	if f.helpView == nil {
+		return
+	}
+	var text string
+	if index := f.focusIndex(); index >= 0 && index < len(f.items) {
+		text = f.GetItemHelp(index)
+	}
+	if text != f.helpShown {
+		f.helpView.SetText(text)
+		f.helpShown = text
+	}
+}
+
+// SetItemValidators sets the validators of the form item at the given index,
+// replacing any previous validators. When the item is validated, the
+// validators are called in the given order until one of them returns an
+// error. For example:
+//
+//	form.SetItemValidators(0,
+//	  tview.ValidateRequired(""),
+//	  tview.ValidateRegexp(regexp.MustCompile(`^\S+@\S+$`), "Not an email address"))
+func (f *Form) SetItemValidators(index int, validators ...FormValidator) *Form {
+	options := f.options(f.items[index])
+	options.validators = validators
+	options.err = nil
+	return f
+}
+
+// ValidateItem validates the form item at the given index and returns the
+// error of the first failing validator or nil if the item is valid. The error
+// is shown below the item until it is validated again.
+func (f *Form) ValidateItem(index int) error {
+	item := f.items[index]
+	options, ok := f.itemOptions[item]
+	if !ok {
+		return nil
+	}
+	options.err = nil
+	text := formItemText(item)
+	for _, validator := range options.validators {
+		if err := validator(text); err != nil {
+			options.err = err
+			break
+		}
+	}
+	return options.err
+}
+
+// Validate validates all visible, enabled form items (see [Form.ValidateItem])
+// and returns the errors of all invalid items, in the order of the items. If all items are
+// valid, nil is returned. Collapsed sections which contain invalid items are
+// expanded so that the error messages can be seen.
+func (f *Form) Validate() (errs []*FormError) {
+	for index, item := range f.items {
+		if options, ok := f.itemOptions[item]; ok && (options.hidden || options.disabled) {
+			options.err = nil
+			continue
+		}
+		if err := f.ValidateItem(index); err != nil {
+			errs = append(errs, &FormError{Index: index, Item: item, Err: err})
+			if sectionIndex := f.sectionIndex(index); sectionIndex >= 0 {
+				f.items[sectionIndex].(*formSection).collapsed = false
+			}
+		}
+	}
+	return
+}
+
+// GetItemError returns the error of the last validation of the form item at
+// the given index or nil if the item was valid or has not been validated yet.
+func (f *Form) GetItemError(index int) error {
+	if options, ok := f.itemOptions[f.items[index]]; ok {
+		return options.err
+	}
+	return nil
+}
+
 // SetFocus shifts the focus to the form element with the given index, counting
 // non-button items first and buttons last. Note that this index is only used
 // when the form itself receives focus.
@@ -350,6 +1154,52 @@ func (f *Form) AddCheckbox(label string, checked bool, changed func(checked bool
 	return f
 }
 
+// AddRadioButtons adds a group of radio buttons to the form. It has a label,
+// the options, the index of the initially selected option (-1 for none), and
+// an (optional) callback function which is invoked when the user selects an
+// option.
+func (f *Form) AddRadioButtons(label string, options []string, initialOption int, selected func(index int, option string)) *Form {
+	f.items = append(f.items, NewRadioButtons(options...).
+		SetLabel(label).
+		SetCurrentOption(initialOption).
+		SetSelectedFunc(selected))
+	return f
+}
+
+// AddSlider adds a slider to the form. It has a label, the range of values
+// (see [Slider.SetRange]), the initial value, and an (optional) callback
+// function which is invoked when the value was changed.
+func (f *Form) AddSlider(label string, min, max, step, value float64, changed func(value float64)) *Form {
+	f.items = append(f.items, NewSlider().
+		SetLabel(label).
+		SetRange(min, max, step).
+		SetValue(value).
+		SetChangedFunc(changed))
+	return f
+}
+
+// AddDatePicker adds a date picker to the form. It has a label, an initial
+// date (the zero time for none), and an (optional) callback function which is
+// invoked when the date was changed.
+func (f *Form) AddDatePicker(label string, date time.Time, changed func(date time.Time)) *Form {
+	f.items = append(f.items, NewDatePicker().
+		SetLabel(label).
+		SetDate(date).
+		SetChangedFunc(changed))
+	return f
+}
+
+// AddTimePicker adds a time picker for a time of day to the form. It has a
+// label, the initial hour and minute, and an (optional) callback function which
+// is invoked when the time was changed.
+func (f *Form) AddTimePicker(label string, hour, minute int, changed func(value time.Duration)) *Form {
+	f.items = append(f.items, NewTimePicker().
+		SetLabel(label).
+		SetTime(hour, minute, 0).
+		SetChangedFunc(changed))
+	return f
+}
+
 // AddImage adds an image to the form. It has a label and the image will fit in
 // the specified width and height (its aspect ratio is preserved). See
 // [Image.SetColors] for a description of the "colors" parameter. Images are not
@@ -365,6 +1215,40 @@ func (f *Form) AddImage(label string, image image.Image, width, height, colors i
 	return f
 }
 
+// AddSection adds a section header with the given title to the form. All items
+// added after it, up to the next section header, belong to this section. The
+// user may collapse and expand the section by selecting its header with Enter
+// or Space or by clicking on it. Items of collapsed sections are not shown and
+// are skipped when the user navigates the form. They are still validated by
+// [Form.Validate], however.
+//
+// Section headers count as form items, i.e. they have an index (see
+// [Form.GetFormItem]), but they have no label.
+func (f *Form) AddSection(title string) *Form {
+	f.items = append(f.items, newFormSection(title))
+	return f
+}
+
+// SetSectionCollapsed collapses or expands the section whose header is the
+// form item at the given index. If the item is not a section header, nothing
+// happens.
+func (f *Form) SetSectionCollapsed(index int, collapsed bool) *Form {
+	if section, ok := f.items[index].(*formSection); ok {
+		section.collapsed = collapsed
+	}
+	return f
+}
+
+// IsSectionCollapsed returns whether the section whose header is the form item
+// at the given index is collapsed. It returns false if the item is not a
+// section header.
+func (f *Form) IsSectionCollapsed(index int) bool {
+	if section, ok := f.items[index].(*formSection); ok {
+		return section.collapsed
+	}
+	return false
+}
+
 // AddButton adds a new button to the form. The "selected" function is called
 // when the user selects this button. It may be nil.
 func (f *Form) AddButton(label string, selected func()) *Form {
@@ -407,6 +1291,7 @@ func (f *Form) GetButtonIndex(label string) int {
 // specified.
 func (f *Form) Clear(includeButtons bool) *Form {
 	f.items = nil
+	f.itemOptions = make(map[FormItem]*formItemOptions)
 	if includeButtons {
 		f.ClearButtons()
 	}
@@ -452,6 +1337,7 @@ func (f *Form) GetFormItem(index int) FormItem {
 // index 0. Elements are referenced in the order they were added. Buttons are
 // not included.
 func (f *Form) RemoveFormItem(index int) *Form {
+	delete(f.itemOptions, f.items[index])
 	f.items = append(f.items[:index], f.items[index+1:]...)
 	return f
 }
@@ -500,42 +1386,66 @@ func (f *Form) SetCancelFunc(callback func()) *Form {
 	return f
 }
 
-// Draw draws this primitive onto the screen.
-func (f *Form) Draw(screen tcell.Screen) {
-	f.Box.DrawForSubclass(screen, f)
-
-	// Determine the actual item that has focus.
-	if index := f.focusIndex(); index >= 0 {
-		f.focusedElement = index
-	}
+// formPosition is the position of a form item or button on screen.
+type formPosition struct{ x, y, width, height, labelWidth int }
 
-	// Determine the dimensions.
-	x, y, width, height := f.GetInnerRect()
-	topLimit := y
-	bottomLimit := y + height
+// layout calculates the positions of all form items and buttons (items counted
+// first, buttons counted last) within the given area, starting at the given
+// coordinates. It also returns the position of the focused element (or a zero
+// position if there is none) and the y-coordinate below the lowest element.
+func (f *Form) layout(x, y, width int) (positions []formPosition, focused formPosition, bottom int) {
 	rightLimit := x + width
 	startX := x
+	bottom = y
 
-	// Find the longest label.
-	var maxLabelWidth int
-	for _, item := range f.items {
+	// Determine the columns.
+	columns := f.itemColumns()
+	columnCount := 1
+	if !f.horizontal {
+		columnCount = f.columns
+	}
+	const columnGap = 2
+	columnWidth := (width - (columnCount-1)*columnGap) / columnCount
+	columnYs := make([]int, columnCount)
+	for column := range columnYs {
+		columnYs[column] = y
+	}
+
+	// Find the longest label of each column.
+	maxLabelWidths := make([]int, columnCount)
+	for index, item := range f.items {
+		if options, ok := f.itemOptions[item]; ok && (options.labelWidth >= 0 || options.fullWidth) {
+			continue // These items don't use the uniform label width.
+		}
 		labelWidth := TaggedStringWidth(item.GetLabel())
-		if labelWidth > maxLabelWidth {
-			maxLabelWidth = labelWidth
+		if labelWidth > maxLabelWidths[columns[index]] {
+			maxLabelWidths[columns[index]] = labelWidth
 		}
 	}
-	maxLabelWidth++ // Add one space.
+	for column := range maxLabelWidths {
+		maxLabelWidths[column]++ // Add one space.
+	}
 
 	// Calculate positions of form items.
-	type position struct{ x, y, width, height int }
-	positions := make([]position, len(f.items)+len(f.buttons))
-	var (
-		focusedPosition position
-		lineHeight      = 1
-	)
+	positions = make([]formPosition, len(f.items)+len(f.buttons))
+	lineHeight := 1
 	for index, item := range f.items {
+		if f.isHidden(index) {
+			continue
+		}
+
 		// Calculate the space needed.
 		labelWidth := TaggedStringWidth(item.GetLabel())
+		labelOverride, fullWidth := -1, false
+		if options, ok := f.itemOptions[item]; ok {
+			labelOverride, fullWidth = options.labelWidth, options.fullWidth
+		}
+		if labelOverride < 0 && fullWidth {
+			labelOverride = 0
+		}
+		if labelOverride == 0 && labelWidth > 0 {
+			labelOverride = labelWidth + 1 // Add one space.
+		}
 		var itemWidth int
 		if f.horizontal {
 			fieldWidth := item.GetFieldWidth()
@@ -543,33 +1453,70 @@ func (f *Form) Draw(screen tcell.Screen) {
 				fieldWidth = DefaultFormFieldWidth
 			}
 			labelWidth++
+			if labelOverride >= 0 {
+				labelWidth = labelOverride
+			}
 			itemWidth = labelWidth + fieldWidth
+			if fullWidth {
+				itemWidth = width
+			}
+		} else if fullWidth {
+			// Place the item below all columns.
+			labelWidth = labelOverride
+			x, y = startX, columnYs[0]
+			for _, columnY := range columnYs {
+				if columnY > y {
+					y = columnY
+				}
+			}
+			itemWidth = width
 		} else {
 			// We want all fields to align vertically.
-			labelWidth = maxLabelWidth
-			itemWidth = width
+			column := columns[index]
+			labelWidth = maxLabelWidths[column]
+			if labelOverride >= 0 {
+				labelWidth = labelOverride
+			}
+			x = startX + column*(columnWidth+columnGap)
+			y = columnYs[column]
+			itemWidth = columnWidth
+			if column == columnCount-1 {
+				itemWidth = rightLimit - x
+			}
 		}
 		itemHeight := item.GetFieldHeight()
 		if itemHeight <= 0 {
 			itemHeight = DefaultFormFieldHeight
 		}
+		var messageHeight int // Error messages and help texts go below the item.
+		if options, ok := f.itemOptions[item]; ok {
+			if options.err != nil {
+				messageHeight++
+			}
+			if options.help != "" && f.helpView == nil && item.HasFocus() {
+				messageHeight++
+			}
+		}
 
 		// Advance to next line if there is no space.
-		if f.horizontal && x+labelWidth+1 >= rightLimit {
+		if f.horizontal && (x+labelWidth+1 >= rightLimit || fullWidth && x > startX) {
 			x = startX
 			y += lineHeight + 1
 			lineHeight = itemHeight
 		}
 
 		// Update line height.
-		if itemHeight > lineHeight {
-			lineHeight = itemHeight
+		if itemHeight+messageHeight > lineHeight {
+			lineHeight = itemHeight + messageHeight
 		}
 
 		// Adjust the item's attributes.
 		if x+itemWidth >= rightLimit {
 			itemWidth = rightLimit - x
 		}
+		if section, ok := item.(*formSection); ok {
+			section.style = f.sectionStyle
+		}
 		item.SetFormAttributes(
 			labelWidth,
 			f.labelColor,
@@ -583,15 +1530,34 @@ func (f *Form) Draw(screen tcell.Screen) {
 		positions[index].y = y
 		positions[index].width = itemWidth
 		positions[index].height = itemHeight
+		positions[index].labelWidth = labelWidth
 		if item.HasFocus() {
-			focusedPosition = positions[index]
+			focused = positions[index]
+			focused.height += messageHeight
+		}
+		if y+itemHeight+messageHeight > bottom {
+			bottom = y + itemHeight + messageHeight
 		}
 
 		// Advance to next item.
 		if f.horizontal {
 			x += itemWidth + f.itemPadding
+		} else if fullWidth {
+			for column := range columnYs {
+				columnYs[column] = y + itemHeight + messageHeight + f.itemPadding
+			}
 		} else {
-			y += itemHeight + f.itemPadding
+			columnYs[columns[index]] += itemHeight + messageHeight + f.itemPadding
+		}
+	}
+
+	// In vertical layouts, buttons go below the longest column.
+	if !f.horizontal {
+		x = startX
+		for _, columnY := range columnYs {
+			if columnY > y {
+				y = columnY
+			}
 		}
 	}
 
@@ -649,28 +1615,93 @@ func (f *Form) Draw(screen tcell.Screen) {
 		positions[buttonIndex].height = 1
 
 		if button.HasFocus() {
-			focusedPosition = positions[buttonIndex]
+			focused = positions[buttonIndex]
+		}
+		if y+1 > bottom {
+			bottom = y + 1
 		}
 
 		x += buttonWidth + 1
 	}
 
-	// Determine vertical offset based on the position of the focused item.
-	var offset int
-	if focusedPosition.y+focusedPosition.height > bottomLimit {
-		offset = focusedPosition.y + focusedPosition.height - bottomLimit
-		if focusedPosition.y-offset < topLimit {
-			offset = focusedPosition.y - topLimit
+	return
+}
+
+// Draw draws this primitive onto the screen.
+func (f *Form) Draw(screen tcell.Screen) {
+	f.Box.DrawForSubclass(screen, f)
+	f.evaluateConditions()
+	f.updateHelp()
+
+	// Determine the actual item that has focus.
+	if index := f.focusIndex(); index >= 0 {
+		f.focusedElement = index
+	}
+
+	// Determine the dimensions.
+	x, y, width, height := f.GetInnerRect()
+	topLimit := y
+	bottomLimit := y + height
+	rightLimit := x + width
+
+	// Calculate positions, making room for the scroll bar if needed.
+	positions, focused, bottom := f.layout(x, y, width)
+	f.bar = scrollBar{}
+	if f.showScrollBar && bottom-topLimit > height && width > 1 && height > 0 {
+		width--
+		rightLimit--
+		positions, focused, bottom = f.layout(x, y, width)
+		f.bar = scrollBar{x: rightLimit, y: topLimit, length: height, total: bottom - topLimit, visible: height, vertical: true}
+	}
+
+	// Keep the focused element visible.
+	if f.followFocus && focused.height > 0 {
+		if focused.y+focused.height-f.scrollOffset > bottomLimit {
+			f.scrollOffset = focused.y + focused.height - bottomLimit
 		}
+		if focused.y-f.scrollOffset < topLimit {
+			f.scrollOffset = focused.y - topLimit
+		}
+	}
+	if f.scrollOffset > bottom-bottomLimit {
+		f.scrollOffset = bottom - bottomLimit
+	}
+	if f.scrollOffset < 0 {
+		f.scrollOffset = 0
+	}
+	offset := f.scrollOffset
+
+	// Draw the scroll bar.
+	if f.bar.length > 0 {
+		f.bar.draw(screen, offset, f.scrollBarStyle)
 	}
 
 	// Draw items.
 	for index, item := range f.items {
+		if f.isHidden(index) {
+			item.SetRect(0, 0, 0, 0)
+			continue
+		}
+
 		// Set position.
 		y := positions[index].y - offset
 		height := positions[index].height
 		item.SetRect(positions[index].x, y, positions[index].width, height)
 
+		// Draw the error message and the help text.
+		if options, ok := f.itemOptions[item]; ok {
+			messageX, messageY := positions[index].x+positions[index].labelWidth, y+height
+			if options.err != nil {
+				if messageY >= topLimit && messageY < bottomLimit {
+					printWithStyle(screen, Escape(options.err.Error()), messageX, messageY, 0, rightLimit-messageX, AlignLeft, f.errorStyle, true)
+				}
+				messageY++
+			}
+			if options.help != "" && f.helpView == nil && item.HasFocus() && messageY >= topLimit && messageY < bottomLimit {
+				printWithStyle(screen, options.help, messageX, messageY, 0, rightLimit-messageX, AlignLeft, f.helpStyle, true)
+			}
+		}
+
 		// Is this item visible?
 		if y+height <= topLimit || y >= bottomLimit {
 			continue
@@ -704,6 +1735,8 @@ func (f *Form) Draw(screen tcell.Screen) {
 
 // Focus is called by the application when the primitive receives focus.
 func (f *Form) Focus(delegate func(p Primitive)) {
+	f.followFocus = true
+
 	// Hand on the focus to one of our child elements.
 	if f.focusedElement < 0 || f.focusedElement >= len(f.items)+len(f.buttons) {
 		f.focusedElement = 0
@@ -713,12 +1746,15 @@ func (f *Form) Focus(delegate func(p Primitive)) {
 		if key >= 0 {
 			f.lastFinishedKey = key
 		}
+		if key == tcell.KeyTab || key == tcell.KeyEnter || key == tcell.KeyBacktab {
+			f.validateFocusedElement()
+		}
 		switch key {
 		case tcell.KeyTab, tcell.KeyEnter:
-			f.focusedElement++
+			f.focusedElement = f.nextElement(f.focusedElement, true)
 			f.Focus(delegate)
 		case tcell.KeyBacktab:
-			f.focusedElement--
+			f.focusedElement = f.nextElement(f.focusedElement, false)
 			if f.focusedElement < 0 {
 				f.focusedElement = len(f.items) + len(f.buttons) - 1
 			}
@@ -738,6 +1774,22 @@ func (f *Form) Focus(delegate func(p Primitive)) {
 		}
 	}
 
+	// Skip hidden items.
+	if f.focusedElement < len(f.items) && f.isHidden(f.focusedElement) {
+		visible := len(f.buttons) > 0
+		for index := range f.items {
+			if !f.isHidden(index) {
+				visible = true
+				break
+			}
+		}
+		if visible {
+			f.focusedElement = f.nextElement(f.focusedElement, f.lastFinishedKey != tcell.KeyBacktab)
+			f.Focus(delegate)
+			return
+		}
+	}
+
 	// Track whether a form item has focus.
 	var itemFocused bool
 	f.hasFocus = false
@@ -774,6 +1826,15 @@ func (f *Form) Focus(delegate func(p Primitive)) {
 	if !itemFocused {
 		f.Box.Focus(delegate)
 	}
+	f.updateHelp()
+}
+
+// validateFocusedElement validates the form item that last had focus, if it
+// has validators. This is called when that item loses focus.
+func (f *Form) validateFocusedElement() {
+	if f.focusedElement >= 0 && f.focusedElement < len(f.items) {
+		f.ValidateItem(f.focusedElement)
+	}
 }
 
 // HasFocus returns whether or not this primitive has focus.
@@ -804,16 +1865,46 @@ func (f *Form) focusIndex() int {
 // MouseHandler returns the mouse handler for this primitive.
 func (f *Form) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return f.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		defer f.updateHelp()
+		defer f.updateConditions(setFocus)
+
 		// At the end, update f.focusedElement and prepare current item/button.
 		defer func() {
 			if consumed {
 				index := f.focusIndex()
 				if index >= 0 {
+					if index != f.focusedElement {
+						f.validateFocusedElement()
+						f.followFocus = true
+					}
 					f.focusedElement = index
 				}
 			}
 		}()
 
+		// Drag the scroll bar.
+		if f.scrollBarDragging {
+			switch action {
+			case MouseMove:
+				f.scrollOffset = f.bar.offset(event.Position())
+				return true, f
+			case MouseLeftUp:
+				f.scrollBarDragging = false
+				return true, nil
+			}
+		}
+		if f.bar.length > 0 && f.bar.contains(event.Position()) {
+			switch action {
+			case MouseLeftDown:
+				f.scrollOffset = f.bar.offset(event.Position())
+				f.followFocus = false
+				f.scrollBarDragging = true
+				return true, f
+			case MouseLeftClick, MouseLeftDoubleClick:
+				return true, nil
+			}
+		}
+
 		// Determine items to pass mouse events to.
 		for _, item := range f.items {
 			// Exclude TextView items from mouse-down events as they are
@@ -841,6 +1932,17 @@ func (f *Form) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 			consumed = true
 		}
 
+		// Scroll with the mouse wheel.
+		if (action == MouseScrollUp || action == MouseScrollDown) && f.InRect(event.Position()) {
+			if action == MouseScrollUp {
+				f.scrollOffset--
+			} else {
+				f.scrollOffset++
+			}
+			f.followFocus = false
+			consumed = true
+		}
+
 		return
 	})
 }
@@ -848,6 +1950,10 @@ func (f *Form) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 // InputHandler returns the handler for this primitive.
 func (f *Form) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return f.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		defer f.updateHelp()
+		defer f.updateConditions(setFocus)
+		f.followFocus = true
+
 		for _, item := range f.items {
 			if item != nil && item.HasFocus() {
 				if handler := item.InputHandler(); handler != nil {
@@ -0,0 +1,277 @@
+package tview
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Block characters for partially filled cells, in eighths.
+var (
+	gaugeHorizontalBlocks = []rune{' ', '▏', '▎', '▍', '▌', '▋', '▊', '▉', '█'}
+	gaugeVerticalBlocks   = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+)
+
+// gaugeThreshold defines the color of a gauge starting at a given value.
+type gaugeThreshold struct {
+	value float64
+	color tcell.Color
+}
+
+// Gauge displays a value within a fixed range, e.g. a CPU load or disk usage,
+// as a bar which is filled from left to right or, if vertical, from bottom to
+// top. Partially filled cells are drawn with block characters so the bar grows
+// smoothly.
+//
+// The color of the bar can change with the value. For example, the following
+// gauge is green below 70%, yellow below 90%, and red above:
+//
+//	gauge := tview.NewGauge().
+//	    SetColor(tcell.ColorGreen).
+//	    AddThreshold(70, tcell.ColorYellow).
+//	    AddThreshold(90, tcell.ColorRed)
+type Gauge struct {
+	*Box
+
+	// The range of values.
+	min, max float64
+
+	// The current value.
+	value float64
+
+	// Whether the bar is filled from bottom to top.
+	vertical bool
+
+	// The color of the bar below the first threshold.
+	color tcell.Color
+
+	// The thresholds, sorted by value.
+	thresholds []gaugeThreshold
+
+	// The style of the unfilled part of the bar.
+	emptyStyle tcell.Style
+
+	// The text shown on top of the bar.
+	label string
+
+	// Whether the value is shown on top of the bar.
+	showValue bool
+
+	// The format string used to show the value.
+	valueFormat string
+
+	// The style of the label and the value.
+	textStyle tcell.Style
+}
+
+// NewGauge returns a new gauge with a range from 0 to 100.
+func NewGauge() *Gauge {
+	return &Gauge{
+		Box:         NewBox(),
+		max:         100,
+		color:       Styles.MoreContrastBackgroundColor,
+		emptyStyle:  tcell.StyleDefault,
+		valueFormat: "%g",
+		textStyle:   tcell.StyleDefault.Foreground(Styles.PrimaryTextColor),
+	}
+}
+
+// SetRange sets the minimum and maximum value. The current value is clamped to
+// the new range.
+func (g *Gauge) SetRange(min, max float64) *Gauge {
+	if max < min {
+		min, max = max, min
+	}
+	g.min, g.max = min, max
+	return g.SetValue(g.value)
+}
+
+// GetRange returns the minimum and maximum value.
+func (g *Gauge) GetRange() (min, max float64) {
+	return g.min, g.max
+}
+
+// SetValue sets the gauge's value. It is clamped to the gauge's range.
+func (g *Gauge) SetValue(value float64) *Gauge {
+	if value < g.min || math.IsNaN(value) {
+		value = g.min
+	} else if value > g.max {
+		value = g.max
+	}
+	g.value = value
+	return g
+}
+
+// GetValue returns the gauge's value.
+func (g *Gauge) GetValue() float64 {
+	return g.value
+}
+
+// SetVertical sets whether the bar is filled from bottom to top instead of
+// from left to right.
+func (g *Gauge) SetVertical(vertical bool) *Gauge {
+	g.vertical = vertical
+	return g
+}
+
+// SetColor sets the color of the bar for values below the first threshold.
+func (g *Gauge) SetColor(color tcell.Color) *Gauge {
+	g.color = color
+	return g
+}
+
+// AddThreshold sets the color of the bar for values greater than or equal to
+// the given value (up to the next threshold). Adding a threshold for an
+// existing value replaces its color.
+func (g *Gauge) AddThreshold(value float64, color tcell.Color) *Gauge {
+	for index := range g.thresholds {
+		if g.thresholds[index].value == value {
+			g.thresholds[index].color = color
+			return g
+		}
+	}
+	g.thresholds = append(g.thresholds, gaugeThreshold{value: value, color: color})
+	sort.Slice(g.thresholds, func(i, j int) bool {
+		return g.thresholds[i].value < g.thresholds[j].value
+	})
+	return g
+}
+
+// ClearThresholds removes all thresholds.
+func (g *Gauge) ClearThresholds() *Gauge {
+	g.thresholds = nil
+	return g
+}
+
+// SetEmptyStyle sets the style of the unfilled part of the bar.
+func (g *Gauge) SetEmptyStyle(style tcell.Style) *Gauge {
+	g.emptyStyle = style
+	return g
+}
+
+// SetLabel sets the text shown centered on top of the bar. It may contain
+// style tags. If the value is shown, too, it follows the label.
+func (g *Gauge) SetLabel(label string) *Gauge {
+	g.label = label
+	return g
+}
+
+// GetLabel returns the text shown on top of the bar.
+func (g *Gauge) GetLabel() string {
+	return g.label
+}
+
+// ShowValue sets whether the value is shown centered on top of the bar.
+func (g *Gauge) ShowValue(show bool) *Gauge {
+	g.showValue = show
+	return g
+}
+
+// SetValueFormat sets the format string (as used by [fmt.Sprintf]) with which
+// the value is shown, see [Gauge.ShowValue]. The default is "%g".
+func (g *Gauge) SetValueFormat(format string) *Gauge {
+	g.valueFormat = format
+	return g
+}
+
+// SetTextStyle sets the style of the label and the value. Its background color
+// is ignored.
+func (g *Gauge) SetTextStyle(style tcell.Style) *Gauge {
+	g.textStyle = style
+	return g
+}
+
+// currentColor returns the color of the bar for the current value.
+func (g *Gauge) currentColor() tcell.Color {
+	color := g.color
+	for _, threshold := range g.thresholds {
+		if g.value < threshold.value {
+			break
+		}
+		color = threshold.color
+	}
+	return color
+}
+
+// Draw draws this primitive onto the screen.
+func (g *Gauge) Draw(screen tcell.Screen) {
+	g.Box.DrawForSubclass(screen, g)
+
+	x, y, width, height := g.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Determine the filled length in eighths of a cell.
+	length, blocks := width, gaugeHorizontalBlocks
+	if g.vertical {
+		length, blocks = height, gaugeVerticalBlocks
+	}
+	var eighths int
+	if g.max > g.min {
+		eighths = int(math.Round((g.value - g.min) / (g.max - g.min) * float64(length*8)))
+	}
+
+	// Draw the bar.
+	color := g.currentColor()
+	emptyStyle := g.emptyStyle
+	_, emptyBg, _ := emptyStyle.Decompose()
+	if emptyBg == tcell.ColorDefault {
+		emptyBg = g.backgroundColor
+		emptyStyle = emptyStyle.Background(emptyBg)
+	}
+	fill := func(position int) rune {
+		if position >= eighths/8 {
+			if position == eighths/8 {
+				return blocks[eighths%8]
+			}
+			return blocks[0]
+		}
+		return blocks[8]
+	}
+	for row := 0; row < height; row++ {
+		for column := 0; column < width; column++ {
+			position := column
+			if g.vertical {
+				position = height - 1 - row
+			}
+			ch := fill(position)
+			style := emptyStyle
+			if ch != ' ' {
+				style = style.Foreground(color)
+			}
+			screen.SetContent(x+column, y+row, ch, nil, style)
+		}
+	}
+
+	// Draw the text on top.
+	text := g.label
+	if g.showValue {
+		if text != "" {
+			text += " "
+		}
+		text += Escape(fmt.Sprintf(g.valueFormat, g.value))
+	}
+	if text == "" {
+		return
+	}
+	textWidth := TaggedStringWidth(text)
+	if textWidth > width {
+		textWidth = width
+	}
+	textX, textY := (width-textWidth)/2, height/2
+	for column := textX; column < textX+textWidth; column++ {
+		position := column
+		if g.vertical {
+			position = height - 1 - textY
+		}
+		bg := emptyBg
+		if fill(position) == blocks[8] {
+			bg = color
+		}
+		screen.SetContent(x+column, y+textY, ' ', nil, tcell.StyleDefault.Background(bg))
+	}
+	printWithStyle(screen, text, x+textX, y+textY, 0, textWidth, AlignLeft, g.textStyle, true)
+}
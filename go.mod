@@ -6,12 +6,12 @@ require (
 	github.com/gdamore/tcell/v2 v2.7.1
 	github.com/lucasb-eyer/go-colorful v1.2.0
 	github.com/rivo/uniseg v0.4.7
+	golang.org/x/sys v0.17.0
 )
 
 require (
 	github.com/gdamore/encoding v1.0.0 // indirect
 	github.com/mattn/go-runewidth v0.0.15 // indirect
-	golang.org/x/sys v0.17.0 // indirect
 	golang.org/x/term v0.17.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
 )
@@ -0,0 +1,728 @@
+package tview
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// hexViewSearchChunk is the number of bytes read at once when searching.
+const hexViewSearchChunk = 64 * 1024
+
+// The hexadecimal digits, used to draw bytes.
+const hexViewDigits = "0123456789abcdef"
+
+// HexView displays binary data as a hex dump: each line shows the offset of
+// its first byte, the bytes in hexadecimal notation, and the bytes as ASCII
+// characters (non-printable characters are shown as dots). The data is read
+// from an [io.ReaderAt] and only the visible part is read when drawing, so the
+// data may be arbitrarily large.
+//
+// The cursor is placed on one byte and shown in both the hexadecimal and the
+// ASCII column. The following keys are available:
+//
+//   - Left arrow / right arrow: Move the cursor by one byte.
+//   - Up arrow / down arrow: Move the cursor by one line.
+//   - Page up / page down: Move the cursor by one page.
+//   - Home / End: Move the cursor to the start / end of the line.
+//   - Ctrl+Home / Ctrl+End: Move the cursor to the start / end of the data.
+//   - Shift plus any of the above: Select bytes.
+//   - Tab: Switch between the hexadecimal and the ASCII column.
+//   - F3 / Shift+F3: Find the next / previous match of the last search.
+//   - Escape: Clear the selection or, if there is none, leave the hex view
+//     (see [HexView.SetDoneFunc]).
+//
+// If editing is enabled with [HexView.SetEditable], typing hexadecimal digits
+// in the hexadecimal column or characters in the ASCII column overwrites the
+// bytes under the cursor. The data itself is never modified. Instead, edits
+// are kept by the hex view and may be written with [HexView.WriteEdits].
+//
+// Dragging the mouse selects bytes, the mouse wheel scrolls.
+type HexView struct {
+	*Box
+
+	// The data source and its size in bytes.
+	reader io.ReaderAt
+	size   int64
+
+	// The error which occurred when the data was last read, if any.
+	err error
+
+	// The number of bytes per line, 0 to fit as many bytes as possible in
+	// multiples of 8.
+	bytesPerLine int
+
+	// The index of the first visible line.
+	lineOffset int64
+
+	// The position of the cursor.
+	cursor int64
+
+	// The other end of the selection, -1 if there is no selection.
+	anchor int64
+
+	// Whether the cursor is in the ASCII column.
+	ascii bool
+
+	// Whether the next hex digit typed overwrites the low nibble of the byte
+	// under the cursor.
+	lowNibble bool
+
+	// If set to true, the lines are scrolled such that the cursor is visible
+	// during the next draw.
+	trackCursor bool
+
+	// Whether the bytes may be edited.
+	editable bool
+
+	// The edited bytes, mapped by their position.
+	edits map[int64]byte
+
+	// The last searched pattern.
+	pattern []byte
+
+	// Styles.
+	offsetStyle, textStyle, cursorStyle, selectionStyle, editedStyle tcell.Style
+
+	// Temporary member variables, set when drawing.
+	lastBytesPerLine, lastHeight int   // The layout of the last draw.
+	lastX, lastY                 int   // The position of the first line.
+	hexX, asciiX                 int   // The start columns of the two columns, relative to lastX.
+	dragging                     bool  // Whether the mouse is used to select bytes.
+	lastDrawn                    int64 // The position of the first byte drawn.
+
+	// An optional function which is called when the cursor moves.
+	changed func(position int64)
+
+	// An optional function which is called when a byte was edited.
+	edited func(position int64, value byte)
+
+	// An optional function which is called when the user presses Escape.
+	done func(key tcell.Key)
+}
+
+// NewHexView returns a new, empty hex view. Use [HexView.SetReader] to show
+// data.
+func NewHexView() *HexView {
+	return &HexView{
+		Box:            NewBox(),
+		bytesPerLine:   16,
+		anchor:         -1,
+		edits:          make(map[int64]byte),
+		offsetStyle:    tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
+		textStyle:      tcell.StyleDefault.Foreground(Styles.PrimaryTextColor),
+		cursorStyle:    tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.PrimitiveBackgroundColor),
+		selectionStyle: tcell.StyleDefault.Background(Styles.MoreContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		editedStyle:    tcell.StyleDefault.Foreground(Styles.TertiaryTextColor).Bold(true),
+	}
+}
+
+// SetReader sets the data to be shown, the first size bytes of the given
+// reader. All edits are discarded and the cursor is moved to the start.
+func (h *HexView) SetReader(reader io.ReaderAt, size int64) *HexView {
+	if size < 0 {
+		size = 0
+	}
+	h.reader, h.size, h.err = reader, size, nil
+	h.cursor, h.anchor, h.lineOffset, h.lowNibble = 0, -1, 0, false
+	h.edits = make(map[int64]byte)
+	return h
+}
+
+// SetBytes is a shortcut to show the given bytes.
+func (h *HexView) SetBytes(data []byte) *HexView {
+	return h.SetReader(bytes.NewReader(data), int64(len(data)))
+}
+
+// GetSize returns the size of the data in bytes.
+func (h *HexView) GetSize() int64 {
+	return h.size
+}
+
+// GetError returns the error which occurred when the data was last read or
+// nil if there was none.
+func (h *HexView) GetError() error {
+	return h.err
+}
+
+// SetBytesPerLine sets the number of bytes shown on each line. If set to 0,
+// as many bytes as fit into the available width are shown, in multiples of
+// 8. The default is 16.
+func (h *HexView) SetBytesPerLine(count int) *HexView {
+	if count < 0 {
+		count = 0
+	}
+	h.bytesPerLine = count
+	h.trackCursor = true
+	return h
+}
+
+// SetEditable sets whether the user may overwrite bytes.
+func (h *HexView) SetEditable(editable bool) *HexView {
+	h.editable = editable
+	h.lowNibble = false
+	return h
+}
+
+// IsEditable returns whether the user may overwrite bytes.
+func (h *HexView) IsEditable() bool {
+	return h.editable
+}
+
+// SetStyles sets the styles of the offset column, of the bytes, of the byte
+// under the cursor, of selected bytes, and of edited bytes.
+func (h *HexView) SetStyles(offset, text, cursor, selection, edited tcell.Style) *HexView {
+	h.offsetStyle, h.textStyle, h.cursorStyle, h.selectionStyle, h.editedStyle = offset, text, cursor, selection, edited
+	return h
+}
+
+// SetCursor moves the cursor to the given position and scrolls it into view.
+// The selection is cleared.
+func (h *HexView) SetCursor(position int64) *HexView {
+	h.anchor = -1
+	h.moveCursor(position)
+	return h
+}
+
+// GetCursor returns the position of the cursor.
+func (h *HexView) GetCursor() int64 {
+	return h.cursor
+}
+
+// Select selects the bytes from start (inclusive) to end (exclusive) and
+// moves the cursor to the last selected byte. If the range is empty, the
+// selection is cleared and the cursor is moved to start.
+func (h *HexView) Select(start, end int64) *HexView {
+	if end > h.size {
+		end = h.size
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end <= start {
+		return h.SetCursor(start)
+	}
+	h.anchor = start
+	h.moveCursor(end - 1)
+	return h
+}
+
+// GetSelection returns the start (inclusive) and end (exclusive) of the
+// selected bytes. If nothing is selected, start and end are the cursor
+// position.
+func (h *HexView) GetSelection() (start, end int64) {
+	if h.anchor < 0 {
+		return h.cursor, h.cursor
+	}
+	start, end = h.anchor, h.cursor
+	if start > end {
+		start, end = end, start
+	}
+	return start, end + 1
+}
+
+// GetSelectedBytes returns the selected bytes, including edits.
+func (h *HexView) GetSelectedBytes() ([]byte, error) {
+	start, end := h.GetSelection()
+	buffer := make([]byte, end-start)
+	n, err := h.read(buffer, start)
+	return buffer[:n], err
+}
+
+// GetEdits returns the edited bytes, mapped by their position.
+func (h *HexView) GetEdits() map[int64]byte {
+	edits := make(map[int64]byte, len(h.edits))
+	for position, value := range h.edits {
+		edits[position] = value
+	}
+	return edits
+}
+
+// HasEdits returns whether any bytes were edited.
+func (h *HexView) HasEdits() bool {
+	return len(h.edits) > 0
+}
+
+// DiscardEdits discards all edits.
+func (h *HexView) DiscardEdits() *HexView {
+	h.edits = make(map[int64]byte)
+	h.lowNibble = false
+	return h
+}
+
+// WriteEdits writes all edited bytes to the given writer, e.g. the file the
+// data was read from, and discards the edits which were written successfully.
+// Consecutive edited bytes are written at once.
+func (h *HexView) WriteEdits(writer io.WriterAt) error {
+	positions := make([]int64, 0, len(h.edits))
+	for position := range h.edits {
+		positions = append(positions, position)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i] < positions[j]
+	})
+	for len(positions) > 0 {
+		run := 1
+		for run < len(positions) && positions[run] == positions[0]+int64(run) {
+			run++
+		}
+		data := make([]byte, run)
+		for index := range data {
+			data[index] = h.edits[positions[index]]
+		}
+		if _, err := writer.WriteAt(data, positions[0]); err != nil {
+			return err
+		}
+		for _, position := range positions[:run] {
+			delete(h.edits, position)
+		}
+		positions = positions[run:]
+	}
+	return nil
+}
+
+// SetChangedFunc sets a handler which is called with the cursor position when
+// the cursor moves.
+func (h *HexView) SetChangedFunc(handler func(position int64)) *HexView {
+	h.changed = handler
+	return h
+}
+
+// SetEditedFunc sets a handler which is called with the position and the new
+// value of a byte when the user edits it.
+func (h *HexView) SetEditedFunc(handler func(position int64, value byte)) *HexView {
+	h.edited = handler
+	return h
+}
+
+// SetDoneFunc sets a handler which is called when the user presses the Escape
+// key while nothing is selected.
+func (h *HexView) SetDoneFunc(handler func(key tcell.Key)) *HexView {
+	h.done = handler
+	return h
+}
+
+// Find searches for the given bytes, starting after the cursor (forward) or
+// before it (backward), and selects the first match. Edits are taken into
+// account. It returns whether a match was found. The search reads the data in
+// chunks and may take a while for large inputs.
+func (h *HexView) Find(pattern []byte, forward bool) (bool, error) {
+	if len(pattern) == 0 {
+		return false, nil
+	}
+	h.pattern = append([]byte(nil), pattern...)
+	var (
+		position int64
+		err      error
+	)
+	if forward {
+		position, err = h.findForward(pattern, h.cursor+1)
+	} else {
+		start, _ := h.GetSelection()
+		position, err = h.findBackward(pattern, start)
+	}
+	if position < 0 {
+		return false, err
+	}
+	h.Select(position, position+int64(len(pattern)))
+	return true, err
+}
+
+// FindString searches for the given text, see [HexView.Find].
+func (h *HexView) FindString(text string, forward bool) (bool, error) {
+	return h.Find([]byte(text), forward)
+}
+
+// findForward returns the position of the first match of the pattern starting
+// at or after the given position, or -1 if there is none.
+func (h *HexView) findForward(pattern []byte, from int64) (int64, error) {
+	buffer := make([]byte, hexViewSearchChunk+len(pattern)-1)
+	for from < h.size {
+		n, err := h.read(buffer, from)
+		if index := bytes.Index(buffer[:n], pattern); index >= 0 {
+			return from + int64(index), nil
+		}
+		if err != nil {
+			return -1, err
+		}
+		from += hexViewSearchChunk
+	}
+	return -1, nil
+}
+
+// findBackward returns the position of the last match of the pattern starting
+// before the given position, or -1 if there is none.
+func (h *HexView) findBackward(pattern []byte, before int64) (int64, error) {
+	buffer := make([]byte, hexViewSearchChunk+len(pattern)-1)
+	for before > 0 {
+		from := before - hexViewSearchChunk
+		if from < 0 {
+			from = 0
+		}
+		n, err := h.read(buffer[:before-from+int64(len(pattern))-1], from)
+		if index := bytes.LastIndex(buffer[:n], pattern); index >= 0 {
+			return from + int64(index), nil
+		}
+		if err != nil {
+			return -1, err
+		}
+		before = from
+	}
+	return -1, nil
+}
+
+// read reads bytes at the given position into the buffer, applying edits. It
+// returns the number of bytes read. Reaching the end of the data is not an
+// error.
+func (h *HexView) read(buffer []byte, position int64) (int, error) {
+	if h.reader == nil || position >= h.size {
+		return 0, nil
+	}
+	if int64(len(buffer)) > h.size-position {
+		buffer = buffer[:h.size-position]
+	}
+	n, err := h.reader.ReadAt(buffer, position)
+	if err == io.EOF {
+		err = nil
+	}
+	h.err = err
+	for index := range buffer[:n] {
+		if value, ok := h.edits[position+int64(index)]; ok {
+			buffer[index] = value
+		}
+	}
+	return n, err
+}
+
+// layout returns the number of bytes per line for the given width.
+func (h *HexView) layout(width int) int {
+	if h.bytesPerLine > 0 {
+		return h.bytesPerLine
+	}
+	count := 8
+	for h.lineWidth(count+8) <= width {
+		count += 8
+	}
+	return count
+}
+
+// offsetWidth returns the width of the offset column.
+func (h *HexView) offsetWidth() int {
+	width := len(fmt.Sprintf("%x", h.size))
+	if width < 8 {
+		width = 8
+	}
+	return width
+}
+
+// lineWidth returns the width of a line with the given number of bytes.
+func (h *HexView) lineWidth(count int) int {
+	return h.offsetWidth() + 2 + count*3 + (count-1)/8 + 1 + count
+}
+
+// moveCursor moves the cursor to the given position, limited to the data, and
+// notifies the handler.
+func (h *HexView) moveCursor(position int64) {
+	if position >= h.size {
+		position = h.size - 1
+	}
+	if position < 0 {
+		position = 0
+	}
+	h.lowNibble = false
+	h.trackCursor = true
+	if position != h.cursor {
+		h.cursor = position
+		if h.changed != nil {
+			h.changed(position)
+		}
+	}
+}
+
+// edit sets the byte under the cursor to the given value.
+func (h *HexView) edit(value byte) {
+	h.edits[h.cursor] = value
+	if h.edited != nil {
+		h.edited(h.cursor, value)
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (h *HexView) Draw(screen tcell.Screen) {
+	h.Box.DrawForSubclass(screen, h)
+	x, y, width, height := h.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Calculate the layout.
+	perLine := h.layout(width)
+	offsetWidth := h.offsetWidth()
+	h.lastBytesPerLine, h.lastHeight, h.lastX, h.lastY = perLine, height, x, y
+	h.hexX = offsetWidth + 2
+	h.asciiX = h.hexX + perLine*3 + (perLine-1)/8 + 1
+
+	// Scroll such that the cursor is visible.
+	lines := (h.size + int64(perLine) - 1) / int64(perLine)
+	if h.trackCursor {
+		h.trackCursor = false
+		cursorLine := h.cursor / int64(perLine)
+		if cursorLine < h.lineOffset {
+			h.lineOffset = cursorLine
+		} else if cursorLine >= h.lineOffset+int64(height) {
+			h.lineOffset = cursorLine - int64(height) + 1
+		}
+	}
+	if h.lineOffset > lines-int64(height) {
+		h.lineOffset = lines - int64(height)
+	}
+	if h.lineOffset < 0 {
+		h.lineOffset = 0
+	}
+
+	// Read the visible bytes.
+	h.lastDrawn = h.lineOffset * int64(perLine)
+	buffer := make([]byte, perLine*height)
+	n, err := h.read(buffer, h.lastDrawn)
+	if err != nil {
+		printWithStyle(screen, Escape(err.Error()), x, y, 0, width, AlignLeft, tcell.StyleDefault.Foreground(Styles.TertiaryTextColor), true)
+		return
+	}
+	buffer = buffer[:n]
+	selectionStart, selectionEnd := h.GetSelection()
+	focused := h.HasFocus()
+
+	// Draw the lines.
+	for line := 0; line < height && line*perLine < len(buffer); line++ {
+		position := h.lastDrawn + int64(line*perLine)
+		printWithStyle(screen, fmt.Sprintf("%0*x", offsetWidth, position), x, y+line, 0, width, AlignLeft, h.offsetStyle, true)
+		for index := 0; index < perLine && line*perLine+index < len(buffer); index++ {
+			bytePosition := position + int64(index)
+			value := buffer[line*perLine+index]
+			style := h.textStyle
+			if _, ok := h.edits[bytePosition]; ok {
+				style = h.editedStyle
+			}
+			if bytePosition >= selectionStart && bytePosition < selectionEnd {
+				style = h.selectionStyle
+			}
+			hexStyle, asciiStyle := style, style
+			if bytePosition == h.cursor && focused {
+				if h.ascii {
+					asciiStyle = h.cursorStyle
+					hexStyle = style.Underline(true)
+				} else {
+					hexStyle = h.cursorStyle
+					asciiStyle = style.Underline(true)
+				}
+			}
+
+			// Hexadecimal column.
+			column := x + h.hexX + index*3 + index/8
+			for digit, r := range []byte{hexViewDigits[value>>4], hexViewDigits[value&0xf]} {
+				if column+digit < x+width {
+					screen.SetContent(column+digit, y+line, rune(r), nil, hexStyle)
+				}
+			}
+
+			// ASCII column.
+			if column := x + h.asciiX + index; column < x+width {
+				r := '.'
+				if value >= 0x20 && value < 0x7f {
+					r = rune(value)
+				}
+				screen.SetContent(column, y+line, r, nil, asciiStyle)
+			}
+		}
+		if column := x + h.asciiX - 1; column < x+width {
+			screen.SetContent(column, y+line, Borders.Vertical, nil, h.offsetStyle)
+		}
+	}
+}
+
+// positionAt returns the position of the byte drawn at the given screen
+// coordinates, whether it is in the ASCII column, and whether there is a byte
+// at all.
+func (h *HexView) positionAt(x, y int) (position int64, ascii bool, ok bool) {
+	line := y - h.lastY
+	if line < 0 || line >= h.lastHeight || h.lastBytesPerLine <= 0 {
+		return 0, false, false
+	}
+	column := x - h.lastX
+	index := -1
+	if column >= h.asciiX {
+		index, ascii = column-h.asciiX, true
+	} else if column >= h.hexX {
+		column -= h.hexX
+		column -= column / (3*8 + 1) // Group gaps.
+		index = column / 3
+	}
+	if index < 0 || index >= h.lastBytesPerLine {
+		return 0, false, false
+	}
+	position = h.lastDrawn + int64(line*h.lastBytesPerLine+index)
+	if position >= h.size {
+		return 0, false, false
+	}
+	return position, ascii, true
+}
+
+// InputHandler returns the handler for this primitive.
+func (h *HexView) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return h.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		perLine := int64(h.lastBytesPerLine)
+		if perLine <= 0 {
+			perLine = int64(h.layout(0))
+		}
+		page := perLine * int64(h.lastHeight)
+		if page < perLine {
+			page = perLine
+		}
+		modifiers := event.Modifiers()
+
+		// Determine the new cursor position for movement keys.
+		var target int64
+		switch event.Key() {
+		case tcell.KeyLeft:
+			target = h.cursor - 1
+		case tcell.KeyRight:
+			target = h.cursor + 1
+		case tcell.KeyUp:
+			target = h.cursor - perLine
+		case tcell.KeyDown:
+			target = h.cursor + perLine
+		case tcell.KeyPgUp:
+			target = h.cursor - page
+		case tcell.KeyPgDn:
+			target = h.cursor + page
+		case tcell.KeyHome:
+			target = h.cursor - h.cursor%perLine
+			if modifiers&tcell.ModCtrl != 0 {
+				target = 0
+			}
+		case tcell.KeyEnd:
+			target = h.cursor - h.cursor%perLine + perLine - 1
+			if modifiers&tcell.ModCtrl != 0 {
+				target = h.size - 1
+			}
+		case tcell.KeyTab:
+			h.ascii = !h.ascii
+			h.lowNibble = false
+			return
+		case tcell.KeyEscape:
+			if h.anchor >= 0 {
+				h.anchor = -1
+			} else if h.done != nil {
+				h.done(tcell.KeyEscape)
+			}
+			return
+		case tcell.KeyF3:
+			if len(h.pattern) > 0 {
+				h.Find(h.pattern, modifiers&tcell.ModShift == 0)
+			}
+			return
+		case tcell.KeyRune:
+			if h.editable && h.cursor < h.size {
+				h.typeRune(event.Rune())
+			}
+			return
+		default:
+			return
+		}
+
+		// Move the cursor, selecting bytes if Shift is pressed.
+		if modifiers&tcell.ModShift != 0 {
+			if h.anchor < 0 {
+				h.anchor = h.cursor
+			}
+		} else {
+			h.anchor = -1
+		}
+		h.moveCursor(target)
+	})
+}
+
+// typeRune overwrites the byte under the cursor with the typed character.
+func (h *HexView) typeRune(r rune) {
+	current := make([]byte, 1)
+	h.read(current, h.cursor)
+	if h.ascii {
+		if r < 0x20 || r >= 0x7f {
+			return
+		}
+		h.edit(byte(r))
+		h.moveCursor(h.cursor + 1)
+		return
+	}
+	var digit byte
+	switch {
+	case r >= '0' && r <= '9':
+		digit = byte(r - '0')
+	case r >= 'a' && r <= 'f':
+		digit = byte(r-'a') + 10
+	case r >= 'A' && r <= 'F':
+		digit = byte(r-'A') + 10
+	default:
+		return
+	}
+	if h.lowNibble {
+		h.edit(current[0]&0xf0 | digit)
+		h.moveCursor(h.cursor + 1)
+		return
+	}
+	h.edit(current[0]&0x0f | digit<<4)
+	h.lowNibble = true
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (h *HexView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return h.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !h.InRect(x, y) && !h.dragging {
+			return false, nil
+		}
+
+		switch action {
+		case MouseLeftDown:
+			setFocus(h)
+			if position, ascii, ok := h.positionAt(x, y); ok {
+				h.ascii = ascii
+				h.anchor = -1
+				h.moveCursor(position)
+				h.dragging = true
+				return true, h
+			}
+			consumed = true
+		case MouseMove:
+			if h.dragging {
+				if position, _, ok := h.positionAt(x, y); ok && position != h.cursor {
+					if h.anchor < 0 {
+						h.anchor = h.cursor
+					}
+					h.moveCursor(position)
+				}
+				return true, h
+			}
+		case MouseLeftUp:
+			if h.dragging {
+				h.dragging = false
+				consumed = true
+			}
+		case MouseLeftClick:
+			consumed = true
+		case MouseScrollUp:
+			h.lineOffset -= 3
+			if h.lineOffset < 0 {
+				h.lineOffset = 0
+			}
+			consumed = true
+		case MouseScrollDown:
+			h.lineOffset += 3
+			consumed = true
+		}
+		return
+	})
+}
@@ -0,0 +1,112 @@
+package tview
+
+import (
+	"regexp"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// StyledSpan describes a range of text (as a half-open interval of byte
+// indices) which is drawn with a specific style.
+type StyledSpan struct {
+	Start, End int
+	Style      tcell.Style
+}
+
+// Highlighter provides syntax highlighting for a [TextArea]. See
+// [TextArea.SetHighlighter] for details.
+//
+// Existing highlighting libraries can be adapted easily. For example, the
+// following highlighter uses github.com/alecthomas/chroma (error handling
+// omitted):
+//
+//	type chromaHighlighter struct {
+//		lexer chroma.Lexer
+//		style *chroma.Style
+//	}
+//
+//	func (c *chromaHighlighter) Highlight(line string, state any) ([]tview.StyledSpan, any) {
+//		var (
+//			spans []tview.StyledSpan
+//			index int
+//		)
+//		iterator, _ := c.lexer.Tokenise(nil, line)
+//		for _, token := range iterator.Tokens() {
+//			entry := c.style.Get(token.Type)
+//			style := tcell.StyleDefault.
+//				Foreground(tcell.NewHexColor(int32(entry.Colour))).
+//				Bold(entry.Bold == chroma.Yes).
+//				Italic(entry.Italic == chroma.Yes)
+//			spans = append(spans, tview.StyledSpan{Start: index, End: index + len(token.Value), Style: style})
+//			index += len(token.Value)
+//		}
+//		return spans, nil
+//	}
+type Highlighter interface {
+	// Highlight returns the styled spans of a single line of text (without its
+	// trailing newline) where indices are relative to the start of the line.
+	// The spans must be sorted by their start index and must not overlap. Text
+	// not covered by any span is drawn in the text area's text style.
+	//
+	// The state is the value returned for the previous line (nil for the
+	// first line). It can be used to keep track of constructs which span
+	// multiple lines, such as block comments.
+	Highlight(line string, state any) (spans []StyledSpan, newState any)
+}
+
+// regexpHighlighterRule is a rule of a [RegexpHighlighter].
+type regexpHighlighterRule struct {
+	pattern *regexp.Regexp
+	style   tcell.Style
+}
+
+// RegexpHighlighter is a simple [Highlighter] which applies styles to all
+// matches of regular expressions. Multi-line constructs are not supported.
+type RegexpHighlighter struct {
+	rules []regexpHighlighterRule
+}
+
+// NewRegexpHighlighter returns a new regular expression highlighter without
+// any rules.
+func NewRegexpHighlighter() *RegexpHighlighter {
+	return &RegexpHighlighter{}
+}
+
+// AddRule adds a rule which applies the given style to all matches of the
+// given regular expression. Where matches of different rules start at the same
+// position, the rule added first takes precedence. Matches don't overlap.
+func (r *RegexpHighlighter) AddRule(pattern *regexp.Regexp, style tcell.Style) *RegexpHighlighter {
+	r.rules = append(r.rules, regexpHighlighterRule{
+		pattern: pattern,
+		style:   style,
+	})
+	return r
+}
+
+// Highlight implements the [Highlighter] interface.
+func (r *RegexpHighlighter) Highlight(line string, state any) (spans []StyledSpan, newState any) {
+	var index int
+	for index < len(line) {
+		// Find the leftmost match of all rules.
+		start, end, rule := -1, -1, -1
+		for ruleIndex, candidate := range r.rules {
+			match := candidate.pattern.FindStringIndex(line[index:])
+			if match == nil || match[0] == match[1] {
+				continue
+			}
+			if start < 0 || index+match[0] < start {
+				start, end, rule = index+match[0], index+match[1], ruleIndex
+			}
+		}
+		if rule < 0 {
+			break // No more matches.
+		}
+		spans = append(spans, StyledSpan{
+			Start: start,
+			End:   end,
+			Style: r.rules[rule].style,
+		})
+		index = end
+	}
+	return
+}
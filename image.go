@@ -1,8 +1,20 @@
 package tview
 
 import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
 	"image"
+	"image/png"
+	"io"
 	"math"
+	"os"
+	"strings"
+	"sync"
+
+	// Register the most common image formats for Image.SetImageBytes.
+	_ "image/gif"
+	_ "image/jpeg"
 
 	"github.com/gdamore/tcell/v2"
 )
@@ -11,6 +23,25 @@ import (
 const (
 	DitheringNone           = iota // No dithering.
 	DitheringFloydSteinberg        // Floyd-Steinberg dithering (the default).
+	DitheringAtkinson              // Atkinson dithering, with more contrast but less detail.
+	DitheringOrdered               // Ordered (Bayer matrix) dithering, without spreading errors.
+)
+
+// Ways to scale images to the available space.
+const (
+	ImageScaleFit    = iota // Fit the image into the space, preserving its aspect ratio (the default).
+	ImageScaleFill          // Fill the entire space, preserving the aspect ratio and cropping the image.
+	ImageScaleCenter        // Don't scale the image, center it and crop it if it's too large.
+)
+
+// Protocols used to draw images.
+const (
+	ImageProtocolAuto       = iota // Use a graphics protocol if the terminal supports it, block elements otherwise (the default).
+	ImageProtocolBlocks            // Approximate the image with block elements.
+	ImageProtocolHalfBlocks        // Draw two pixels per cell using the upper half block.
+	ImageProtocolSixel             // Use the Sixel graphics protocol.
+	ImageProtocolKitty             // Use the kitty graphics protocol.
+	ImageProtocolITerm2            // Use the iTerm2 inline images protocol.
 )
 
 // The number of colors supported by true color terminals (R*G*B = 256*256*256).
@@ -42,6 +73,34 @@ var blockElements = map[rune]uint64{
 	BlockQuadrantUpperLeftAndLowerRight: 0b1111000011110000111100001111000000001111000011110000111100001111,
 }
 
+// The masks of the error diffusion dithering algorithms, determining how the
+// error is distributed. Each element has three values: dx, dy, and weight (in
+// 16th).
+var ditheringMasks = map[int][][3]int{
+	DitheringFloydSteinberg: {
+		{1, 0, 7},
+		{-1, 1, 3},
+		{0, 1, 5},
+		{1, 1, 1},
+	},
+	DitheringAtkinson: {
+		{1, 0, 2},
+		{2, 0, 2},
+		{-1, 1, 2},
+		{0, 1, 2},
+		{1, 1, 2},
+		{0, 2, 2},
+	},
+}
+
+// The threshold map used for ordered dithering, in 16th.
+var bayerMatrix = [4][4]float64{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
 // pixel represents a character on screen used to draw part of an image.
 type pixel struct {
 	style   tcell.Style
@@ -60,6 +119,12 @@ type pixel struct {
 // quality of the final image also depends on the terminal's font and spacing
 // settings, none of which are under the control of this package. Results may
 // vary.
+//
+// If the terminal supports one of the Sixel, kitty, or iTerm2 graphics
+// protocols, the image is drawn in full resolution instead (see
+// [Image.SetProtocol]). This requires the image to be part of an
+// [Application] running in a real terminal. Primitives drawn on top of such an
+// image may be hidden by it.
 type Image struct {
 	*Box
 
@@ -78,9 +143,17 @@ type Image struct {
 	colors int
 
 	// The dithering algorithm to use, one of the constants starting with
-	// "ImageDithering".
+	// "Dithering".
 	dithering int
 
+	// How the image is scaled, one of the constants starting with
+	// "ImageScale".
+	scaling int
+
+	// How the image is drawn, one of the constants starting with
+	// "ImageProtocol".
+	protocol int
+
 	// The width of a terminal's cell divided by its height.
 	aspectRatio float64
 
@@ -100,6 +173,22 @@ type Image struct {
 	// The actual image size (in cells) when it was drawn the last time.
 	lastWidth, lastHeight int
 
+	// The part of the image drawn the last time.
+	lastCrop image.Rectangle
+
+	// The protocol used and the size of a cell in pixels when the image was
+	// drawn the last time.
+	lastProtocol, lastCellWidth, lastCellHeight int
+
+	// The protocol used and the size of a cell in pixels for the current
+	// draw.
+	currentProtocol, cellWidth, cellHeight int
+
+	// The escape sequence which draws the image when using a graphics
+	// protocol, and a counter incremented each time it changes.
+	graphic           []byte
+	graphicGeneration int
+
 	// The actual image (in cells) when it was drawn the last time. The size of
 	// this slice is lastWidth * lastHeight, indexed by y*lastWidth + x.
 	pixels []pixel
@@ -130,6 +219,34 @@ func (i *Image) SetImage(image image.Image) *Image {
 	return i
 }
 
+// SetImageBytes decodes the given image file contents (PNG, JPEG, or GIF,
+// plus any other format registered with the [image] package) and displays the
+// image. The current image is kept if decoding fails.
+func (i *Image) SetImageBytes(data []byte) error {
+	return i.readImage(bytes.NewReader(data))
+}
+
+// SetImageFile decodes the image file with the given path and displays the
+// image. See [Image.SetImageBytes] for the supported formats.
+func (i *Image) SetImageFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return i.readImage(file)
+}
+
+// readImage decodes an image from the given reader and displays it.
+func (i *Image) readImage(reader io.Reader) error {
+	decoded, _, err := image.Decode(reader)
+	if err != nil {
+		return err
+	}
+	i.SetImage(decoded)
+	return nil
+}
+
 // SetSize sets the size of the image. Positive values refer to cells in the
 // terminal. Negative values refer to a percentage of the available space (e.g.
 // -50 means 50%). A value of 0 means that the corresponding size is chosen
@@ -176,13 +293,58 @@ func (i *Image) GetColors() int {
 
 // SetDithering sets the dithering algorithm to use, one of the constants
 // starting with "Dithering", for example [DitheringFloydSteinberg] (the
-// default). Dithering is not applied when rendering in true-color.
+// default). Dithering is not applied when rendering in true-color or with the
+// kitty or iTerm2 protocols. The Sixel protocol always uses at most 256
+// colors.
 func (i *Image) SetDithering(dithering int) *Image {
 	i.dithering = dithering
 	i.lastWidth, i.lastHeight = 0, 0
 	return i
 }
 
+// SetScaling sets how the image is scaled to the available space (or the size
+// set with [Image.SetSize]), one of the constants starting with "ImageScale":
+//
+//   - [ImageScaleFit] (the default): The image is as large as possible while
+//     fitting into the space and preserving its aspect ratio.
+//   - [ImageScaleFill]: The image covers the entire space, preserving its
+//     aspect ratio. Parts of the image which don't fit are cut off.
+//   - [ImageScaleCenter]: The image is shown at its original size, assuming
+//     one image pixel per screen pixel. If it is too large, it is cut off.
+//
+// With [ImageScaleFill] and [ImageScaleCenter], a size of 0 set with
+// [Image.SetSize] means the available space.
+func (i *Image) SetScaling(scaling int) *Image {
+	i.scaling = scaling
+	i.lastWidth, i.lastHeight = 0, 0
+	return i
+}
+
+// SetProtocol sets how the image is drawn, one of the constants starting with
+// "ImageProtocol". With [ImageProtocolAuto] (the default), a graphics protocol
+// is used if the environment variables indicate that the terminal supports it
+// (this is not the case inside terminal multiplexers like tmux). Otherwise,
+// and if the image is not drawn by an application running in a terminal, the
+// image is approximated with block elements. [ImageProtocolHalfBlocks] is
+// faster but shows fewer details than [ImageProtocolBlocks].
+//
+// Forcing a graphics protocol the terminal doesn't support will result in
+// garbage on the screen.
+func (i *Image) SetProtocol(protocol int) *Image {
+	i.protocol = protocol
+	i.lastWidth, i.lastHeight = 0, 0
+	return i
+}
+
+// GetProtocol returns the protocol with which the image was last drawn, one
+// of the constants starting with "ImageProtocol" except [ImageProtocolAuto].
+func (i *Image) GetProtocol() int {
+	if i.lastProtocol == ImageProtocolAuto {
+		return ImageProtocolBlocks
+	}
+	return i.lastProtocol
+}
+
 // SetAspectRatio sets the width of a terminal's cell divided by its height.
 // You may change the default of 0.5 if your terminal / font has a different
 // aspect ratio. This is used to calculate the size of the image if the
@@ -294,23 +456,65 @@ func (i *Image) Focus(delegate func(p Primitive)) {
 	i.Box.Focus(delegate)
 }
 
-// render re-populates the [Image.pixels] slice based on the current settings,
-// if [Image.lastWidth] and [Image.lastHeight] don't match the current image's
-// size. It also sets the new image size in these two variables.
+// render re-populates the [Image.pixels] slice (or, when using a graphics
+// protocol, [Image.graphic]) based on the current settings, if the layout
+// determined by [Image.layout] differs from the last one. It also sets the new
+// image size in [Image.lastWidth] and [Image.lastHeight].
 func (i *Image) render() {
 	// If there is no image, there are no pixels.
 	if i.image == nil {
-		i.pixels = nil
+		i.pixels, i.graphic = nil, nil
+		i.lastWidth, i.lastHeight = 0, 0
 		return
 	}
 
 	// Calculate the new (terminal-space) image size.
+	width, height, crop := i.layout()
+	if width <= 0 || height <= 0 {
+		i.pixels, i.graphic = nil, nil
+		i.lastWidth, i.lastHeight = 0, 0
+		return
+	}
+
+	// If nothing has changed, we're done.
+	if i.lastWidth == width && i.lastHeight == height && i.lastCrop == crop &&
+		i.lastProtocol == i.currentProtocol && i.lastCellWidth == i.cellWidth && i.lastCellHeight == i.cellHeight {
+		return
+	}
+	i.lastWidth, i.lastHeight, i.lastCrop = width, height, crop // This could still be larger than the available space but that's ok for now.
+	i.lastProtocol, i.lastCellWidth, i.lastCellHeight = i.currentProtocol, i.cellWidth, i.cellHeight
+
+	source := cropImage(i.image, crop)
+	i.pixels, i.graphic = nil, nil
+	switch i.currentProtocol {
+	case ImageProtocolBlocks:
+		// Generate the initial pixels by resizing the image (8x8 per cell).
+		pixels := i.resize(source, width*8, height*8)
+
+		// Turn them into block elements with background/foreground colors.
+		i.stamp(pixels)
+	case ImageProtocolHalfBlocks:
+		i.stampHalfBlocks(i.resize(source, width, height*2))
+	default:
+		i.graphic = i.encode(source)
+		i.graphicGeneration++
+	}
+}
+
+// layout calculates the size of the image in cells and the part of the
+// original image which is shown, based on the available space and the
+// current settings.
+func (i *Image) layout() (width, height int, crop image.Rectangle) {
 	bounds := i.image.Bounds()
+	crop = bounds
 	imageWidth, imageHeight := bounds.Dx(), bounds.Dy()
-	if i.aspectRatio != 1.0 {
-		imageWidth = int(float64(imageWidth) / i.aspectRatio)
+	if imageWidth <= 0 || imageHeight <= 0 {
+		return 0, 0, crop
+	}
+	aspectRatio := i.aspectRatio
+	if i.currentProtocol >= ImageProtocolSixel && i.cellWidth > 0 && i.cellHeight > 0 {
+		aspectRatio = float64(i.cellWidth) / float64(i.cellHeight)
 	}
-	width, height := i.width, i.height
 	_, _, innerWidth, innerHeight := i.GetInnerRect()
 	if i.labelWidth > 0 {
 		innerWidth -= i.labelWidth
@@ -318,26 +522,82 @@ func (i *Image) render() {
 		innerWidth -= TaggedStringWidth(i.label)
 	}
 	if innerWidth <= 0 {
-		i.pixels = nil
-		return
+		return 0, 0, crop
 	}
-	if width == 0 && height == 0 {
-		// Use all available space.
-		width, height = innerWidth, innerHeight
-		if adjustedWidth := imageWidth * height / imageHeight; adjustedWidth < width {
-			width = adjustedWidth
-		} else {
-			height = imageHeight * width / imageWidth
+
+	// Turn percentages into absolute values.
+	width, height = i.width, i.height
+	if width < 0 {
+		width = innerWidth * -width / 100
+	}
+	if height < 0 {
+		height = innerHeight * -height / 100
+	}
+
+	switch i.scaling {
+	case ImageScaleFill:
+		// Use the given or the available space and cut off what doesn't fit.
+		if width == 0 {
+			width = innerWidth
 		}
-	} else {
-		// Turn percentages into absolute values.
-		if width < 0 {
-			width = innerWidth * -width / 100
+		if height == 0 {
+			height = innerHeight
 		}
-		if height < 0 {
-			height = innerHeight * -height / 100
+		if width <= 0 || height <= 0 {
+			return 0, 0, crop
 		}
+		spaceRatio := float64(width) * aspectRatio / float64(height)
+		if imageRatio := float64(imageWidth) / float64(imageHeight); imageRatio > spaceRatio {
+			cropWidth := int(math.Round(float64(imageHeight) * spaceRatio))
+			crop.Min.X += (imageWidth - cropWidth) / 2
+			crop.Max.X = crop.Min.X + cropWidth
+		} else {
+			cropHeight := int(math.Round(float64(imageWidth) / spaceRatio))
+			crop.Min.Y += (imageHeight - cropHeight) / 2
+			crop.Max.Y = crop.Min.Y + cropHeight
+		}
+	case ImageScaleCenter:
+		// Use the original size, assuming one image pixel per screen pixel.
 		if width == 0 {
+			width = innerWidth
+		}
+		if height == 0 {
+			height = innerHeight
+		}
+		cellWidth, cellHeight := i.cellWidth, i.cellHeight
+		if cellWidth <= 0 || cellHeight <= 0 {
+			cellHeight = 16
+			cellWidth = int(math.Round(16 * aspectRatio))
+		}
+		naturalWidth := (imageWidth + cellWidth - 1) / cellWidth
+		naturalHeight := (imageHeight + cellHeight - 1) / cellHeight
+		if naturalWidth > width {
+			cropWidth := width * cellWidth
+			crop.Min.X += (imageWidth - cropWidth) / 2
+			crop.Max.X = crop.Min.X + cropWidth
+		} else {
+			width = naturalWidth
+		}
+		if naturalHeight > height {
+			cropHeight := height * cellHeight
+			crop.Min.Y += (imageHeight - cropHeight) / 2
+			crop.Max.Y = crop.Min.Y + cropHeight
+		} else {
+			height = naturalHeight
+		}
+	default:
+		if aspectRatio != 1.0 {
+			imageWidth = int(float64(imageWidth) / aspectRatio)
+		}
+		if width == 0 && height == 0 {
+			// Use all available space.
+			width, height = innerWidth, innerHeight
+			if adjustedWidth := imageWidth * height / imageHeight; adjustedWidth < width {
+				width = adjustedWidth
+			} else {
+				height = imageHeight * width / imageWidth
+			}
+		} else if width == 0 {
 			// Adjust the width.
 			width = imageWidth * height / imageHeight
 		} else if height == 0 {
@@ -345,45 +605,52 @@ func (i *Image) render() {
 			height = imageHeight * width / imageWidth
 		}
 	}
-	if width <= 0 || height <= 0 {
-		i.pixels = nil
-		return
-	}
+	return width, height, crop
+}
 
-	// If nothing has changed, we're done.
-	if i.lastWidth == width && i.lastHeight == height {
-		return
+// cropImage returns the given part of the image.
+func cropImage(img image.Image, rect image.Rectangle) image.Image {
+	if rect == img.Bounds() {
+		return img
+	}
+	if sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
 	}
-	i.lastWidth, i.lastHeight = width, height // This could still be larger than the available space but that's ok for now.
+	return croppedImage{Image: img, rect: rect}
+}
 
-	// Generate the initial pixels by resizing the image (8x8 per cell).
-	pixels := i.resize()
+// croppedImage is a part of an image which doesn't provide a SubImage method.
+type croppedImage struct {
+	image.Image
+	rect image.Rectangle
+}
 
-	// Turn them into block elements with background/foreground colors.
-	i.stamp(pixels)
+// Bounds returns the bounds of the cropped part.
+func (c croppedImage) Bounds() image.Rectangle {
+	return c.rect
 }
 
-// resize resizes the image to the current size and returns the result as a
-// slice of pixels. It is assumed that [Image.lastWidth] (w) and
-// [Image.lastHeight] (h) are positive, non-zero values, and the slice has a
-// size of 64*w*h, with each pixel being represented by 3 float64 values in the
-// range of 0-1. The factor of 64 is due to the fact that we calculate 8x8
-// pixels per cell.
-func (i *Image) resize() [][3]float64 {
+// resize resizes the given image to the given size (in pixels) and returns
+// the result as a slice of pixels. It is assumed that the size is positive,
+// and the slice has a size of tgtWidth*tgtHeight, with each pixel being
+// represented by 3 float64 values in the range of 0-1. For block elements, 8x8
+// pixels are calculated per cell.
+func (i *Image) resize(source image.Image, tgtWidth, tgtHeight int) [][3]float64 {
 	// Because most of the time, we will be downsizing the image, we don't even
 	// attempt to do any fancy interpolation. For each target pixel, we
 	// calculate a weighted average of the source pixels using their coverage
 	// area.
 
-	bounds := i.image.Bounds()
+	bounds := source.Bounds()
 	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
-	tgtWidth, tgtHeight := i.lastWidth*8, i.lastHeight*8
 	coverageWidth, coverageHeight := float64(tgtWidth)/float64(srcWidth), float64(tgtHeight)/float64(srcHeight)
 	pixels := make([][3]float64, tgtWidth*tgtHeight)
 	weights := make([]float64, tgtWidth*tgtHeight)
 	for srcY := bounds.Min.Y; srcY < bounds.Max.Y; srcY++ {
 		for srcX := bounds.Min.X; srcX < bounds.Max.X; srcX++ {
-			r32, g32, b32, _ := i.image.At(srcX, srcY).RGBA()
+			r32, g32, b32, _ := source.At(srcX, srcY).RGBA()
 			r, g, b := float64(r32)/0xffff, float64(g32)/0xffff, float64(b32)/0xffff
 
 			// Iterate over all target pixels. Outer loop is Y.
@@ -443,6 +710,14 @@ func (i *Image) stamp(resized [][3]float64) {
 	// given the available colors.
 	i.pixels = make([]pixel, i.lastWidth*i.lastHeight)
 	colors := i.GetColors()
+	if colors < TrueColor && i.dithering == DitheringOrdered {
+		// Apply the threshold map to 4x4 pixel blocks.
+		step := 1.0
+		if colors == 256 {
+			step = 1.0 / 6
+		}
+		orderedDithering(resized, i.lastWidth*8, i.lastHeight*8, step, 4)
+	}
 	for row := 0; row < i.lastHeight; row++ {
 		for col := 0; col < i.lastWidth; col++ {
 			// Calculate an error for each potential block element + color. Keep
@@ -667,15 +942,8 @@ func (i *Image) stamp(resized [][3]float64) {
 			}
 
 			// Apply dithering.
-			if colors < TrueColor && i.dithering == DitheringFloydSteinberg {
-				// The dithering mask determines how the error is distributed.
-				// Each element has three values: dx, dy, and weight (in 16th).
-				var mask = [4][3]int{
-					{1, 0, 7},
-					{-1, 1, 3},
-					{0, 1, 5},
-					{1, 1, 1},
-				}
+			if colors < TrueColor && (i.dithering == DitheringFloydSteinberg || i.dithering == DitheringAtkinson) {
+				mask := ditheringMasks[i.dithering]
 
 				// We dither the 8x8 block as a 2x2 block, transferring errors
 				// to its 2x2 neighbors.
@@ -710,10 +978,448 @@ func (i *Image) stamp(resized [][3]float64) {
 	}
 }
 
+// orderedDithering adds the threshold map to the given pixels, such that
+// quantizing them with the given step between color levels results in
+// ordered dithering. Each element of the threshold map covers blocks of
+// size x size pixels.
+func orderedDithering(pixels [][3]float64, width, height int, step float64, size int) {
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			threshold := ((bayerMatrix[(y/size)%4][(x/size)%4]+0.5)/16 - 0.5) * step
+			for ch := 0; ch < 3; ch++ {
+				pixels[y*width+x][ch] += threshold
+			}
+		}
+	}
+}
+
+// quantizeColor returns the color closest to the given one, given the number
+// of colors (2, 8, 256, or [TrueColor]). 256 colors refer to the 6x6x6 color
+// cube.
+func quantizeColor(color [3]float64, colors int) [3]float64 {
+	for ch := 0; ch < 3; ch++ {
+		color[ch] = math.Max(0, math.Min(1, color[ch]))
+	}
+	switch colors {
+	case 2:
+		// Monochrome, see [Image.stamp] for the weights.
+		if 0.299*color[0]+0.587*color[1]+0.114*color[2] < 0.5 {
+			return [3]float64{0, 0, 0}
+		}
+		return [3]float64{1, 1, 1}
+	case 8:
+		for ch := 0; ch < 3; ch++ {
+			color[ch] = math.Round(color[ch])
+		}
+	case 256:
+		for ch := 0; ch < 3; ch++ {
+			color[ch] = math.Round(color[ch]*5) / 5
+		}
+	}
+	return color
+}
+
+// quantize reduces the given pixels to the given number of colors (see
+// [quantizeColor]), applying the selected dithering algorithm.
+func (i *Image) quantize(pixels [][3]float64, width, height, colors int) {
+	mask := ditheringMasks[i.dithering]
+	if i.dithering == DitheringOrdered {
+		step := 1.0
+		if colors == 256 {
+			step = 1.0 / 5
+		}
+		orderedDithering(pixels, width, height, step, 1)
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			index := y*width + x
+			old := pixels[index]
+			pixels[index] = quantizeColor(old, colors)
+
+			// Distribute the error to the neighbors.
+			for _, dist := range mask {
+				targetX, targetY := x+dist[0], y+dist[1]
+				if targetX < 0 || targetX >= width || targetY >= height {
+					continue
+				}
+				for ch := 0; ch < 3; ch++ {
+					pixels[targetY*width+targetX][ch] += (old[ch] - pixels[index][ch]) * float64(dist[2]) / 16
+				}
+			}
+		}
+	}
+}
+
+// rgbColor converts a pixel to a tcell color.
+func rgbColor(color [3]float64) tcell.Color {
+	return tcell.NewRGBColor(int32(math.Min(255, math.Max(0, color[0]*255))), int32(math.Min(255, math.Max(0, color[1]*255))), int32(math.Min(255, math.Max(0, color[2]*255))))
+}
+
+// stampHalfBlocks populates the [Image.pixels] slice with upper half blocks,
+// taking the pixels generated by [Image.resize] with two pixels per cell.
+func (i *Image) stampHalfBlocks(resized [][3]float64) {
+	width, height := i.lastWidth, i.lastHeight
+	if colors := i.GetColors(); colors < TrueColor {
+		i.quantize(resized, width, height*2, colors)
+	}
+	i.pixels = make([]pixel, width*height)
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			i.pixels[row*width+col] = pixel{
+				element: BlockUpperHalfBlock,
+				style: tcell.StyleDefault.
+					Foreground(rgbColor(resized[row*2*width+col])).
+					Background(rgbColor(resized[(row*2+1)*width+col])),
+			}
+		}
+	}
+}
+
+// graphicCellSize returns the size of a cell in pixels used to encode images
+// for graphics protocols.
+func (i *Image) graphicCellSize() (width, height int) {
+	if i.cellWidth > 0 && i.cellHeight > 0 {
+		return i.cellWidth, i.cellHeight
+	}
+	return 10, 20
+}
+
+// encode returns the escape sequence which draws the given image with the
+// current graphics protocol, occupying [Image.lastWidth] x [Image.lastHeight]
+// cells. The sequence starts at the cursor position.
+func (i *Image) encode(source image.Image) []byte {
+	cellWidth, cellHeight := i.graphicCellSize()
+	width, height := i.lastWidth*cellWidth, i.lastHeight*cellHeight
+	if i.currentProtocol == ImageProtocolSixel {
+		height = height / 6 * 6 // Sixels are six pixels high, don't draw past the last row.
+	}
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	pixels := i.resize(source, width, height)
+
+	if i.currentProtocol == ImageProtocolSixel {
+		return i.encodeSixel(pixels, width, height)
+	}
+
+	// Kitty and iTerm2 accept PNG images.
+	rgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for index, pixel := range pixels {
+		for ch := 0; ch < 3; ch++ {
+			rgba.Pix[index*4+ch] = uint8(math.Min(255, math.Max(0, math.Round(pixel[ch]*255))))
+		}
+		rgba.Pix[index*4+3] = 255
+	}
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, rgba); err != nil {
+		return nil
+	}
+	data := base64.StdEncoding.EncodeToString(encoded.Bytes())
+
+	var buffer bytes.Buffer
+	if i.currentProtocol == ImageProtocolITerm2 {
+		fmt.Fprintf(&buffer, "\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=0:%s\x07", encoded.Len(), i.lastWidth, i.lastHeight, data)
+		return buffer.Bytes()
+	}
+
+	// The kitty protocol requires the data to be sent in chunks.
+	const chunkSize = 4096
+	for start := 0; start < len(data); start += chunkSize {
+		end := start + chunkSize
+		more := 1
+		if end >= len(data) {
+			end, more = len(data), 0
+		}
+		if start == 0 {
+			fmt.Fprintf(&buffer, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\", i.lastWidth, i.lastHeight, more, data[start:end])
+		} else {
+			fmt.Fprintf(&buffer, "\x1b_Gm=%d;%s\x1b\\", more, data[start:end])
+		}
+	}
+	return buffer.Bytes()
+}
+
+// encodeSixel returns the Sixel sequence for the given pixels. The height
+// must be a multiple of 6. At most 256 colors are used.
+func (i *Image) encodeSixel(pixels [][3]float64, width, height int) []byte {
+	colors, levels := i.GetColors(), 6
+	switch {
+	case colors > 256:
+		colors = 256
+	case colors <= 8:
+		levels = 2
+	}
+	i.quantize(pixels, width, height, colors)
+	indices := make([]int, len(pixels))
+	used := make(map[int]bool)
+	for index, pixel := range pixels {
+		r := int(math.Round(pixel[0] * float64(levels-1)))
+		g := int(math.Round(pixel[1] * float64(levels-1)))
+		b := int(math.Round(pixel[2] * float64(levels-1)))
+		indices[index] = (r*levels+g)*levels + b
+		used[indices[index]] = true
+	}
+
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+	for color := 0; color < levels*levels*levels; color++ {
+		if used[color] {
+			r, g, b := color/(levels*levels), color/levels%levels, color%levels
+			fmt.Fprintf(&buffer, "#%d;2;%d;%d;%d", color, r*100/(levels-1), g*100/(levels-1), b*100/(levels-1))
+		}
+	}
+
+	// Encode the image in bands of six rows, one pass per color.
+	bits := make(map[int][]byte)
+	for top := 0; top < height; top += 6 {
+		for color := range bits {
+			delete(bits, color)
+		}
+		var order []int
+		for row := 0; row < 6; row++ {
+			for x := 0; x < width; x++ {
+				color := indices[(top+row)*width+x]
+				band, ok := bits[color]
+				if !ok {
+					band = make([]byte, width)
+					bits[color] = band
+					order = append(order, color)
+				}
+				band[x] |= 1 << row
+			}
+		}
+		for index, color := range order {
+			if index > 0 {
+				buffer.WriteByte('$') // Back to the start of the band.
+			}
+			fmt.Fprintf(&buffer, "#%d", color)
+			band := bits[color]
+			for x := 0; x < width; {
+				run := 1
+				for x+run < width && band[x+run] == band[x] {
+					run++
+				}
+				if run > 3 {
+					fmt.Fprintf(&buffer, "!%d%c", run, 63+band[x])
+				} else {
+					buffer.WriteString(strings.Repeat(string(rune(63+band[x])), run))
+				}
+				x += run
+			}
+		}
+		buffer.WriteByte('-') // Next band.
+	}
+	buffer.WriteString("\x1b\\")
+	return buffer.Bytes()
+}
+
+// The graphics protocol supported by the terminal, detected once.
+var (
+	detectedImageProtocol     int
+	detectedImageProtocolOnce sync.Once
+)
+
+// detectImageProtocol returns the graphics protocol supported by the
+// terminal, based on environment variables, or [ImageProtocolBlocks] if none
+// is known to be supported.
+func detectImageProtocol() int {
+	detectedImageProtocolOnce.Do(func() {
+		detectedImageProtocol = ImageProtocolBlocks
+		term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+		if os.Getenv("TMUX") != "" || strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux") {
+			return // Multiplexers don't pass graphics through.
+		}
+		switch {
+		case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || term == "xterm-ghostty" || program == "ghostty":
+			detectedImageProtocol = ImageProtocolKitty
+		case program == "iTerm.app" || program == "WezTerm" || os.Getenv("LC_TERMINAL") == "iTerm2":
+			detectedImageProtocol = ImageProtocolITerm2
+		case strings.HasPrefix(term, "foot") || strings.Contains(term, "mlterm") || strings.Contains(term, "sixel") || program == "mintty":
+			detectedImageProtocol = ImageProtocolSixel
+		}
+	})
+	return detectedImageProtocol
+}
+
+// imagePlacement is an image drawn with a graphics protocol at a screen
+// position.
+type imagePlacement struct {
+	owner               *Image
+	generation          int
+	protocol            int
+	x, y, width, height int
+	data                []byte
+}
+
+// imageGraphics keeps track of the images drawn with graphics protocols on an
+// application's screen. Because tcell doesn't know about them, the image data
+// is written to the terminal after the screen was updated and the cells
+// covered by images are locked so tcell doesn't overwrite them.
+type imageGraphics struct {
+	// Protects all fields below.
+	mu sync.Mutex
+
+	// The screen, its terminal, and the size of a cell in pixels (0 if
+	// unknown).
+	screen                tcell.Screen
+	tty                   tcell.Tty
+	cellWidth, cellHeight int
+
+	// The images currently visible on the terminal and the images placed
+	// during the current draw.
+	shown, pending []imagePlacement
+}
+
+// The graphics of the screens of all running applications.
+var (
+	imageGraphicsMutex  sync.Mutex
+	imageGraphicsScreen = make(map[tcell.Screen]*imageGraphics)
+)
+
+// imageGraphicsFor returns the graphics for the given screen or nil if images
+// can't be drawn with graphics protocols on this screen.
+func imageGraphicsFor(screen tcell.Screen) *imageGraphics {
+	imageGraphicsMutex.Lock()
+	defer imageGraphicsMutex.Unlock()
+	return imageGraphicsScreen[screen]
+}
+
+// begin is called by the application before drawing its primitives onto the
+// given screen.
+func (g *imageGraphics) begin(screen tcell.Screen) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if screen != g.screen {
+		imageGraphicsMutex.Lock()
+		delete(imageGraphicsScreen, g.screen)
+		imageGraphicsMutex.Unlock()
+		g.screen, g.tty, g.shown = screen, nil, nil
+		if tty, ok := screen.Tty(); ok {
+			g.tty = tty
+			if size, err := tty.WindowSize(); err == nil {
+				g.cellWidth, g.cellHeight = size.CellDimensions()
+			}
+			imageGraphicsMutex.Lock()
+			imageGraphicsScreen[screen] = g
+			imageGraphicsMutex.Unlock()
+		}
+	}
+	for _, placement := range g.shown {
+		screen.LockRegion(placement.x, placement.y, placement.width, placement.height, false)
+	}
+	g.pending = g.pending[:0]
+}
+
+// place adds an image to the current draw.
+func (g *imageGraphics) place(placement imagePlacement) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pending = append(g.pending, placement)
+}
+
+// cellSize returns the size of a cell in pixels or 0, 0 if it is unknown.
+func (g *imageGraphics) cellSize() (width, height int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.cellWidth, g.cellHeight
+}
+
+// show updates the screen and writes all images which are not visible yet. If
+// a visible image was removed or changed, the entire screen is redrawn.
+func (g *imageGraphics) show(screen tcell.Screen) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.tty == nil || len(g.shown) == 0 && len(g.pending) == 0 {
+		screen.Show()
+		return
+	}
+
+	contains := func(placements []imagePlacement, placement imagePlacement) bool {
+		for _, p := range placements {
+			if p.owner == placement.owner && p.generation == placement.generation &&
+				p.x == placement.x && p.y == placement.y && p.width == placement.width && p.height == placement.height {
+				return true
+			}
+		}
+		return false
+	}
+	redraw := false
+	for _, placement := range g.shown {
+		if !contains(g.pending, placement) {
+			redraw = true
+			break
+		}
+	}
+
+	var output bytes.Buffer
+	if redraw {
+		screen.Sync()
+		for _, placement := range g.shown {
+			if placement.protocol == ImageProtocolKitty {
+				output.WriteString("\x1b_Ga=d,d=A,q=2\x1b\\") // Clearing the screen may not remove kitty images.
+				break
+			}
+		}
+	} else {
+		for _, placement := range g.pending {
+			if contains(g.shown, placement) {
+				screen.LockRegion(placement.x, placement.y, placement.width, placement.height, true)
+			}
+		}
+		screen.Show()
+	}
+	for _, placement := range g.pending {
+		if redraw || !contains(g.shown, placement) {
+			fmt.Fprintf(&output, "\x1b7\x1b[%d;%dH", placement.y+1, placement.x+1)
+			output.Write(placement.data)
+			output.WriteString("\x1b8")
+		}
+		screen.LockRegion(placement.x, placement.y, placement.width, placement.height, true)
+	}
+	if output.Len() > 0 {
+		g.tty.Write(output.Bytes())
+	}
+	g.shown = append(g.shown[:0], g.pending...)
+}
+
+// invalidate is called when the terminal was cleared, e.g. after a resize.
+// All images will be written again during the next draw.
+func (g *imageGraphics) invalidate(screen tcell.Screen) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if screen == g.screen {
+		for _, placement := range g.shown {
+			screen.LockRegion(placement.x, placement.y, placement.width, placement.height, false)
+		}
+		if g.tty != nil {
+			if size, err := g.tty.WindowSize(); err == nil {
+				g.cellWidth, g.cellHeight = size.CellDimensions()
+			}
+		}
+	}
+	g.shown = nil
+}
+
 // Draw draws this primitive onto the screen.
 func (i *Image) Draw(screen tcell.Screen) {
 	i.DrawForSubclass(screen, i)
 
+	// Determine the protocol to use.
+	graphics := imageGraphicsFor(screen)
+	i.currentProtocol, i.cellWidth, i.cellHeight = i.protocol, 0, 0
+	if graphics != nil {
+		i.cellWidth, i.cellHeight = graphics.cellSize()
+	}
+	if i.currentProtocol == ImageProtocolAuto {
+		i.currentProtocol = detectImageProtocol()
+		if i.currentProtocol == ImageProtocolSixel && i.cellWidth == 0 {
+			i.currentProtocol = ImageProtocolBlocks // We need to know the exact size of sixels.
+		}
+	}
+	if graphics == nil && i.currentProtocol >= ImageProtocolSixel {
+		i.currentProtocol = ImageProtocolBlocks
+	}
+
 	// Regenerate image if necessary.
 	i.render()
 
@@ -747,7 +1453,35 @@ func (i *Image) Draw(screen tcell.Screen) {
 		y += viewHeight - height
 	}
 
+	// Graphics protocols draw the image after the screen was updated. The
+	// image must be fully visible.
+	if i.graphic != nil {
+		if x < viewX || y < viewY || x+width > viewX+viewWidth || y+height > viewY+viewHeight {
+			return
+		}
+		style := tcell.StyleDefault.Background(i.backgroundColor)
+		for row := 0; row < height; row++ {
+			for col := 0; col < width; col++ {
+				screen.SetContent(x+col, y+row, ' ', nil, style)
+			}
+		}
+		graphics.place(imagePlacement{
+			owner:      i,
+			generation: i.graphicGeneration,
+			protocol:   i.currentProtocol,
+			x:          x,
+			y:          y,
+			width:      width,
+			height:     height,
+			data:       i.graphic,
+		})
+		return
+	}
+
 	// Draw the image.
+	if len(i.pixels) < width*height {
+		return
+	}
 	for row := 0; row < height; row++ {
 		if y+row < viewY || y+row >= viewY+viewHeight {
 			continue
@@ -2,7 +2,9 @@ package tview
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/gdamore/tcell/v2"
 )
@@ -13,6 +15,11 @@ type listItem struct {
 	SecondaryText string // A secondary text to be shown underneath the main text.
 	Shortcut      rune   // The key to select the list item directly, 0 if there is no shortcut.
 	Selected      func() // The optional function which is called when the item is selected.
+	Marked        bool   // Whether the item is part of the multi-selection.
+	Header        bool   // Whether the item is a group header.
+	Collapsed     bool   // Whether the items of a header's group are hidden.
+	Disabled      bool   // Whether the item cannot be navigated to or selected.
+	Separator     bool   // Whether the item is a separator line (always disabled).
 }
 
 // List displays rows of items, each of which can be selected. List items can be
@@ -26,9 +33,44 @@ type listItem struct {
 //   - End: Move to the last item.
 //   - Page down: Move down one page.
 //   - Page up: Move up one page.
-//   - Enter / Space: Select the current item.
+//   - Enter / Space: Select the current item. In multi-select mode, Space
+//     toggles whether the current item is part of the multi-selection.
 //   - Right / left: Scroll horizontally. Only if the list is wider than the
 //     available space.
+//   - /: Start typing a filter text. Only if the list is filterable (see
+//     [List.SetFilterable]).
+//   - Alt-Up / Alt-Down: Move the current item up or down. Only if the list
+//     is reorderable (see [List.SetReorderable]). Items of reorderable lists
+//     can also be moved by dragging them with the mouse.
+//
+// Disabled items (see [List.SetItemDisabled]) are drawn dimmed. They are
+// skipped during navigation and cannot be selected.
+//
+// If the items don't fit into the available space, an optional scroll bar
+// (see [List.ShowScrollBar]) or indicators (see
+// [List.ShowOverflowIndicators]) show that there are more items.
+//
+// Items can be organized in groups by inserting headers (see
+// [List.AddHeader]). A group consists of the items following its header up
+// to the next header. Headers are skipped during navigation. Clicking on a
+// header collapses or expands its group. Pressing "-" collapses the group of
+// the current item, the header then becomes the current item. Pressing Enter,
+// Space, or "+" on the header of a collapsed group expands it. Separator
+// lines (see [List.AddSeparator]) may be added between items, too.
+//
+// Instead of storing all items, a list may retrieve the texts of the visible
+// items from a function (see [List.SetItemProvider]). This allows lists with
+// a very large number of items. The application may also draw the items
+// itself (see [List.SetItemDrawFunc]).
+//
+// In multi-select mode (see [List.SetMultiSelect]), each item is prefixed with
+// a marker showing whether it is part of the multi-selection. See
+// [List.GetSelectedItems] for a way to retrieve the selected items.
+//
+// Items can be narrowed down with a filter text (see [List.SetFilterText]).
+// Only items whose main text contains the characters of the filter text in
+// the same order (ignoring case) are then shown, with the matching characters
+// highlighted. Item indices always refer to the full list of items.
 //
 // See [List.SetChangedFunc] for a way to be notified when the user navigates
 // to a list item. See [List.SetSelectedFunc] for a way to be notified when a
@@ -97,6 +139,107 @@ type List struct {
 
 	// Whether or not to wrap text
 	wrapText bool
+
+	// Whether the user may type a filter text after pressing '/'.
+	filterable bool
+
+	// Whether the user is currently typing the filter text.
+	filtering bool
+
+	// The text the items are filtered by, empty if they are not filtered.
+	filterText string
+
+	// The indices of the items matching the filter text, in ascending order.
+	// Only valid if filterDirty is false.
+	filteredItems []int
+
+	// The positions of the grapheme clusters of the items' main texts matched
+	// by the filter text, mapped by item index.
+	filterMatches map[int][]int
+
+	// Set to true if the items changed since the filter was last applied.
+	filterDirty bool
+
+	// The style of main text characters matched by the filter text.
+	filterMatchStyle tcell.Style
+
+	// The function matching the filter text against the items' main texts. If
+	// nil, fuzzyMatch() is used.
+	filterMatch func(pattern, text string) []int
+
+	// Whether the filter prompt is not drawn, e.g. because the owner of the
+	// list shows the filter text elsewhere (see DropDown).
+	filterPromptHidden bool
+
+	// Whether the user may select multiple items.
+	multiSelect bool
+
+	// The markers printed in front of selected and unselected items in
+	// multi-select mode.
+	markerSelected, markerUnselected string
+
+	// The screen x-coordinate of the markers and their width as of the last
+	// time the list was drawn.
+	markerX, markerWidth int
+
+	// An optional function which is called when an item was added to or
+	// removed from the multi-selection by the user.
+	toggled func(index int, selected bool)
+
+	// An optional function which provides the texts of the items. If set, it
+	// replaces the items added with AddItem().
+	provider func(index int) (main, secondary string)
+
+	// The number of items provided by the provider.
+	providedCount int
+
+	// The indices of the provided items which are part of the multi-selection.
+	providedMarks map[int]bool
+
+	// The indices of the provided items which are disabled.
+	providedDisabled map[int]bool
+
+	// The style of the texts of disabled items.
+	disabledStyle tcell.Style
+
+	// Whether the user may move items.
+	reorderable bool
+
+	// The index of the item currently being dragged with the mouse (-1 if
+	// none) and its index when dragging started.
+	dragItem, dragStart int
+
+	// The style of the item being dragged with the mouse.
+	dragStyle tcell.Style
+
+	// An optional function which is called when the user moved an item.
+	moved func(from, to int)
+
+	// An optional function which draws the main text line of an item.
+	itemDraw func(screen tcell.Screen, x, y, width, index int, selected bool)
+
+	// The style of group headers.
+	headerStyle tcell.Style
+
+	// The number of collapsed groups.
+	collapsedGroups int
+
+	// Whether a scroll bar is shown when the items don't fit.
+	showScrollBar bool
+
+	// Whether indicators are shown when there are items above or below the
+	// visible items.
+	showOverflowIndicators bool
+
+	// The style of the scroll bar and the overflow indicators.
+	scrollBarStyle tcell.Style
+
+	// The position of the scroll bar as of the last time the list was drawn.
+	// Its length is 0 if it was not drawn.
+	bar scrollBar
+
+	// Whether the scroll bar is currently being dragged with the mouse.
+	scrollBarDragging bool
 }
 
 // NewList returns a new list.
@@ -109,6 +252,14 @@ func NewList() *List {
 		secondaryTextStyle: tcell.StyleDefault.Foreground(Styles.TertiaryTextColor),
 		shortcutStyle:      tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
 		selectedStyle:      tcell.StyleDefault.Foreground(Styles.PrimitiveBackgroundColor).Background(Styles.PrimaryTextColor),
+		filterMatchStyle:   tcell.StyleDefault.Foreground(Styles.SecondaryTextColor).Underline(true),
+		markerSelected:     "[x] ",
+		markerUnselected:   "[ ] ",
+		dragItem:           -1,
+		dragStyle:          tcell.StyleDefault.Foreground(Styles.PrimaryTextColor).Background(Styles.MoreContrastBackgroundColor),
+		headerStyle:        tcell.StyleDefault.Foreground(Styles.SecondaryTextColor).Bold(true),
+		scrollBarStyle:     tcell.StyleDefault.Foreground(Styles.GraphicsColor),
+		disabledStyle:      tcell.StyleDefault.Foreground(Styles.TertiaryTextColor).Dim(true),
 	}
 }
 
@@ -119,18 +270,19 @@ func NewList() *List {
 //
 // Calling this function triggers a "changed" event if the selection changes.
 func (l *List) SetCurrentItem(index int) *List {
+	count := l.itemCount()
 	if index < 0 {
-		index = len(l.items) + index
+		index = count + index
 	}
-	if index >= len(l.items) {
-		index = len(l.items) - 1
+	if index >= count {
+		index = count - 1
 	}
 	if index < 0 {
 		index = 0
 	}
 
-	if index != l.currentItem && l.changed != nil {
-		item := l.items[index]
+	if index != l.currentItem && index < count && l.changed != nil {
+		item := l.getItem(index)
 		l.changed(index, item.MainText, item.SecondaryText, item.Shortcut)
 	}
 
@@ -193,7 +345,11 @@ func (l *List) RemoveItem(index int) *List {
 	}
 
 	// Remove item.
+	if item := l.items[index]; item.Header && item.Collapsed {
+		l.collapsedGroups--
+	}
 	l.items = append(l.items[:index], l.items[index+1:]...)
+	l.filterDirty = true
 
 	// If there is nothing left, we're done.
 	if len(l.items) == 0 {
@@ -215,6 +371,43 @@ func (l *List) RemoveItem(index int) *List {
 	return l
 }
 
+// SetItemDrawFunc sets a function which draws the main text line of each
+// visible item instead of the list, e.g. to render icons, progress bars, or
+// multiple columns. It receives the screen area of the line (one row at the
+// given position with the given width) and the item's index. The "selected"
+// flag indicates whether the item is the currently selected item and should
+// be highlighted. Shortcuts, multi-selection markers, and secondary texts are
+// still drawn by the list. Navigation and selection work as usual.
+//
+// Provide nil to let the list draw the main texts again.
+func (l *List) SetItemDrawFunc(handler func(screen tcell.Screen, x, y, width, index int, selected bool)) *List {
+	l.itemDraw = handler
+	return l
+}
+
+// ShowScrollBar sets whether a vertical scroll bar is shown at the right edge
+// of the list when its items don't fit into the available space. It takes up
+// one column and can be dragged with the mouse to scroll the list.
+func (l *List) ShowScrollBar(show bool) *List {
+	l.showScrollBar = show
+	return l
+}
+
+// ShowOverflowIndicators sets whether "↑ more" and "↓ more" are shown at the
+// right end of the first and last rows of the list when there are more items
+// above or below the visible items.
+func (l *List) ShowOverflowIndicators(show bool) *List {
+	l.showOverflowIndicators = show
+	return l
+}
+
+// SetScrollBarStyle sets the style of the scroll bar and the overflow
+// indicators.
+func (l *List) SetScrollBarStyle(style tcell.Style) *List {
+	l.scrollBarStyle = style
+	return l
+}
+
 // SetWrapText sets the flag that determines whether text is wrapped or not
 func (l *List) SetWrapText(wrap bool) *List {
 	l.wrapText = wrap
@@ -374,13 +567,191 @@ func (l *List) AddItem(mainText, secondaryText string, shortcut rune, selected f
 // was previously empty, a "changed" event is fired because the new item becomes
 // selected.
 func (l *List) InsertItem(index int, mainText, secondaryText string, shortcut rune, selected func()) *List {
-	item := &listItem{
+	l.insertItem(index, &listItem{
 		MainText:      mainText,
 		SecondaryText: secondaryText,
 		Shortcut:      shortcut,
 		Selected:      selected,
+	})
+	return l
+}
+
+// AddHeader calls InsertHeader() with an index of -1.
+func (l *List) AddHeader(text string) *List {
+	return l.InsertHeader(-1, text)
+}
+
+// InsertHeader adds a group header with the given text to the list at the
+// specified index (see InsertItem() for how the index is interpreted). The
+// header's group consists of the items following it up to the next header.
+// Headers are drawn with the header style (see SetHeaderStyle()) and cannot be
+// selected.
+func (l *List) InsertHeader(index int, text string) *List {
+	l.insertItem(index, &listItem{
+		MainText: text,
+		Header:   true,
+	})
+	return l
+}
+
+// AddSeparator calls InsertSeparator() with an index of -1.
+func (l *List) AddSeparator() *List {
+	return l.InsertSeparator(-1)
+}
+
+// InsertSeparator adds a horizontal separator line to the list at the
+// specified index (see InsertItem() for how the index is interpreted).
+// Separators are drawn with the disabled style (see SetDisabledStyle()), they
+// are always disabled, and they are hidden while the list is filtered.
+func (l *List) InsertSeparator(index int) *List {
+	l.insertItem(index, &listItem{
+		Disabled:  true,
+		Separator: true,
+	})
+	return l
+}
+
+// IsHeader returns whether the item with the given index is a group header.
+// Panics if the index is out of range.
+func (l *List) IsHeader(index int) bool {
+	return l.getItem(index).Header
+}
+
+// SetGroupCollapsed sets whether the items of the group of the header with the
+// given index are hidden. If the current item is hidden, the header becomes the
+// current item. If the header is the current item when its group is expanded,
+// the group's first item becomes the current item. This has no effect if the
+// index does not refer to a header.
+func (l *List) SetGroupCollapsed(header int, collapsed bool) *List {
+	if l.provider != nil || header < 0 || header >= len(l.items) {
+		return l
+	}
+	item := l.items[header]
+	if !item.Header || item.Collapsed == collapsed {
+		return l
+	}
+	item.Collapsed = collapsed
+	if collapsed {
+		l.collapsedGroups++
+	} else {
+		l.collapsedGroups--
+	}
+	l.filterDirty = true
+
+	// Adjust the current item.
+	if collapsed && l.currentItem > header && l.itemPosition(l.currentItem) < 0 {
+		l.SetCurrentItem(header)
+	} else if !collapsed && l.currentItem == header && header+1 < len(l.items) && l.itemPosition(header+1) >= 0 && l.isSelectable(header+1) {
+		l.SetCurrentItem(header + 1)
+	}
+	l.adjustOffset()
+
+	return l
+}
+
+// IsGroupCollapsed returns whether the items of the group of the header with
+// the given index are hidden.
+func (l *List) IsGroupCollapsed(header int) bool {
+	if l.provider != nil || header < 0 || header >= len(l.items) {
+		return false
+	}
+	return l.items[header].Header && l.items[header].Collapsed
+}
+
+// SetHeaderStyle sets the style of group headers.
+func (l *List) SetHeaderStyle(style tcell.Style) *List {
+	l.headerStyle = style
+	return l
+}
+
+// groupHeader returns the index of the header of the group the item with the
+// given index belongs to or -1 if there is no such header.
+func (l *List) groupHeader(index int) int {
+	for ; index >= 0 && l.provider == nil; index-- {
+		if l.items[index].Header {
+			return index
+		}
+	}
+	return -1
+}
+
+// SetItemDisabled sets whether the item with the given index is disabled.
+// Disabled items are drawn with the disabled style (see SetDisabledStyle()),
+// they are skipped during navigation, and they cannot be selected, neither
+// with the keyboard nor with the mouse. Panics if the index is out of range.
+func (l *List) SetItemDisabled(index int, disabled bool) *List {
+	if l.provider == nil {
+		l.items[index].Disabled = disabled || l.items[index].Separator
+	} else if disabled {
+		if l.providedDisabled == nil {
+			l.providedDisabled = make(map[int]bool)
+		}
+		l.providedDisabled[index] = true
+	} else {
+		delete(l.providedDisabled, index)
+	}
+	return l
+}
+
+// IsItemDisabled returns whether the item with the given index is disabled.
+// Panics if the index is out of range.
+func (l *List) IsItemDisabled(index int) bool {
+	if l.provider != nil {
+		return l.providedDisabled[index]
+	}
+	return l.items[index].Disabled
+}
+
+// SetDisabledStyle sets the style of the main and secondary texts of disabled
+// items.
+func (l *List) SetDisabledStyle(style tcell.Style) *List {
+	l.disabledStyle = style
+	return l
+}
+
+// isSelectable returns whether the item with the given index may become the
+// current item through navigation.
+func (l *List) isSelectable(index int) bool {
+	if l.provider != nil {
+		return !l.providedDisabled[index]
 	}
+	item := l.items[index]
+	return !item.Disabled && (!item.Header || item.Collapsed)
+}
 
+// selectablePosition returns the position of the first item at or after the
+// given position (or before it, for a negative direction) which may become the
+// current item, wrapping around if the list wraps around. If there is no such
+// item, items in the opposite direction are considered. Returns -1 if no item
+// may become the current item.
+func (l *List) selectablePosition(position, direction int) int {
+	count := l.visibleCount()
+	if count == 0 {
+		return -1
+	}
+	start := position
+	for tries := 0; tries < count; tries++ {
+		if position < 0 || position >= count {
+			if !l.wrapAround {
+				break
+			}
+			position = (position + count) % count
+		}
+		if l.isSelectable(l.itemAtPosition(position)) {
+			return position
+		}
+		position += direction
+	}
+	for position = start - direction; position >= 0 && position < count; position -= direction {
+		if l.isSelectable(l.itemAtPosition(position)) {
+			return position
+		}
+	}
+	return -1
+}
+
+// insertItem inserts the given item at the given index (see InsertItem()).
+func (l *List) insertItem(index int, item *listItem) {
 	// Shift index to range.
 	if index < 0 {
 		index = len(l.items) + index + 1
@@ -402,33 +773,46 @@ func (l *List) InsertItem(index int, mainText, secondaryText string, shortcut ru
 		copy(l.items[index+1:], l.items[index:])
 	}
 	l.items[index] = item
+	l.filterDirty = true
 
 	// Fire a "change" event for the first item in the list.
-	if len(l.items) == 1 && l.changed != nil {
+	if len(l.items) == 1 && l.changed != nil && !item.Header {
 		item := l.items[0]
 		l.changed(0, item.MainText, item.SecondaryText, item.Shortcut)
 	}
 
-	return l
+	// Headers don't remain the current item.
+	if index != l.currentItem && !l.isSelectable(l.currentItem) && l.isSelectable(index) {
+		l.currentItem = index
+		if l.changed != nil {
+			l.changed(index, item.MainText, item.SecondaryText, item.Shortcut)
+		}
+	}
 }
 
 // GetItemCount returns the number of items in the list.
 func (l *List) GetItemCount() int {
-	return len(l.items)
+	return l.itemCount()
 }
 
 // GetItemText returns an item's texts (main and secondary). Panics if the index
 // is out of range.
 func (l *List) GetItemText(index int) (main, secondary string) {
-	return l.items[index].MainText, l.items[index].SecondaryText
+	item := l.getItem(index)
+	return item.MainText, item.SecondaryText
 }
 
 // SetItemText sets an item's main and secondary text. Panics if the index is
-// out of range.
+// out of range. This has no effect while an item provider is set (see
+// SetItemProvider()).
 func (l *List) SetItemText(index int, main, secondary string) *List {
+	if l.provider != nil {
+		return l
+	}
 	item := l.items[index]
 	item.MainText = main
 	item.SecondaryText = secondary
+	l.filterDirty = true
 	return l
 }
 
@@ -452,7 +836,8 @@ func (l *List) FindItems(mainSearch, secondarySearch string, mustContainBoth, ig
 		secondarySearch = strings.ToLower(secondarySearch)
 	}
 
-	for index, item := range l.items {
+	for index := 0; index < l.itemCount(); index++ {
+		item := l.getItem(index)
 		mainText := item.MainText
 		secondaryText := item.SecondaryText
 		if ignoreCase {
@@ -472,13 +857,441 @@ func (l *List) FindItems(mainSearch, secondarySearch string, mustContainBoth, ig
 	return
 }
 
+// MoveItem moves the item with the given index to a new index, shifting the
+// items in between. The currently selected item is shifted accordingly. Out of
+// range indices are ignored. This has no effect while an item provider is set
+// (see SetItemProvider()).
+func (l *List) MoveItem(from, to int) *List {
+	if l.provider != nil || from < 0 || from >= len(l.items) || to < 0 || to >= len(l.items) || from == to {
+		return l
+	}
+	item := l.items[from]
+	if from < to {
+		copy(l.items[from:to], l.items[from+1:to+1])
+	} else {
+		copy(l.items[to+1:from+1], l.items[to:from])
+	}
+	l.items[to] = item
+	l.filterDirty = true
+
+	// Shift current item.
+	switch {
+	case l.currentItem == from:
+		l.currentItem = to
+	case from < l.currentItem && l.currentItem <= to:
+		l.currentItem--
+	case to <= l.currentItem && l.currentItem < from:
+		l.currentItem++
+	}
+
+	return l
+}
+
+// SetReorderable sets whether the user may move items, either by dragging them
+// with the mouse or by pressing Alt-Up and Alt-Down. Items of lists with an
+// item provider (see SetItemProvider()) cannot be moved.
+func (l *List) SetReorderable(reorderable bool) *List {
+	l.reorderable = reorderable
+	return l
+}
+
+// SetDragStyle sets the style of the item which is being dragged with the
+// mouse. It marks the position the item will be dropped at.
+func (l *List) SetDragStyle(style tcell.Style) *List {
+	l.dragStyle = style
+	return l
+}
+
+// SetMovedFunc sets a function which is called when the user moved an item.
+// The function receives the item's previous and new index.
+func (l *List) SetMovedFunc(handler func(from, to int)) *List {
+	l.moved = handler
+	return l
+}
+
+// moveItem moves an item on behalf of the user.
+func (l *List) moveItem(from, to int) {
+	l.MoveItem(from, to)
+	if l.moved != nil {
+		l.moved(from, to)
+	}
+}
+
+// SetItemProvider sets a function which provides the main and secondary texts
+// of the items with the given indices, replacing the items added with
+// AddItem() and InsertItem() (which are kept but not shown). The number of
+// items is set with SetItemCount(). The function is only called for items which
+// are drawn, so that lists with hundreds of thousands of entries do not need
+// to store them all. (Filtering such a list, however, retrieves the texts of
+// all items.) Provided items have no shortcuts or individual "selected"
+// callbacks. Use SetSelectedFunc() to be notified of selections.
+//
+// Provide nil to show the items added with AddItem() again.
+func (l *List) SetItemProvider(provider func(index int) (main, secondary string)) *List {
+	l.provider = provider
+	l.providedMarks = nil
+	l.providedDisabled = nil
+	l.filterDirty = true
+	l.SetCurrentItem(l.currentItem)
+	return l
+}
+
+// SetItemCount sets the number of items retrieved from the function set with
+// SetItemProvider(). If the currently selected item is no longer available,
+// the last item is selected.
+func (l *List) SetItemCount(count int) *List {
+	if count < 0 {
+		count = 0
+	}
+	l.providedCount = count
+	for index := range l.providedMarks {
+		if index >= count {
+			delete(l.providedMarks, index)
+		}
+	}
+	for index := range l.providedDisabled {
+		if index >= count {
+			delete(l.providedDisabled, index)
+		}
+	}
+	l.filterDirty = true
+	if l.provider != nil {
+		l.SetCurrentItem(l.currentItem)
+	}
+	return l
+}
+
+// itemCount returns the number of items, either the stored or the provided
+// ones.
+func (l *List) itemCount() int {
+	if l.provider != nil {
+		return l.providedCount
+	}
+	return len(l.items)
+}
+
+// getItem returns the item with the given index. Provided items are returned
+// as a new object, modifying it has no effect.
+func (l *List) getItem(index int) *listItem {
+	if l.provider == nil {
+		return l.items[index]
+	}
+	main, secondary := l.provider(index)
+	return &listItem{
+		MainText:      main,
+		SecondaryText: secondary,
+		Marked:        l.providedMarks[index],
+		Disabled:      l.providedDisabled[index],
+	}
+}
+
 // Clear removes all items from the list.
 func (l *List) Clear() *List {
 	l.items = nil
 	l.currentItem = 0
+	l.collapsedGroups = 0
+	l.filterDirty = true
+	return l
+}
+
+// SetMultiSelect sets whether the user may select multiple items. If enabled,
+// each item is prefixed with a marker (see SetSelectionMarkers()) and pressing
+// the space bar or clicking on the marker toggles whether the current item is
+// selected. The multi-selection is independent of the current item.
+func (l *List) SetMultiSelect(multiSelect bool) *List {
+	l.multiSelect = multiSelect
+	return l
+}
+
+// SetSelectionMarkers sets the texts printed in front of the main texts of
+// selected and unselected items in multi-select mode. They are printed as is
+// (style tags are not interpreted) and should have the same screen width. The
+// defaults are "[x] " and "[ ] ".
+func (l *List) SetSelectionMarkers(selected, unselected string) *List {
+	l.markerSelected, l.markerUnselected = selected, unselected
+	return l
+}
+
+// SetToggledFunc sets a function which is called when the user adds an item
+// to or removes it from the multi-selection. The function receives the item's
+// index and whether it is now selected.
+func (l *List) SetToggledFunc(handler func(index int, selected bool)) *List {
+	l.toggled = handler
+	return l
+}
+
+// SetItemSelected sets whether the item with the given index is part of the
+// multi-selection. Panics if the index is out of range.
+func (l *List) SetItemSelected(index int, selected bool) *List {
+	if l.provider == nil {
+		l.items[index].Marked = selected
+	} else if selected {
+		if l.providedMarks == nil {
+			l.providedMarks = make(map[int]bool)
+		}
+		l.providedMarks[index] = true
+	} else {
+		delete(l.providedMarks, index)
+	}
+	return l
+}
+
+// ToggleItemSelected adds the item with the given index to the
+// multi-selection if it is not part of it, or removes it otherwise. Panics if
+// the index is out of range.
+func (l *List) ToggleItemSelected(index int) *List {
+	return l.SetItemSelected(index, !l.IsItemSelected(index))
+}
+
+// IsItemSelected returns whether the item with the given index is part of the
+// multi-selection. Panics if the index is out of range.
+func (l *List) IsItemSelected(index int) bool {
+	if l.provider != nil {
+		return l.providedMarks[index]
+	}
+	return l.items[index].Marked
+}
+
+// GetSelectedItems returns the indices of the items which are part of the
+// multi-selection, in ascending order. This includes items hidden by a filter.
+func (l *List) GetSelectedItems() (indices []int) {
+	if l.provider != nil {
+		for index := range l.providedMarks {
+			indices = append(indices, index)
+		}
+		sort.Ints(indices)
+		return
+	}
+	for index, item := range l.items {
+		if item.Marked {
+			indices = append(indices, index)
+		}
+	}
+	return
+}
+
+// ClearSelectedItems removes all items from the multi-selection.
+func (l *List) ClearSelectedItems() *List {
+	for _, item := range l.items {
+		item.Marked = false
+	}
+	l.providedMarks = nil
 	return l
 }
 
+// toggleItem toggles the multi-selection state of the item with the given
+// index on behalf of the user.
+func (l *List) toggleItem(index int) {
+	l.ToggleItemSelected(index)
+	if l.toggled != nil {
+		l.toggled(index, l.IsItemSelected(index))
+	}
+}
+
+// SetFilterable sets whether the user may filter the list by pressing '/' and
+// typing a filter text (see SetFilterText()). While typing, Backspace removes
+// the last character, Enter finishes typing, and Escape removes the filter
+// text. Any other keys navigate the list as usual. After typing, Escape
+// removes the filter text before it invokes the "done" handler. Note that '/'
+// can then not be used as a shortcut.
+func (l *List) SetFilterable(filterable bool) *List {
+	l.filterable = filterable
+	if !filterable {
+		l.filtering = false
+	}
+	return l
+}
+
+// SetFilterText sets the text the list items are filtered by. Only items whose
+// main text contains all characters of the filter text in the same order
+// (ignoring case, not necessarily adjacent) are shown, and the matched
+// characters are highlighted (see SetFilterMatchStyle()). Provide an empty
+// string to show all items.
+//
+// Item indices, including those passed to callbacks, always refer to the full
+// list of items. The filter is updated automatically when items are added,
+// removed, or changed. If the currently selected item is hidden by the filter,
+// the first matching item is selected, triggering a "changed" event.
+func (l *List) SetFilterText(text string) *List {
+	l.filterText = text
+	l.filterDirty = true
+	l.itemOffset = 0
+	if visible := l.visibleItems(); visible != nil && l.itemPosition(l.currentItem) < 0 {
+		if position := l.selectablePosition(0, 1); position >= 0 {
+			l.SetCurrentItem(visible[position])
+		}
+	}
+	l.adjustOffset()
+	return l
+}
+
+// GetFilterText returns the text the list items are filtered by.
+func (l *List) GetFilterText() string {
+	return l.filterText
+}
+
+// SetFilterMatchStyle sets the style of the main text characters matched by
+// the filter text. Only the foreground color and the attributes are applied.
+func (l *List) SetFilterMatchStyle(style tcell.Style) *List {
+	l.filterMatchStyle = style
+	return l
+}
+
+// visibleItems returns the indices of the items which are displayed, in
+// ascending order, or nil if all items are displayed.
+func (l *List) visibleItems() []int {
+	if l.filterText == "" && (l.collapsedGroups == 0 || l.provider != nil) {
+		return nil
+	}
+	if l.filterDirty {
+		count := l.itemCount()
+		l.filteredItems = make([]int, 0, count)
+		l.filterMatches = make(map[int][]int)
+		header, headerShown := -1, false
+		for index := 0; index < count; index++ {
+			item := l.getItem(index)
+			if item.Header {
+				// Headers are shown if their group has matching items.
+				header, headerShown = index, l.filterText == ""
+				if headerShown {
+					l.filteredItems = append(l.filteredItems, index)
+				}
+				continue
+			}
+			if l.filterText != "" && item.Separator {
+				continue
+			}
+			if l.filterText != "" {
+				match := l.filterMatch
+				if match == nil {
+					match = fuzzyMatch
+				}
+				matches := match(l.filterText, item.MainText)
+				if matches == nil {
+					continue
+				}
+				l.filterMatches[index] = matches
+			}
+			if header >= 0 && !headerShown {
+				l.filteredItems = append(l.filteredItems, header)
+				headerShown = true
+			}
+			if header < 0 || !l.items[header].Collapsed {
+				l.filteredItems = append(l.filteredItems, index)
+			}
+		}
+		l.filterDirty = false
+	}
+	return l.filteredItems
+}
+
+// visibleCount returns the number of items which are displayed.
+func (l *List) visibleCount() int {
+	if visible := l.visibleItems(); visible != nil {
+		return len(visible)
+	}
+	return l.itemCount()
+}
+
+// itemAtPosition returns the index of the item displayed at the given
+// position, starting at 0 for the first displayed item.
+func (l *List) itemAtPosition(position int) int {
+	if visible := l.visibleItems(); visible != nil {
+		return visible[position]
+	}
+	return position
+}
+
+// itemPosition returns the position at which the item with the given index is
+// displayed or -1 if it is not displayed.
+func (l *List) itemPosition(index int) int {
+	visible := l.visibleItems()
+	if visible == nil {
+		if index < 0 || index >= l.itemCount() {
+			return -1
+		}
+		return index
+	}
+	position := sort.SearchInts(visible, index)
+	if position >= len(visible) || visible[position] != index {
+		return -1
+	}
+	return position
+}
+
+// selectItem invokes the "selected" callbacks for the item with the given
+// index. Headers expand their group instead. Disabled items are ignored.
+func (l *List) selectItem(index int) {
+	item := l.getItem(index)
+	if item.Disabled {
+		return
+	}
+	if item.Header {
+		l.SetGroupCollapsed(index, false)
+		return
+	}
+	if item.Selected != nil {
+		item.Selected()
+	}
+	if l.selected != nil {
+		l.selected(index, item.MainText, item.SecondaryText, item.Shortcut)
+	}
+}
+
+// listHeight returns the number of rows available for list items.
+func (l *List) listHeight() int {
+	_, _, _, height := l.GetInnerRect()
+	if (l.filtering || l.filterText != "") && !l.filterPromptHidden {
+		height-- // The filter prompt.
+	}
+	return height
+}
+
+// fuzzyMatch checks if the given text (which may contain style tags) contains
+// all characters of the given pattern in the same order, ignoring case. It
+// returns the indices of the text's grapheme clusters matching the pattern's
+// characters or nil if there is no match.
+func fuzzyMatch(pattern, text string) []int {
+	patternRunes := []rune(strings.ToLower(pattern))
+	positions := make([]int, 0, len(patternRunes))
+	var (
+		cluster string
+		state   *stepState
+	)
+	for index := 0; len(text) > 0 && len(positions) < len(patternRunes); index++ {
+		cluster, text, state = step(text, state, stepOptionsStyle)
+		if r, _ := utf8.DecodeRuneInString(strings.ToLower(cluster)); r == patternRunes[len(positions)] {
+			positions = append(positions, index)
+		}
+	}
+	if len(positions) < len(patternRunes) {
+		return nil
+	}
+	return positions
+}
+
+// prefixMatch is like fuzzyMatch() but only matches texts starting with the
+// given pattern.
+func prefixMatch(pattern, text string) []int {
+	patternRunes := []rune(strings.ToLower(pattern))
+	positions := make([]int, 0, len(patternRunes))
+	var (
+		cluster string
+		state   *stepState
+	)
+	for index := 0; len(text) > 0 && len(positions) < len(patternRunes); index++ {
+		cluster, text, state = step(text, state, stepOptionsStyle)
+		if r, _ := utf8.DecodeRuneInString(strings.ToLower(cluster)); r != patternRunes[len(positions)] {
+			return nil
+		}
+		positions = append(positions, index)
+	}
+	if len(positions) < len(patternRunes) {
+		return nil
+	}
+	return positions
+}
+
 // SetInlined sets the flag that determines whether the secondary text is
 // inlined with the main text.
 func (l *List) SetInlined(inlined bool) *List {
@@ -498,11 +1311,36 @@ func (l *List) Draw(screen tcell.Screen) {
 		bottomLimit = totalHeight
 	}
 
+	// Draw the filter prompt.
+	if (l.filtering || l.filterText != "") && !l.filterPromptHidden && bottomLimit > y {
+		bottomLimit--
+		prompt := "/" + Escape(l.filterText)
+		if l.filtering {
+			prompt += "_"
+		}
+		printWithStyle(screen, prompt, x, bottomLimit, 0, width, AlignLeft, l.shortcutStyle, true)
+	}
+
+	// Make room for the scroll bar.
+	visibleCount := l.visibleCount()
+	topY := y
+	l.bar = scrollBar{}
+	if l.showScrollBar && width > 0 {
+		rows := visibleCount
+		if l.showSecondaryText {
+			rows *= 2
+		}
+		if rows > bottomLimit-y {
+			width--
+			l.bar = scrollBar{x: x + width, y: y, length: bottomLimit - y, total: visibleCount, vertical: true}
+		}
+	}
+
 	// Do we show any shortcuts?
 	var showShortcuts bool
 
 	for _, item := range l.items {
-		if item.Shortcut != 0 {
+		if item.Shortcut != 0 && l.provider == nil {
 			showShortcuts = true
 			x += 4
 			width -= 4
@@ -510,6 +1348,17 @@ func (l *List) Draw(screen tcell.Screen) {
 		}
 	}
 
+	// Make room for the multi-selection markers.
+	l.markerX, l.markerWidth = x, 0
+	if l.multiSelect {
+		l.markerWidth = TaggedStringWidth(Escape(l.markerUnselected))
+		if w := TaggedStringWidth(Escape(l.markerSelected)); w > l.markerWidth {
+			l.markerWidth = w
+		}
+		x += l.markerWidth
+		width -= l.markerWidth
+	}
+
 	if l.horizontalOffset < 0 {
 		l.horizontalOffset = 0
 	}
@@ -519,47 +1368,101 @@ func (l *List) Draw(screen tcell.Screen) {
 		maxWidth    int  // The maximum printed item width.
 		overflowing bool // Whether a text's end exceeds the right border.
 	)
-	for index, item := range l.items {
-		if index < l.itemOffset {
-			continue
-		}
+	var drawn int // The number of items drawn.
+	for position := l.itemOffset; position < visibleCount; position++ {
+		index := l.itemAtPosition(position)
+		item := l.getItem(index)
 
 		if y >= bottomLimit {
 			break
 		}
+		drawn++
 
 		// Shortcuts.
 		if showShortcuts && item.Shortcut != 0 {
-			printWithStyle(screen, fmt.Sprintf("(%s)", string(item.Shortcut)), x-5, y, 0, 4, AlignRight, l.shortcutStyle, true)
+			printWithStyle(screen, fmt.Sprintf("(%s)", string(item.Shortcut)), l.markerX-5, y, 0, 4, AlignRight, l.shortcutStyle, true)
 		}
 
-		// Main text.
-		_, end, printedWidth := printWithStyle(screen, item.MainText, x, y, l.horizontalOffset, width, AlignLeft, l.mainTextStyle, false)
-		if printedWidth > maxWidth {
-			maxWidth = printedWidth
-		}
-		if end < len(item.MainText) {
-			overflowing = true
+		// Separator line.
+		if item.Separator {
+			if lineWidth := x + width - l.markerX; lineWidth > 0 {
+				line := strings.Repeat(string(Borders.Horizontal), lineWidth)
+				printWithStyle(screen, line, l.markerX, y, 0, lineWidth, AlignLeft, l.disabledStyle, true)
+			}
+			y++
+			if l.showSecondaryText && !l.inlined {
+				y++
+			}
+			continue
 		}
 
-		// Background color of selected text.
-		if index == l.currentItem && (!l.selectedFocusOnly || l.HasFocus()) {
-			textWidth := width
-			if !l.highlightFullLine {
-				if w := TaggedStringWidth(item.MainText); w < textWidth {
-					textWidth = w
-				}
+		// Multi-selection marker.
+		if l.multiSelect && !item.Header {
+			marker := l.markerUnselected
+			if item.Marked {
+				marker = l.markerSelected
 			}
+			printWithStyle(screen, Escape(marker), l.markerX, y, 0, l.markerWidth, AlignLeft, l.mainTextStyle, true)
+		}
 
-			mainTextColor, _, _ := l.mainTextStyle.Decompose()
-			for bx := 0; bx < textWidth; bx++ {
-				m, c, style, _ := screen.GetContent(x+bx, y)
-				fg, _, _ := style.Decompose()
+		// Main text.
+		if item.Header {
+			indicator := "▾ "
+			if item.Collapsed {
+				indicator = "▸ "
+			}
+			style := l.headerStyle
+			if index == l.currentItem && (!l.selectedFocusOnly || l.HasFocus()) {
 				style = l.selectedStyle
-				if fg != mainTextColor {
-					style = style.Foreground(fg)
+			}
+			printWithStyle(screen, indicator+item.MainText, l.markerX, y, 0, x+width-l.markerX, AlignLeft, style, true)
+		} else if l.itemDraw != nil {
+			l.itemDraw(screen, x, y, width, index, index == l.currentItem && (!l.selectedFocusOnly || l.HasFocus()))
+		} else {
+			mainStyle := l.mainTextStyle
+			if item.Disabled {
+				mainStyle = l.disabledStyle
+			}
+			_, end, printedWidth := printWithStyle(screen, item.MainText, x, y, l.horizontalOffset, width, AlignLeft, mainStyle, false)
+			if printedWidth > maxWidth {
+				maxWidth = printedWidth
+			}
+			if end < len(item.MainText) {
+				overflowing = true
+			}
+
+			// Highlight characters matched by the filter text.
+			if matches := l.filterMatches[index]; l.filterText != "" && len(matches) > 0 {
+				l.highlightMatches(screen, item.MainText, matches, x, y, width)
+			}
+
+			// Background color of selected text.
+			if index == l.currentItem && (!l.selectedFocusOnly || l.HasFocus()) {
+				textWidth := width
+				if !l.highlightFullLine {
+					if w := TaggedStringWidth(item.MainText); w < textWidth {
+						textWidth = w
+					}
+				}
+
+				mainTextColor, _, _ := l.mainTextStyle.Decompose()
+				for bx := 0; bx < textWidth; bx++ {
+					m, c, style, _ := screen.GetContent(x+bx, y)
+					fg, _, _ := style.Decompose()
+					style = l.selectedStyle
+					if fg != mainTextColor {
+						style = style.Foreground(fg)
+					}
+					screen.SetContent(x+bx, y, m, c, style)
 				}
-				screen.SetContent(x+bx, y, m, c, style)
+			}
+		}
+
+		// The item being dragged.
+		if index == l.dragItem && l.dragItem != l.dragStart {
+			for bx := l.markerX; bx < x+width; bx++ {
+				m, c, _, _ := screen.GetContent(bx, y)
+				screen.SetContent(bx, y, m, c, l.dragStyle)
 			}
 		}
 
@@ -569,6 +1472,10 @@ func (l *List) Draw(screen tcell.Screen) {
 
 		// Secondary text.
 		if l.showSecondaryText {
+			secondaryStyle := l.secondaryTextStyle
+			if item.Disabled {
+				secondaryStyle = l.disabledStyle
+			}
 			sX := x
       maxWidth = width
 			if l.inlined {
@@ -581,7 +1488,7 @@ func (l *List) Draw(screen tcell.Screen) {
 			if l.wrapText {
 				secondaryText := item.SecondaryText
 				for len(secondaryText) > 0 {
-					_, end, printedWidth := printWithStyle(screen, secondaryText, sX, y, l.horizontalOffset, maxWidth, AlignLeft, l.secondaryTextStyle, false)
+					_, end, printedWidth := printWithStyle(screen, secondaryText, sX, y, l.horizontalOffset, maxWidth, AlignLeft, secondaryStyle, false)
 					if printedWidth > maxWidth {
 						maxWidth = printedWidth
 					}
@@ -592,7 +1499,7 @@ func (l *List) Draw(screen tcell.Screen) {
 					y++
 				}
 			} else {
-				_, end, printedWidth := printWithStyle(screen, " "+item.SecondaryText, sX, y, l.horizontalOffset, maxWidth, AlignLeft, l.secondaryTextStyle, false)
+				_, end, printedWidth := printWithStyle(screen, " "+item.SecondaryText, sX, y, l.horizontalOffset, maxWidth, AlignLeft, secondaryStyle, false)
 				if printedWidth > maxWidth {
 					maxWidth = printedWidth
 				}
@@ -605,6 +1512,21 @@ func (l *List) Draw(screen tcell.Screen) {
 		y++
 	}
 
+	// Draw the scroll bar and the overflow indicators.
+	if l.bar.length > 0 {
+		l.bar.visible = drawn
+		l.bar.draw(screen, l.itemOffset, l.scrollBarStyle)
+	}
+	if l.showOverflowIndicators && bottomLimit > topY {
+		right := x + width
+		if l.itemOffset > 0 {
+			printWithStyle(screen, "↑ more", l.markerX, topY, 0, right-l.markerX, AlignRight, l.scrollBarStyle, true)
+		}
+		if l.itemOffset+drawn < visibleCount {
+			printWithStyle(screen, "↓ more", l.markerX, bottomLimit-1, 0, right-l.markerX, AlignRight, l.scrollBarStyle, true)
+		}
+	}
+
 	// We don't want the item text to get out of view. If the horizontal offset
 	// is too high, we reset it and redraw. (That should be about as efficient
 	// as calculating everything up front.)
@@ -615,22 +1537,56 @@ func (l *List) Draw(screen tcell.Screen) {
 	l.overflowing = overflowing
 }
 
+// highlightMatches applies the filter match style to the grapheme clusters of
+// the given main text with the given indices, printed at the given position.
+func (l *List) highlightMatches(screen tcell.Screen, text string, matches []int, x, y, width int) {
+	matchFg, _, matchAttributes := l.filterMatchStyle.Decompose()
+	var (
+		state *stepState
+		next  int
+	)
+	column := -l.horizontalOffset
+	for index := 0; len(text) > 0 && next < len(matches); index++ {
+		_, text, state = step(text, state, stepOptionsStyle)
+		clusterWidth := state.Width()
+		if index == matches[next] {
+			next++
+			for pos := column; pos < column+clusterWidth; pos++ {
+				if pos < 0 || pos >= width {
+					continue
+				}
+				m, c, style, _ := screen.GetContent(x+pos, y)
+				_, _, attributes := style.Decompose()
+				if matchFg != tcell.ColorDefault {
+					style = style.Foreground(matchFg)
+				}
+				screen.SetContent(x+pos, y, m, c, style.Attributes(attributes|matchAttributes))
+			}
+		}
+		column += clusterWidth
+	}
+}
+
 // adjustOffset adjusts the vertical offset to keep the current selection in
 // view.
 func (l *List) adjustOffset() {
-	_, _, _, height := l.GetInnerRect()
-	if height == 0 {
+	height := l.listHeight()
+	if height <= 0 {
 		return
 	}
-	if l.currentItem < l.itemOffset {
-		l.itemOffset = l.currentItem
+	position := l.itemPosition(l.currentItem)
+	if position < 0 {
+		return
+	}
+	if position < l.itemOffset {
+		l.itemOffset = position
 	} else if l.showSecondaryText {
-		if 2*(l.currentItem-l.itemOffset) >= height-1 {
-			l.itemOffset = (2*l.currentItem + 3 - height) / 2
+		if 2*(position-l.itemOffset) >= height-1 {
+			l.itemOffset = (2*position + 3 - height) / 2
 		}
 	} else {
-		if l.currentItem-l.itemOffset >= height {
-			l.itemOffset = l.currentItem + 1 - height
+		if position-l.itemOffset >= height {
+			l.itemOffset = position + 1 - height
 		}
 	}
 }
@@ -638,113 +1594,174 @@ func (l *List) adjustOffset() {
 // InputHandler returns the handler for this primitive.
 func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return l.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		// Typing the filter text.
+		if l.filtering {
+			switch event.Key() {
+			case tcell.KeyEscape:
+				l.filtering = false
+				l.SetFilterText("")
+				return
+			case tcell.KeyEnter:
+				l.filtering = false
+				return
+			case tcell.KeyBackspace, tcell.KeyBackspace2:
+				if l.filterText == "" {
+					l.filtering = false
+				} else {
+					_, size := utf8.DecodeLastRuneInString(l.filterText)
+					l.SetFilterText(l.filterText[:len(l.filterText)-size])
+				}
+				return
+			case tcell.KeyRune:
+				l.SetFilterText(l.filterText + string(event.Rune()))
+				return
+			}
+		} else if l.filterable && event.Key() == tcell.KeyRune && event.Rune() == '/' {
+			l.filtering = true
+			return
+		} else if l.filterable && event.Key() == tcell.KeyEscape && l.filterText != "" {
+			l.SetFilterText("")
+			return
+		}
+
+		visibleCount := l.visibleCount()
 		if event.Key() == tcell.KeyEscape {
 			if l.done != nil {
 				l.done()
 			}
 			return
-		} else if len(l.items) == 0 {
+		} else if visibleCount == 0 {
 			return
 		}
 
 		previousItem := l.currentItem
+		position := l.itemPosition(l.currentItem)
+
+		// Move the current item.
+		if key := event.Key(); l.reorderable && l.provider == nil && event.Modifiers()&tcell.ModAlt != 0 && (key == tcell.KeyUp || key == tcell.KeyDown) {
+			if key == tcell.KeyUp {
+				position--
+			} else {
+				position++
+			}
+			if position >= 0 && position < visibleCount && previousItem < l.itemCount() {
+				l.moveItem(previousItem, l.itemAtPosition(position))
+				l.adjustOffset()
+			}
+			return
+		}
+
+		// Expand and collapse groups.
+		if position >= 0 && l.IsGroupCollapsed(l.currentItem) && (event.Key() == tcell.KeyEnter || event.Key() == tcell.KeyRune && (event.Rune() == ' ' || event.Rune() == '+')) {
+			l.SetGroupCollapsed(l.currentItem, false)
+			return
+		} else if event.Key() == tcell.KeyRune && event.Rune() == '-' {
+			if header := l.groupHeader(l.currentItem); header >= 0 && header != l.currentItem && position >= 0 {
+				l.SetGroupCollapsed(header, true)
+				return
+			}
+		}
 
+		direction := 1 // The direction in which non-selectable items are skipped.
 		switch key := event.Key(); key {
 		case tcell.KeyTab, tcell.KeyDown:
-			l.currentItem++
+			position++
 		case tcell.KeyBacktab, tcell.KeyUp:
-			l.currentItem--
+			position--
+			direction = -1
 		case tcell.KeyRight:
 			if l.overflowing {
 				l.horizontalOffset += 2 // We shift by 2 to account for two-cell characters.
 			} else {
-				l.currentItem++
+				position++
 			}
 		case tcell.KeyLeft:
 			if l.horizontalOffset > 0 {
 				l.horizontalOffset -= 2
 			} else {
-				l.currentItem--
+				position--
+				direction = -1
 			}
 		case tcell.KeyHome:
-			l.currentItem = 0
+			position = 0
 		case tcell.KeyEnd:
-			l.currentItem = len(l.items) - 1
+			position = visibleCount - 1
+			direction = -1
 		case tcell.KeyPgDn:
-			_, _, _, height := l.GetInnerRect()
-			l.currentItem += height
-			if l.currentItem >= len(l.items) {
-				l.currentItem = len(l.items) - 1
+			position += l.listHeight()
+			if position >= visibleCount {
+				position = visibleCount - 1
 			}
 		case tcell.KeyPgUp:
-			_, _, _, height := l.GetInnerRect()
-			l.currentItem -= height
-			if l.currentItem < 0 {
-				l.currentItem = 0
+			position -= l.listHeight()
+			if position < 0 {
+				position = 0
 			}
+			direction = -1
 		case tcell.KeyEnter:
 			// if space or enter is pressed, call the selected function
-			if l.currentItem >= 0 && l.currentItem < len(l.items) {
-				item := l.items[l.currentItem]
-				if item.Selected != nil {
-					item.Selected()
-				}
-				if l.selected != nil {
-					l.selected(l.currentItem, item.MainText, item.SecondaryText, item.Shortcut)
-				}
+			if position >= 0 {
+				l.selectItem(l.currentItem)
 			}
 		case tcell.KeyRune:
 			ch := event.Rune()
 			if ch == 'j' {
-				l.currentItem++
+				position++
 			} else if ch == 'k' {
-				l.currentItem--
+				position--
+				direction = -1
 			} else if ch == 'g' {
-				l.currentItem = 0
+				position = 0
 			} else if ch == 'G' {
-				l.currentItem = len(l.items) - 1
+				position = visibleCount - 1
+				direction = -1
 			}
-			if ch != ' ' {
+			if ch == ' ' && l.multiSelect {
+				if position >= 0 && !l.IsItemDisabled(l.currentItem) {
+					l.toggleItem(l.currentItem)
+				}
+				break
+			} else if ch != ' ' {
 				// It's not a space bar. Is it a shortcut?
 				var found bool
-				for index, item := range l.items {
-					if item.Shortcut == ch {
+				for p := 0; p < visibleCount && l.provider == nil; p++ {
+					if item := l.items[l.itemAtPosition(p)]; item.Shortcut == ch && !item.Disabled {
 						// We have a shortcut.
 						found = true
-						l.currentItem = index
+						position = p
 						break
 					}
 				}
 				if !found {
 					break
 				}
+			} else if position < 0 {
+				break
 			}
-			item := l.items[l.currentItem]
-			if item.Selected != nil {
-				item.Selected()
-			}
-			if l.selected != nil {
-				l.selected(l.currentItem, item.MainText, item.SecondaryText, item.Shortcut)
-			}
+			l.selectItem(l.itemAtPosition(position))
 		}
 
-		if l.currentItem < 0 {
+		if position < 0 {
 			if l.wrapAround {
-				l.currentItem = len(l.items) - 1
+				position = visibleCount - 1
 			} else {
-				l.currentItem = 0
+				position = 0
 			}
-		} else if l.currentItem >= len(l.items) {
+		} else if position >= visibleCount {
 			if l.wrapAround {
-				l.currentItem = 0
+				position = 0
 			} else {
-				l.currentItem = len(l.items) - 1
+				position = visibleCount - 1
 			}
 		}
+		if position = l.selectablePosition(position, direction); position < 0 {
+			return
+		}
+		l.currentItem = l.itemAtPosition(position)
 
-		if l.currentItem != previousItem && l.currentItem < len(l.items) {
+		if l.currentItem != previousItem {
 			if l.changed != nil {
-				item := l.items[l.currentItem]
+				item := l.getItem(l.currentItem)
 				l.changed(l.currentItem, item.MainText, item.SecondaryText, item.Shortcut)
 			}
 			l.adjustOffset()
@@ -755,42 +1772,95 @@ func (l *List) InputHandler() func(event *tcell.EventKey, setFocus func(p Primit
 // indexAtPoint returns the index of the list item found at the given position
 // or a negative value if there is no such list item.
 func (l *List) indexAtPoint(x, y int) int {
-	rectX, rectY, width, height := l.GetInnerRect()
-	if rectX < 0 || rectX >= rectX+width || y < rectY || y >= rectY+height {
+	rectX, rectY, width, _ := l.GetInnerRect()
+	if rectX < 0 || rectX >= rectX+width || y < rectY || y >= rectY+l.listHeight() {
 		return -1
 	}
 
-	index := y - rectY
+	position := y - rectY
 	if l.showSecondaryText {
-		index /= 2
+		position /= 2
 	}
-	index += l.itemOffset
+	position += l.itemOffset
 
-	if index >= len(l.items) {
+	if position >= l.visibleCount() {
 		return -1
 	}
-	return index
+	return l.itemAtPosition(position)
 }
 
 // MouseHandler returns the mouse handler for this primitive.
 func (l *List) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return l.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		// Drag the scroll bar.
+		if l.scrollBarDragging {
+			switch action {
+			case MouseMove:
+				l.itemOffset = l.bar.offset(event.Position())
+				return true, l
+			case MouseLeftUp:
+				l.scrollBarDragging = false
+				return true, nil
+			}
+		}
+
+		// Drag an item.
+		if l.dragItem >= 0 {
+			switch action {
+			case MouseMove:
+				if index := l.indexAtPoint(event.Position()); index >= 0 && index != l.dragItem {
+					l.MoveItem(l.dragItem, index)
+					l.dragItem = index
+				}
+				return true, l
+			case MouseLeftUp:
+				from, to := l.dragStart, l.dragItem
+				l.dragItem = -1
+				if from != to && l.moved != nil {
+					l.moved(from, to)
+				}
+				return true, nil
+			}
+		}
+
 		if !l.InRect(event.Position()) {
 			return false, nil
 		}
 
+		// Clicks on the scroll bar scroll the list.
+		if l.bar.length > 0 && l.bar.contains(event.Position()) {
+			switch action {
+			case MouseLeftDown:
+				setFocus(l)
+				l.itemOffset = l.bar.offset(event.Position())
+				l.scrollBarDragging = true
+				return true, l
+			case MouseLeftClick, MouseLeftDoubleClick:
+				return true, nil
+			}
+		}
+
 		// Process mouse event.
 		switch action {
+		case MouseLeftDown:
+			if l.reorderable && l.provider == nil {
+				if index := l.indexAtPoint(event.Position()); index >= 0 {
+					setFocus(l)
+					l.dragItem, l.dragStart = index, index
+					return true, l
+				}
+			}
 		case MouseLeftClick:
 			setFocus(l)
 			index := l.indexAtPoint(event.Position())
-			if index != -1 {
-				item := l.items[index]
-				if item.Selected != nil {
-					item.Selected()
-				}
-				if l.selected != nil {
-					l.selected(index, item.MainText, item.SecondaryText, item.Shortcut)
+			if index != -1 && l.IsHeader(index) {
+				l.SetGroupCollapsed(index, !l.IsGroupCollapsed(index))
+			} else if index != -1 && !l.IsItemDisabled(index) {
+				item := l.getItem(index)
+				if x, _ := event.Position(); l.multiSelect && x >= l.markerX && x < l.markerX+l.markerWidth {
+					l.toggleItem(index)
+				} else {
+					l.selectItem(index)
 				}
 				if index != l.currentItem {
 					if l.changed != nil {
@@ -807,11 +1877,11 @@ func (l *List) MouseHandler() func(action MouseAction, event *tcell.EventMouse,
 			}
 			consumed = true
 		case MouseScrollDown:
-			lines := len(l.items) - l.itemOffset
+			lines := l.visibleCount() - l.itemOffset
 			if l.showSecondaryText {
 				lines *= 2
 			}
-			if _, _, _, height := l.GetInnerRect(); lines > height {
+			if lines > l.listHeight() {
 				l.itemOffset++
 			}
 			consumed = true
@@ -0,0 +1,724 @@
+package tview
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Regular expressions for Markdown block elements.
+var (
+	markdownHeadingPattern   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	markdownFencePattern     = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	markdownRulePattern      = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	markdownListPattern      = regexp.MustCompile(`^( {0,3})([-*+]|[0-9]{1,9}[.)])(?:[ \t]+|$)`)
+	markdownTableRulePattern = regexp.MustCompile(`^ {0,3}\|?(?:[ \t]*:?-+:?[ \t]*\|)*[ \t]*:?-+:?[ \t]*\|?[ \t]*$`)
+	markdownAutolinkPattern  = regexp.MustCompile(`^<((?:https?://|mailto:)[^ <>\[\]]+)>`)
+)
+
+// markdownRenderer converts a Markdown document into text with style tags. See
+// [TextView.SetMarkdown] for details.
+type markdownRenderer struct {
+	// If true, text from the document which looks like a tag is escaped.
+	escape bool
+}
+
+// renderMarkdown converts the given Markdown document into text with style
+// tags, wrapped to the given screen width (which may be 0 to avoid wrapping).
+// If escape is true, tag-like text of the document will not be interpreted as
+// a tag.
+func renderMarkdown(source string, width int, escape bool) string {
+	m := &markdownRenderer{escape: escape}
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\t", "    ")
+	return strings.Join(m.blocks(strings.Split(source, "\n"), width, false), "\n")
+}
+
+// blocks renders the given lines as a sequence of block elements and returns
+// the output lines. If tight is true, blocks are not separated by empty lines.
+func (m *markdownRenderer) blocks(lines []string, width int, tight bool) (out []string) {
+	add := func(block []string) {
+		if len(out) > 0 && !tight {
+			out = append(out, "")
+		}
+		out = append(out, block...)
+	}
+
+	for index := 0; index < len(lines); {
+		line := lines[index]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			index++
+
+		case markdownFencePattern.MatchString(line):
+			// Fenced code block.
+			fence := markdownFencePattern.FindStringSubmatch(line)[1]
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			var code []string
+			for index++; index < len(lines); index++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[index]), fence) && strings.Trim(strings.TrimSpace(lines[index]), fence[:1]) == "" {
+					index++
+					break
+				}
+				code = append(code, trimIndent(lines[index], indent))
+			}
+			add(m.code(code))
+
+		case strings.HasPrefix(line, "    "):
+			// Indented code block.
+			var code []string
+			for ; index < len(lines) && (strings.HasPrefix(lines[index], "    ") || strings.TrimSpace(lines[index]) == ""); index++ {
+				code = append(code, trimIndent(lines[index], 4))
+			}
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			add(m.code(code))
+
+		case markdownHeadingPattern.MatchString(line):
+			heading := markdownHeadingPattern.FindStringSubmatch(line)
+			add(m.heading(heading[2], len(heading[1]), width))
+			index++
+
+		case markdownRulePattern.MatchString(line):
+			add(m.rule(width))
+			index++
+
+		case strings.HasPrefix(trimmed, ">"):
+			// Block quote. Lines without a quote marker continue the quote as
+			// long as they don't start a new block.
+			var quote []string
+			for ; index < len(lines); index++ {
+				trimmed := strings.TrimLeft(lines[index], " ")
+				if strings.HasPrefix(trimmed, ">") {
+					trimmed = strings.TrimPrefix(trimmed[1:], " ")
+				} else if len(quote) == 0 || strings.TrimSpace(quote[len(quote)-1]) == "" || strings.TrimSpace(trimmed) == "" || m.startsBlock(lines[index]) {
+					break
+				}
+				quote = append(quote, trimmed)
+			}
+			inner := m.blocks(quote, shrinkWidth(width, 2), false)
+			bar := markdownStyleTag(tcell.StyleDefault.Foreground(Styles.GraphicsColor)) + "│ [-:-:-]"
+			for row := range inner {
+				inner[row] = bar + inner[row]
+			}
+			add(inner)
+
+		case markdownListPattern.MatchString(line):
+			var list []string
+			list, index = m.list(lines, index, width)
+			add(list)
+
+		case index+1 < len(lines) && strings.Contains(line, "|") && markdownTableRulePattern.MatchString(lines[index+1]) && strings.Contains(lines[index+1], "-"):
+			var table []string
+			table, index = m.table(lines, index, width)
+			add(table)
+
+		default:
+			// Paragraph, possibly a setext heading.
+			var paragraph strings.Builder
+			level := 0
+			for ; index < len(lines); index++ {
+				line := lines[index]
+				if strings.TrimSpace(line) == "" || paragraph.Len() > 0 && m.startsBlock(line) {
+					break
+				}
+				if paragraph.Len() > 0 {
+					if underline := strings.TrimSpace(line); strings.Trim(underline, "=") == "" {
+						level = 1
+					} else if strings.Trim(underline, "-") == "" {
+						level = 2
+					}
+					if level > 0 {
+						index++
+						break
+					}
+					previous := paragraph.String()
+					if strings.HasSuffix(previous, "  ") || strings.HasSuffix(previous, "\\") {
+						// Hard line break.
+						paragraph.Reset()
+						paragraph.WriteString(strings.TrimRight(strings.TrimSuffix(previous, "\\"), " "))
+						paragraph.WriteByte('\n')
+					} else {
+						paragraph.WriteByte(' ')
+					}
+				}
+				paragraph.WriteString(strings.TrimLeft(line, " "))
+			}
+			text := strings.TrimRight(paragraph.String(), " ")
+			if level > 0 {
+				add(m.heading(text, level, width))
+			} else {
+				var block []string
+				for _, hardLine := range strings.Split(text, "\n") {
+					block = append(block, markdownWrap(m.inline(hardLine, tcell.StyleDefault), width)...)
+				}
+				add(block)
+			}
+		}
+	}
+
+	return
+}
+
+// startsBlock returns whether the given line starts a block element which may
+// interrupt a paragraph.
+func (m *markdownRenderer) startsBlock(line string) bool {
+	if markdownList := markdownListPattern.FindStringSubmatch(line); markdownList != nil {
+		// Other than bullets, only lists starting with 1 interrupt paragraphs.
+		marker := markdownList[2]
+		if strings.TrimSpace(line[len(markdownList[0]):]) == "" {
+			return false
+		}
+		return len(marker) == 1 || marker[:len(marker)-1] == "1"
+	}
+	return markdownFencePattern.MatchString(line) ||
+		markdownHeadingPattern.MatchString(line) ||
+		markdownRulePattern.MatchString(line) ||
+		strings.HasPrefix(strings.TrimLeft(line, " "), ">")
+}
+
+// heading renders a heading of the given level (1 to 6).
+func (m *markdownRenderer) heading(text string, level, width int) []string {
+	style := tcell.StyleDefault.Foreground(Styles.SecondaryTextColor).Bold(true)
+	switch level {
+	case 1:
+		style = style.Underline(true)
+	case 2:
+	default:
+		style = style.Italic(true)
+	}
+	return markdownWrap(m.inline(text, style), width)
+}
+
+// rule renders a horizontal rule.
+func (m *markdownRenderer) rule(width int) []string {
+	if width <= 0 {
+		width = 20
+	}
+	return []string{markdownStyleTag(tcell.StyleDefault.Foreground(Styles.GraphicsColor)) + strings.Repeat("─", width) + "[-:-:-]"}
+}
+
+// code renders the lines of a code block. Code is never wrapped.
+func (m *markdownRenderer) code(lines []string) []string {
+	tag := markdownStyleTag(tcell.StyleDefault.Foreground(Styles.TertiaryTextColor))
+	out := make([]string, len(lines))
+	for index, line := range lines {
+		out[index] = tag + "  " + Escape(line) + "[-:-:-]"
+	}
+	return out
+}
+
+// list renders the list starting at the given line and returns the output
+// lines and the index of the first line after the list.
+func (m *markdownRenderer) list(lines []string, index, width int) ([]string, int) {
+	var (
+		items   [][]string // The lines of each item, with markers removed.
+		markers []string   // The list markers of each item.
+		loose   bool
+	)
+	first := markdownListPattern.FindStringSubmatch(lines[index])
+	ordered := first[2][0] >= '0' && first[2][0] <= '9'
+	delimiter := first[2][len(first[2])-1]
+	number, _ := strconv.Atoi(strings.TrimRight(first[2], ".)"))
+
+	continues := func(line string) bool {
+		match := markdownListPattern.FindStringSubmatch(line)
+		return match != nil && (match[2][0] >= '0' && match[2][0] <= '9') == ordered && match[2][len(match[2])-1] == delimiter
+	}
+
+	for index < len(lines) && continues(lines[index]) {
+		match := markdownListPattern.FindStringSubmatch(lines[index])
+
+		// Collect the lines of this item.
+		content := len(match[0])
+		if strings.TrimSpace(lines[index][content:]) == "" {
+			content = len(match[1]) + len(match[2]) + 1
+		}
+		item := []string{lines[index][len(match[0]):]}
+		var blank bool
+		for index++; index < len(lines); index++ {
+			line := lines[index]
+			if strings.TrimSpace(line) == "" {
+				blank = true
+				item = append(item, "")
+				continue
+			}
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			if indent >= content {
+				if blank {
+					loose = true
+				}
+				blank = false
+				item = append(item, line[content:])
+				continue
+			}
+			if blank || markdownListPattern.MatchString(line) || m.startsBlock(line) {
+				break // End of item.
+			}
+			item = append(item, strings.TrimLeft(line, " ")) // Lazy continuation.
+		}
+		for len(item) > 0 && item[len(item)-1] == "" {
+			item = item[:len(item)-1]
+		}
+		if blank && index < len(lines) && continues(lines[index]) {
+			loose = true
+		}
+		items = append(items, item)
+
+		// Determine the marker.
+		if ordered {
+			markers = append(markers, strconv.Itoa(number)+string(delimiter))
+			number++
+		} else {
+			markers = append(markers, "•")
+		}
+
+	}
+
+	// Render the items.
+	var markerWidth int
+	for _, marker := range markers {
+		if w := TaggedStringWidth(marker); w > markerWidth {
+			markerWidth = w
+		}
+	}
+	markerTag := markdownStyleTag(tcell.StyleDefault.Foreground(Styles.GraphicsColor))
+	var out []string
+	for itemIndex, item := range items {
+		marker := markers[itemIndex]
+		if len(item) > 0 {
+			// Task list items.
+			if strings.HasPrefix(item[0], "[ ] ") {
+				marker, item[0] = "☐", item[0][4:]
+			} else if strings.HasPrefix(item[0], "[x] ") || strings.HasPrefix(item[0], "[X] ") {
+				marker, item[0] = "☑", item[0][4:]
+			}
+		}
+		if loose && itemIndex > 0 {
+			out = append(out, "")
+		}
+		inner := m.blocks(item, shrinkWidth(width, markerWidth+1), !loose)
+		if len(inner) == 0 {
+			inner = []string{""}
+		}
+		for row, line := range inner {
+			if row == 0 {
+				padding := strings.Repeat(" ", markerWidth-TaggedStringWidth(marker))
+				if ordered {
+					out = append(out, markerTag+padding+marker+" [-:-:-]"+line)
+				} else {
+					out = append(out, markerTag+marker+padding+" [-:-:-]"+line)
+				}
+			} else {
+				out = append(out, strings.Repeat(" ", markerWidth+1)+line)
+			}
+		}
+	}
+
+	return out, index
+}
+
+// table renders the table starting at the given line and returns the output
+// lines and the index of the first line after the table.
+func (m *markdownRenderer) table(lines []string, index, width int) ([]string, int) {
+	// Parse the table.
+	header := markdownTableCells(lines[index])
+	var aligns []int
+	for _, cell := range markdownTableCells(lines[index+1]) {
+		switch {
+		case strings.HasPrefix(cell, ":") && strings.HasSuffix(cell, ":"):
+			aligns = append(aligns, AlignCenter)
+		case strings.HasSuffix(cell, ":"):
+			aligns = append(aligns, AlignRight)
+		default:
+			aligns = append(aligns, AlignLeft)
+		}
+	}
+	rows := [][]string{header}
+	for index += 2; index < len(lines) && strings.TrimSpace(lines[index]) != "" && !m.startsBlock(lines[index]); index++ {
+		rows = append(rows, markdownTableCells(lines[index]))
+	}
+
+	// Render the cells and determine the natural column widths.
+	columns := len(aligns)
+	cells := make([][]string, len(rows))
+	widths := make([]int, columns)
+	for rowIndex, row := range rows {
+		style := tcell.StyleDefault
+		if rowIndex == 0 {
+			style = style.Bold(true)
+		}
+		cells[rowIndex] = make([]string, columns)
+		for column := 0; column < columns; column++ {
+			if column < len(row) {
+				cells[rowIndex][column] = m.inline(row[column], style)
+			}
+			if w := TaggedStringWidth(cells[rowIndex][column]); w > widths[column] {
+				widths[column] = w
+			}
+		}
+	}
+
+	// Shrink the widest columns until the table fits.
+	if width > 0 {
+		available := width - 3*(columns-1)
+		for {
+			total, widest := 0, 0
+			for column, w := range widths {
+				total += w
+				if w > widths[widest] {
+					widest = column
+				}
+			}
+			if total <= available || widths[widest] <= 1 {
+				break
+			}
+			widths[widest]--
+		}
+	}
+
+	// Draw the table.
+	graphics := markdownStyleTag(tcell.StyleDefault.Foreground(Styles.GraphicsColor))
+	var out []string
+	for rowIndex := range cells {
+		wrapped := make([][]string, columns)
+		var height int
+		for column, cell := range cells[rowIndex] {
+			wrapped[column] = markdownWrap(cell, widths[column])
+			if len(wrapped[column]) > height {
+				height = len(wrapped[column])
+			}
+		}
+		for row := 0; row < height; row++ {
+			var line strings.Builder
+			for column := 0; column < columns; column++ {
+				if column > 0 {
+					line.WriteString(" " + graphics + "│[-:-:-] ")
+				}
+				var text string
+				if row < len(wrapped[column]) {
+					text = wrapped[column][row]
+				}
+				padding := widths[column] - TaggedStringWidth(text)
+				if padding < 0 {
+					padding = 0
+				}
+				switch aligns[column] {
+				case AlignCenter:
+					line.WriteString(strings.Repeat(" ", padding/2) + text + strings.Repeat(" ", padding-padding/2))
+				case AlignRight:
+					line.WriteString(strings.Repeat(" ", padding) + text)
+				default:
+					line.WriteString(text + strings.Repeat(" ", padding))
+				}
+			}
+			out = append(out, line.String())
+		}
+		if rowIndex == 0 {
+			separators := make([]string, columns)
+			for column, w := range widths {
+				separators[column] = strings.Repeat("─", w)
+			}
+			out = append(out, graphics+strings.Join(separators, "─┼─")+"[-:-:-]")
+		}
+	}
+
+	return out, index
+}
+
+// markdownTableCells splits a table row into its (trimmed) cells.
+func markdownTableCells(line string) (cells []string) {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, "\\|") {
+		line = line[:len(line)-1]
+	}
+	var cell strings.Builder
+	for index := 0; index < len(line); index++ {
+		if line[index] == '\\' && index+1 < len(line) && line[index+1] == '|' {
+			cell.WriteByte('|')
+			index++
+		} else if line[index] == '|' {
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		} else {
+			cell.WriteByte(line[index])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// inline renders the inline elements (emphasis, code spans, links, etc.) of
+// the given text, based on the given style.
+func (m *markdownRenderer) inline(text string, base tcell.Style) string {
+	var (
+		out, literal          strings.Builder
+		bold, italic, strike  bool
+		boldMarker, itaMarker byte
+	)
+	flush := func() {
+		if literal.Len() > 0 {
+			if m.escape {
+				out.WriteString(Escape(literal.String()))
+			} else {
+				out.WriteString(literal.String())
+			}
+			literal.Reset()
+		}
+	}
+	current := func() tcell.Style {
+		style := base
+		if bold {
+			style = style.Bold(true)
+		}
+		if italic {
+			style = style.Italic(true)
+		}
+		if strike {
+			style = style.StrikeThrough(true)
+		}
+		return style
+	}
+	restyle := func() {
+		flush()
+		out.WriteString(markdownStyleTag(current()))
+	}
+	isAlphanumeric := func(index int) bool {
+		if index < 0 || index >= len(text) {
+			return false
+		}
+		c := text[index]
+		return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+	}
+
+	out.WriteString(markdownStyleTag(base))
+	for index := 0; index < len(text); {
+		c := text[index]
+		switch {
+		case c == '\\' && index+1 < len(text) && strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", text[index+1]) >= 0:
+			literal.WriteByte(text[index+1])
+			index += 2
+
+		case c == '`':
+			// Code span.
+			run := len(text[index:]) - len(strings.TrimLeft(text[index:], "`"))
+			fence := text[index : index+run]
+			end := strings.Index(text[index+run:], fence)
+			if end < 0 {
+				literal.WriteString(fence)
+				index += run
+				break
+			}
+			code := text[index+run : index+run+end]
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+				code = code[1 : len(code)-1]
+			}
+			flush()
+			out.WriteString(markdownStyleTag(current().Foreground(Styles.TertiaryTextColor)))
+			out.WriteString(Escape(code))
+			restyle()
+			index += 2*run + end
+
+		case (c == '*' || c == '_') && index+1 < len(text) && text[index+1] == c && (boldMarker == 0 || boldMarker == c):
+			if c == '_' && isAlphanumeric(index-1) && isAlphanumeric(index+2) {
+				literal.WriteString("__")
+			} else {
+				bold = !bold
+				boldMarker = 0
+				if bold {
+					boldMarker = c
+				}
+				restyle()
+			}
+			index += 2
+
+		case (c == '*' || c == '_') && (itaMarker == 0 || itaMarker == c):
+			if c == '_' && isAlphanumeric(index-1) && isAlphanumeric(index+1) ||
+				!italic && (index+1 >= len(text) || text[index+1] == ' ') {
+				literal.WriteByte(c) // Intra-word underscore or a lone asterisk.
+			} else {
+				italic = !italic
+				itaMarker = 0
+				if italic {
+					itaMarker = c
+				}
+				restyle()
+			}
+			index++
+
+		case c == '~' && strings.HasPrefix(text[index:], "~~"):
+			strike = !strike
+			restyle()
+			index += 2
+
+		case c == '[' || c == '!' && strings.HasPrefix(text[index:], "!["):
+			image := c == '!'
+			start := index
+			if image {
+				start++
+			}
+			label, url, length := markdownLink(text[start:])
+			if length == 0 {
+				literal.WriteByte(c)
+				index++
+				break
+			}
+			flush()
+			if image {
+				out.WriteString(m.inline("🖼 "+label, current().Italic(true)))
+			} else {
+				style := current().Underline(true).Foreground(Styles.ContrastSecondaryTextColor)
+				if url != "" {
+					out.WriteString("[:::" + url + "]")
+				}
+				out.WriteString(m.inline(label, style))
+				if url != "" {
+					out.WriteString("[:::-]")
+				}
+			}
+			restyle()
+			index = start + length
+
+		case c == '<' && markdownAutolinkPattern.MatchString(text[index:]):
+			match := markdownAutolinkPattern.FindStringSubmatch(text[index:])
+			flush()
+			out.WriteString("[:::" + match[1] + "]")
+			out.WriteString(markdownStyleTag(current().Underline(true).Foreground(Styles.ContrastSecondaryTextColor)))
+			out.WriteString(Escape(match[1]))
+			out.WriteString("[:::-]")
+			restyle()
+			index += len(match[0])
+
+		default:
+			literal.WriteByte(c)
+			index++
+		}
+	}
+	flush()
+
+	return out.String()
+}
+
+// markdownLink parses a link of the form "[label](url)" at the start of the
+// given text. It returns the label, the URL, and the length of the link in
+// bytes, or a length of 0 if the text does not start with a link.
+func markdownLink(text string) (label, url string, length int) {
+	depth := 0
+	for index := 0; index < len(text); index++ {
+		switch text[index] {
+		case '\\':
+			index++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if index+1 >= len(text) || text[index+1] != '(' {
+				return "", "", 0
+			}
+			end := strings.IndexByte(text[index+2:], ')')
+			if end < 0 {
+				return "", "", 0
+			}
+			url = strings.TrimSpace(text[index+2 : index+2+end])
+			if space := strings.IndexAny(url, " \t"); space >= 0 {
+				url = url[:space] // Remove the title.
+			}
+			url = strings.Trim(url, "<>")
+			if strings.ContainsAny(url, "[]") {
+				url = ""
+			}
+			return text[1:index], url, index + 3 + end
+		}
+	}
+	return "", "", 0
+}
+
+// markdownStyleTag returns a style tag which results in the given style,
+// regardless of the previous style. Colors which are not set are reset to the
+// initial colors. URLs are not included.
+func markdownStyleTag(style tcell.Style) string {
+	fg, bg, attributes := style.Decompose()
+	color := func(c tcell.Color) string {
+		if c == tcell.ColorDefault {
+			return "-"
+		}
+		return c.String()
+	}
+	tag := "[" + color(fg) + ":" + color(bg) + ":-]"
+	var flags string
+	for _, flag := range []struct {
+		mask tcell.AttrMask
+		name byte
+	}{
+		{tcell.AttrBold, 'b'},
+		{tcell.AttrItalic, 'i'},
+		{tcell.AttrUnderline, 'u'},
+		{tcell.AttrStrikeThrough, 's'},
+		{tcell.AttrDim, 'd'},
+		{tcell.AttrBlink, 'l'},
+		{tcell.AttrReverse, 'r'},
+	} {
+		if attributes&flag.mask != 0 {
+			flags += string(flag.name)
+		}
+	}
+	if flags != "" {
+		tag += "[::" + flags + "]"
+	}
+	return tag
+}
+
+// markdownWrap wraps the given text with style tags to the given width (no
+// wrapping if width is 0 or less). Each resulting line starts with the style
+// that was active where the line begins and ends with a reset of the style so
+// lines can be indented or prefixed freely.
+func markdownWrap(text string, width int) []string {
+	var lines []string
+	if width > 0 {
+		lines = WordWrap(text, width)
+	} else {
+		lines = []string{text}
+	}
+	state := &stepState{unisegState: -1, style: tcell.StyleDefault}
+	var prefix string
+	for index, line := range lines {
+		lines[index] = prefix + line + "[-:-:-]"
+		if strings.Contains(line, "[:::") {
+			lines[index] += "[:::-]"
+		}
+		str := prefix + line
+		for len(str) > 0 {
+			_, str, state = step(str, state, stepOptionsStyle)
+		}
+		prefix = markdownStyleTag(state.Style())
+	}
+	return lines
+}
+
+// shrinkWidth returns the given width reduced by the given amount but at least
+// 1. A width of 0 or less (i.e. no wrapping) is returned unchanged.
+func shrinkWidth(width, amount int) int {
+	if width <= 0 {
+		return width
+	}
+	if width-amount < 1 {
+		return 1
+	}
+	return width - amount
+}
+
+// trimIndent removes up to the given number of leading spaces from a line.
+func trimIndent(line string, indent int) string {
+	for indent > 0 && strings.HasPrefix(line, " ") {
+		line = line[1:]
+		indent--
+	}
+	return line
+}
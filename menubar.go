@@ -0,0 +1,790 @@
+package tview
+
+import (
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// MenuItem is an entry of a menu, e.g. of a [MenuBar]. An item which contains
+// further items opens them as a submenu when it is selected. Other items
+// invoke their "selected" callback. Items may also be separators (see
+// [NewMenuSeparator]), disabled, or checkable, in which case selecting them
+// toggles their checkmark.
+type MenuItem struct {
+	// The text shown for this item.
+	label string
+
+	// The rune which selects this item, 0 for none.
+	shortcut rune
+
+	// The items of this item's submenu.
+	items []*MenuItem
+
+	// Whether this item is a separator line.
+	separator bool
+
+	// Whether this item cannot be selected.
+	disabled bool
+
+	// Whether selecting this item toggles its checkmark.
+	checkable bool
+
+	// Whether this item has a checkmark.
+	checked bool
+
+	// An optional function which is called when the user selects this item.
+	selected func()
+}
+
+// NewMenuItem returns a new menu item with the given label.
+func NewMenuItem(label string) *MenuItem {
+	return &MenuItem{
+		label: label,
+	}
+}
+
+// NewMenuSeparator returns a new menu item which is drawn as a horizontal line
+// and which cannot be selected.
+func NewMenuSeparator() *MenuItem {
+	return &MenuItem{
+		separator: true,
+	}
+}
+
+// SetLabel sets the text shown for this item.
+func (i *MenuItem) SetLabel(label string) *MenuItem {
+	i.label = label
+	return i
+}
+
+// GetLabel returns the text shown for this item.
+func (i *MenuItem) GetLabel() string {
+	return i.label
+}
+
+// SetShortcut sets the rune which selects this item while its menu is open.
+// The first occurrence of the rune in the label is underlined. For the menus
+// of a [MenuBar], this is the letter which opens them together with the Alt
+// key. Shortcuts are not case-sensitive. Set to 0 for no shortcut.
+func (i *MenuItem) SetShortcut(shortcut rune) *MenuItem {
+	i.shortcut = shortcut
+	return i
+}
+
+// GetShortcut returns the rune which selects this item.
+func (i *MenuItem) GetShortcut() rune {
+	return i.shortcut
+}
+
+// AddItem adds an item to this item's submenu.
+func (i *MenuItem) AddItem(item *MenuItem) *MenuItem {
+	i.items = append(i.items, item)
+	return i
+}
+
+// GetItems returns the items of this item's submenu.
+func (i *MenuItem) GetItems() []*MenuItem {
+	return i.items
+}
+
+// ClearItems removes all items from this item's submenu.
+func (i *MenuItem) ClearItems() *MenuItem {
+	i.items = nil
+	return i
+}
+
+// IsSeparator returns whether this item is a separator line.
+func (i *MenuItem) IsSeparator() bool {
+	return i.separator
+}
+
+// SetDisabled sets whether this item is disabled. Disabled items are shown
+// but cannot be selected.
+func (i *MenuItem) SetDisabled(disabled bool) *MenuItem {
+	i.disabled = disabled
+	return i
+}
+
+// IsDisabled returns whether this item is disabled.
+func (i *MenuItem) IsDisabled() bool {
+	return i.disabled
+}
+
+// SetCheckable sets whether selecting this item toggles its checkmark.
+func (i *MenuItem) SetCheckable(checkable bool) *MenuItem {
+	i.checkable = checkable
+	return i
+}
+
+// IsCheckable returns whether selecting this item toggles its checkmark.
+func (i *MenuItem) IsCheckable() bool {
+	return i.checkable
+}
+
+// SetChecked sets whether this item has a checkmark.
+func (i *MenuItem) SetChecked(checked bool) *MenuItem {
+	i.checked = checked
+	return i
+}
+
+// IsChecked returns whether this item has a checkmark.
+func (i *MenuItem) IsChecked() bool {
+	return i.checked
+}
+
+// SetSelectedFunc sets a function which is called when the user selects this
+// item. For checkable items, the checkmark has already been toggled when the
+// function is called.
+func (i *MenuItem) SetSelectedFunc(handler func()) *MenuItem {
+	i.selected = handler
+	return i
+}
+
+// selectable returns whether the user can select this item.
+func (i *MenuItem) selectable() bool {
+	return !i.separator && !i.disabled
+}
+
+// matchShortcut returns whether the given rune is this item's shortcut.
+func (i *MenuItem) matchShortcut(r rune) bool {
+	return i.shortcut != 0 && unicode.ToLower(i.shortcut) == unicode.ToLower(r)
+}
+
+// printMenuLabel prints a menu item's label at the given position, with the
+// first occurrence of its shortcut underlined. It returns the printed width.
+func printMenuLabel(screen tcell.Screen, item *MenuItem, x, y, maxWidth int, style tcell.Style) int {
+	label, index := item.label, -1
+	if item.shortcut != 0 {
+		for pos, r := range label {
+			if item.matchShortcut(r) {
+				index = pos
+				break
+			}
+		}
+	}
+	if index < 0 {
+		_, _, width := printWithStyle(screen, Escape(label), x, y, 0, maxWidth, AlignLeft, style, false)
+		return width
+	}
+	_, _, width := printWithStyle(screen, Escape(label[:index]), x, y, 0, maxWidth, AlignLeft, style, false)
+	r := []rune(label[index:])[0]
+	_, _, shortcutWidth := printWithStyle(screen, Escape(string(r)), x+width, y, 0, maxWidth-width, AlignLeft, style.Underline(true), false)
+	width += shortcutWidth
+	_, _, restWidth := printWithStyle(screen, Escape(label[index+len(string(r)):]), x+width, y, 0, maxWidth-width, AlignLeft, style, false)
+	return width + restWidth
+}
+
+// menuPopup is an open menu, showing the items of a menu item's submenu.
+type menuPopup struct {
+	// The menu item whose items are shown.
+	item *MenuItem
+
+	// The index of the highlighted item, -1 for none.
+	current int
+
+	// The position and size of the popup, including its border. They are
+	// calculated by the primitive which shows the popup.
+	x, y, width, height int
+
+	// Whether any of the items are checkable or have a submenu.
+	checks, arrows bool
+}
+
+// newMenuPopup returns a popup for the given item's submenu with its first
+// selectable item highlighted.
+func newMenuPopup(item *MenuItem) *menuPopup {
+	p := &menuPopup{
+		item:    item,
+		current: -1,
+	}
+	p.move(1)
+	for _, child := range item.items {
+		p.checks = p.checks || child.checkable
+		p.arrows = p.arrows || len(child.items) > 0
+		if width := TaggedStringWidth(Escape(child.label)); width > p.width {
+			p.width = width
+		}
+	}
+	p.width += 4 // Borders and padding.
+	if p.checks {
+		p.width += 2
+	}
+	if p.arrows {
+		p.width += 2
+	}
+	p.height = len(item.items) + 2
+	return p
+}
+
+// move highlights the next selectable item in the given direction (1 or -1),
+// wrapping around at the ends. Nothing happens if there are no selectable
+// items.
+func (p *menuPopup) move(direction int) {
+	count := len(p.item.items)
+	index := p.current
+	for step := 0; step < count; step++ {
+		index += direction
+		if index < 0 {
+			index = count - 1
+		} else if index >= count {
+			index = 0
+		}
+		if p.item.items[index].selectable() {
+			p.current = index
+			return
+		}
+	}
+}
+
+// highlighted returns the highlighted item or nil if there is none.
+func (p *menuPopup) highlighted() *MenuItem {
+	if p.current < 0 || p.current >= len(p.item.items) {
+		return nil
+	}
+	return p.item.items[p.current]
+}
+
+// contains returns whether the given screen coordinate is within the popup.
+func (p *menuPopup) contains(x, y int) bool {
+	return x >= p.x && x < p.x+p.width && y >= p.y && y < p.y+p.height
+}
+
+// indexAt returns the index of the item at the given screen coordinate or -1
+// if there is none.
+func (p *menuPopup) indexAt(x, y int) int {
+	if x <= p.x || x >= p.x+p.width-1 || y <= p.y || y >= p.y+p.height-1 {
+		return -1
+	}
+	return y - p.y - 1
+}
+
+// placeMenuPopups positions submenus next to the highlighted item of their
+// parent popup and moves all popups into the screen of the given size (unless
+// it is 0). The first popup must already be positioned.
+func placeMenuPopups(popups []*menuPopup, screenWidth, screenHeight int) {
+	for index, p := range popups {
+		if index > 0 {
+			parent := popups[index-1]
+			p.x, p.y = parent.x+parent.width, parent.y+parent.current
+			if screenWidth > 0 && p.x+p.width > screenWidth {
+				p.x = parent.x - p.width // Open to the left instead.
+			}
+		}
+		if screenWidth > 0 && p.x+p.width > screenWidth {
+			p.x = screenWidth - p.width
+		}
+		if screenHeight > 0 && p.y+p.height > screenHeight {
+			p.y = screenHeight - p.height
+		}
+		if p.x < 0 {
+			p.x = 0
+		}
+		if p.y < 0 {
+			p.y = 0
+		}
+	}
+}
+
+// draw draws the popup with the given styles for regular, highlighted, and
+// disabled items.
+func (p *menuPopup) draw(screen tcell.Screen, style, selectedStyle, disabledStyle tcell.Style) {
+	right, bottom := p.x+p.width-1, p.y+p.height-1
+	for y := p.y; y <= bottom; y++ {
+		for x := p.x; x <= right; x++ {
+			ch := ' '
+			switch {
+			case y == p.y && x == p.x:
+				ch = Borders.TopLeft
+			case y == p.y && x == right:
+				ch = Borders.TopRight
+			case y == bottom && x == p.x:
+				ch = Borders.BottomLeft
+			case y == bottom && x == right:
+				ch = Borders.BottomRight
+			case y == p.y || y == bottom:
+				ch = Borders.Horizontal
+			case x == p.x || x == right:
+				ch = Borders.Vertical
+			}
+			screen.SetContent(x, y, ch, nil, style)
+		}
+	}
+
+	for index, item := range p.item.items {
+		y := p.y + 1 + index
+		if item.separator {
+			screen.SetContent(p.x, y, Borders.LeftT, nil, style)
+			for x := p.x + 1; x < right; x++ {
+				screen.SetContent(x, y, Borders.Horizontal, nil, style)
+			}
+			screen.SetContent(right, y, Borders.RightT, nil, style)
+			continue
+		}
+
+		itemStyle := style
+		if item.disabled {
+			itemStyle = disabledStyle
+		} else if index == p.current {
+			itemStyle = selectedStyle
+		}
+		for x := p.x + 1; x < right; x++ {
+			screen.SetContent(x, y, ' ', nil, itemStyle)
+		}
+		x := p.x + 2
+		if p.checks {
+			if item.checked {
+				screen.SetContent(x, y, '✓', nil, itemStyle)
+			}
+			x += 2
+		}
+		printMenuLabel(screen, item, x, y, right-x, itemStyle)
+		if len(item.items) > 0 {
+			screen.SetContent(right-2, y, '▸', nil, itemStyle)
+		}
+	}
+}
+
+// MenuBar is a horizontal bar of menus, usually placed at the top of the
+// screen. Each menu is a [MenuItem] whose items are shown in a pull-down menu
+// when it is opened. Menus are drawn on top of the primitives below the menu
+// bar. For this to work, the menu bar must be drawn after them which is the
+// case for a focused item of a [Flex] or [Grid].
+//
+// The following keys are available while the menu bar has focus:
+//
+//   - Left arrow / right arrow: Switch to the previous / next menu.
+//   - Up arrow / down arrow: Move the highlight within the open menu. The down
+//     arrow also opens the current menu.
+//   - Enter, Space: Select the highlighted item.
+//   - Right arrow: Open the highlighted item's submenu.
+//   - Left arrow: Close a submenu.
+//   - Alt+letter: Open the menu with this shortcut (see [MenuItem.SetShortcut]).
+//   - Letter: Select the item of the open menu with this shortcut.
+//   - Escape, Tab, Backtab: Close all menus and leave the menu bar.
+//
+// To open menus with their Alt+letter shortcut while other primitives have
+// focus, call [MenuBar.HandleAccelerator] from an input capture function, e.g.
+// the one set with [Application.SetInputCapture].
+type MenuBar struct {
+	*Box
+
+	// The menus of the menu bar.
+	menus []*MenuItem
+
+	// The index of the highlighted menu.
+	current int
+
+	// The open menus, from the pull-down menu to the innermost submenu.
+	popups []*menuPopup
+
+	// The size of the screen as of the last call to Draw().
+	screenWidth, screenHeight int
+
+	// The style of the bar and the menus, of highlighted entries, and of
+	// disabled entries.
+	style, selectedStyle, disabledStyle tcell.Style
+
+	// An optional function which is called when the user selects any item.
+	selected func(item *MenuItem)
+
+	// An optional function which is called when the user leaves the menu bar.
+	done func(key tcell.Key)
+}
+
+// NewMenuBar returns a new menu bar without any menus.
+func NewMenuBar() *MenuBar {
+	return &MenuBar{
+		Box:           NewBox(),
+		style:         tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		selectedStyle: tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.ContrastBackgroundColor),
+		disabledStyle: tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.ContrastSecondaryTextColor),
+	}
+}
+
+// AddMenu adds a menu to the right end of the menu bar. The menu's items are
+// shown in its pull-down menu. A menu without items is selected directly.
+func (m *MenuBar) AddMenu(menu *MenuItem) *MenuBar {
+	m.menus = append(m.menus, menu)
+	return m
+}
+
+// GetMenus returns the menus of the menu bar.
+func (m *MenuBar) GetMenus() []*MenuItem {
+	return m.menus
+}
+
+// ClearMenus removes all menus from the menu bar.
+func (m *MenuBar) ClearMenus() *MenuBar {
+	m.menus = nil
+	m.popups = nil
+	m.current = 0
+	return m
+}
+
+// SetStyles sets the style of the bar and the menus, of highlighted entries,
+// and of disabled entries.
+func (m *MenuBar) SetStyles(style, selected, disabled tcell.Style) *MenuBar {
+	m.style, m.selectedStyle, m.disabledStyle = style, selected, disabled
+	return m
+}
+
+// SetSelectedFunc sets a handler which is called when the user selects any
+// menu item which doesn't have a submenu, after the item's own "selected"
+// callback.
+func (m *MenuBar) SetSelectedFunc(handler func(item *MenuItem)) *MenuBar {
+	m.selected = handler
+	return m
+}
+
+// SetDoneFunc sets a handler which is called when the user leaves the menu
+// bar. The key is the key pressed (Escape, Tab, or Backtab) or KeyEnter if the
+// user selected an item. In the latter case, the handler is called before the
+// item's callbacks so focus changes made by them take precedence.
+func (m *MenuBar) SetDoneFunc(handler func(key tcell.Key)) *MenuBar {
+	m.done = handler
+	return m
+}
+
+// IsOpen returns whether a menu is open.
+func (m *MenuBar) IsOpen() bool {
+	return len(m.popups) > 0
+}
+
+// HandleAccelerator opens the menu whose shortcut matches the given Alt+letter
+// key event and gives the menu bar focus. It returns whether the event was
+// handled. It is meant to be called from an input capture function so menus
+// can be opened regardless of which primitive has focus:
+//
+//	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+//		if menuBar.HandleAccelerator(event, func(p tview.Primitive) { app.SetFocus(p) }) {
+//			return nil
+//		}
+//		return event
+//	})
+func (m *MenuBar) HandleAccelerator(event *tcell.EventKey, setFocus func(p Primitive)) bool {
+	if event.Key() != tcell.KeyRune || event.Modifiers()&tcell.ModAlt == 0 {
+		return false
+	}
+	for index, menu := range m.menus {
+		if menu.selectable() && menu.matchShortcut(event.Rune()) {
+			setFocus(m)
+			m.openMenu(index)
+			return true
+		}
+	}
+	return false
+}
+
+// Blur is called when this primitive loses focus.
+func (m *MenuBar) Blur() {
+	m.popups = nil
+	m.Box.Blur()
+}
+
+// menuX returns the screen x-coordinates of the menus' left edges, followed by
+// the right edge of the last menu.
+func (m *MenuBar) menuX() []int {
+	x, _, _, _ := m.GetInnerRect()
+	positions := make([]int, 0, len(m.menus)+1)
+	for _, menu := range m.menus {
+		positions = append(positions, x)
+		x += TaggedStringWidth(Escape(menu.label)) + 2
+	}
+	return append(positions, x)
+}
+
+// layout positions the open menus.
+func (m *MenuBar) layout() {
+	if len(m.popups) == 0 {
+		return
+	}
+	_, y, _, _ := m.GetInnerRect()
+	m.popups[0].x, m.popups[0].y = m.menuX()[m.current], y+1
+	placeMenuPopups(m.popups, m.screenWidth, m.screenHeight)
+}
+
+// openMenu highlights the menu with the given index and opens it if it has
+// items.
+func (m *MenuBar) openMenu(index int) {
+	if index < 0 || index >= len(m.menus) {
+		return
+	}
+	m.current = index
+	m.popups = nil
+	if len(m.menus[index].items) > 0 {
+		m.popups = []*menuPopup{newMenuPopup(m.menus[index])}
+	}
+}
+
+// switchMenu highlights the next selectable menu in the given direction (1 or
+// -1), keeping it open if the current menu is open.
+func (m *MenuBar) switchMenu(direction int) {
+	open := len(m.popups) > 0
+	index := m.current
+	for step := 0; step < len(m.menus); step++ {
+		index = (index + direction + len(m.menus)) % len(m.menus)
+		if !m.menus[index].selectable() {
+			continue
+		}
+		m.current = index
+		m.popups = nil
+		if open {
+			m.openMenu(index)
+		}
+		return
+	}
+}
+
+// activate selects the given item. If it has a submenu, the submenu is opened.
+// Otherwise, all menus are closed and the item's callbacks are invoked.
+func (m *MenuBar) activate(item *MenuItem) {
+	if item == nil || !item.selectable() {
+		return
+	}
+	if len(item.items) > 0 {
+		if len(m.popups) == 0 || m.popups[len(m.popups)-1].item != item {
+			m.popups = append(m.popups, newMenuPopup(item))
+		}
+		return
+	}
+	if item.checkable {
+		item.checked = !item.checked
+	}
+	m.popups = nil
+	if m.done != nil {
+		m.done(tcell.KeyEnter)
+	}
+	if item.selected != nil {
+		item.selected()
+	}
+	if m.selected != nil {
+		m.selected(item)
+	}
+}
+
+// leave closes all menus and calls the "done" handler.
+func (m *MenuBar) leave(key tcell.Key) {
+	m.popups = nil
+	if m.done != nil {
+		m.done(key)
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (m *MenuBar) Draw(screen tcell.Screen) {
+	m.Box.DrawForSubclass(screen, m)
+
+	x, y, width, height := m.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Draw the bar.
+	for index := 0; index < width; index++ {
+		screen.SetContent(x+index, y, ' ', nil, m.style)
+	}
+	positions := m.menuX()
+	for index, menu := range m.menus {
+		menuX := positions[index]
+		if menuX >= x+width {
+			break
+		}
+		style := m.style
+		if menu.disabled {
+			style = m.disabledStyle
+		} else if index == m.current && (m.HasFocus() || len(m.popups) > 0) {
+			style = m.selectedStyle
+		}
+		menuWidth := positions[index+1] - menuX
+		if menuX+menuWidth > x+width {
+			menuWidth = x + width - menuX
+		}
+		for offset := 0; offset < menuWidth; offset++ {
+			screen.SetContent(menuX+offset, y, ' ', nil, style)
+		}
+		printMenuLabel(screen, menu, menuX+1, y, menuWidth-1, style)
+	}
+
+	// Draw the open menus.
+	m.screenWidth, m.screenHeight = screen.Size()
+	m.layout()
+	for _, popup := range m.popups {
+		popup.draw(screen, m.style, m.selectedStyle, m.disabledStyle)
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (m *MenuBar) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return m.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if len(m.menus) == 0 {
+			switch key := event.Key(); key {
+			case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+				m.leave(key)
+			}
+			return
+		}
+		if m.HandleAccelerator(event, setFocus) {
+			return
+		}
+
+		// Keys while all menus are closed.
+		if len(m.popups) == 0 {
+			switch key := event.Key(); key {
+			case tcell.KeyLeft:
+				m.switchMenu(-1)
+			case tcell.KeyRight:
+				m.switchMenu(1)
+			case tcell.KeyDown:
+				m.openMenu(m.current)
+			case tcell.KeyEnter:
+				if menu := m.menus[m.current]; len(menu.items) > 0 {
+					m.openMenu(m.current)
+				} else {
+					m.activate(menu)
+				}
+			case tcell.KeyRune:
+				if event.Rune() == ' ' {
+					m.openMenu(m.current)
+					break
+				}
+				for index, menu := range m.menus {
+					if menu.selectable() && menu.matchShortcut(event.Rune()) {
+						m.openMenu(index)
+						break
+					}
+				}
+			case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+				m.leave(key)
+			}
+			return
+		}
+
+		// Keys for the innermost open menu.
+		popup := m.popups[len(m.popups)-1]
+		switch key := event.Key(); key {
+		case tcell.KeyUp:
+			popup.move(-1)
+		case tcell.KeyDown:
+			popup.move(1)
+		case tcell.KeyHome:
+			popup.current = -1
+			popup.move(1)
+		case tcell.KeyEnd:
+			popup.current = len(popup.item.items)
+			popup.move(-1)
+		case tcell.KeyLeft:
+			if len(m.popups) > 1 {
+				m.popups = m.popups[:len(m.popups)-1]
+			} else {
+				m.switchMenu(-1)
+			}
+		case tcell.KeyRight:
+			if item := popup.highlighted(); item != nil && len(item.items) > 0 {
+				m.activate(item)
+			} else {
+				m.switchMenu(1)
+			}
+		case tcell.KeyEnter:
+			m.activate(popup.highlighted())
+		case tcell.KeyRune:
+			if event.Rune() == ' ' {
+				m.activate(popup.highlighted())
+				break
+			}
+			for index, item := range popup.item.items {
+				if item.selectable() && item.matchShortcut(event.Rune()) {
+					popup.current = index
+					m.activate(item)
+					break
+				}
+			}
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			m.leave(key)
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (m *MenuBar) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return m.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		rectX, rectY, rectWidth, _ := m.GetInnerRect()
+		inBar := y == rectY && x >= rectX && x < rectX+rectWidth
+		if len(m.popups) == 0 && !inBar {
+			return m.InRect(x, y), nil
+		}
+
+		// As long as a menu is open, we capture all mouse events.
+		m.layout()
+		if len(m.popups) > 0 {
+			capture = m
+		}
+		consumed = true
+
+		// Find the menu under the mouse.
+		menu := -1
+		if inBar {
+			positions := m.menuX()
+			for index := range m.menus {
+				if x >= positions[index] && x < positions[index+1] {
+					menu = index
+					break
+				}
+			}
+		}
+
+		// Find the open menu and the item under the mouse.
+		level, index := -1, -1
+		for popupIndex := len(m.popups) - 1; popupIndex >= 0; popupIndex-- {
+			if m.popups[popupIndex].contains(x, y) {
+				level, index = popupIndex, m.popups[popupIndex].indexAt(x, y)
+				break
+			}
+		}
+
+		switch action {
+		case MouseLeftDown:
+			if menu >= 0 {
+				setFocus(m)
+				if len(m.popups) > 0 && m.current == menu {
+					m.popups = nil // Clicking an open menu closes it.
+				} else if m.menus[menu].selectable() {
+					if len(m.menus[menu].items) > 0 {
+						m.openMenu(menu)
+					} else {
+						m.current = menu
+						m.activate(m.menus[menu])
+					}
+				}
+			} else if level < 0 {
+				m.popups = nil // Clicking outside the menus closes them.
+				capture = nil
+			}
+		case MouseLeftClick:
+			if level >= 0 && index >= 0 {
+				popup := m.popups[level]
+				if item := popup.item.items[index]; item.selectable() {
+					m.popups = m.popups[:level+1]
+					popup.current = index
+					m.activate(item)
+				}
+			}
+		case MouseMove:
+			if menu >= 0 && len(m.popups) > 0 && menu != m.current && m.menus[menu].selectable() {
+				m.openMenu(menu)
+			} else if level >= 0 && index >= 0 {
+				popup := m.popups[level]
+				if item := popup.item.items[index]; item.selectable() {
+					m.popups = m.popups[:level+1]
+					popup.current = index
+					if len(item.items) > 0 {
+						m.activate(item) // Open submenus when hovering over their item.
+					}
+				}
+			}
+		}
+
+		return
+	})
+}
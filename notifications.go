@@ -0,0 +1,310 @@
+package tview
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Notification severities, see [Notifications.Notify].
+const (
+	NotificationInfo = iota
+	NotificationSuccess
+	NotificationWarning
+	NotificationError
+)
+
+// The titles shown for each severity.
+var notificationTitles = []string{"Info", "Success", "Warning", "Error"}
+
+// notification is one toast shown by Notifications.
+type notification struct {
+	id       int
+	severity int
+	message  string
+
+	// Temporary member variables.
+	x, y, width, height int // The toast's position and size when last drawn.
+}
+
+// Notifications shows transient messages ("toasts") on top of the
+// application's primitives, stacked in a corner of the screen. Each
+// application has one Notifications object, see
+// [Application.GetNotifications]. Toasts disappear after a timeout (see
+// [Notifications.SetTimeout]) or when the user clicks on them.
+//
+//	app.GetNotifications().Notify(tview.NotificationError, "Could not save file")
+//
+// All functions of this type may be called from any goroutine. The screen is
+// redrawn automatically when toasts appear or disappear.
+type Notifications struct {
+	// Protects all fields below.
+	mu sync.Mutex
+
+	// The application which shows the toasts.
+	app *Application
+
+	// The visible toasts, from the oldest to the newest.
+	toasts []*notification
+
+	// The ID of the most recently added toast.
+	lastID int
+
+	// The default time after which toasts disappear, 0 for never.
+	timeout time.Duration
+
+	// The maximum number of toasts shown at the same time.
+	maxVisible int
+
+	// The width of the toasts.
+	width int
+
+	// The corner of the screen in which toasts are shown.
+	vertical, horizontal int
+
+	// The styles of the toasts for each severity.
+	styles []tcell.Style
+}
+
+// newNotifications returns a new Notifications object for the given
+// application.
+func newNotifications(app *Application) *Notifications {
+	return &Notifications{
+		app:        app,
+		timeout:    5 * time.Second,
+		maxVisible: 5,
+		width:      40,
+		vertical:   AlignTop,
+		horizontal: AlignRight,
+		styles: []tcell.Style{
+			tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
+			tcell.StyleDefault.Background(tcell.ColorGreen).Foreground(tcell.ColorBlack),
+			tcell.StyleDefault.Background(tcell.ColorYellow).Foreground(tcell.ColorBlack),
+			tcell.StyleDefault.Background(tcell.ColorRed).Foreground(tcell.ColorWhite),
+		},
+	}
+}
+
+// Notify shows a toast with the given severity (one of [NotificationInfo],
+// [NotificationSuccess], [NotificationWarning], or [NotificationError]) and
+// message which disappears after the default timeout. The message may contain
+// style tags. The returned ID may be used to dismiss the toast early.
+func (n *Notifications) Notify(severity int, message string) int {
+	n.mu.Lock()
+	timeout := n.timeout
+	n.mu.Unlock()
+	return n.NotifyWithTimeout(severity, message, timeout)
+}
+
+// NotifyWithTimeout is like [Notifications.Notify] but the toast disappears
+// after the given timeout. If it is 0, the toast stays until it is dismissed.
+func (n *Notifications) NotifyWithTimeout(severity int, message string, timeout time.Duration) int {
+	if severity < NotificationInfo || severity > NotificationError {
+		severity = NotificationInfo
+	}
+	n.mu.Lock()
+	n.lastID++
+	id := n.lastID
+	n.toasts = append(n.toasts, &notification{
+		id:       id,
+		severity: severity,
+		message:  message,
+	})
+	if len(n.toasts) > n.maxVisible {
+		n.toasts = n.toasts[len(n.toasts)-n.maxVisible:]
+	}
+	n.mu.Unlock()
+	n.app.requestDraw()
+
+	if timeout > 0 {
+		time.AfterFunc(timeout, func() {
+			n.Dismiss(id)
+		})
+	}
+	return id
+}
+
+// Dismiss removes the toast with the given ID. Nothing happens if it was
+// already removed.
+func (n *Notifications) Dismiss(id int) *Notifications {
+	n.mu.Lock()
+	for index, toast := range n.toasts {
+		if toast.id == id {
+			n.toasts = append(n.toasts[:index], n.toasts[index+1:]...)
+			break
+		}
+	}
+	n.mu.Unlock()
+	n.app.requestDraw()
+	return n
+}
+
+// DismissAll removes all toasts.
+func (n *Notifications) DismissAll() *Notifications {
+	n.mu.Lock()
+	n.toasts = nil
+	n.mu.Unlock()
+	n.app.requestDraw()
+	return n
+}
+
+// GetCount returns the number of visible toasts.
+func (n *Notifications) GetCount() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.toasts)
+}
+
+// SetTimeout sets the default time after which toasts disappear. Set to 0 to
+// keep toasts until they are dismissed. The default is 5 seconds.
+func (n *Notifications) SetTimeout(timeout time.Duration) *Notifications {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.timeout = timeout
+	return n
+}
+
+// SetMaxVisible sets the maximum number of toasts shown at the same time. If
+// there are more, the oldest toasts are removed. The default is 5.
+func (n *Notifications) SetMaxVisible(count int) *Notifications {
+	if count < 1 {
+		count = 1
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.maxVisible = count
+	if len(n.toasts) > count {
+		n.toasts = n.toasts[len(n.toasts)-count:]
+	}
+	return n
+}
+
+// SetWidth sets the width of the toasts, including their border. The default
+// is 40.
+func (n *Notifications) SetWidth(width int) *Notifications {
+	if width < 5 {
+		width = 5
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.width = width
+	return n
+}
+
+// SetPosition sets the corner of the screen in which toasts are shown. The
+// vertical position is either [AlignTop] or [AlignBottom], the horizontal
+// position is either [AlignLeft] or [AlignRight]. The newest toast is shown
+// closest to the corner. The default is the top-right corner.
+func (n *Notifications) SetPosition(vertical, horizontal int) *Notifications {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.vertical, n.horizontal = vertical, horizontal
+	return n
+}
+
+// SetSeverityStyle sets the style of the toasts with the given severity.
+func (n *Notifications) SetSeverityStyle(severity int, style tcell.Style) *Notifications {
+	if severity < NotificationInfo || severity > NotificationError {
+		return n
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.styles[severity] = style
+	return n
+}
+
+// draw draws the toasts onto the screen. It is called by the application
+// after the root primitive was drawn.
+func (n *Notifications) draw(screen tcell.Screen) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	screenWidth, screenHeight := screen.Size()
+	width := n.width
+	if width > screenWidth-2 {
+		width = screenWidth - 2
+	}
+	if width < 5 {
+		return
+	}
+	x := 1
+	if n.horizontal == AlignRight {
+		x = screenWidth - width - 1
+	}
+	y := 1
+	if n.vertical == AlignBottom {
+		y = screenHeight - 1
+	}
+
+	for index := len(n.toasts) - 1; index >= 0; index-- {
+		toast := n.toasts[index]
+		toast.width = 0
+		lines := WordWrap(toast.message, width-4)
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
+		toast.x, toast.width, toast.height = x, width, len(lines)+2
+		if n.vertical == AlignBottom {
+			y -= toast.height
+			toast.y = y
+		} else {
+			toast.y = y
+			y += toast.height
+		}
+		if toast.y < 0 || toast.y+toast.height > screenHeight {
+			toast.width = 0 // Doesn't fit anymore.
+			continue
+		}
+
+		// Draw the toast.
+		style := n.styles[toast.severity]
+		right, bottom := toast.x+toast.width-1, toast.y+toast.height-1
+		for row := toast.y; row <= bottom; row++ {
+			for column := toast.x; column <= right; column++ {
+				ch := ' '
+				switch {
+				case row == toast.y && column == toast.x:
+					ch = Borders.TopLeft
+				case row == toast.y && column == right:
+					ch = Borders.TopRight
+				case row == bottom && column == toast.x:
+					ch = Borders.BottomLeft
+				case row == bottom && column == right:
+					ch = Borders.BottomRight
+				case row == toast.y || row == bottom:
+					ch = Borders.Horizontal
+				case column == toast.x || column == right:
+					ch = Borders.Vertical
+				}
+				screen.SetContent(column, row, ch, nil, style)
+			}
+		}
+		printWithStyle(screen, " "+notificationTitles[toast.severity]+" ", toast.x+1, toast.y, 0, toast.width-2, AlignLeft, style.Bold(true), false)
+		for line, text := range lines {
+			printWithStyle(screen, text, toast.x+2, toast.y+1+line, 0, toast.width-4, AlignLeft, style, false)
+		}
+	}
+}
+
+// mouseAction handles the given mouse action at the given screen position.
+// Clicking on a toast dismisses it. It returns whether the action happened
+// over a toast and should not be passed on to other primitives.
+func (n *Notifications) mouseAction(action MouseAction, x, y int) bool {
+	n.mu.Lock()
+	var hit *notification
+	for _, toast := range n.toasts {
+		if toast.width > 0 && x >= toast.x && x < toast.x+toast.width && y >= toast.y && y < toast.y+toast.height {
+			hit = toast
+			break
+		}
+	}
+	n.mu.Unlock()
+	if hit == nil || action == MouseMove {
+		return false
+	}
+	if action == MouseLeftClick {
+		n.Dismiss(hit.id)
+	}
+	return true
+}
@@ -0,0 +1,453 @@
+package tview
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Markers with which the points of a plot are drawn, see [Plot.SetMarker].
+const (
+	PlotMarkerBraille = iota // Braille characters, 2x4 points per cell.
+	PlotMarkerBlock          // Half block characters, 1x2 points per cell.
+)
+
+// Types of plots, see [Plot.SetPlotType].
+const (
+	PlotTypeLine    = iota // Consecutive points are connected by lines.
+	PlotTypeScatter        // Only the points themselves are drawn.
+)
+
+// plotSeries is one series of data points of a plot.
+type plotSeries struct {
+	name   string
+	color  tcell.Color
+	xs, ys []float64
+}
+
+// Plot draws one or more series of data points into a coordinate system. The
+// points are drawn with braille or block characters (see [Plot.SetMarker])
+// and may be connected by lines (see [Plot.SetPlotType]).
+//
+// By default, both axes are scaled to the visible data points. Use
+// [Plot.SetXRange] and [Plot.SetYRange] for fixed ranges. For streaming data,
+// [Plot.SetWindow] limits the x-axis to the most recent points and discards
+// older ones:
+//
+//	plot := tview.NewPlot().SetWindow(60)
+//	cpu := plot.AddSeries("CPU", tcell.ColorGreen)
+//	// For every new measurement:
+//	plot.AddPoint(cpu, seconds, load)
+type Plot struct {
+	*Box
+
+	// The series, in the order in which they were added.
+	series []*plotSeries
+
+	// The marker with which points are drawn.
+	marker int
+
+	// The type of the plot.
+	plotType int
+
+	// The fixed axis ranges, if the corresponding flag is set.
+	xMin, xMax, yMin, yMax float64
+	xFixed, yFixed         bool
+
+	// The width of the visible x-range for streaming data or 0 to show all
+	// points.
+	window float64
+
+	// Whether the axes and their labels are shown.
+	showAxes bool
+
+	// Whether the legend is shown.
+	showLegend bool
+
+	// The format string used for the axis labels.
+	axisFormat string
+
+	// The style of the axes and their labels.
+	axisStyle tcell.Style
+
+	// The style of the legend's text.
+	legendStyle tcell.Style
+}
+
+// NewPlot returns a new plot without any series.
+func NewPlot() *Plot {
+	return &Plot{
+		Box:         NewBox(),
+		showAxes:    true,
+		axisFormat:  "%.4g",
+		axisStyle:   tcell.StyleDefault.Foreground(Styles.GraphicsColor),
+		legendStyle: tcell.StyleDefault.Foreground(Styles.PrimaryTextColor),
+	}
+}
+
+// AddSeries adds a new, empty series with the given name, which is shown in
+// the legend, and color. It returns the index of the series which is used to
+// add data points.
+func (p *Plot) AddSeries(name string, color tcell.Color) int {
+	p.series = append(p.series, &plotSeries{name: name, color: color})
+	return len(p.series) - 1
+}
+
+// GetSeriesCount returns the number of series.
+func (p *Plot) GetSeriesCount() int {
+	return len(p.series)
+}
+
+// SetSeriesData replaces the data points of the series with the given index.
+// The slices contain the points' x- and y-coordinates, respectively. If they
+// differ in length, the longer one is truncated. Invalid indices are ignored.
+func (p *Plot) SetSeriesData(index int, xs, ys []float64) *Plot {
+	if index < 0 || index >= len(p.series) {
+		return p
+	}
+	if len(xs) > len(ys) {
+		xs = xs[:len(ys)]
+	} else if len(ys) > len(xs) {
+		ys = ys[:len(xs)]
+	}
+	series := p.series[index]
+	series.xs = append([]float64(nil), xs...)
+	series.ys = append([]float64(nil), ys...)
+	p.trim()
+	return p
+}
+
+// AddPoint adds a data point to the end of the series with the given index.
+// Points are expected to be added in order of increasing x-coordinates. If a
+// window was set, points which fall out of it are discarded. Invalid indices
+// are ignored.
+func (p *Plot) AddPoint(index int, x, y float64) *Plot {
+	if index < 0 || index >= len(p.series) {
+		return p
+	}
+	series := p.series[index]
+	series.xs = append(series.xs, x)
+	series.ys = append(series.ys, y)
+	p.trim()
+	return p
+}
+
+// ClearSeries removes all data points from the series with the given index.
+// Invalid indices are ignored.
+func (p *Plot) ClearSeries(index int) *Plot {
+	if index >= 0 && index < len(p.series) {
+		p.series[index].xs, p.series[index].ys = nil, nil
+	}
+	return p
+}
+
+// Clear removes all series.
+func (p *Plot) Clear() *Plot {
+	p.series = nil
+	return p
+}
+
+// SetMarker sets the characters with which points are drawn, either
+// [PlotMarkerBraille] (the default) or [PlotMarkerBlock].
+func (p *Plot) SetMarker(marker int) *Plot {
+	p.marker = marker
+	return p
+}
+
+// SetPlotType sets whether consecutive points are connected by lines
+// ([PlotTypeLine], the default) or not ([PlotTypeScatter]).
+func (p *Plot) SetPlotType(plotType int) *Plot {
+	p.plotType = plotType
+	return p
+}
+
+// SetXRange sets a fixed range for the x-axis. If min and max are equal, the
+// x-axis is scaled to the data points again.
+func (p *Plot) SetXRange(min, max float64) *Plot {
+	if max < min {
+		min, max = max, min
+	}
+	p.xMin, p.xMax, p.xFixed = min, max, min != max
+	return p
+}
+
+// SetYRange sets a fixed range for the y-axis. If min and max are equal, the
+// y-axis is scaled to the data points again.
+func (p *Plot) SetYRange(min, max float64) *Plot {
+	if max < min {
+		min, max = max, min
+	}
+	p.yMin, p.yMax, p.yFixed = min, max, min != max
+	return p
+}
+
+// SetWindow limits the x-axis to the given width, ending at the largest
+// x-coordinate of all series. Points to the left of the window are discarded.
+// This takes precedence over [Plot.SetXRange]. A width of 0 (the default)
+// turns windowing off.
+func (p *Plot) SetWindow(width float64) *Plot {
+	if width < 0 {
+		width = 0
+	}
+	p.window = width
+	p.trim()
+	return p
+}
+
+// ShowAxes sets whether the axes and their labels are shown. They are shown by
+// default.
+func (p *Plot) ShowAxes(show bool) *Plot {
+	p.showAxes = show
+	return p
+}
+
+// ShowLegend sets whether a legend with the names of all series is shown in
+// the upper right corner.
+func (p *Plot) ShowLegend(show bool) *Plot {
+	p.showLegend = show
+	return p
+}
+
+// SetAxisFormat sets the format string (as used by [fmt.Sprintf]) for the
+// values on the axes. The default is "%.4g".
+func (p *Plot) SetAxisFormat(format string) *Plot {
+	p.axisFormat = format
+	return p
+}
+
+// SetAxisStyle sets the style of the axes and their labels.
+func (p *Plot) SetAxisStyle(style tcell.Style) *Plot {
+	p.axisStyle = style
+	return p
+}
+
+// SetLegendStyle sets the style of the series names in the legend.
+func (p *Plot) SetLegendStyle(style tcell.Style) *Plot {
+	p.legendStyle = style
+	return p
+}
+
+// latest returns the largest x-coordinate of all series and whether there are
+// any points.
+func (p *Plot) latest() (float64, bool) {
+	latest, found := math.Inf(-1), false
+	for _, series := range p.series {
+		for _, x := range series.xs {
+			if x > latest {
+				latest, found = x, true
+			}
+		}
+	}
+	return latest, found
+}
+
+// trim discards points which fall out of the window.
+func (p *Plot) trim() {
+	if p.window <= 0 {
+		return
+	}
+	latest, found := p.latest()
+	if !found {
+		return
+	}
+	for _, series := range p.series {
+		var keep int
+		for keep < len(series.xs) && series.xs[keep] < latest-p.window {
+			keep++
+		}
+		series.xs, series.ys = series.xs[keep:], series.ys[keep:]
+	}
+}
+
+// ranges returns the visible ranges of both axes.
+func (p *Plot) ranges() (xMin, xMax, yMin, yMax float64) {
+	xMin, xMax, yMin, yMax = math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	for _, series := range p.series {
+		for index, x := range series.xs {
+			xMin, xMax = math.Min(xMin, x), math.Max(xMax, x)
+			y := series.ys[index]
+			yMin, yMax = math.Min(yMin, y), math.Max(yMax, y)
+		}
+	}
+	if p.window > 0 {
+		if latest, found := p.latest(); found {
+			xMin, xMax = latest-p.window, latest
+		}
+	} else if p.xFixed {
+		xMin, xMax = p.xMin, p.xMax
+	}
+	if p.yFixed {
+		yMin, yMax = p.yMin, p.yMax
+	}
+	if math.IsInf(xMin, 0) || math.IsInf(xMax, 0) {
+		xMin, xMax = 0, 1
+	}
+	if math.IsInf(yMin, 0) || math.IsInf(yMax, 0) {
+		yMin, yMax = 0, 1
+	}
+	if xMin == xMax {
+		xMin, xMax = xMin-1, xMax+1
+	}
+	if yMin == yMax {
+		yMin, yMax = yMin-1, yMax+1
+	}
+	return
+}
+
+// plotCanvas collects the points drawn onto a plot at sub-cell resolution.
+type plotCanvas struct {
+	width, height int           // The size in cells.
+	dotsX, dotsY  int           // The number of points per cell.
+	dots          []uint8       // The set points of each cell, one bit per point.
+	colors        []tcell.Color // The color of each cell.
+}
+
+// set sets the point at the given sub-cell coordinates, if it is on the
+// canvas.
+func (c *plotCanvas) set(x, y int, color tcell.Color) {
+	if x < 0 || y < 0 || x >= c.width*c.dotsX || y >= c.height*c.dotsY {
+		return
+	}
+	cell := y/c.dotsY*c.width + x/c.dotsX
+	c.dots[cell] |= 1 << uint((x%c.dotsX)*c.dotsY+y%c.dotsY)
+	c.colors[cell] = color
+}
+
+// line sets all points on the line between the given sub-cell coordinates.
+func (c *plotCanvas) line(x1, y1, x2, y2 int, color tcell.Color) {
+	dx, dy := x2-x1, y2-y1
+	steps := dx
+	if steps < 0 {
+		steps = -steps
+	}
+	if dy > steps {
+		steps = dy
+	} else if -dy > steps {
+		steps = -dy
+	}
+	if steps == 0 {
+		c.set(x1, y1, color)
+		return
+	}
+	for step := 0; step <= steps; step++ {
+		c.set(x1+int(math.Round(float64(dx*step)/float64(steps))), y1+int(math.Round(float64(dy*step)/float64(steps))), color)
+	}
+}
+
+// rune returns the character representing the points of the given cell.
+func (c *plotCanvas) rune(cell int) rune {
+	dots := c.dots[cell]
+	if c.dotsX == 1 {
+		// Half blocks: bit 0 is the upper, bit 1 the lower half.
+		return []rune{' ', '▀', '▄', '█'}[dots]
+	}
+
+	// Braille: our bits are column-major (bits 0-3 the left column, bits 4-7
+	// the right column), the braille dots are numbered differently.
+	var braille rune
+	for bit, dot := range []rune{0x01, 0x02, 0x04, 0x40, 0x08, 0x10, 0x20, 0x80} {
+		if dots&(1<<uint(bit)) != 0 {
+			braille |= dot
+		}
+	}
+	return 0x2800 + braille
+}
+
+// Draw draws this primitive onto the screen.
+func (p *Plot) Draw(screen tcell.Screen) {
+	p.Box.DrawForSubclass(screen, p)
+
+	x, y, width, height := p.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	xMin, xMax, yMin, yMax := p.ranges()
+
+	// Draw the axes.
+	axisStyle := p.axisStyle
+	_, axisBg, _ := axisStyle.Decompose()
+	if axisBg == tcell.ColorDefault {
+		axisStyle = axisStyle.Background(p.backgroundColor)
+	}
+	if p.showAxes && width > 2 && height > 2 {
+		yLabels := []string{Escape(fmt.Sprintf(p.axisFormat, yMax)), Escape(fmt.Sprintf(p.axisFormat, yMin))}
+		labelWidth := TaggedStringWidth(yLabels[0])
+		if w := TaggedStringWidth(yLabels[1]); w > labelWidth {
+			labelWidth = w
+		}
+		if labelWidth > width/2 {
+			labelWidth = width / 2
+		}
+		axisX, axisY := x+labelWidth, y+height-2
+		printWithStyle(screen, yLabels[0], x, y, 0, labelWidth, AlignRight, axisStyle, true)
+		printWithStyle(screen, yLabels[1], x, axisY-1, 0, labelWidth, AlignRight, axisStyle, true)
+		for row := y; row < axisY; row++ {
+			screen.SetContent(axisX, row, Borders.Vertical, nil, axisStyle)
+		}
+		screen.SetContent(axisX, axisY, Borders.BottomLeft, nil, axisStyle)
+		for column := axisX + 1; column < x+width; column++ {
+			screen.SetContent(column, axisY, Borders.Horizontal, nil, axisStyle)
+		}
+		printWithStyle(screen, Escape(fmt.Sprintf(p.axisFormat, xMin)), axisX+1, axisY+1, 0, x+width-axisX-1, AlignLeft, axisStyle, true)
+		printWithStyle(screen, Escape(fmt.Sprintf(p.axisFormat, xMax)), axisX+1, axisY+1, 0, x+width-axisX-1, AlignRight, axisStyle, true)
+		width -= axisX + 1 - x
+		height -= 2
+		x = axisX + 1
+	}
+
+	// Draw the data points.
+	canvas := &plotCanvas{width: width, height: height, dotsX: 2, dotsY: 4}
+	if p.marker == PlotMarkerBlock {
+		canvas.dotsX, canvas.dotsY = 1, 2
+	}
+	canvas.dots = make([]uint8, width*height)
+	canvas.colors = make([]tcell.Color, width*height)
+	dotsWidth, dotsHeight := float64(width*canvas.dotsX-1), float64(height*canvas.dotsY-1)
+	for _, series := range p.series {
+		previousX, previousY, previous := 0, 0, false
+		for index, value := range series.xs {
+			dotX := int(math.Round((value - xMin) / (xMax - xMin) * dotsWidth))
+			dotY := int(math.Round((yMax - series.ys[index]) / (yMax - yMin) * dotsHeight))
+			if p.plotType == PlotTypeLine && previous {
+				canvas.line(previousX, previousY, dotX, dotY, series.color)
+			} else {
+				canvas.set(dotX, dotY, series.color)
+			}
+			previousX, previousY, previous = dotX, dotY, true
+		}
+	}
+	for cell, dots := range canvas.dots {
+		if dots == 0 {
+			continue
+		}
+		style := tcell.StyleDefault.Background(p.backgroundColor).Foreground(canvas.colors[cell])
+		screen.SetContent(x+cell%width, y+cell/width, canvas.rune(cell), nil, style)
+	}
+
+	// Draw the legend.
+	if !p.showLegend {
+		return
+	}
+	var legendWidth int
+	for _, series := range p.series {
+		if w := TaggedStringWidth(series.name) + 2; w > legendWidth {
+			legendWidth = w
+		}
+	}
+	if legendWidth > width {
+		legendWidth = width
+	}
+	_, legendBg, _ := p.legendStyle.Decompose()
+	for index, series := range p.series {
+		if index >= height {
+			break
+		}
+		legendX := x + width - legendWidth
+		for column := legendX; column < x+width; column++ {
+			screen.SetContent(column, y+index, ' ', nil, tcell.StyleDefault.Background(p.backgroundColor))
+		}
+		screen.SetContent(legendX, y+index, '●', nil, tcell.StyleDefault.Background(p.backgroundColor).Foreground(series.color))
+		printWithStyle(screen, series.name, legendX+2, y+index, 0, legendWidth-2, AlignLeft, p.legendStyle, legendBg == tcell.ColorDefault)
+	}
+}
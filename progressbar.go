@@ -0,0 +1,289 @@
+package tview
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// The time it takes the pulse of an indeterminate progress bar to move by one
+// cell.
+const progressPulseInterval = 60 * time.Millisecond
+
+// ProgressBar shows the progress of an operation as a horizontal bar which is
+// filled from left to right. The progress is a value between 0 and 1, see
+// [ProgressBar.SetProgress]. A label and the percentage may be shown centered
+// on top of the bar.
+//
+// In indeterminate mode (see [ProgressBar.SetIndeterminate]), the progress is
+// ignored and a pulse moves back and forth across the bar instead.
+//
+// The progress may be changed from any goroutine. If an application was
+// provided with [ProgressBar.SetApplication], it redraws the screen whenever
+// the progress changes and drives the animation in indeterminate mode:
+//
+//	bar := tview.NewProgressBar().SetApplication(app)
+//	go func() {
+//	    for i := 1; i <= 100; i++ {
+//	        work()
+//	        bar.SetProgress(float64(i) / 100)
+//	    }
+//	}()
+type ProgressBar struct {
+	*Box
+
+	// Protects the fields below which may be changed from other goroutines.
+	mu sync.Mutex
+
+	// The progress, between 0 and 1.
+	progress float64
+
+	// Whether the progress is unknown.
+	indeterminate bool
+
+	// The time indeterminate mode was entered.
+	started time.Time
+
+	// The application which is redrawn when the progress changes, if any.
+	app *Application
+
+	// The text shown on top of the bar.
+	label string
+
+	// Whether the percentage is shown on top of the bar.
+	showPercentage bool
+
+	// The runes used for the filled and the empty part of the bar.
+	fillRune, emptyRune rune
+
+	// The styles of the filled and the empty part of the bar.
+	fillStyle, emptyStyle tcell.Style
+
+	// The style of the label and the percentage.
+	textStyle tcell.Style
+}
+
+// NewProgressBar returns a new, empty progress bar.
+func NewProgressBar() *ProgressBar {
+	return &ProgressBar{
+		Box:        NewBox(),
+		fillRune:   '█',
+		emptyRune:  '░',
+		fillStyle:  tcell.StyleDefault.Foreground(Styles.ContrastBackgroundColor),
+		emptyStyle: tcell.StyleDefault.Foreground(Styles.MoreContrastBackgroundColor),
+		textStyle:  tcell.StyleDefault.Foreground(Styles.PrimaryTextColor),
+	}
+}
+
+// SetProgress sets the progress, a value between 0 and 1. Values outside this
+// range are clamped. This function may be called from any goroutine. If an
+// application was set, the screen is redrawn.
+func (p *ProgressBar) SetProgress(progress float64) *ProgressBar {
+	if progress < 0 || math.IsNaN(progress) {
+		progress = 0
+	} else if progress > 1 {
+		progress = 1
+	}
+	p.mu.Lock()
+	changed := progress != p.progress
+	p.progress = progress
+	app := p.app
+	p.mu.Unlock()
+	if changed && app != nil {
+		app.requestDraw()
+	}
+	return p
+}
+
+// GetProgress returns the progress, a value between 0 and 1.
+func (p *ProgressBar) GetProgress() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.progress
+}
+
+// SetIndeterminate sets whether the progress is unknown. In this mode, a pulse
+// moves back and forth across the bar. The animation requires an application,
+// see [ProgressBar.SetApplication]. This function may be called from any
+// goroutine but not from a draw handler.
+func (p *ProgressBar) SetIndeterminate(indeterminate bool) *ProgressBar {
+	p.mu.Lock()
+	if indeterminate == p.indeterminate {
+		p.mu.Unlock()
+		return p
+	}
+	p.indeterminate = indeterminate
+	p.started = time.Now()
+	app := p.app
+	p.mu.Unlock()
+	if app != nil {
+		if indeterminate {
+			app.startAnimation(p, progressPulseInterval)
+		} else {
+			app.stopAnimation(p)
+			app.requestDraw()
+		}
+	}
+	return p
+}
+
+// IsIndeterminate returns whether the progress bar is in indeterminate mode.
+func (p *ProgressBar) IsIndeterminate() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.indeterminate
+}
+
+// SetApplication sets the application which redraws the screen when the
+// progress changes and which drives the animation in indeterminate mode. Use
+// nil to stop redrawing the screen automatically.
+func (p *ProgressBar) SetApplication(app *Application) *ProgressBar {
+	p.mu.Lock()
+	previous := p.app
+	p.app = app
+	indeterminate := p.indeterminate
+	p.mu.Unlock()
+	if previous != nil && previous != app {
+		previous.stopAnimation(p)
+	}
+	if app != nil && indeterminate {
+		app.startAnimation(p, progressPulseInterval)
+	}
+	return p
+}
+
+// SetLabel sets the text shown centered on top of the bar. It may contain
+// style tags. If the percentage is shown, too, it follows the label.
+func (p *ProgressBar) SetLabel(label string) *ProgressBar {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.label = label
+	return p
+}
+
+// GetLabel returns the text shown on top of the bar.
+func (p *ProgressBar) GetLabel() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.label
+}
+
+// ShowPercentage sets whether the percentage is shown centered on top of the
+// bar. It is not shown in indeterminate mode.
+func (p *ProgressBar) ShowPercentage(show bool) *ProgressBar {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.showPercentage = show
+	return p
+}
+
+// SetRunes sets the runes used for the filled and the empty part of the bar.
+// The defaults are '█' and '░'.
+func (p *ProgressBar) SetRunes(fill, empty rune) *ProgressBar {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fillRune, p.emptyRune = fill, empty
+	return p
+}
+
+// SetFillStyle sets the style of the filled part of the bar. Its foreground
+// color is also used as the background color of text on the filled part.
+func (p *ProgressBar) SetFillStyle(style tcell.Style) *ProgressBar {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fillStyle = style
+	return p
+}
+
+// SetEmptyStyle sets the style of the empty part of the bar.
+func (p *ProgressBar) SetEmptyStyle(style tcell.Style) *ProgressBar {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.emptyStyle = style
+	return p
+}
+
+// SetTextStyle sets the style of the label and the percentage.
+func (p *ProgressBar) SetTextStyle(style tcell.Style) *ProgressBar {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.textStyle = style
+	return p
+}
+
+// Draw draws this primitive onto the screen.
+func (p *ProgressBar) Draw(screen tcell.Screen) {
+	p.Box.DrawForSubclass(screen, p)
+
+	x, y, width, height := p.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Determine the filled part of the bar.
+	var fillFrom, fillTo int
+	if p.indeterminate {
+		pulse := width / 5
+		if pulse < 1 {
+			pulse = 1
+		}
+		if span := width - pulse; span > 0 {
+			from := int(time.Since(p.started)/progressPulseInterval) % (2 * span)
+			if from > span {
+				from = 2*span - from
+			}
+			fillFrom = from
+		}
+		fillTo = fillFrom + pulse
+	} else {
+		fillTo = int(math.Round(p.progress * float64(width)))
+	}
+
+	// Draw the bar.
+	filled := func(column int) bool {
+		return column >= fillFrom && column < fillTo
+	}
+	for row := 0; row < height; row++ {
+		for column := 0; column < width; column++ {
+			ch, style := p.emptyRune, p.emptyStyle
+			if filled(column) {
+				ch, style = p.fillRune, p.fillStyle
+			}
+			if _, bg, _ := style.Decompose(); bg == tcell.ColorDefault {
+				style = style.Background(p.backgroundColor)
+			}
+			screen.SetContent(x+column, y+row, ch, nil, style)
+		}
+	}
+
+	// Draw the text on top.
+	text := p.label
+	if p.showPercentage && !p.indeterminate {
+		if text != "" {
+			text += " "
+		}
+		text += fmt.Sprintf("%d%%", int(p.progress*100))
+	}
+	if text == "" {
+		return
+	}
+	textWidth := TaggedStringWidth(text)
+	if textWidth > width {
+		textWidth = width
+	}
+	textX, textY := (width-textWidth)/2, y+height/2
+	for column := textX; column < textX+textWidth; column++ {
+		bg := p.backgroundColor
+		if filled(column) {
+			bg, _, _ = p.fillStyle.Decompose()
+		}
+		screen.SetContent(x+column, textY, ' ', nil, tcell.StyleDefault.Background(bg))
+	}
+	printWithStyle(screen, text, x+textX, textY, 0, textWidth, AlignLeft, p.textStyle, true)
+}
@@ -0,0 +1,398 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// RadioButtons implements a group of mutually exclusive options, arranged
+// vertically or horizontally (see [RadioButtons.SetHorizontal]). At most one
+// option is selected at a time.
+//
+// The following keys are available:
+//
+//   - Up arrow / left arrow: Select the previous option.
+//   - Down arrow / right arrow: Select the next option.
+//   - Home: Select the first option.
+//   - End: Select the last option.
+//
+// Clicking on an option selects it, too.
+type RadioButtons struct {
+	*Box
+
+	// Whether or not the radio buttons are disabled/read-only.
+	disabled bool
+
+	// The options from which the user can choose.
+	options []string
+
+	// The index of the selected option or -1 if no option is selected.
+	currentOption int
+
+	// Whether the options are arranged horizontally.
+	horizontal bool
+
+	// The text to be displayed before the options.
+	label string
+
+	// The screen width of the label area. A value of 0 means use the width of
+	// the label text.
+	labelWidth int
+
+	// The label style.
+	labelStyle tcell.Style
+
+	// The style of the options.
+	optionStyle tcell.Style
+
+	// The style of the selected option when the radio buttons have focus.
+	focusStyle tcell.Style
+
+	// The strings placed before selected and unselected options.
+	selectedMarker, unselectedMarker string
+
+	// An optional function which is called when the user selects an option.
+	selected func(index int, option string)
+
+	// An optional function which is called when the user indicated that they
+	// are done selecting options. The key which was pressed is provided (tab,
+	// shift-tab, enter, or escape).
+	done func(tcell.Key)
+
+	// A callback function set by the Form class and called when the user leaves
+	// this form item.
+	finished func(tcell.Key)
+}
+
+// NewRadioButtons returns a new radio button group with the given options.
+// Initially, no option is selected.
+func NewRadioButtons(options ...string) *RadioButtons {
+	return &RadioButtons{
+		Box:              NewBox(),
+		options:          options,
+		currentOption:    -1,
+		labelStyle:       tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
+		optionStyle:      tcell.StyleDefault.Foreground(Styles.PrimaryTextColor),
+		focusStyle:       tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.ContrastBackgroundColor),
+		selectedMarker:   "◉ ",
+		unselectedMarker: "○ ",
+	}
+}
+
+// SetOptions replaces all options with the given ones. No option is selected
+// afterwards.
+func (r *RadioButtons) SetOptions(options ...string) *RadioButtons {
+	r.options = options
+	r.currentOption = -1
+	return r
+}
+
+// GetOptionCount returns the number of options.
+func (r *RadioButtons) GetOptionCount() int {
+	return len(r.options)
+}
+
+// SetCurrentOption selects the option with the given index. A negative or out
+// of range index deselects all options. This does not trigger the "selected"
+// callback.
+func (r *RadioButtons) SetCurrentOption(index int) *RadioButtons {
+	if index < 0 || index >= len(r.options) {
+		index = -1
+	}
+	r.currentOption = index
+	return r
+}
+
+// GetCurrentOption returns the index and the text of the selected option. If
+// no option is selected, -1 and an empty string are returned.
+func (r *RadioButtons) GetCurrentOption() (int, string) {
+	if r.currentOption < 0 {
+		return -1, ""
+	}
+	return r.currentOption, r.options[r.currentOption]
+}
+
+// SetHorizontal sets whether the options are arranged from left to right
+// instead of from top to bottom.
+func (r *RadioButtons) SetHorizontal(horizontal bool) *RadioButtons {
+	r.horizontal = horizontal
+	return r
+}
+
+// SetLabel sets the text to be displayed before the options.
+func (r *RadioButtons) SetLabel(label string) *RadioButtons {
+	r.label = label
+	return r
+}
+
+// GetLabel returns the text to be displayed before the options.
+func (r *RadioButtons) GetLabel() string {
+	return r.label
+}
+
+// SetLabelWidth sets the screen width of the label. A value of 0 will cause the
+// primitive to use the width of the label string.
+func (r *RadioButtons) SetLabelWidth(width int) *RadioButtons {
+	r.labelWidth = width
+	return r
+}
+
+// SetLabelStyle sets the style of the label.
+func (r *RadioButtons) SetLabelStyle(style tcell.Style) *RadioButtons {
+	r.labelStyle = style
+	return r
+}
+
+// SetOptionStyle sets the style of the options.
+func (r *RadioButtons) SetOptionStyle(style tcell.Style) *RadioButtons {
+	r.optionStyle = style
+	return r
+}
+
+// SetActivatedStyle sets the style of the selected option when the radio
+// buttons have focus.
+func (r *RadioButtons) SetActivatedStyle(style tcell.Style) *RadioButtons {
+	r.focusStyle = style
+	return r
+}
+
+// SetMarkers sets the strings placed before selected and unselected options.
+// They should have the same screen width. The defaults are "◉ " and "○ ".
+func (r *RadioButtons) SetMarkers(selected, unselected string) *RadioButtons {
+	r.selectedMarker, r.unselectedMarker = selected, unselected
+	return r
+}
+
+// SetSelectedFunc sets a handler which is called when the user selects an
+// option. The handler receives the option's index and text.
+func (r *RadioButtons) SetSelectedFunc(handler func(index int, option string)) *RadioButtons {
+	r.selected = handler
+	return r
+}
+
+// SetDoneFunc sets a handler which is called when the user is done selecting
+// options. The callback function is provided with the key that was pressed,
+// which is one of the following:
+//
+//   - KeyEnter: Done selecting.
+//   - KeyEscape: Abort selection.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (r *RadioButtons) SetDoneFunc(handler func(key tcell.Key)) *RadioButtons {
+	r.done = handler
+	return r
+}
+
+// SetFinishedFunc sets a callback invoked when the user leaves this form item.
+func (r *RadioButtons) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
+	r.finished = handler
+	return r
+}
+
+// SetFormAttributes sets attributes shared by all form items.
+func (r *RadioButtons) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
+	r.labelWidth = labelWidth
+	r.labelStyle = r.labelStyle.Foreground(labelColor)
+	r.backgroundColor = bgColor
+	r.optionStyle = r.optionStyle.Foreground(fieldTextColor)
+	return r
+}
+
+// optionWidth returns the screen width of the option with the given index,
+// including its marker.
+func (r *RadioButtons) optionWidth(index int) int {
+	markerWidth := TaggedStringWidth(Escape(r.unselectedMarker))
+	if width := TaggedStringWidth(Escape(r.selectedMarker)); width > markerWidth {
+		markerWidth = width
+	}
+	return markerWidth + TaggedStringWidth(r.options[index])
+}
+
+// GetFieldWidth returns this primitive's field width.
+func (r *RadioButtons) GetFieldWidth() int {
+	var width int
+	for index := range r.options {
+		optionWidth := r.optionWidth(index)
+		if r.horizontal {
+			if index > 0 {
+				width++ // The gap between options.
+			}
+			width += optionWidth
+		} else if optionWidth > width {
+			width = optionWidth
+		}
+	}
+	return width
+}
+
+// GetFieldHeight returns this primitive's field height.
+func (r *RadioButtons) GetFieldHeight() int {
+	if r.horizontal || len(r.options) == 0 {
+		return 1
+	}
+	return len(r.options)
+}
+
+// SetDisabled sets whether or not the item is disabled / read-only.
+func (r *RadioButtons) SetDisabled(disabled bool) FormItem {
+	r.disabled = disabled
+	if r.finished != nil {
+		r.finished(-1)
+	}
+	return r
+}
+
+// Focus is called when this primitive receives focus.
+func (r *RadioButtons) Focus(delegate func(p Primitive)) {
+	// If we're part of a form and this item is disabled, there's nothing the
+	// user can do here so we're finished.
+	if r.finished != nil && r.disabled {
+		r.finished(-1)
+		return
+	}
+
+	r.Box.Focus(delegate)
+}
+
+// fieldX returns the screen x-coordinate at which the options start.
+func (r *RadioButtons) fieldX() int {
+	x, _, width, _ := r.GetInnerRect()
+	labelWidth := r.labelWidth
+	if labelWidth <= 0 {
+		labelWidth = TaggedStringWidth(r.label)
+	}
+	if labelWidth > width {
+		labelWidth = width
+	}
+	return x + labelWidth
+}
+
+// optionPosition returns the screen coordinates of the option with the given
+// index.
+func (r *RadioButtons) optionPosition(index int) (x, y int) {
+	_, y, _, _ = r.GetInnerRect()
+	x = r.fieldX()
+	if !r.horizontal {
+		return x, y + index
+	}
+	for option := 0; option < index; option++ {
+		x += r.optionWidth(option) + 1
+	}
+	return x, y
+}
+
+// optionAt returns the index of the option at the given screen coordinates or
+// -1 if there is none.
+func (r *RadioButtons) optionAt(x, y int) int {
+	for index := range r.options {
+		optionX, optionY := r.optionPosition(index)
+		if y == optionY && x >= optionX && x < optionX+r.optionWidth(index) {
+			return index
+		}
+	}
+	return -1
+}
+
+// Draw draws this primitive onto the screen.
+func (r *RadioButtons) Draw(screen tcell.Screen) {
+	r.Box.DrawForSubclass(screen, r)
+
+	// Prepare.
+	x, y, width, height := r.GetInnerRect()
+	rightLimit, bottomLimit := x+width, y+height
+	if height < 1 || rightLimit <= x {
+		return
+	}
+
+	// Draw label.
+	_, labelBg, _ := r.labelStyle.Decompose()
+	printWithStyle(screen, r.label, x, y, 0, r.fieldX()-x, AlignLeft, r.labelStyle, labelBg == tcell.ColorDefault)
+
+	// Draw options.
+	for index, option := range r.options {
+		optionX, optionY := r.optionPosition(index)
+		if optionY >= bottomLimit || optionX >= rightLimit {
+			break
+		}
+		marker := r.unselectedMarker
+		if index == r.currentOption {
+			marker = r.selectedMarker
+		}
+		style := r.optionStyle
+		if index == r.currentOption && r.HasFocus() && !r.disabled {
+			style = r.focusStyle
+		}
+		_, optionBg, _ := style.Decompose()
+		maintainBackground := optionBg == tcell.ColorDefault
+		_, _, printed := printWithStyle(screen, Escape(marker), optionX, optionY, 0, rightLimit-optionX, AlignLeft, r.optionStyle, true)
+		printWithStyle(screen, option, optionX+printed, optionY, 0, rightLimit-optionX-printed, AlignLeft, style, maintainBackground)
+	}
+}
+
+// selectOption selects the option with the given index on behalf of the user.
+func (r *RadioButtons) selectOption(index int) {
+	if index < 0 || index >= len(r.options) || index == r.currentOption {
+		return
+	}
+	r.currentOption = index
+	if r.selected != nil {
+		r.selected(index, r.options[index])
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (r *RadioButtons) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return r.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if r.disabled {
+			return
+		}
+
+		// Process key event.
+		switch key := event.Key(); key {
+		case tcell.KeyUp, tcell.KeyLeft:
+			if r.currentOption < 0 {
+				r.selectOption(len(r.options) - 1)
+			} else {
+				r.selectOption(r.currentOption - 1)
+			}
+		case tcell.KeyDown, tcell.KeyRight:
+			r.selectOption(r.currentOption + 1)
+		case tcell.KeyHome:
+			r.selectOption(0)
+		case tcell.KeyEnd:
+			r.selectOption(len(r.options) - 1)
+		case tcell.KeyEnter, tcell.KeyTab, tcell.KeyBacktab, tcell.KeyEscape: // We're done.
+			if r.done != nil {
+				r.done(key)
+			}
+			if r.finished != nil {
+				r.finished(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (r *RadioButtons) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return r.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if r.disabled {
+			return false, nil
+		}
+
+		x, y := event.Position()
+		if !r.InRect(x, y) {
+			return false, nil
+		}
+
+		// Process mouse event.
+		switch action {
+		case MouseLeftDown:
+			setFocus(r)
+			consumed = true
+		case MouseLeftClick:
+			r.selectOption(r.optionAt(x, y))
+			consumed = true
+		}
+
+		return
+	})
+}
@@ -0,0 +1,76 @@
+package tview
+
+import "github.com/gdamore/tcell/v2"
+
+// scrollBar describes the position of a primitive's scroll bar on screen and
+// the range it represents.
+type scrollBar struct {
+	x, y, length   int  // The screen position and length of the scroll bar.
+	total, visible int  // The number of scrollable and visible units (e.g. rows).
+	vertical       bool // Whether the scroll bar is vertical or horizontal.
+}
+
+// thumb returns the start position (relative to the scroll bar) and the length
+// of the scroll bar's thumb for the given offset.
+func (b scrollBar) thumb(offset int) (start, length int) {
+	if b.total <= b.visible || b.total <= 0 {
+		return 0, b.length
+	}
+	length = b.length * b.visible / b.total
+	if length < 1 {
+		length = 1
+	}
+	start = (b.length - length) * offset / (b.total - b.visible)
+	if start > b.length-length {
+		start = b.length - length
+	}
+	if start < 0 {
+		start = 0
+	}
+	return
+}
+
+// contains returns whether the given screen coordinates are on the scroll bar.
+func (b scrollBar) contains(x, y int) bool {
+	if b.vertical {
+		return x == b.x && y >= b.y && y < b.y+b.length
+	}
+	return y == b.y && x >= b.x && x < b.x+b.length
+}
+
+// offset returns the offset which causes the thumb to be centered at the given
+// screen coordinates.
+func (b scrollBar) offset(x, y int) int {
+	position := x - b.x
+	if b.vertical {
+		position = y - b.y
+	}
+	_, length := b.thumb(0)
+	if b.length <= length {
+		return 0
+	}
+	offset := (position - length/2) * (b.total - b.visible) / (b.length - length)
+	if offset > b.total-b.visible {
+		offset = b.total - b.visible
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset
+}
+
+// draw draws the scroll bar for the given offset.
+func (b scrollBar) draw(screen tcell.Screen, offset int, style tcell.Style) {
+	start, length := b.thumb(offset)
+	for pos := 0; pos < b.length; pos++ {
+		ch := '░'
+		if pos >= start && pos < start+length {
+			ch = '█'
+		}
+		if b.vertical {
+			screen.SetContent(b.x, b.y+pos, ch, nil, style)
+		} else {
+			screen.SetContent(b.x+pos, b.y, ch, nil, style)
+		}
+	}
+}
@@ -0,0 +1,513 @@
+package tview
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Slider implements a horizontal track with a thumb for selecting a numeric
+// value from a range. The value is changed in steps (see [Slider.SetRange]).
+//
+// The following keys are available:
+//
+//   - Left arrow / down arrow: Decrease the value by one step.
+//   - Right arrow / up arrow: Increase the value by one step.
+//   - Page down / page up: Decrease or increase the value by ten steps.
+//   - Home / end: Set the value to the minimum or maximum.
+//
+// The value can also be changed by clicking on the track, dragging the thumb,
+// or using the mouse wheel. Optionally, the value is shown next to the track
+// (see [Slider.ShowValue]) and tick marks are shown below it (see
+// [Slider.SetTicks]).
+type Slider struct {
+	*Box
+
+	// Whether or not this slider is disabled/read-only.
+	disabled bool
+
+	// The range of values and the step size (0 for continuous values).
+	min, max, step float64
+
+	// The current value.
+	value float64
+
+	// The text to be displayed before the track.
+	label string
+
+	// The screen width of the label area. A value of 0 means use the width of
+	// the label text.
+	labelWidth int
+
+	// The screen width of the field (track and value). A value of 0 means
+	// extend as much as possible.
+	fieldWidth int
+
+	// The label style.
+	labelStyle tcell.Style
+
+	// The style of the track to the right of the thumb.
+	trackStyle tcell.Style
+
+	// The style of the track to the left of the thumb and of the thumb itself.
+	filledStyle tcell.Style
+
+	// The style of the thumb when the slider has focus.
+	focusStyle tcell.Style
+
+	// The runes used to draw the slider.
+	trackRune, filledRune, thumbRune, tickRune rune
+
+	// Whether the value is shown to the right of the track.
+	showValue bool
+
+	// The format string used to show the value.
+	valueFormat string
+
+	// The interval between tick marks or 0 if no tick marks are shown.
+	tickInterval float64
+
+	// The track's position as of the last call to Draw().
+	trackX, trackY, trackWidth int
+
+	// Whether the user is dragging the thumb.
+	dragging bool
+
+	// An optional function which is called when the value was changed.
+	changed func(value float64)
+
+	// An optional function which is called when the user indicated that they
+	// are done changing the value. The key which was pressed is provided (tab,
+	// shift-tab, enter, or escape).
+	done func(tcell.Key)
+
+	// A callback function set by the Form class and called when the user leaves
+	// this form item.
+	finished func(tcell.Key)
+}
+
+// NewSlider returns a new slider with a range from 0 to 100 and a step size of
+// 1.
+func NewSlider() *Slider {
+	return &Slider{
+		Box:         NewBox(),
+		max:         100,
+		step:        1,
+		labelStyle:  tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
+		trackStyle:  tcell.StyleDefault.Foreground(Styles.ContrastBackgroundColor),
+		filledStyle: tcell.StyleDefault.Foreground(Styles.PrimaryTextColor),
+		focusStyle:  tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.ContrastBackgroundColor),
+		trackRune:   '─',
+		filledRune:  '━',
+		thumbRune:   '●',
+		tickRune:    '╵',
+		valueFormat: "%g",
+	}
+}
+
+// SetRange sets the minimum and maximum value and the step size. Values are
+// rounded to the nearest multiple of the step size, counted from the minimum.
+// A step size of 0 allows any value within the range. The current value is
+// adjusted to the new range.
+func (s *Slider) SetRange(min, max, step float64) *Slider {
+	if max < min {
+		min, max = max, min
+	}
+	if step < 0 {
+		step = 0
+	}
+	s.min, s.max, s.step = min, max, step
+	s.value = s.normalize(s.value)
+	return s
+}
+
+// GetRange returns the minimum and maximum value and the step size.
+func (s *Slider) GetRange() (min, max, step float64) {
+	return s.min, s.max, s.step
+}
+
+// SetValue sets the slider's value. It is adjusted to the slider's range and
+// step size. This also triggers the "changed" callback if the value changes
+// with this call.
+func (s *Slider) SetValue(value float64) *Slider {
+	s.setValue(value)
+	return s
+}
+
+// GetValue returns the slider's value.
+func (s *Slider) GetValue() float64 {
+	return s.value
+}
+
+// normalize rounds the given value to the step size and clamps it to the
+// slider's range.
+func (s *Slider) normalize(value float64) float64 {
+	if s.step > 0 {
+		value = s.min + math.Round((value-s.min)/s.step)*s.step
+	}
+	if value > s.max {
+		value = s.max
+	}
+	if value < s.min {
+		value = s.min
+	}
+	return value
+}
+
+// setValue sets the value, adjusted to the slider's range and step size, and
+// triggers the "changed" callback if the value changed.
+func (s *Slider) setValue(value float64) {
+	value = s.normalize(value)
+	if value == s.value {
+		return
+	}
+	s.value = value
+	if s.changed != nil {
+		s.changed(value)
+	}
+}
+
+// stepSize returns the amount by which the value changes with one key press.
+func (s *Slider) stepSize() float64 {
+	if s.step > 0 {
+		return s.step
+	}
+	return (s.max - s.min) / 100
+}
+
+// SetLabel sets the text to be displayed before the track.
+func (s *Slider) SetLabel(label string) *Slider {
+	s.label = label
+	return s
+}
+
+// GetLabel returns the text to be displayed before the track.
+func (s *Slider) GetLabel() string {
+	return s.label
+}
+
+// SetLabelWidth sets the screen width of the label. A value of 0 will cause the
+// primitive to use the width of the label string.
+func (s *Slider) SetLabelWidth(width int) *Slider {
+	s.labelWidth = width
+	return s
+}
+
+// SetLabelStyle sets the style of the label.
+func (s *Slider) SetLabelStyle(style tcell.Style) *Slider {
+	s.labelStyle = style
+	return s
+}
+
+// SetFieldWidth sets the screen width of the track, including the value if it
+// is shown. A value of 0 means extend as much as possible.
+func (s *Slider) SetFieldWidth(width int) *Slider {
+	s.fieldWidth = width
+	return s
+}
+
+// SetTrackStyle sets the style of the track to the right of the thumb.
+func (s *Slider) SetTrackStyle(style tcell.Style) *Slider {
+	s.trackStyle = style
+	return s
+}
+
+// SetFilledStyle sets the style of the track to the left of the thumb and of
+// the thumb itself.
+func (s *Slider) SetFilledStyle(style tcell.Style) *Slider {
+	s.filledStyle = style
+	return s
+}
+
+// SetActivatedStyle sets the style of the thumb when the slider has focus.
+func (s *Slider) SetActivatedStyle(style tcell.Style) *Slider {
+	s.focusStyle = style
+	return s
+}
+
+// SetRunes sets the runes used to draw the track to the right of the thumb,
+// the track to the left of the thumb, the thumb, and the tick marks. The
+// defaults are '─', '━', '●', and '╵'.
+func (s *Slider) SetRunes(track, filled, thumb, tick rune) *Slider {
+	s.trackRune, s.filledRune, s.thumbRune, s.tickRune = track, filled, thumb, tick
+	return s
+}
+
+// ShowValue sets whether the value is shown to the right of the track.
+func (s *Slider) ShowValue(show bool) *Slider {
+	s.showValue = show
+	return s
+}
+
+// SetValueFormat sets the format string (as used by [fmt.Sprintf]) with which
+// the value is shown, see [Slider.ShowValue]. The default is "%g".
+func (s *Slider) SetValueFormat(format string) *Slider {
+	s.valueFormat = format
+	return s
+}
+
+// SetTicks sets the interval between tick marks, which are shown in a row
+// below the track, starting at the minimum value. An interval of 0 (the
+// default) hides the tick marks.
+func (s *Slider) SetTicks(interval float64) *Slider {
+	if interval < 0 {
+		interval = 0
+	}
+	s.tickInterval = interval
+	return s
+}
+
+// SetChangedFunc sets a handler which is called when the value of the slider
+// was changed. The handler receives the new value.
+func (s *Slider) SetChangedFunc(handler func(value float64)) *Slider {
+	s.changed = handler
+	return s
+}
+
+// SetDoneFunc sets a handler which is called when the user is done changing
+// the value. The callback function is provided with the key that was pressed,
+// which is one of the following:
+//
+//   - KeyEnter: Done changing the value.
+//   - KeyEscape: Abort.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (s *Slider) SetDoneFunc(handler func(key tcell.Key)) *Slider {
+	s.done = handler
+	return s
+}
+
+// SetFinishedFunc sets a callback invoked when the user leaves this form item.
+func (s *Slider) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
+	s.finished = handler
+	return s
+}
+
+// SetFormAttributes sets attributes shared by all form items.
+func (s *Slider) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
+	s.labelWidth = labelWidth
+	s.labelStyle = s.labelStyle.Foreground(labelColor)
+	s.backgroundColor = bgColor
+	s.trackStyle = s.trackStyle.Foreground(fieldBgColor)
+	s.filledStyle = s.filledStyle.Foreground(fieldTextColor)
+	s.focusStyle = s.focusStyle.Background(fieldTextColor).Foreground(fieldBgColor)
+	return s
+}
+
+// GetFieldWidth returns this primitive's field width.
+func (s *Slider) GetFieldWidth() int {
+	return s.fieldWidth
+}
+
+// GetFieldHeight returns this primitive's field height.
+func (s *Slider) GetFieldHeight() int {
+	if s.tickInterval > 0 {
+		return 2
+	}
+	return 1
+}
+
+// SetDisabled sets whether or not the item is disabled / read-only.
+func (s *Slider) SetDisabled(disabled bool) FormItem {
+	s.disabled = disabled
+	if s.finished != nil {
+		s.finished(-1)
+	}
+	return s
+}
+
+// Focus is called when this primitive receives focus.
+func (s *Slider) Focus(delegate func(p Primitive)) {
+	// If we're part of a form and this item is disabled, there's nothing the
+	// user can do here so we're finished.
+	if s.finished != nil && s.disabled {
+		s.finished(-1)
+		return
+	}
+
+	s.Box.Focus(delegate)
+}
+
+// valueText returns the given value formatted with the value format.
+func (s *Slider) valueText(value float64) string {
+	return fmt.Sprintf(s.valueFormat, value)
+}
+
+// position returns the track cell (starting at 0) which represents the given
+// value.
+func (s *Slider) position(value float64) int {
+	if s.max <= s.min || s.trackWidth <= 1 {
+		return 0
+	}
+	return int(math.Round((value - s.min) / (s.max - s.min) * float64(s.trackWidth-1)))
+}
+
+// Draw draws this primitive onto the screen.
+func (s *Slider) Draw(screen tcell.Screen) {
+	s.Box.DrawForSubclass(screen, s)
+
+	// Prepare.
+	x, y, width, height := s.GetInnerRect()
+	rightLimit := x + width
+	if height < 1 || rightLimit <= x {
+		return
+	}
+
+	// Draw label.
+	_, labelBg, _ := s.labelStyle.Decompose()
+	if s.labelWidth > 0 {
+		labelWidth := s.labelWidth
+		if labelWidth > width {
+			labelWidth = width
+		}
+		printWithStyle(screen, s.label, x, y, 0, labelWidth, AlignLeft, s.labelStyle, labelBg == tcell.ColorDefault)
+		x += labelWidth
+	} else {
+		_, _, drawnWidth := printWithStyle(screen, s.label, x, y, 0, width, AlignLeft, s.labelStyle, labelBg == tcell.ColorDefault)
+		x += drawnWidth
+	}
+
+	// Determine the track's dimensions.
+	fieldWidth := s.fieldWidth
+	if fieldWidth <= 0 || fieldWidth > rightLimit-x {
+		fieldWidth = rightLimit - x
+	}
+	var valueWidth int
+	if s.showValue {
+		for _, value := range []float64{s.min, s.max, s.value} {
+			if w := TaggedStringWidth(Escape(s.valueText(value))); w > valueWidth {
+				valueWidth = w
+			}
+		}
+		valueWidth++ // Add one space.
+	}
+	if valueWidth >= fieldWidth {
+		valueWidth = 0
+	}
+	s.trackX, s.trackY, s.trackWidth = x, y, fieldWidth-valueWidth
+	if s.trackWidth <= 0 {
+		return
+	}
+
+	// Draw the track.
+	thumb := s.position(s.value)
+	for index := 0; index < s.trackWidth; index++ {
+		ch, style := s.trackRune, s.trackStyle
+		if index < thumb {
+			ch, style = s.filledRune, s.filledStyle
+		} else if index == thumb {
+			ch, style = s.thumbRune, s.filledStyle
+			if s.HasFocus() && !s.disabled {
+				style = s.focusStyle
+			}
+		}
+		if _, bg, _ := style.Decompose(); bg == tcell.ColorDefault {
+			style = style.Background(s.backgroundColor)
+		}
+		screen.SetContent(x+index, y, ch, nil, style)
+	}
+
+	// Draw the value.
+	if valueWidth > 0 {
+		valueX := x + s.trackWidth + 1
+		printWithStyle(screen, Escape(s.valueText(s.value)), valueX, y, 0, valueWidth-1, AlignRight, s.filledStyle, true)
+	}
+
+	// Draw the tick marks.
+	if s.tickInterval > 0 && height > 1 && s.max > s.min {
+		style := s.trackStyle.Background(s.backgroundColor)
+		for value := s.min; value <= s.max; value += s.tickInterval {
+			screen.SetContent(x+s.position(value), y+1, s.tickRune, nil, style)
+		}
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (s *Slider) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return s.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if s.disabled {
+			return
+		}
+
+		// Process key event.
+		switch key := event.Key(); key {
+		case tcell.KeyLeft, tcell.KeyDown:
+			s.setValue(s.value - s.stepSize())
+		case tcell.KeyRight, tcell.KeyUp:
+			s.setValue(s.value + s.stepSize())
+		case tcell.KeyPgDn:
+			s.setValue(s.value - 10*s.stepSize())
+		case tcell.KeyPgUp:
+			s.setValue(s.value + 10*s.stepSize())
+		case tcell.KeyHome:
+			s.setValue(s.min)
+		case tcell.KeyEnd:
+			s.setValue(s.max)
+		case tcell.KeyEnter, tcell.KeyTab, tcell.KeyBacktab, tcell.KeyEscape: // We're done.
+			if s.done != nil {
+				s.done(key)
+			}
+			if s.finished != nil {
+				s.finished(key)
+			}
+		}
+	})
+}
+
+// valueAt returns the value represented by the given screen x-coordinate on
+// the track.
+func (s *Slider) valueAt(x int) float64 {
+	if s.trackWidth <= 1 {
+		return s.min
+	}
+	return s.min + float64(x-s.trackX)/float64(s.trackWidth-1)*(s.max-s.min)
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (s *Slider) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return s.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if s.disabled {
+			return false, nil
+		}
+
+		// Drag the thumb.
+		x, y := event.Position()
+		if s.dragging {
+			switch action {
+			case MouseMove:
+				s.setValue(s.valueAt(x))
+				return true, s
+			case MouseLeftUp:
+				s.dragging = false
+				return true, nil
+			}
+		}
+
+		if !s.InRect(x, y) {
+			return false, nil
+		}
+
+		// Process mouse event.
+		switch action {
+		case MouseLeftDown:
+			setFocus(s)
+			consumed = true
+			if y == s.trackY && x >= s.trackX && x < s.trackX+s.trackWidth {
+				s.setValue(s.valueAt(x))
+				s.dragging = true
+				capture = s
+			}
+		case MouseLeftClick:
+			consumed = true
+		case MouseScrollUp, MouseScrollRight:
+			s.setValue(s.value + s.stepSize())
+			consumed = true
+		case MouseScrollDown, MouseScrollLeft:
+			s.setValue(s.value - s.stepSize())
+			consumed = true
+		}
+
+		return
+	})
+}
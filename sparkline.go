@@ -0,0 +1,227 @@
+package tview
+
+import (
+	"math"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Sparkline shows a rolling series of values as a small bar chart drawn with
+// block characters, one column per value. The most recent value is shown in
+// the rightmost column. The sparkline keeps a fixed number of values (see
+// [Sparkline.SetCapacity]), older values are discarded.
+//
+// By default, the bars are scaled between the minimum and the maximum of the
+// visible values. Use [Sparkline.SetRange] for a fixed scale.
+//
+// Values may be added from any goroutine with [Sparkline.Push]. If an
+// application was provided with [Sparkline.SetApplication], the screen is then
+// redrawn automatically.
+type Sparkline struct {
+	*Box
+
+	// Protects the fields below which may be changed from other goroutines.
+	mu sync.Mutex
+
+	// The ring buffer holding the values.
+	values []float64
+
+	// The index of the oldest value in the ring buffer.
+	start int
+
+	// The number of values in the ring buffer.
+	count int
+
+	// Whether the scale is determined by the visible values.
+	autoScale bool
+
+	// The fixed scale if autoScale is false.
+	min, max float64
+
+	// The color of the bars.
+	color tcell.Color
+
+	// The application which is redrawn when values are added, if any.
+	app *Application
+}
+
+// NewSparkline returns a new, empty sparkline which keeps up to 100 values.
+func NewSparkline() *Sparkline {
+	return &Sparkline{
+		Box:       NewBox(),
+		values:    make([]float64, 100),
+		autoScale: true,
+		color:     Styles.GraphicsColor,
+	}
+}
+
+// SetCapacity sets the maximum number of values kept by the sparkline. If
+// there are more values, the oldest ones are discarded.
+func (s *Sparkline) SetCapacity(capacity int) *Sparkline {
+	if capacity < 1 {
+		capacity = 1
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values := s.ordered()
+	if len(values) > capacity {
+		values = values[len(values)-capacity:]
+	}
+	s.values = make([]float64, capacity)
+	copy(s.values, values)
+	s.start, s.count = 0, len(values)
+	return s
+}
+
+// GetCapacity returns the maximum number of values kept by the sparkline.
+func (s *Sparkline) GetCapacity() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.values)
+}
+
+// Push adds a value to the end of the series, discarding the oldest value if
+// the capacity is reached. This function may be called from any goroutine. If
+// an application was set, the screen is redrawn.
+func (s *Sparkline) Push(value float64) *Sparkline {
+	s.mu.Lock()
+	if s.count < len(s.values) {
+		s.values[(s.start+s.count)%len(s.values)] = value
+		s.count++
+	} else {
+		s.values[s.start] = value
+		s.start = (s.start + 1) % len(s.values)
+	}
+	app := s.app
+	s.mu.Unlock()
+	if app != nil {
+		app.requestDraw()
+	}
+	return s
+}
+
+// GetValues returns a copy of the values, from the oldest to the most recent.
+func (s *Sparkline) GetValues() []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ordered()
+}
+
+// ordered returns a copy of the values, from the oldest to the most recent.
+// The caller must hold the lock.
+func (s *Sparkline) ordered() []float64 {
+	values := make([]float64, s.count)
+	for index := range values {
+		values[index] = s.values[(s.start+index)%len(s.values)]
+	}
+	return values
+}
+
+// Clear removes all values.
+func (s *Sparkline) Clear() *Sparkline {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.start, s.count = 0, 0
+	return s
+}
+
+// SetRange sets a fixed scale for the bars. Values outside the range are
+// clamped. This turns off automatic scaling.
+func (s *Sparkline) SetRange(min, max float64) *Sparkline {
+	if max < min {
+		min, max = max, min
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.min, s.max = min, max
+	s.autoScale = false
+	return s
+}
+
+// SetAutoScale sets whether the bars are scaled between the minimum and the
+// maximum of the visible values. This is the default. If turned off, the range
+// set with [Sparkline.SetRange] is used.
+func (s *Sparkline) SetAutoScale(autoScale bool) *Sparkline {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.autoScale = autoScale
+	return s
+}
+
+// SetColor sets the color of the bars.
+func (s *Sparkline) SetColor(color tcell.Color) *Sparkline {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.color = color
+	return s
+}
+
+// SetApplication sets the application which redraws the screen when values
+// are added. Use nil to stop redrawing the screen automatically.
+func (s *Sparkline) SetApplication(app *Application) *Sparkline {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.app = app
+	return s
+}
+
+// Draw draws this primitive onto the screen.
+func (s *Sparkline) Draw(screen tcell.Screen) {
+	s.Box.DrawForSubclass(screen, s)
+
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Get the visible values.
+	values := s.ordered()
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return
+	}
+
+	// Determine the scale.
+	min, max := s.min, s.max
+	if s.autoScale {
+		min, max = math.Inf(1), math.Inf(-1)
+		for _, value := range values {
+			if value < min {
+				min = value
+			}
+			if value > max {
+				max = value
+			}
+		}
+	}
+
+	// Draw the bars. Even the lowest value is visible.
+	style := tcell.StyleDefault.Background(s.backgroundColor).Foreground(s.color)
+	x += width - len(values)
+	for column, value := range values {
+		eighths := 1
+		if max > min {
+			fraction := (value - min) / (max - min)
+			if fraction < 0 || math.IsNaN(fraction) {
+				fraction = 0
+			} else if fraction > 1 {
+				fraction = 1
+			}
+			eighths = 1 + int(math.Round(fraction*float64(height*8-1)))
+		}
+		for row := 0; row < height && eighths > 0; row++ {
+			block := eighths
+			if block > 8 {
+				block = 8
+			}
+			screen.SetContent(x+column, y+height-1-row, gaugeVerticalBlocks[block], nil, style)
+			eighths -= block
+		}
+	}
+}
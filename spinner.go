@@ -0,0 +1,211 @@
+package tview
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Built-in frame sets for spinners, see [Spinner.SetFrameSet].
+const (
+	SpinnerDots = iota // Braille dots: ⠋ ⠙ ⠹ ⠸ ⠼ ⠴ ⠦ ⠧ ⠇ ⠏
+	SpinnerLine        // A rotating line: - \ | /
+	SpinnerArc         // A rotating arc: ◜ ◠ ◝ ◞ ◡ ◟
+)
+
+// spinnerFrameSets contains the frames and the time between frames of the
+// built-in frame sets.
+var spinnerFrameSets = map[int]struct {
+	frames   []string
+	interval time.Duration
+}{
+	SpinnerDots: {[]string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}, 80 * time.Millisecond},
+	SpinnerLine: {[]string{"-", "\\", "|", "/"}, 130 * time.Millisecond},
+	SpinnerArc:  {[]string{"◜", "◠", "◝", "◞", "◡", "◟"}, 100 * time.Millisecond},
+}
+
+// Spinner is an activity indicator which cycles through a set of frames while
+// some work is in progress, optionally followed by a label. The animation is
+// started with [Spinner.Start] and driven by the application which redraws the
+// screen as needed. No separate goroutine is required:
+//
+//	spinner := tview.NewSpinner().SetLabel("Loading...")
+//	spinner.Start(app)
+//	go func() {
+//	    load()
+//	    spinner.Stop()
+//	}()
+//
+// While the spinner is stopped, only the label is shown.
+type Spinner struct {
+	*Box
+
+	// Protects the fields below which may be changed from other goroutines.
+	mu sync.Mutex
+
+	// The frames to cycle through.
+	frames []string
+
+	// The time between two frames.
+	interval time.Duration
+
+	// The text to be displayed after the frames.
+	label string
+
+	// The style of the frames.
+	frameStyle tcell.Style
+
+	// The style of the label.
+	labelStyle tcell.Style
+
+	// The application driving the animation or nil if the spinner is stopped.
+	app *Application
+
+	// The time the spinner was started.
+	started time.Time
+}
+
+// NewSpinner returns a new, stopped spinner using the [SpinnerDots] frame set.
+func NewSpinner() *Spinner {
+	set := spinnerFrameSets[SpinnerDots]
+	return &Spinner{
+		Box:        NewBox(),
+		frames:     set.frames,
+		interval:   set.interval,
+		frameStyle: tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
+		labelStyle: tcell.StyleDefault.Foreground(Styles.PrimaryTextColor),
+	}
+}
+
+// SetFrameSet sets one of the built-in frame sets [SpinnerDots], [SpinnerLine],
+// or [SpinnerArc], including its interval. Unknown values are ignored.
+func (s *Spinner) SetFrameSet(set int) *Spinner {
+	frameSet, ok := spinnerFrameSets[set]
+	if !ok {
+		return s
+	}
+	s.mu.Lock()
+	s.frames = frameSet.frames
+	s.mu.Unlock()
+	return s.SetInterval(frameSet.interval)
+}
+
+// SetFrames sets custom frames to cycle through. Frames may contain style tags.
+// They should all have the same screen width.
+func (s *Spinner) SetFrames(frames ...string) *Spinner {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frames = frames
+	return s
+}
+
+// SetInterval sets the time between two frames.
+func (s *Spinner) SetInterval(interval time.Duration) *Spinner {
+	if interval <= 0 {
+		return s
+	}
+	s.mu.Lock()
+	s.interval = interval
+	app := s.app
+	s.mu.Unlock()
+	if app != nil {
+		app.startAnimation(s, interval)
+	}
+	return s
+}
+
+// SetLabel sets the text to be displayed after the frames.
+func (s *Spinner) SetLabel(label string) *Spinner {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.label = label
+	return s
+}
+
+// GetLabel returns the text to be displayed after the frames.
+func (s *Spinner) GetLabel() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.label
+}
+
+// SetFrameStyle sets the style of the frames.
+func (s *Spinner) SetFrameStyle(style tcell.Style) *Spinner {
+	s.frameStyle = style
+	return s
+}
+
+// SetLabelStyle sets the style of the label.
+func (s *Spinner) SetLabelStyle(style tcell.Style) *Spinner {
+	s.labelStyle = style
+	return s
+}
+
+// Start starts the animation. The given application redraws the screen
+// whenever the next frame is due until [Spinner.Stop] is called. Calling this
+// function on a running spinner has no effect. It may be called from any
+// goroutine but not from a draw handler (see [Application.SetBeforeDrawFunc]).
+func (s *Spinner) Start(app *Application) *Spinner {
+	s.mu.Lock()
+	if s.app != nil || app == nil {
+		s.mu.Unlock()
+		return s
+	}
+	s.app = app
+	s.started = time.Now()
+	interval := s.interval
+	s.mu.Unlock()
+	app.startAnimation(s, interval)
+	return s
+}
+
+// Stop stops the animation. It may be called from any goroutine but not from
+// a draw handler.
+func (s *Spinner) Stop() *Spinner {
+	s.mu.Lock()
+	app := s.app
+	s.app = nil
+	s.mu.Unlock()
+	if app != nil {
+		app.stopAnimation(s)
+		app.requestDraw()
+	}
+	return s
+}
+
+// IsRunning returns whether the animation is running.
+func (s *Spinner) IsRunning() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.app != nil
+}
+
+// Draw draws this primitive onto the screen.
+func (s *Spinner) Draw(screen tcell.Screen) {
+	s.Box.DrawForSubclass(screen, s)
+
+	x, y, width, height := s.GetInnerRect()
+	if height < 1 || width < 1 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Draw the current frame.
+	if s.app != nil && len(s.frames) > 0 {
+		frame := int(time.Since(s.started)/s.interval) % len(s.frames)
+		_, _, printed := printWithStyle(screen, s.frames[frame], x, y, 0, width, AlignLeft, s.frameStyle, true)
+		if s.label != "" {
+			printed++ // Add one space.
+		}
+		x += printed
+		width -= printed
+	}
+
+	// Draw the label.
+	if width > 0 {
+		printWithStyle(screen, s.label, x, y, 0, width, AlignLeft, s.labelStyle, true)
+	}
+}
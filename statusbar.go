@@ -0,0 +1,409 @@
+package tview
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// StatusSegment is a piece of text shown in a [StatusBar]. Segments are
+// aligned to the left, center, or right of the status bar. When there is not
+// enough space for all segments, those with the lowest priority are truncated
+// or hidden first.
+type StatusSegment struct {
+	// The segment's text which may contain style tags.
+	text string
+
+	// The alignment within the status bar, one of AlignLeft, AlignCenter, or
+	// AlignRight.
+	align int
+
+	// Segments with a higher priority are truncated later.
+	priority int
+
+	// The segment's style. If it is tcell.StyleDefault, the status bar's
+	// style is used.
+	style tcell.Style
+
+	// An optional function which is called when the user clicks on the
+	// segment.
+	clicked func()
+
+	// Temporary member variables.
+	x, width int // The position and width of the segment when last drawn.
+}
+
+// NewStatusSegment returns a new left-aligned segment with the given text.
+func NewStatusSegment(text string) *StatusSegment {
+	return &StatusSegment{
+		text:  text,
+		align: AlignLeft,
+	}
+}
+
+// SetText sets the segment's text which may contain style tags.
+func (s *StatusSegment) SetText(text string) *StatusSegment {
+	s.text = text
+	return s
+}
+
+// GetText returns the segment's text.
+func (s *StatusSegment) GetText() string {
+	return s.text
+}
+
+// SetAlign sets the segment's alignment within the status bar, one of
+// AlignLeft, AlignCenter, or AlignRight. Segments with the same alignment are
+// shown in the order they were added.
+func (s *StatusSegment) SetAlign(align int) *StatusSegment {
+	s.align = align
+	return s
+}
+
+// GetAlign returns the segment's alignment.
+func (s *StatusSegment) GetAlign() int {
+	return s.align
+}
+
+// SetPriority sets the segment's priority. When there is not enough space,
+// segments with lower priorities are truncated or hidden first. Among
+// segments with the same priority, the one added last goes first. The
+// default is 0.
+func (s *StatusSegment) SetPriority(priority int) *StatusSegment {
+	s.priority = priority
+	return s
+}
+
+// GetPriority returns the segment's priority.
+func (s *StatusSegment) GetPriority() int {
+	return s.priority
+}
+
+// SetStyle sets the segment's style. Set to tcell.StyleDefault to use the
+// status bar's style.
+func (s *StatusSegment) SetStyle(style tcell.Style) *StatusSegment {
+	s.style = style
+	return s
+}
+
+// SetClickedFunc sets a function which is called when the user clicks on the
+// segment.
+func (s *StatusSegment) SetClickedFunc(handler func()) *StatusSegment {
+	s.clicked = handler
+	return s
+}
+
+// StatusBar is a single line of text segments, usually placed at the bottom
+// of the screen. See [StatusSegment] for how segments are aligned and
+// truncated. Segments may react to mouse clicks.
+//
+// In addition, the status bar may temporarily show a message (see
+// [StatusBar.Flash]) to the left of all other segments. If an application was
+// provided with [StatusBar.SetApplication], the screen is redrawn
+// automatically when the message appears and disappears.
+type StatusBar struct {
+	*Box
+
+	// The segments, in the order they were added.
+	segments []*StatusSegment
+
+	// The text drawn between adjacent segments with the same alignment.
+	separator string
+
+	// The style of the status bar.
+	style tcell.Style
+
+	// Protects the fields below which may be changed from other goroutines.
+	mu sync.Mutex
+
+	// The current flash message, if any.
+	flash string
+
+	// The style of flash messages.
+	flashStyle tcell.Style
+
+	// Incremented with each flash message so expired timers can be ignored.
+	flashGeneration int
+
+	// The application which is redrawn when flash messages change, if any.
+	app *Application
+}
+
+// NewStatusBar returns a new status bar without any segments.
+func NewStatusBar() *StatusBar {
+	return &StatusBar{
+		Box:        NewBox(),
+		separator:  "│",
+		style:      tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		flashStyle: tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.SecondaryTextColor).Bold(true),
+	}
+}
+
+// AddSegment adds a segment to the status bar.
+func (s *StatusBar) AddSegment(segment *StatusSegment) *StatusBar {
+	s.segments = append(s.segments, segment)
+	return s
+}
+
+// RemoveSegment removes the given segment from the status bar.
+func (s *StatusBar) RemoveSegment(segment *StatusSegment) *StatusBar {
+	for index, seg := range s.segments {
+		if seg == segment {
+			s.segments = append(s.segments[:index], s.segments[index+1:]...)
+			break
+		}
+	}
+	return s
+}
+
+// GetSegments returns the segments of the status bar.
+func (s *StatusBar) GetSegments() []*StatusSegment {
+	return s.segments
+}
+
+// ClearSegments removes all segments from the status bar.
+func (s *StatusBar) ClearSegments() *StatusBar {
+	s.segments = nil
+	return s
+}
+
+// SetSeparator sets the text drawn between adjacent segments with the same
+// alignment. It may contain style tags.
+func (s *StatusBar) SetSeparator(separator string) *StatusBar {
+	s.separator = separator
+	return s
+}
+
+// SetStyle sets the style of the status bar which is also used for segments
+// without their own style.
+func (s *StatusBar) SetStyle(style tcell.Style) *StatusBar {
+	s.style = style
+	return s
+}
+
+// SetFlashStyle sets the style of flash messages.
+func (s *StatusBar) SetFlashStyle(style tcell.Style) *StatusBar {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flashStyle = style
+	return s
+}
+
+// Flash shows the given message for the given duration, replacing any
+// previous message. The message is shown to the left of all other segments
+// and is never truncated in favor of them. This function may be called from
+// any goroutine.
+func (s *StatusBar) Flash(message string, duration time.Duration) *StatusBar {
+	s.mu.Lock()
+	s.flash = message
+	s.flashGeneration++
+	generation := s.flashGeneration
+	app := s.app
+	s.mu.Unlock()
+	if app != nil {
+		app.requestDraw()
+	}
+
+	time.AfterFunc(duration, func() {
+		s.mu.Lock()
+		if s.flashGeneration != generation {
+			s.mu.Unlock()
+			return // A newer message is shown.
+		}
+		s.flash = ""
+		app := s.app
+		s.mu.Unlock()
+		if app != nil {
+			app.requestDraw()
+		}
+	})
+	return s
+}
+
+// ClearFlash removes the current flash message, if any. This function may be
+// called from any goroutine.
+func (s *StatusBar) ClearFlash() *StatusBar {
+	s.mu.Lock()
+	s.flash = ""
+	s.flashGeneration++
+	app := s.app
+	s.mu.Unlock()
+	if app != nil {
+		app.requestDraw()
+	}
+	return s
+}
+
+// GetFlash returns the current flash message or an empty string if there is
+// none.
+func (s *StatusBar) GetFlash() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flash
+}
+
+// SetApplication sets the application which redraws the screen when flash
+// messages appear and disappear. Set to nil to disable automatic redraws.
+func (s *StatusBar) SetApplication(app *Application) *StatusBar {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.app = app
+	return s
+}
+
+// layout calculates the widths of the given segments so they fit into the
+// given width, truncating or hiding the segments with the lowest priority
+// first. Hidden segments have a width of 0.
+func (s *StatusBar) layout(segments []*StatusSegment, width int) {
+	for _, segment := range segments {
+		segment.width = 0
+		if segment.text != "" {
+			segment.width = TaggedStringWidth(segment.text) + 2 // Padding.
+		}
+	}
+	separatorWidth := TaggedStringWidth(s.separator)
+	total := func() int {
+		var sum int
+		counts := make(map[int]int)
+		for _, segment := range segments {
+			if segment.width > 0 {
+				sum += segment.width
+				if counts[segment.align] > 0 {
+					sum += separatorWidth
+				}
+				counts[segment.align]++
+			}
+		}
+		return sum
+	}
+
+	for {
+		excess := total() - width
+		if excess <= 0 {
+			return
+		}
+		var victim *StatusSegment
+		for _, segment := range segments {
+			if segment.width > 0 && (victim == nil || segment.priority <= victim.priority) {
+				victim = segment
+			}
+		}
+		if victim == nil {
+			return
+		}
+		if victim.width-excess >= 3 {
+			victim.width -= excess
+		} else {
+			victim.width = 0
+		}
+	}
+}
+
+// Draw draws this primitive onto the screen.
+func (s *StatusBar) Draw(screen tcell.Screen) {
+	s.Box.DrawForSubclass(screen, s)
+
+	x, y, width, height := s.GetInnerRect()
+	for _, segment := range s.segments {
+		segment.width = 0
+	}
+	if width <= 0 || height <= 0 {
+		return
+	}
+	for index := 0; index < width; index++ {
+		screen.SetContent(x+index, y, ' ', nil, s.style)
+	}
+
+	// Determine which segments are shown and how wide.
+	segments := s.segments
+	s.mu.Lock()
+	if s.flash != "" {
+		flash := &StatusSegment{
+			text:     s.flash,
+			align:    AlignLeft,
+			priority: math.MaxInt,
+			style:    s.flashStyle,
+		}
+		segments = append([]*StatusSegment{flash}, segments...)
+	}
+	s.mu.Unlock()
+	s.layout(segments, width)
+
+	// Calculate the positions of the groups.
+	separatorWidth := TaggedStringWidth(s.separator)
+	groupWidths := make(map[int]int)
+	for _, segment := range segments {
+		if segment.width > 0 {
+			if groupWidths[segment.align] > 0 {
+				groupWidths[segment.align] += separatorWidth
+			}
+			groupWidths[segment.align] += segment.width
+		}
+	}
+	leftEnd, rightStart := x+groupWidths[AlignLeft], x+width-groupWidths[AlignRight]
+	groupX := map[int]int{
+		AlignLeft:   x,
+		AlignCenter: x + (width-groupWidths[AlignCenter])/2,
+		AlignRight:  rightStart,
+	}
+	if groupX[AlignCenter]+groupWidths[AlignCenter] > rightStart {
+		groupX[AlignCenter] = rightStart - groupWidths[AlignCenter]
+	}
+	if groupX[AlignCenter] < leftEnd {
+		groupX[AlignCenter] = leftEnd
+	}
+
+	// Draw the segments.
+	drawn := make(map[int]bool)
+	for _, segment := range segments {
+		if segment.width <= 0 {
+			continue
+		}
+		align := segment.align
+		if drawn[align] {
+			printWithStyle(screen, s.separator, groupX[align], y, 0, separatorWidth, AlignLeft, s.style, true)
+			groupX[align] += separatorWidth
+		}
+		drawn[align] = true
+
+		segment.x = groupX[align]
+		groupX[align] += segment.width
+		style := segment.style
+		if style == tcell.StyleDefault {
+			style = s.style
+		}
+		for index := 0; index < segment.width; index++ {
+			screen.SetContent(segment.x+index, y, ' ', nil, style)
+		}
+		textWidth := segment.width - 2
+		printWithStyle(screen, segment.text, segment.x+1, y, 0, textWidth, AlignLeft, style, true)
+		if textWidth < TaggedStringWidth(segment.text) {
+			screen.SetContent(segment.x+textWidth, y, '…', nil, style)
+		}
+	}
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (s *StatusBar) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return s.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if !s.InRect(x, y) {
+			return false, nil
+		}
+
+		if action == MouseLeftClick {
+			_, rectY, _, _ := s.GetInnerRect()
+			for _, segment := range s.segments {
+				if segment.clicked != nil && segment.width > 0 && y == rectY && x >= segment.x && x < segment.x+segment.width {
+					segment.clicked()
+					consumed = true
+					break
+				}
+			}
+		}
+
+		return
+	})
+}
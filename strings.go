@@ -33,6 +33,7 @@ type stepState struct {
 	boundaries      int         // Information about boundaries, as returned by uniseg.Step.
 	style           tcell.Style // The current style.
 	region          string      // The current region.
+	url             string      // The current URL of a hyperlink.
 	escapedTagState int         // States for parsing escaped tags (defined in [step]).
 	grossLength     int         // The length of the cluster, including any tags not returned.
 
@@ -173,10 +174,11 @@ func step(str string, state *stepState, opts stepOptions) (cluster, rest string,
 		if state.escapedTagState == etNone {
 			if cluster[0] == '[' {
 				// We've already opened a tag. Parse it.
-				length, style, region := parseTag(str, state)
+				length, style, region, url := parseTag(str, state)
 				if length > 0 {
 					state.style = style
 					state.region = region
+					state.url = url
 					cluster, rest, state.boundaries, state.unisegState = uniseg.StepString(str[length:], preState)
 					state.grossLength = len(cluster) + length
 					if rest == "" {
@@ -193,7 +195,7 @@ func step(str string, state *stepState, opts stepOptions) (cluster, rest string,
 			if len(rest) > 0 && rest[0] == '[' {
 				// A tag might follow the cluster. If so, we need to fix the state
 				// for the boundaries to be correct.
-				if length, _, _ := parseTag(rest, state); length > 0 {
+				if length, _, _, _ := parseTag(rest, state); length > 0 {
 					if len(rest) > length {
 						_, l := utf8.DecodeRuneInString(rest[length:])
 						cluster += rest[length : length+l]
@@ -217,8 +219,9 @@ func step(str string, state *stepState, opts stepOptions) (cluster, rest string,
 // parseTag parses str for consecutive style and/or region tags, assuming that
 // str starts with the opening bracket for the first tag. It returns the string
 // length of all valid tags (0 if the first tag is not valid) and the updated
-// style and region for valid tags (based on the provided state).
-func parseTag(str string, state *stepState) (length int, style tcell.Style, region string) {
+// style, region, and hyperlink URL for valid tags (based on the provided
+// state).
+func parseTag(str string, state *stepState) (length int, style tcell.Style, region, url string) {
 	// Automata states for parsing tags.
 	const (
 		tagStateNone = iota
@@ -268,6 +271,7 @@ func parseTag(str string, state *stepState) (length int, style tcell.Style, regi
 	)
 	tStyle := state.style
 	tRegion := state.region
+	tURL := state.url
 
 	// Process state transitions.
 	for len(str) > 0 {
@@ -278,11 +282,27 @@ func parseTag(str string, state *stepState) (length int, style tcell.Style, regi
 		// Transition.
 		switch tagState {
 		case tagStateNone:
-			if ch == '[' { // Start of a tag.
+			if ch != '[' { // Not a tag. We're done.
+				return
+			}
+			if !strings.HasPrefix(str, "link=") { // Start of a tag.
 				tagState = tagStateStart
-			} else { // Not a tag. We're done.
+				break
+			}
+			// A hyperlink tag, equivalent to a URL-only style tag.
+			end := strings.IndexAny(str, "[]")
+			if end < 0 || str[end] != ']' { // Invalid tag.
 				return
 			}
+			tURL = str[5:end]
+			if tURL == "" {
+				tStyle = tStyle.Url("").UrlId("")
+			} else {
+				tStyle = tStyle.Url(tURL).UrlId(strconv.Itoa(int(rand.Uint32())))
+			}
+			str = str[end+1:]
+			tagLength += end + 1
+			tagState = tagStateDoneTag
 		case tagStateStart:
 			if ch == '"' { // Start of a region tag.
 				tempStr.Reset()
@@ -457,6 +477,7 @@ func parseTag(str string, state *stepState) (length int, style tcell.Style, regi
 				tagState = tagStateDoneTag
 			} else if ch == '-' { // Reset URL.
 				tStyle = tStyle.Url("").UrlId("")
+				tURL = ""
 				tagState = tagStateEndURL
 			} else { // URL character.
 				tempStr.Reset()
@@ -472,7 +493,8 @@ func parseTag(str string, state *stepState) (length int, style tcell.Style, regi
 			}
 		case tagStateURL:
 			if ch == ']' { // End of tag.
-				tStyle = tStyle.Url(tempStr.String())
+				tURL = tempStr.String()
+				tStyle = tStyle.Url(tURL)
 				tagState = tagStateDoneTag
 			} else { // URL character.
 				tempStr.WriteByte(ch)
@@ -505,7 +527,7 @@ func parseTag(str string, state *stepState) (length int, style tcell.Style, regi
 
 		// The last transition led to a tag end. Make the tag permanent.
 		if tagState == tagStateDoneTag {
-			length, style, region = tagLength, tStyle, tRegion
+			length, style, region, url = tagLength, tStyle, tRegion, tURL
 			tagState = tagStateNone // Reset state.
 		}
 	}
@@ -1,7 +1,13 @@
 package tview
 
 import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	colorful "github.com/lucasb-eyer/go-colorful"
@@ -46,6 +52,11 @@ type TableCell struct {
 	// If set to true, this cell cannot be selected.
 	NotSelectable bool
 
+	// The number of columns and rows this cell spans. Values less than 2
+	// mean that the cell occupies only its own column or row. See SetColSpan()
+	// and SetRowSpan() for details.
+	ColSpan, RowSpan int
+
 	// An optional handler for mouse clicks. This also fires if the cell is not
 	// selectable. If true is returned, no additional "selected" event is fired
 	// on selectable cells.
@@ -154,6 +165,31 @@ func (c *TableCell) SetSelectable(selectable bool) *TableCell {
 	return c
 }
 
+// SetColSpan sets the number of columns this cell spans, starting with its own
+// column. The cells covered by this cell are not drawn and cannot be selected,
+// the area is treated as a single cell instead. If the cell's text does not
+// fit into the spanned columns, the last of them which doesn't have a fixed
+// width (see Table.SetColumnWidth()) is widened.
+//
+// A span only extends over columns which are displayed next to each other,
+// e.g. it ends at the boundary between fixed and scrolled columns.
+func (c *TableCell) SetColSpan(columns int) *TableCell {
+	c.ColSpan = columns
+	return c
+}
+
+// SetRowSpan sets the number of rows this cell spans, starting with its own
+// row. The cells covered by this cell are not drawn and cannot be selected,
+// the area is treated as a single cell instead. The cell's text is printed
+// in its first row.
+//
+// A span only extends over rows which are displayed below each other, e.g. it
+// ends at the boundary between fixed and scrolled rows.
+func (c *TableCell) SetRowSpan(rows int) *TableCell {
+	c.RowSpan = rows
+	return c
+}
+
 // SetReference allows you to store a reference of any type in this cell. This
 // will allow you to establish a mapping between the cell and your
 // actual data.
@@ -187,6 +223,107 @@ func (c *TableCell) SetClickedFunc(clicked func() bool) *TableCell {
 	return c
 }
 
+// Truncation modes for texts which don't fit into their table cell, see
+// TableColumn.SetTruncation().
+const (
+	TableTruncateEllipsis = iota // Cut off the end, the last character is replaced with an ellipsis.
+	TableTruncateClip            // Cut off the end without an ellipsis.
+	TableTruncateStart           // Cut off the beginning, the first character is replaced with an ellipsis.
+)
+
+// TableColumn describes how all cells of a table column are displayed. Use
+// Table.SetColumn() to assign a column descriptor to a column. Its settings
+// take precedence over the settings of the individual cells.
+type TableColumn struct {
+	// The alignment of the column's cell texts. One of AlignLeft, AlignCenter,
+	// AlignRight, or -1 to use the alignment of each cell.
+	Align int
+
+	// The maximum width of the column's cells in screen space. Set to 0 to use
+	// the maximum width of each cell.
+	MaxWidth int
+
+	// How texts are cut off if they don't fit into their cell. One of the
+	// TableTruncate constants.
+	Truncation int
+
+	// An optional function which returns the text to be displayed for a cell
+	// of the column, instead of the cell's text.
+	Formatter func(row int, cell *TableCell) string
+}
+
+// NewTableColumn returns a new column descriptor which does not change the
+// display of the cells, i.e. each cell's alignment and maximum width is used
+// and texts are cut off with an ellipsis.
+func NewTableColumn() *TableColumn {
+	return &TableColumn{
+		Align:      -1,
+		Truncation: TableTruncateEllipsis,
+	}
+}
+
+// SetAlign sets the alignment of all cell texts of the column. This must be
+// either AlignLeft, AlignCenter, or AlignRight, or -1 to use the alignment of
+// each cell.
+func (c *TableColumn) SetAlign(align int) *TableColumn {
+	c.Align = align
+	return c
+}
+
+// SetMaxWidth sets the maximum width of all cells of the column in screen
+// space. Any cell text whose screen width exceeds this width is cut off
+// according to the column's truncation mode. Set to 0 to use the maximum width
+// of each cell.
+func (c *TableColumn) SetMaxWidth(maxWidth int) *TableColumn {
+	c.MaxWidth = maxWidth
+	return c
+}
+
+// SetTruncation sets how cell texts which don't fit into their cell are cut
+// off. This must be one of the TableTruncate constants.
+func (c *TableColumn) SetTruncation(truncation int) *TableColumn {
+	c.Truncation = truncation
+	return c
+}
+
+// SetFormatter sets a function which returns the text to be displayed for a
+// cell of the column, e.g. to format numbers. The cell's text itself remains
+// unchanged and is used for sorting and exporting. The function receives the
+// cell's row so that header rows can be excluded. Provide nil to display the
+// cells' texts.
+func (c *TableColumn) SetFormatter(formatter func(row int, cell *TableCell) string) *TableColumn {
+	c.Formatter = formatter
+	return c
+}
+
+// Export formats, see Table.Export().
+const (
+	TableExportCSV  = iota // Comma-separated values (RFC 4180).
+	TableExportTSV         // Tab-separated values.
+	TableExportText        // Plain text with aligned columns.
+)
+
+// Editor types for table columns, see Table.SetColumnEditor().
+const (
+	TableEditorNone = iota
+	TableEditorText
+	TableEditorDropDown
+	TableEditorCheckbox
+)
+
+// tableColumnEditor describes how the cells of a table column are edited.
+type tableColumnEditor struct {
+	editor  int      // One of the TableEditor constants.
+	options []string // Editor-specific options.
+}
+
+// Scroll bars of a table.
+const (
+	tableScrollBarNone = iota
+	tableScrollBarVertical
+	tableScrollBarHorizontal
+)
+
 // TableContent defines a Table's data. You may replace a Table's default
 // implementation with your own using the Table.SetContent() function. This will
 // allow you to turn Table into a view of your own data structure. The
@@ -279,6 +416,16 @@ func (t TableContentReadOnly) Clear() {
 	// nop.
 }
 
+// TableContentSorter may be implemented by TableContent implementations whose
+// rows can be rearranged by the table, see Table.SortByColumn(). The table's
+// default content implements it.
+type TableContentSorter interface {
+	// Rearrange the rows starting at the given row such that the row which
+	// was at position order[i] is moved to position start+i. The order
+	// contains all rows from "start" to the end of the table.
+	ReorderRows(start int, order []int)
+}
+
 // tableDefaultContent implements the default TableContent interface for the
 // Table class.
 type tableDefaultContent struct {
@@ -377,6 +524,15 @@ func (t *tableDefaultContent) GetColumnCount() int {
 	return t.lastColumn + 1
 }
 
+// ReorderRows rearranges the rows starting at the given row.
+func (t *tableDefaultContent) ReorderRows(start int, order []int) {
+	cells := make([][]*TableCell, len(order))
+	for index, row := range order {
+		cells[index] = t.cells[row]
+	}
+	copy(t.cells[start:], cells)
+}
+
 // Table visualizes two-dimensional data consisting of rows and columns. Each
 // Table cell is defined via SetCell() by the TableCell type. They can be added
 // dynamically to the table and changed any time.
@@ -389,13 +545,65 @@ func (t *tableDefaultContent) GetColumnCount() int {
 // by lines. Therefore one table row will require two rows on screen.
 //
 // Columns will use as much horizontal space as they need. You can constrain
-// their size with the MaxWidth parameter of the TableCell type.
+// their size with the MaxWidth parameter of the TableCell type or for entire
+// columns with SetColumnWidth() and SetColumnMinMax(). If enabled with
+// SetColumnsResizable(), users may also resize columns by dragging the column
+// separators (or cell borders) of the header rows with the mouse.
+// Double-clicking a separator fits the column to the width of its visible
+// cells.
+//
+// A column descriptor (see TableColumn and SetColumn()) defines the alignment,
+// maximum width, truncation, and text formatting of all cells of a column.
+//
+// Styles which depend on a cell's position or content, e.g. stripes of
+// alternating row colors, can be applied at draw time with SetRowStyleFunc()
+// and SetCellStyleFunc() instead of being set on each cell.
 //
 // # Fixed Columns
 //
 // You can define fixed rows and rolumns via SetFixed(). They will always stay
 // in their place, even when the table is scrolled. Fixed rows are always the
-// top rows. Fixed columns are always the leftmost columns.
+// top rows. Fixed columns are always the leftmost columns. Additionally, the
+// rightmost columns can be pinned to the right edge of the table via
+// SetFixedColumnsRight(), e.g. to keep an "actions" column visible.
+//
+// Only the columns which fit on screen are evaluated when the table is drawn so
+// tables with a large number of columns can be scrolled horizontally without
+// a performance penalty.
+//
+// # Filtering
+//
+// Rows below the fixed rows can be hidden without removing them from the table
+// by setting a filter function with SetFilterFunc(). The filter is evaluated
+// for all rows when it is set and when ApplyFilter() is called, e.g. after the
+// filter criteria (such as a search term) changed. Rows added to the table in
+// the meantime are displayed. All row indices (e.g. of the selection) refer to
+// the unfiltered table.
+//
+// # Spanning Cells
+//
+// Cells may span multiple columns and rows, see TableCell.SetColSpan() and
+// TableCell.SetRowSpan(). This is useful for grouped headers or summary rows.
+//
+// # Pagination
+//
+// Instead of holding all rows in memory, a table can load one page of rows at
+// a time, e.g. from a database. Set a function which loads a page with
+// SetPageLoader() and the number of rows per page with SetPageSize(). The
+// loaded rows are placed below the fixed rows. A footer below the table shows
+// which rows are displayed. The user can switch pages with the following keys:
+//
+//   - ]: Move to the next page.
+//   - [: Move to the previous page.
+//
+// # Sorting
+//
+// Rows below the fixed rows can be sorted by the values of a column with
+// SortByColumn(). If sorting is enabled with SetSortable(), the user can also
+// sort the table by clicking on a column's cell in the fixed (header) rows.
+// Clicking the same column again toggles between ascending and descending
+// order. The sort direction is indicated in the header cell of the last fixed
+// row.
 //
 // # Selections
 //
@@ -405,6 +613,21 @@ func (t *tableDefaultContent) GetColumnCount() int {
 // set, individual cells can be selected. The "selected" handler set via
 // SetSelectedFunc() is invoked when the user presses Enter on a selection.
 //
+// If individual cells can be selected, the user may also select a rectangular
+// range of cells by holding Shift while moving the selection with the arrow
+// keys or by dragging the mouse. Use GetSelectionRange() to retrieve the
+// selected range. Pressing Ctrl-Q passes the selected cells to the function
+// set with SetCopyFunc(), e.g. to copy them to the clipboard.
+//
+// # Editing
+//
+// If both rows and columns are selectable, cells can be edited in place.
+// Define how the cells of a column are edited with SetColumnEditor(). When the
+// user presses Enter on such a cell, an input field, a drop-down, or a
+// checkbox is shown in its place. The new value is passed to the function set
+// with SetValidateFunc() which may reject it. Accepted values replace the
+// cell's text. Press Escape to abort editing.
+//
 // # Navigation
 //
 // If the table extends beyond the available space, it can be navigated with
@@ -423,6 +646,9 @@ func (t *tableDefaultContent) GetColumnCount() int {
 // rows and columns). When there is a selection, the user moves the selection.
 // The class will attempt to keep the selection from moving out of the screen.
 //
+// Optional scroll bars (see SetScrollBars()) show the position of the visible
+// part of the table. They can be dragged with the mouse.
+//
 // Use SetInputCapture() to override or modify keyboard input.
 //
 // See https://github.com/rivo/tview/wiki/Table for an example.
@@ -441,6 +667,14 @@ type Table struct {
 	// The table's data structure.
 	content TableContent
 
+	// An optional function which determines which rows are displayed.
+	filter func(row int) bool
+
+	// The sorted indices of the rows which passed the filter, including the
+	// fixed rows, and the number of rows the filter was applied to.
+	filteredRows   []int
+	filterRowCount int
+
 	// If true, when calculating the widths of the columns, all rows are evaluated
 	// instead of only the visible ones.
 	evaluateAllRows bool
@@ -448,6 +682,27 @@ type Table struct {
 	// The number of fixed rows / columns.
 	fixedRows, fixedColumns int
 
+	// The number of fixed columns on the right side of the table.
+	fixedColumnsRight int
+
+	// The widths of columns which don't use the automatic width, mapped by
+	// column index.
+	columnWidths map[int]int
+
+	// The minimum and maximum widths of columns (0 for no limit), mapped by
+	// column index.
+	columnLimits map[int][2]int
+
+	// The descriptors of columns, mapped by column index.
+	columnDescriptors map[int]*TableColumn
+
+	// Whether or not users may resize columns with the mouse.
+	columnsResizable bool
+
+	// The column currently being resized with the mouse (-1 if none) and the
+	// mouse position and column width when resizing started.
+	resizeColumn, resizeX, resizeWidth int
+
 	// Whether or not rows or columns can be selected. If both are set to true,
 	// cells can be selected.
 	rowsSelectable, columnsSelectable bool
@@ -458,6 +713,21 @@ type Table struct {
 	// multiple selected rows and columns
 	selectedRows, selectedColumns []int
 
+	// The cell where a rectangular selection range starts (the other corner
+	// being the current selection) or -1 if there is no such range.
+	rangeRow, rangeColumn int
+
+	// Whether the user is currently selecting a range with the mouse.
+	rangeDragging bool
+
+	// The style of selected cells in a rectangular selection range, other than
+	// the current selection.
+	rangeStyle tcell.Style
+
+	// An optional function which receives the text of the selected cells when
+	// the user presses Ctrl-Q.
+	copyToClipboard func(text string)
+
 	// A temporary flag which causes the next call to Draw() to force the
 	// current selection to remain visible. It is set to false afterwards.
 	clampToSelection bool
@@ -477,6 +747,40 @@ type Table struct {
 	// The number of visible rows the last time the table was drawn.
 	visibleRows int
 
+	// The indices of the visible rows as of the last time the table was drawn.
+	visibleRowIndices []int
+
+	// An optional function which loads the rows of a page.
+	pageLoader func(page int) (rows [][]string, total int)
+
+	// The number of rows per page, the current page, and the total number of
+	// rows in all pages as reported by the page loader.
+	pageSize, page, pageTotal int
+
+	// The style of the page footer.
+	pageFooterStyle tcell.Style
+
+	// Whether the vertical and horizontal scroll bars are shown.
+	verticalScrollBar, horizontalScrollBar bool
+
+	// The style of the scroll bars.
+	scrollBarStyle tcell.Style
+
+	// The positions of the scroll bars as of the last time the table was
+	// drawn.
+	verticalBar, horizontalBar scrollBar
+
+	// The scroll bar currently being dragged with the mouse (one of the
+	// tableScrollBar constants).
+	scrollBarDragging int
+
+	// The row and column offsets last reported to the "scroll" handler.
+	reportedRowOffset, reportedColumnOffset int
+
+	// An optional function which gets called when the table's row or column
+	// offset changed.
+	scrollChanged func(rowOffset, columnOffset int)
+
 	// The indices of the visible columns as of the last time the table was drawn.
 	visibleColumnIndices []int
 
@@ -484,10 +788,19 @@ type Table struct {
 	// drawn.
 	visibleColumnWidths []int
 
+	// The largest number of rows and columns spanned by a visible cell as of
+	// the last time the table was drawn.
+	maxRowSpan, maxColSpan int
+
 	// The style of the selected rows. If this value is the empty struct,
 	// selected rows are simply inverted.
 	selectedStyle tcell.Style
 
+	// Optional functions which return styles applied to rows and cells when
+	// they are drawn.
+	rowStyle  func(row int) tcell.Style
+	cellStyle func(row, column int, cell *TableCell) tcell.Style
+
 	// An optional function which gets called when the user presses Enter on a
 	// selected cell. If entire rows selected, the column value is undefined.
 	// Likewise for entire columns.
@@ -501,14 +814,61 @@ type Table struct {
 	// An optional function which gets called when the user presses Escape, Tab,
 	// or Backtab. Also when the user presses Enter if nothing is selectable.
 	done func(key tcell.Key)
+
+	// Whether clicking on header cells sorts the table.
+	sortable bool
+
+	// The column by which the table's rows were last sorted or -1 if they
+	// weren't sorted.
+	sortColumn int
+
+	// Whether the table's rows were sorted in ascending order.
+	sortAscending bool
+
+	// The indicators appended to the header cell of the sort column.
+	sortAscendingIndicator, sortDescendingIndicator string
+
+	// An optional function which compares two cells of a column when sorting.
+	// If nil, the cells' texts are compared.
+	sortLess func(column int, a, b *TableCell) bool
+
+	// An optional function which gets called after the table's rows were
+	// sorted.
+	sorted func(column int, ascending bool)
+
+	// The editors of the table's columns, mapped by column index.
+	columnEditors map[int]tableColumnEditor
+
+	// The form item used to edit a cell or nil if no cell is being edited.
+	editor FormItem
+
+	// The position of the cell being edited.
+	editRow, editColumn int
+
+	// The screen position and width of the cell being edited, as drawn in the
+	// current frame. The width is 0 if the cell is not visible.
+	editX, editY, editWidth int
+
+	// An optional function which validates a new cell value after editing.
+	validate func(row, column int, text string) bool
 }
 
 // NewTable returns a new table.
 func NewTable() *Table {
 	t := &Table{
-		Box:          NewBox(),
-		bordersColor: Styles.GraphicsColor,
-		separator:    ' ',
+		Box:                     NewBox(),
+		bordersColor:            Styles.GraphicsColor,
+		separator:               ' ',
+		sortColumn:              -1,
+		rangeRow:                -1,
+		resizeColumn:            -1,
+		pageSize:                100,
+		pageFooterStyle:         tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
+		scrollBarStyle:          tcell.StyleDefault.Foreground(Styles.GraphicsColor),
+		rangeColumn:             -1,
+		rangeStyle:              tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		sortAscendingIndicator:  " ▲",
+		sortDescendingIndicator: " ▼",
 	}
 	t.SetContent(nil)
 	return t
@@ -563,6 +923,74 @@ func (t *Table) SetSelectedStyle(style tcell.Style) *Table {
 	return t
 }
 
+// SetRowStyleFunc sets a function which is called for every visible row when
+// the table is drawn. The style it returns is applied to all cells of that row
+// on top of the cells' own styles: Colors other than tcell.ColorDefault
+// replace the cells' colors (a background color makes a cell opaque) and
+// attributes are added to the cells' attributes. For example, to draw
+// alternating row colors:
+//
+//	table.SetRowStyleFunc(func(row int) tcell.Style {
+//	  if row%2 == 1 {
+//	    return tcell.StyleDefault.Background(tcell.ColorDarkSlateGray)
+//	  }
+//	  return tcell.StyleDefault
+//	})
+//
+// The style returned by the function set with SetCellStyleFunc() takes
+// precedence over the row style. Provide nil to remove the function.
+func (t *Table) SetRowStyleFunc(handler func(row int) tcell.Style) *Table {
+	t.rowStyle = handler
+	return t
+}
+
+// SetCellStyleFunc sets a function which is called for every visible cell
+// when the table is drawn. The style it returns is applied on top of the
+// cell's own style and the row style (see SetRowStyleFunc()). For example, to
+// show negative numbers in red:
+//
+//	table.SetCellStyleFunc(func(row, column int, cell *tview.TableCell) tcell.Style {
+//	  if strings.HasPrefix(cell.Text, "-") {
+//	    return tcell.StyleDefault.Foreground(tcell.ColorRed)
+//	  }
+//	  return tcell.StyleDefault
+//	})
+//
+// The cell must not be modified by the function. Provide nil to remove the
+// function.
+func (t *Table) SetCellStyleFunc(handler func(row, column int, cell *TableCell) tcell.Style) *Table {
+	t.cellStyle = handler
+	return t
+}
+
+// styledCell returns the given cell with the styles of the functions set with
+// SetRowStyleFunc() and SetCellStyleFunc() applied. If no such functions are
+// set, the cell itself is returned. Otherwise, the result is a copy.
+func (t *Table) styledCell(row, column int, cell *TableCell) *TableCell {
+	if t.rowStyle == nil && t.cellStyle == nil {
+		return cell
+	}
+	styled := *cell
+	apply := func(style tcell.Style) {
+		fg, bg, attr := style.Decompose()
+		if fg != tcell.ColorDefault {
+			styled.Color = fg
+		}
+		if bg != tcell.ColorDefault {
+			styled.BackgroundColor = bg
+			styled.Transparent = false
+		}
+		styled.Attributes |= attr
+	}
+	if t.rowStyle != nil {
+		apply(t.rowStyle(row))
+	}
+	if t.cellStyle != nil {
+		apply(t.cellStyle(row, column, cell))
+	}
+	return &styled
+}
+
 // SetSeparator sets the character used to fill the space between two
 // neighboring cells. This is a space character ' ' per default but you may
 // want to set it to Borders.Vertical (or any other rune) if the column
@@ -583,6 +1011,164 @@ func (t *Table) SetFixed(rows, columns int) *Table {
 	return t
 }
 
+// SetColumnsResizable sets whether or not users may resize columns by dragging
+// the column separators (or cell borders) of the fixed rows with the mouse, or
+// of the first row if there are no fixed rows. Double-clicking a separator fits
+// the column to the width of its visible cells. This is disabled by default.
+func (t *Table) SetColumnsResizable(resizable bool) *Table {
+	t.columnsResizable = resizable
+	return t
+}
+
+// SetColumnWidth sets the width of the given column in screen space,
+// overriding the automatic width calculation which is based on the widths of
+// the column's cells and their expansion values. A value of 0 or less returns
+// the column to its automatic width.
+func (t *Table) SetColumnWidth(column, width int) *Table {
+	if width <= 0 {
+		delete(t.columnWidths, column)
+		return t
+	}
+	if t.columnWidths == nil {
+		t.columnWidths = make(map[int]int)
+	}
+	t.columnWidths[column] = width
+	return t
+}
+
+// GetColumnWidth returns the width set for the given column with
+// SetColumnWidth() (or by the user resizing the column), or 0 if the column
+// uses its automatic width.
+func (t *Table) GetColumnWidth(column int) int {
+	return t.columnWidths[column]
+}
+
+// SetColumnMinMax sets the minimum and maximum width of the given column in
+// screen space. These limits apply to the column's automatic width, including
+// extra width added due to expansion values. A value of 0 means no limit.
+func (t *Table) SetColumnMinMax(column, minWidth, maxWidth int) *Table {
+	if t.columnLimits == nil {
+		t.columnLimits = make(map[int][2]int)
+	}
+	if minWidth <= 0 && maxWidth <= 0 {
+		delete(t.columnLimits, column)
+	} else {
+		t.columnLimits[column] = [2]int{minWidth, maxWidth}
+	}
+	return t
+}
+
+// SetColumn sets a descriptor which defines the alignment, maximum width,
+// truncation mode, and text formatting of all cells of the given column. The
+// descriptor's settings take precedence over the settings of the cells. Provide
+// nil to remove the descriptor.
+//
+//	table.SetColumn(2, tview.NewTableColumn().
+//	  SetAlign(tview.AlignRight).
+//	  SetFormatter(func(row int, cell *tview.TableCell) string {
+//	    if row == 0 {
+//	      return cell.Text // The header.
+//	    }
+//	    return "$" + cell.Text
+//	  }))
+func (t *Table) SetColumn(column int, descriptor *TableColumn) *Table {
+	if descriptor == nil {
+		delete(t.columnDescriptors, column)
+		return t
+	}
+	if t.columnDescriptors == nil {
+		t.columnDescriptors = make(map[int]*TableColumn)
+	}
+	t.columnDescriptors[column] = descriptor
+	return t
+}
+
+// GetColumn returns the descriptor of the given column or nil if the column
+// has no descriptor.
+func (t *Table) GetColumn(column int) *TableColumn {
+	return t.columnDescriptors[column]
+}
+
+// cellAlign returns the alignment of the given cell, taking its column's
+// descriptor into account.
+func (t *Table) cellAlign(column int, cell *TableCell) int {
+	if descriptor := t.columnDescriptors[column]; descriptor != nil && descriptor.Align >= 0 {
+		return descriptor.Align
+	}
+	return cell.Align
+}
+
+// cellMaxWidth returns the maximum width of the given cell (0 for no limit),
+// taking its column's descriptor into account.
+func (t *Table) cellMaxWidth(column int, cell *TableCell) int {
+	if descriptor := t.columnDescriptors[column]; descriptor != nil && descriptor.MaxWidth > 0 {
+		return descriptor.MaxWidth
+	}
+	return cell.MaxWidth
+}
+
+// fitColumn sets the width of the given column to the largest width of its
+// visible cells.
+func (t *Table) fitColumn(column int) {
+	var width int
+	for _, row := range t.visibleRowIndices {
+		if cell := t.content.GetCell(row, column); cell != nil {
+			if cellWidth := TaggedStringWidth(t.cellText(row, column, cell)); cellWidth > width {
+				width = cellWidth
+			}
+		}
+	}
+	if width < 1 {
+		width = 1
+	}
+	t.SetColumnWidth(column, width)
+}
+
+// separatorAt returns the index of the visible column whose right separator
+// is located at the given screen coordinates or -1 if there is no separator.
+// Only separators in the header rows (the fixed rows or the first row if there
+// are none) are considered, and only if columns are resizable.
+func (t *Table) separatorAt(x, y int) int {
+	rectX, rectY, _, _ := t.GetInnerRect()
+	if !t.columnsResizable || !t.InRect(x, y) {
+		return -1
+	}
+	headerRows := t.fixedRows
+	if headerRows < 1 {
+		headerRows = 1
+	}
+	headerHeight := headerRows
+	if t.borders {
+		headerHeight = 2*headerRows + 1
+	}
+	if y < rectY || y >= rectY+headerHeight {
+		return -1
+	}
+	columnX := rectX
+	if t.borders {
+		columnX++
+	}
+	for index, width := range t.visibleColumnWidths {
+		columnX += width + 1
+		if x == columnX-1 {
+			return index
+		}
+		if x < columnX {
+			break
+		}
+	}
+	return -1
+}
+
+// SetFixedColumnsRight sets the number of right-most columns which are always
+// visible at the right edge of the table, even when the rest of the columns
+// are scrolled horizontally. Columns fixed via SetFixed() take precedence if
+// there is not enough space for both.
+func (t *Table) SetFixedColumnsRight(columns int) *Table {
+	t.fixedColumnsRight = columns
+	return t
+}
+
 // SetSelectable sets the flags which determine what can be selected in a table.
 // There are three selection modi:
 //
@@ -615,6 +1201,7 @@ func (t *Table) GetSelection() (row, column int) {
 // if cells are not selectable).
 func (t *Table) Select(row, column int) *Table {
 	t.selectedRow, t.selectedColumn = row, column
+	t.rangeRow, t.rangeColumn = -1, -1
 	t.clampToSelection = true
 	if t.selectionChanged != nil {
 		t.selectionChanged(row, column)
@@ -622,6 +1209,179 @@ func (t *Table) Select(row, column int) *Table {
 	return t
 }
 
+// GetSelectionRange returns the top-left and bottom-right corners of the
+// rectangular range of selected cells. If no range was selected, both corners
+// are the currently selected cell. See the "Selections" section in the Table
+// documentation for details.
+func (t *Table) GetSelectionRange() (fromRow, fromColumn, toRow, toColumn int) {
+	fromRow, fromColumn, toRow, toColumn = t.selectedRow, t.selectedColumn, t.selectedRow, t.selectedColumn
+	if t.rangeRow < 0 || t.rangeColumn < 0 {
+		return
+	}
+	if t.rangeRow < fromRow {
+		fromRow = t.rangeRow
+	} else {
+		toRow = t.rangeRow
+	}
+	if t.rangeColumn < fromColumn {
+		fromColumn = t.rangeColumn
+	} else {
+		toColumn = t.rangeColumn
+	}
+	return
+}
+
+// SetSelectionRangeStyle sets the style of the cells in a rectangular
+// selection range, except the currently selected cell which uses the style set
+// with SetSelectedStyle().
+func (t *Table) SetSelectionRangeStyle(style tcell.Style) *Table {
+	t.rangeStyle = style
+	return t
+}
+
+// SetCopyFunc sets a handler which is called when the user presses Ctrl-Q.
+// It receives the selected cells as tab-separated values (see CopySelection()).
+// Use it to copy the selected cells to the clipboard.
+func (t *Table) SetCopyFunc(handler func(text string)) *Table {
+	t.copyToClipboard = handler
+	return t
+}
+
+// Export writes the texts of all cells of the rows which are displayed (i.e.
+// which pass the filter, see SetFilterFunc()) to the given writer in the given
+// format, one of:
+//
+//   - TableExportCSV: Comma-separated values.
+//   - TableExportTSV: Tab-separated values. Tabs and line breaks in cell texts
+//     are replaced with spaces.
+//   - TableExportText: Plain text with columns aligned according to their
+//     cells' alignment.
+//
+// Style tags are removed from all texts.
+func (t *Table) Export(writer io.Writer, format int) error {
+	rowCount := t.content.GetRowCount()
+	rows := t.displayedRows(rowCount)
+	if rows == nil {
+		rows = make([]int, rowCount)
+		for row := range rows {
+			rows[row] = row
+		}
+	}
+	columns := make([]int, t.content.GetColumnCount())
+	for column := range columns {
+		columns[column] = column
+	}
+	return t.export(writer, format, rows, columns)
+}
+
+// CopySelection returns the texts of the selected cells in the given format
+// (see Export() for a list of formats). If individual cells can be selected,
+// this is the selected range of cells (see GetSelectionRange()). If only rows
+// or only columns can be selected, the entire selected row or column is
+// returned. Hidden rows (see SetFilterFunc()) are skipped.
+//
+// If a "copy" handler was set with SetCopyFunc(), it is called with the
+// returned text.
+func (t *Table) CopySelection(format int) string {
+	var rows, columns []int
+	if t.rowsSelectable || t.columnsSelectable {
+		fromRow, fromColumn, toRow, toColumn := t.GetSelectionRange()
+		if !t.rowsSelectable {
+			fromRow, toRow = 0, t.content.GetRowCount()-1
+		}
+		if !t.columnsSelectable {
+			fromColumn, toColumn = 0, t.content.GetColumnCount()-1
+		}
+		for row := fromRow; row <= toRow; row++ {
+			if !t.rowHidden(row) {
+				rows = append(rows, row)
+			}
+		}
+		for column := fromColumn; column <= toColumn; column++ {
+			columns = append(columns, column)
+		}
+	}
+	var text strings.Builder
+	t.export(&text, format, rows, columns)
+	result := strings.TrimSuffix(text.String(), "\n")
+	if t.copyToClipboard != nil {
+		t.copyToClipboard(result)
+	}
+	return result
+}
+
+// export writes the texts of the cells in the given rows and columns to the
+// writer in the given format.
+func (t *Table) export(writer io.Writer, format int, rows, columns []int) error {
+	// Collect the texts.
+	texts := make([][]string, len(rows))
+	for index, row := range rows {
+		texts[index] = make([]string, len(columns))
+		for c, column := range columns {
+			if cell := t.content.GetCell(row, column); cell != nil {
+				texts[index][c] = stripTags(cell.Text)
+			}
+		}
+	}
+
+	switch format {
+	case TableExportCSV:
+		csvWriter := csv.NewWriter(writer)
+		csvWriter.WriteAll(texts)
+		return csvWriter.Error()
+	case TableExportTSV:
+		buffered := bufio.NewWriter(writer)
+		replacer := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+		for _, row := range texts {
+			for c, text := range row {
+				if c > 0 {
+					buffered.WriteByte('\t')
+				}
+				buffered.WriteString(replacer.Replace(text))
+			}
+			buffered.WriteByte('\n')
+		}
+		return buffered.Flush()
+	default:
+		// Determine the column widths.
+		widths := make([]int, len(columns))
+		for _, row := range texts {
+			for c, text := range row {
+				if width := TaggedStringWidth(Escape(text)); width > widths[c] {
+					widths[c] = width
+				}
+			}
+		}
+
+		// Write aligned texts.
+		buffered := bufio.NewWriter(writer)
+		for index, row := range texts {
+			var line strings.Builder
+			for c, text := range row {
+				if c > 0 {
+					line.WriteByte(' ')
+				}
+				padding := widths[c] - TaggedStringWidth(Escape(text))
+				align := AlignLeft
+				if cell := t.content.GetCell(rows[index], columns[c]); cell != nil {
+					align = t.cellAlign(columns[c], cell)
+				}
+				switch align {
+				case AlignRight:
+					line.WriteString(strings.Repeat(" ", padding) + text)
+				case AlignCenter:
+					line.WriteString(strings.Repeat(" ", padding/2) + text + strings.Repeat(" ", padding-padding/2))
+				default:
+					line.WriteString(text + strings.Repeat(" ", padding))
+				}
+			}
+			buffered.WriteString(strings.TrimRight(line.String(), " "))
+			buffered.WriteByte('\n')
+		}
+		return buffered.Flush()
+	}
+}
+
 // MultipleSelect sets the selected cells to the given positions. Depending on
 // the selection settings specified via SetSelectable(), this may be an entire
 // row or column, or even ignored completely
@@ -649,51 +1409,514 @@ func (t *Table) SetOffset(row, column int) *Table {
 	return t
 }
 
-// GetOffset returns the current row and column offset. This indicates how many
-// rows and columns the table is scrolled down and to the right.
-func (t *Table) GetOffset() (row, column int) {
-	return t.rowOffset, t.columnOffset
+// GetOffset returns the current row and column offset. This indicates how many
+// rows and columns the table is scrolled down and to the right.
+func (t *Table) GetOffset() (row, column int) {
+	return t.rowOffset, t.columnOffset
+}
+
+// SetEvaluateAllRows sets a flag which determines the rows to be evaluated when
+// calculating the widths of the table's columns. When false, only visible rows
+// are evaluated. When true, all rows in the table are evaluated.
+//
+// Set this flag to true to avoid shifting column widths when the table is
+// scrolled. (May come with a performance penalty for large tables.)
+//
+// Use with caution on very large tables, especially those not backed by the
+// default TableContent data structure.
+func (t *Table) SetEvaluateAllRows(all bool) *Table {
+	t.evaluateAllRows = all
+	return t
+}
+
+// SetSelectedFunc sets a handler which is called whenever the user presses the
+// Enter key on a selected cell/row/column. The handler receives the position of
+// the selection and its cell contents. If entire rows are selected, the column
+// index is undefined. Likewise for entire columns.
+func (t *Table) SetSelectedFunc(handler func(row, column int)) *Table {
+	t.selected = handler
+	return t
+}
+
+// SetSelectionChangedFunc sets a handler which is called whenever the current
+// selection changes. The handler receives the position of the new selection.
+// If entire rows are selected, the column index is undefined. Likewise for
+// entire columns.
+func (t *Table) SetSelectionChangedFunc(handler func(row, column int)) *Table {
+	t.selectionChanged = handler
+	return t
+}
+
+// SetDoneFunc sets a handler which is called whenever the user presses the
+// Escape, Tab, or Backtab key. If nothing is selected, it is also called when
+// user presses the Enter key (because pressing Enter on a selection triggers
+// the "selected" handler set via SetSelectedFunc()).
+func (t *Table) SetDoneFunc(handler func(key tcell.Key)) *Table {
+	t.done = handler
+	return t
+}
+
+// SetFilterFunc sets a function which determines whether the row with the
+// given index is displayed. The function is called for all rows below the
+// fixed rows immediately and whenever ApplyFilter() is called. Hidden rows
+// are not removed from the table. Set to nil to display all rows.
+//
+// If the selected row is hidden, the selection moves to the next displayed
+// row.
+func (t *Table) SetFilterFunc(filter func(row int) bool) *Table {
+	t.filter = filter
+	return t.ApplyFilter()
+}
+
+// ApplyFilter evaluates the filter function set with SetFilterFunc() for all
+// rows again. Call this function when the filter criteria or the table's
+// content changed.
+func (t *Table) ApplyFilter() *Table {
+	t.filteredRows = nil
+	t.filterRowCount = 0
+	if t.filter == nil {
+		return t
+	}
+	rowCount := t.content.GetRowCount()
+	t.filteredRows = make([]int, 0, rowCount)
+	for row := 0; row < rowCount; row++ {
+		if row < t.fixedRows || t.filter(row) {
+			t.filteredRows = append(t.filteredRows, row)
+		}
+	}
+	t.filterRowCount = rowCount
+
+	// Keep the selection on a displayed row.
+	if t.rowsSelectable && t.rowHidden(t.selectedRow) {
+		index := sort.SearchInts(t.filteredRows, t.selectedRow)
+		if index >= len(t.filteredRows) {
+			index = len(t.filteredRows) - 1
+		}
+		if index >= t.fixedRows && index >= 0 {
+			t.selectedRow = t.filteredRows[index]
+			t.clampToSelection = true
+			if t.selectionChanged != nil {
+				t.selectionChanged(t.selectedRow, t.selectedColumn)
+			}
+		}
+	}
+	return t
+}
+
+// GetVisibleRowCount returns the number of rows which are displayed, i.e. the
+// number of rows which pass the filter (see SetFilterFunc()), including the
+// fixed rows.
+func (t *Table) GetVisibleRowCount() int {
+	rows := t.displayedRows(t.content.GetRowCount())
+	if rows == nil {
+		return t.content.GetRowCount()
+	}
+	return len(rows)
+}
+
+// rowHidden returns whether the row with the given index is hidden by the
+// filter.
+func (t *Table) rowHidden(row int) bool {
+	if t.filter == nil || row < t.fixedRows || row >= t.filterRowCount {
+		return false
+	}
+	index := sort.SearchInts(t.filteredRows, row)
+	return index >= len(t.filteredRows) || t.filteredRows[index] != row
+}
+
+// displayedRows returns the sorted indices of the rows which are displayed,
+// given the current number of rows, or nil if all rows are displayed.
+func (t *Table) displayedRows(rowCount int) []int {
+	if t.filter == nil {
+		return nil
+	}
+	rows := t.filteredRows[:sort.SearchInts(t.filteredRows, rowCount)]
+	if rowCount > t.filterRowCount {
+		// Rows added after filtering are displayed.
+		rows = append([]int(nil), rows...)
+		for row := t.filterRowCount; row < rowCount; row++ {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// shiftRow returns the index of the row which is displayed the given number of
+// rows below (or above, if negative) the given row.
+func (t *Table) shiftRow(row, delta, rowCount int) int {
+	rows := t.displayedRows(rowCount)
+	if rows == nil {
+		return row + delta
+	}
+	index := sort.SearchInts(rows, row) + delta
+	if index >= len(rows) {
+		index = len(rows) - 1
+	}
+	if index < 0 {
+		return 0
+	}
+	return rows[index]
+}
+
+// SetPageSize sets the number of rows per page when the table's rows are
+// loaded page by page (see SetPageLoader()). The default is 100. If a page
+// loader was set, the first page is loaded again.
+func (t *Table) SetPageSize(rows int) *Table {
+	if rows < 1 {
+		rows = 1
+	}
+	t.pageSize = rows
+	if t.pageLoader != nil {
+		t.SetPage(0)
+	}
+	return t
+}
+
+// SetPageLoader sets a function which loads the rows of the page with the
+// given index (starting at 0) and returns them, each row a slice of cell
+// texts, along with the total number of rows across all pages. The function
+// should return at most the number of rows set with SetPageSize(). The loaded
+// rows replace all rows below the fixed rows. See the "Pagination" section of
+// the Table documentation for details.
+//
+// The first page is loaded immediately. Set to nil to stop loading pages.
+// Note that the rows of the current page remain in the table.
+func (t *Table) SetPageLoader(loader func(page int) (rows [][]string, total int)) *Table {
+	t.pageLoader = loader
+	if loader != nil {
+		t.SetPage(0)
+	}
+	return t
+}
+
+// SetPage loads the page with the given index (starting at 0) using the page
+// loader set with SetPageLoader(). Out of range values are clamped to the
+// total number of rows returned by the loader, which may therefore be called
+// with an index beyond the last page. The selection moves to the first row of
+// the page.
+func (t *Table) SetPage(page int) *Table {
+	if t.pageLoader == nil {
+		return t
+	}
+	if page < 0 {
+		page = 0
+	}
+	rows, total := t.pageLoader(page)
+	t.pageTotal = total
+
+	// The total may have changed. Load the last page if the page is out of
+	// range now.
+	if pageCount := t.getPageCount(); page >= pageCount {
+		page = pageCount - 1
+		rows, total = t.pageLoader(page)
+		t.pageTotal = total
+	}
+	t.page = page
+
+	// Replace the rows.
+	for row := t.content.GetRowCount() - 1; row >= t.fixedRows; row-- {
+		t.content.RemoveRow(row)
+	}
+	for index, texts := range rows {
+		for column, text := range texts {
+			t.content.SetCell(t.fixedRows+index, column, NewTableCell(text))
+		}
+	}
+	if t.filter != nil {
+		t.ApplyFilter()
+	}
+	t.rowOffset, t.trackEnd = 0, false
+	if t.rowsSelectable {
+		t.Select(t.fixedRows, t.selectedColumn)
+	}
+	return t
+}
+
+// GetPage returns the index of the current page (starting at 0) and the total
+// number of pages when the table's rows are loaded page by page (see
+// SetPageLoader()).
+func (t *Table) GetPage() (page, pageCount int) {
+	return t.page, t.getPageCount()
+}
+
+// getPageCount returns the number of pages, based on the total number of rows
+// last reported by the page loader. There is always at least one page.
+func (t *Table) getPageCount() int {
+	if t.pageTotal <= 0 || t.pageSize <= 0 {
+		return 1
+	}
+	return (t.pageTotal + t.pageSize - 1) / t.pageSize
+}
+
+// SetPageFooterStyle sets the style of the footer which is shown below the
+// table when its rows are loaded page by page (see SetPageLoader()).
+func (t *Table) SetPageFooterStyle(style tcell.Style) *Table {
+	t.pageFooterStyle = style
+	return t
+}
+
+// SetScrollBars sets whether a vertical scroll bar is shown at the right edge
+// of the table and a horizontal scroll bar at its bottom edge. Each scroll bar
+// takes up one column or row. Scroll bars may be dragged with the mouse.
+func (t *Table) SetScrollBars(vertical, horizontal bool) *Table {
+	t.verticalScrollBar, t.horizontalScrollBar = vertical, horizontal
+	return t
+}
+
+// SetScrollBarStyle sets the style of the scroll bars.
+func (t *Table) SetScrollBarStyle(style tcell.Style) *Table {
+	t.scrollBarStyle = style
+	return t
+}
+
+// SetScrollChangedFunc sets a handler which is called when the table's row or
+// column offset (see GetOffset()) changed. This happens when the table is
+// drawn.
+func (t *Table) SetScrollChangedFunc(handler func(rowOffset, columnOffset int)) *Table {
+	t.scrollChanged = handler
+	return t
+}
+
+// SetSortable sets whether the user may sort the table's rows by clicking on a
+// cell of one of the fixed (header) rows. Clicking the same column again
+// toggles the sort direction. See SortByColumn() for details on sorting.
+func (t *Table) SetSortable(sortable bool) *Table {
+	t.sortable = sortable
+	return t
+}
+
+// SetSortFunc sets a function which reports whether cell "a" should be sorted
+// before cell "b" when the rows are sorted in ascending order by the given
+// column. Either cell may be nil if it wasn't set. If no such function is set
+// (the default), the cells' texts (without style tags) are compared, numerically
+// if both are numbers.
+func (t *Table) SetSortFunc(less func(column int, a, b *TableCell) bool) *Table {
+	t.sortLess = less
+	return t
+}
+
+// SetSortIndicators sets the strings which are appended to the text of the
+// header cell of the column by which the table is sorted, in the last fixed
+// row. The defaults are " ▲" and " ▼".
+func (t *Table) SetSortIndicators(ascending, descending string) *Table {
+	t.sortAscendingIndicator, t.sortDescendingIndicator = ascending, descending
+	return t
+}
+
+// SetSortedFunc sets a handler which is called after the table's rows were
+// sorted, either by calling SortByColumn() or because the user clicked on a
+// header cell.
+//
+// For table content which doesn't implement TableContentSorter, the rows
+// cannot be rearranged by the table. You may use this handler to sort your own
+// data instead.
+func (t *Table) SetSortedFunc(handler func(column int, ascending bool)) *Table {
+	t.sorted = handler
+	return t
+}
+
+// SortByColumn sorts all rows below the fixed rows by the cells of the given
+// column, in ascending or descending order. Sorting is stable, i.e. rows with
+// equal cells keep their order. The selection moves with the selected row.
+//
+// Rows of table content set with SetContent() are only rearranged if it
+// implements TableContentSorter. Otherwise, the rows and the selection are
+// left alone and you may sort your own data in the handler set with
+// SetSortedFunc().
+func (t *Table) SortByColumn(column int, ascending bool) *Table {
+	t.sortColumn, t.sortAscending = column, ascending
+
+	// Determine the new order of the rows.
+	rowCount := t.content.GetRowCount()
+	sorter, ok := t.content.(TableContentSorter)
+	if !ok || t.fixedRows >= rowCount {
+		if t.sorted != nil {
+			t.sorted(column, ascending)
+		}
+		return t
+	}
+	less := t.sortLess
+	if less == nil {
+		less = compareTableCells
+	}
+	order := make([]int, rowCount-t.fixedRows)
+	for index := range order {
+		order[index] = t.fixedRows + index
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := t.content.GetCell(order[i], column), t.content.GetCell(order[j], column)
+		if ascending {
+			return less(column, a, b)
+		}
+		return less(column, b, a)
+	})
+
+	// Rearrange the rows.
+	sorter.ReorderRows(t.fixedRows, order)
+
+	// Follow the selected row.
+	if t.filter != nil {
+		defer t.ApplyFilter()
+	}
+	for index, row := range order {
+		if row == t.selectedRow {
+			t.selectedRow = t.fixedRows + index
+			t.clampToSelection = true
+			break
+		}
+	}
+
+	if t.sorted != nil {
+		t.sorted(column, ascending)
+	}
+	return t
+}
+
+// GetSortColumn returns the column by which the table was last sorted (or -1
+// if it wasn't sorted) and whether it was sorted in ascending order.
+func (t *Table) GetSortColumn() (column int, ascending bool) {
+	return t.sortColumn, t.sortAscending
 }
 
-// SetEvaluateAllRows sets a flag which determines the rows to be evaluated when
-// calculating the widths of the table's columns. When false, only visible rows
-// are evaluated. When true, all rows in the table are evaluated.
-//
-// Set this flag to true to avoid shifting column widths when the table is
-// scrolled. (May come with a performance penalty for large tables.)
+// compareTableCells is the default function used to sort table cells. It
+// compares the cells' texts without style tags, numerically if both are
+// numbers. Nil cells are sorted first.
+func compareTableCells(column int, a, b *TableCell) bool {
+	var textA, textB string
+	if a != nil {
+		textA = strings.TrimSpace(stripTags(a.Text))
+	}
+	if b != nil {
+		textB = strings.TrimSpace(stripTags(b.Text))
+	}
+	numberA, errA := strconv.ParseFloat(textA, 64)
+	numberB, errB := strconv.ParseFloat(textB, 64)
+	if errA == nil && errB == nil {
+		return numberA < numberB
+	}
+	return textA < textB
+}
+
+// cellText returns the text to be displayed for the given cell, formatted by
+// its column's descriptor and including the sort indicator in header cells.
+func (t *Table) cellText(row, column int, cell *TableCell) string {
+	text := cell.Text
+	if descriptor := t.columnDescriptors[column]; descriptor != nil && descriptor.Formatter != nil {
+		text = descriptor.Formatter(row, cell)
+	}
+	if row == t.fixedRows-1 && column == t.sortColumn {
+		if t.sortAscending {
+			return text + t.sortAscendingIndicator
+		}
+		return text + t.sortDescendingIndicator
+	}
+	return text
+}
+
+// SetColumnEditor sets how the cells of the given column are edited (see the
+// "Editing" section in the Table documentation). The editor is one of:
 //
-// Use with caution on very large tables, especially those not backed by the
-// default TableContent data structure.
-func (t *Table) SetEvaluateAllRows(all bool) *Table {
-	t.evaluateAllRows = all
+//   - TableEditorNone: Cells are not editable (the default).
+//   - TableEditorText: Cells are edited in an input field. Press Enter to
+//     confirm.
+//   - TableEditorDropDown: Cells are edited with a drop-down whose options are
+//     given as additional arguments. Selecting an option confirms it.
+//   - TableEditorCheckbox: Cells are edited with a checkbox. The optional
+//     additional arguments are the texts of checked and unchecked cells
+//     (default "X" and ""). Each change is confirmed immediately.
+func (t *Table) SetColumnEditor(column, editor int, options ...string) *Table {
+	if t.columnEditors == nil {
+		t.columnEditors = make(map[int]tableColumnEditor)
+	}
+	if editor == TableEditorNone {
+		delete(t.columnEditors, column)
+	} else {
+		t.columnEditors[column] = tableColumnEditor{
+			editor:  editor,
+			options: options,
+		}
+	}
 	return t
 }
 
-// SetSelectedFunc sets a handler which is called whenever the user presses the
-// Enter key on a selected cell/row/column. The handler receives the position of
-// the selection and its cell contents. If entire rows are selected, the column
-// index is undefined. Likewise for entire columns.
-func (t *Table) SetSelectedFunc(handler func(row, column int)) *Table {
-	t.selected = handler
+// SetValidateFunc sets a function which is called when the user confirms a
+// new value for a cell being edited. If it returns true, the value is accepted
+// and becomes the cell's text. Otherwise, it is discarded and the user may
+// continue editing.
+func (t *Table) SetValidateFunc(handler func(row, column int, text string) bool) *Table {
+	t.validate = handler
 	return t
 }
 
-// SetSelectionChangedFunc sets a handler which is called whenever the current
-// selection changes. The handler receives the position of the new selection.
-// If entire rows are selected, the column index is undefined. Likewise for
-// entire columns.
-func (t *Table) SetSelectionChangedFunc(handler func(row, column int)) *Table {
-	t.selectionChanged = handler
-	return t
+// checkboxTexts returns the texts of checked and unchecked cells for the given
+// column editor.
+func (e tableColumnEditor) checkboxTexts() (checked, unchecked string) {
+	checked = "X"
+	if len(e.options) > 0 {
+		checked = e.options[0]
+	}
+	if len(e.options) > 1 {
+		unchecked = e.options[1]
+	}
+	return
 }
 
-// SetDoneFunc sets a handler which is called whenever the user presses the
-// Escape, Tab, or Backtab key. If nothing is selected, it is also called when
-// user presses the Enter key (because pressing Enter on a selection triggers
-// the "selected" handler set via SetSelectedFunc()).
-func (t *Table) SetDoneFunc(handler func(key tcell.Key)) *Table {
-	t.done = handler
-	return t
+// startEditing creates an editor for the cell at the given position. It
+// returns false if the cell cannot be edited.
+func (t *Table) startEditing(row, column int) bool {
+	columnEditor, ok := t.columnEditors[column]
+	cell := t.content.GetCell(row, column)
+	if !ok || cell == nil || cell.NotSelectable {
+		return false
+	}
+	switch columnEditor.editor {
+	case TableEditorText:
+		t.editor = NewInputField().SetText(cell.Text)
+	case TableEditorDropDown:
+		dropDown := NewDropDown().SetOptions(columnEditor.options, nil)
+		for index, option := range columnEditor.options {
+			if option == cell.Text {
+				dropDown.SetCurrentOption(index)
+				break
+			}
+		}
+		t.editor = dropDown
+	case TableEditorCheckbox:
+		checked, _ := columnEditor.checkboxTexts()
+		t.editor = NewCheckbox().SetChecked(cell.Text == checked)
+	default:
+		return false
+	}
+	t.editRow, t.editColumn = row, column
+	return true
+}
+
+// commitEditing validates the current value of the editor and writes it to the
+// cell being edited. It returns false if the value was rejected.
+func (t *Table) commitEditing() bool {
+	var text string
+	switch editor := t.editor.(type) {
+	case *InputField:
+		text = editor.GetText()
+	case *DropDown:
+		_, text = editor.GetCurrentOption()
+	case *Checkbox:
+		checked, unchecked := t.columnEditors[t.editColumn].checkboxTexts()
+		text = unchecked
+		if editor.IsChecked() {
+			text = checked
+		}
+	}
+	if t.validate != nil && !t.validate(t.editRow, t.editColumn, text) {
+		return false
+	}
+	cell := t.content.GetCell(t.editRow, t.editColumn)
+	if cell == nil {
+		cell = NewTableCell(text)
+	}
+	cell.Text = text
+	t.content.SetCell(t.editRow, t.editColumn, cell)
+	return true
 }
 
 // SetCell sets the content of a cell the specified position. It is ok to
@@ -780,6 +2003,66 @@ func (t *Table) GetColumnCount() int {
 	return t.content.GetColumnCount()
 }
 
+// spanAnchor returns the position of the cell spanning the cell at the given
+// position. If the cell at the given position is not covered by another cell,
+// "ok" is false.
+func (t *Table) spanAnchor(row, column int) (anchorRow, anchorColumn int, ok bool) {
+	if t.maxRowSpan <= 1 && t.maxColSpan <= 1 {
+		return // No cell spans other cells.
+	}
+	for anchorRow = row; anchorRow >= 0 && anchorRow > row-t.maxRowSpan; anchorRow-- {
+		for anchorColumn = column; anchorColumn >= 0 && anchorColumn > column-t.maxColSpan; anchorColumn-- {
+			if anchorRow == row && anchorColumn == column {
+				continue
+			}
+			cell := t.content.GetCell(anchorRow, anchorColumn)
+			if cell == nil {
+				continue
+			}
+			rowSpan, colSpan := cell.RowSpan, cell.ColSpan
+			if rowSpan < 1 {
+				rowSpan = 1
+			}
+			if colSpan < 1 {
+				colSpan = 1
+			}
+			if anchorRow+rowSpan > row && anchorColumn+colSpan > column {
+				return anchorRow, anchorColumn, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// borderJunction returns the border rune which connects border lines in the
+// given directions.
+func borderJunction(up, down, left, right bool) rune {
+	switch {
+	case up && down && left && right:
+		return Borders.Cross
+	case down && left && right:
+		return Borders.TopT
+	case up && left && right:
+		return Borders.BottomT
+	case up && down && right:
+		return Borders.LeftT
+	case up && down && left:
+		return Borders.RightT
+	case down && right:
+		return Borders.TopLeft
+	case down && left:
+		return Borders.TopRight
+	case up && right:
+		return Borders.BottomLeft
+	case up && left:
+		return Borders.BottomRight
+	case left || right:
+		return Borders.Horizontal
+	default:
+		return Borders.Vertical
+	}
+}
+
 // cellAt returns the row and column located at the given screen coordinates.
 // Each returned value may be negative if there is no row and/or cell. This
 // function will also process coordinates outside the table's inner rectangle so
@@ -794,12 +2077,11 @@ func (t *Table) cellAt(x, y int) (row, column int) {
 		row = y - rectY
 	}
 
-	// Respect fixed rows and row offset.
+	// Respect fixed rows, row offset, and filtered rows.
 	if row >= 0 {
-		if row >= t.fixedRows {
-			row += t.rowOffset
-		}
-		if row >= t.content.GetRowCount() {
+		if row < len(t.visibleRowIndices) {
+			row = t.visibleRowIndices[row]
+		} else {
 			row = -1
 		}
 	}
@@ -820,6 +2102,13 @@ func (t *Table) cellAt(x, y int) (row, column int) {
 		}
 	}
 
+	// Cells covered by other cells belong to those cells.
+	if row >= 0 && column >= 0 {
+		if anchorRow, anchorColumn, ok := t.spanAnchor(row, column); ok {
+			row, column = anchorRow, anchorColumn
+		}
+	}
+
 	return
 }
 
@@ -860,13 +2149,91 @@ func (t *Table) SetWrapSelection(vertical, horizontal bool) *Table {
 	return t
 }
 
+// Focus is called when this primitive receives focus.
+func (t *Table) Focus(delegate func(p Primitive)) {
+	if t.editor == nil {
+		t.Box.Focus(delegate)
+		return
+	}
+
+	// Hand the focus to the cell editor.
+	finish := func() {
+		t.editor = nil
+		delegate(t)
+	}
+	t.editor.SetFinishedFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			finish()
+		case tcell.KeyEnter, tcell.KeyTab, tcell.KeyBacktab:
+			if _, ok := t.editor.(*Checkbox); ok || t.commitEditing() {
+				finish()
+			}
+		}
+	})
+	switch editor := t.editor.(type) {
+	case *DropDown:
+		editor.SetSelectedFunc(func(text string, index int) {
+			if t.commitEditing() {
+				finish()
+			}
+		})
+	case *Checkbox:
+		editor.SetChangedFunc(func(checked bool) {
+			if !t.commitEditing() {
+				editor.SetChecked(!checked)
+			}
+		})
+	}
+	delegate(t.editor)
+}
+
+// HasFocus returns whether or not this primitive or its cell editor has focus.
+func (t *Table) HasFocus() bool {
+	if t.editor != nil && t.editor.HasFocus() {
+		return true
+	}
+	return t.Box.HasFocus()
+}
+
 // Draw draws this primitive onto the screen.
 func (t *Table) Draw(screen tcell.Screen) {
 	t.Box.DrawForSubclass(screen, t)
 
+	// Draw the cell editor last, on top of the cell being edited.
+	if t.editor != nil {
+		t.editWidth = 0
+		defer func() {
+			if t.editor != nil && t.editWidth > 0 {
+				t.editor.SetRect(t.editX, t.editY, t.editWidth, 1)
+				t.editor.Draw(screen)
+			}
+		}()
+	}
+
 	// What's our available screen space?
 	_, totalHeight := screen.Size()
 	x, y, width, height := t.GetInnerRect()
+
+	// Draw the page footer.
+	if t.pageLoader != nil && height > 0 {
+		height--
+		footer := "no rows"
+		if t.pageTotal > 0 {
+			first := t.page*t.pageSize + 1
+			last := first + t.content.GetRowCount() - t.fixedRows - 1
+			footer = fmt.Sprintf("rows %d–%d of %d", first, last, t.pageTotal)
+		}
+		printWithStyle(screen, footer, x, y+height, 0, width, AlignRight, t.pageFooterStyle, true)
+	}
+
+	// Make room for the scroll bars.
+	if t.verticalScrollBar && width > 0 {
+		width--
+	}
+	if t.horizontalScrollBar && height > 0 {
+		height--
+	}
 	netWidth := width
 	if t.borders {
 		t.visibleRows = height / 2
@@ -884,10 +2251,15 @@ func (t *Table) Draw(screen tcell.Screen) {
 		if t.selectedRow < 0 {
 			t.selectedRow = 0
 		}
+		if anchorRow, anchorColumn, ok := t.spanAnchor(t.selectedRow, t.selectedColumn); ok {
+			t.selectedRow, t.selectedColumn = anchorRow, anchorColumn
+		}
 		for t.selectedRow < rowCount {
 			cell := t.content.GetCell(t.selectedRow, t.selectedColumn)
-			if cell != nil && !cell.NotSelectable {
-				break
+			if cell != nil && !cell.NotSelectable && !t.rowHidden(t.selectedRow) {
+				if _, _, covered := t.spanAnchor(t.selectedRow, t.selectedColumn); !covered {
+					break
+				}
 			}
 			t.selectedColumn++
 			if t.selectedColumn > columnCount-1 {
@@ -897,23 +2269,37 @@ func (t *Table) Draw(screen tcell.Screen) {
 		}
 	}
 
+	// If rows are filtered, the row offset and the selection refer to the
+	// positions of the displayed rows.
+	displayRows := t.displayedRows(rowCount)
+	displayCount, selectedIndex := rowCount, t.selectedRow
+	if displayRows != nil {
+		displayCount, selectedIndex = len(displayRows), sort.SearchInts(displayRows, t.selectedRow)
+	}
+	rowAt := func(index int) int {
+		if displayRows == nil {
+			return index
+		}
+		return displayRows[index]
+	}
+
 	// Clamp row offsets if requested.
 	defer func() {
 		t.clampToSelection = false // Only once.
 	}()
 	if t.clampToSelection && t.rowsSelectable {
-		if t.selectedRow >= t.fixedRows && t.selectedRow < t.fixedRows+t.rowOffset {
-			t.rowOffset = t.selectedRow - t.fixedRows
+		if selectedIndex >= t.fixedRows && selectedIndex < t.fixedRows+t.rowOffset {
+			t.rowOffset = selectedIndex - t.fixedRows
 			t.trackEnd = false
 		}
 		if t.borders {
-			if t.selectedRow+1-t.rowOffset >= height/2 {
-				t.rowOffset = t.selectedRow + 1 - height/2
+			if selectedIndex+1-t.rowOffset >= height/2 {
+				t.rowOffset = selectedIndex + 1 - height/2
 				t.trackEnd = false
 			}
 		} else {
-			if t.selectedRow+1-t.rowOffset >= height {
-				t.rowOffset = t.selectedRow + 1 - height
+			if selectedIndex+1-t.rowOffset >= height {
+				t.rowOffset = selectedIndex + 1 - height
 				t.trackEnd = false
 			}
 		}
@@ -922,28 +2308,38 @@ func (t *Table) Draw(screen tcell.Screen) {
 		t.rowOffset = 0
 	}
 	if t.borders {
-		if rowCount-t.rowOffset < height/2 {
+		if displayCount-t.rowOffset < height/2 {
 			t.trackEnd = true
 		}
 	} else {
-		if rowCount-t.rowOffset < height {
+		if displayCount-t.rowOffset < height {
 			t.trackEnd = true
 		}
 	}
 	if t.trackEnd {
 		if t.borders {
-			t.rowOffset = rowCount - height/2
+			t.rowOffset = displayCount - height/2
 		} else {
-			t.rowOffset = rowCount - height
+			t.rowOffset = displayCount - height
 		}
 	}
 	if t.rowOffset < 0 {
 		t.rowOffset = 0
 	}
 
+	// The columns which are pinned to the right are excluded from scrolling.
+	fixedColumnsRight := t.fixedColumnsRight
+	if fixedColumnsRight > columnCount-t.fixedColumns {
+		fixedColumnsRight = columnCount - t.fixedColumns
+	}
+	if fixedColumnsRight < 0 {
+		fixedColumnsRight = 0
+	}
+	scrollEnd := columnCount - fixedColumnsRight
+
 	// Avoid invalid column offsets.
-	if t.columnOffset >= columnCount-t.fixedColumns {
-		t.columnOffset = columnCount - t.fixedColumns - 1
+	if t.columnOffset >= scrollEnd-t.fixedColumns {
+		t.columnOffset = scrollEnd - t.fixedColumns - 1
 	}
 	if t.columnOffset < 0 {
 		t.columnOffset = 0
@@ -959,9 +2355,9 @@ func (t *Table) Draw(screen tcell.Screen) {
 		rowStep = 2 // With borders, every table row takes two screen rows.
 	}
 	if t.evaluateAllRows {
-		allRows = make([]int, rowCount)
-		for row := 0; row < rowCount; row++ {
-			allRows[row] = row
+		allRows = make([]int, displayCount)
+		for index := 0; index < displayCount; index++ {
+			allRows[index] = rowAt(index)
 		}
 	}
 	indexRow := func(row int) bool { // Determine if this row is visible, store its index.
@@ -972,13 +2368,13 @@ func (t *Table) Draw(screen tcell.Screen) {
 		tableHeight += rowStep
 		return true
 	}
-	for row := 0; row < t.fixedRows && row < rowCount; row++ { // Do the fixed rows first.
-		if !indexRow(row) {
+	for index := 0; index < t.fixedRows && index < displayCount; index++ { // Do the fixed rows first.
+		if !indexRow(rowAt(index)) {
 			break
 		}
 	}
-	for row := t.fixedRows + t.rowOffset; row < rowCount; row++ { // Then the remaining rows.
-		if !indexRow(row) {
+	for index := t.fixedRows + t.rowOffset; index < displayCount; index++ { // Then the remaining rows.
+		if !indexRow(rowAt(index)) {
 			break
 		}
 	}
@@ -1003,11 +2399,16 @@ func (t *Table) Draw(screen tcell.Screen) {
 		if t.evaluateAllRows {
 			evaluationRows = allRows
 		}
+		columnWidth, fixedWidth := t.columnWidths[column]
+		if fixedWidth {
+			evaluationRows = nil // The width is known.
+			maxWidth = columnWidth
+		}
 		for _, row := range evaluationRows {
-			if cell := t.content.GetCell(row, column); cell != nil {
-				cellWidth := TaggedStringWidth(cell.Text)
-				if cell.MaxWidth > 0 && cell.MaxWidth < cellWidth {
-					cellWidth = cell.MaxWidth
+			if cell := t.content.GetCell(row, column); cell != nil && cell.ColSpan < 2 {
+				cellWidth := TaggedStringWidth(t.cellText(row, column, cell))
+				if cellMaxWidth := t.cellMaxWidth(column, cell); cellMaxWidth > 0 && cellMaxWidth < cellWidth {
+					cellWidth = cellMaxWidth
 				}
 				if cellWidth > maxWidth {
 					maxWidth = cellWidth
@@ -1017,6 +2418,14 @@ func (t *Table) Draw(screen tcell.Screen) {
 				}
 			}
 		}
+		if limits, ok := t.columnLimits[column]; ok && !fixedWidth {
+			if limits[0] > 0 && maxWidth < limits[0] {
+				maxWidth = limits[0]
+			}
+			if limits[1] > 0 && maxWidth > limits[1] {
+				maxWidth = limits[1]
+			}
+		}
 		clampedMaxWidth := maxWidth
 		if tableWidth+maxWidth > netWidth {
 			clampedMaxWidth = netWidth - tableWidth
@@ -1081,12 +2490,31 @@ func (t *Table) Draw(screen tcell.Screen) {
 	}
 
 	// Add fixed columns.
+	var (
+		rightColumns, rightWidths, rightExpansions []int
+		rightTableWidth, rightExpansionTotal       int
+	)
 	if indexColumns(0, t.fixedColumns) < 0 {
 		fixedTableWidth = tableWidth
 		fixedExpansionTotal = expansionTotal
 
+		// Add the columns fixed on the right and set them aside, reducing the
+		// space available for the remaining columns.
+		if fixedColumnsRight > 0 {
+			indexColumns(scrollEnd, columnCount)
+			rightColumns = append(rightColumns, columns[t.fixedColumns:]...)
+			rightWidths = append(rightWidths, widths[t.fixedColumns:]...)
+			rightExpansions = append(rightExpansions, expansions[t.fixedColumns:]...)
+			rightTableWidth, rightExpansionTotal = tableWidth-fixedTableWidth, expansionTotal-fixedExpansionTotal
+			resetColumns()
+			netWidth -= rightTableWidth
+			if t.selectedColumn >= scrollEnd {
+				includesSelection = true // The selection is always visible.
+			}
+		}
+
 		// Add unclamped columns.
-		if column := indexColumns(t.fixedColumns+t.columnOffset, columnCount); !includesSelection || column < 0 && t.columnOffset > 0 {
+		if column := indexColumns(t.fixedColumns+t.columnOffset, scrollEnd); !includesSelection || column < 0 && t.columnOffset > 0 {
 			// Offset is not optimal. Try again.
 			if !includesSelection {
 				// Clamp to selection.
@@ -1094,7 +2522,7 @@ func (t *Table) Draw(screen tcell.Screen) {
 				if t.selectedColumn <= t.fixedColumns+t.columnOffset {
 					// It's on the left. Start with the selection.
 					t.columnOffset = t.selectedColumn - t.fixedColumns
-					indexColumns(t.fixedColumns+t.columnOffset, columnCount)
+					indexColumns(t.fixedColumns+t.columnOffset, scrollEnd)
 				} else {
 					// It's on the right. End with the selection.
 					if column := indexColumns(t.selectedColumn, t.fixedColumns); column >= 0 {
@@ -1106,7 +2534,7 @@ func (t *Table) Draw(screen tcell.Screen) {
 			} else if tableWidth < netWidth {
 				// Don't waste space. Try to fit as much on screen as possible.
 				resetColumns()
-				if column := indexColumns(columnCount-1, t.fixedColumns); column >= 0 {
+				if column := indexColumns(scrollEnd-1, t.fixedColumns); column >= 0 {
 					t.columnOffset = column + 1 - t.fixedColumns
 				} else {
 					t.columnOffset = 0
@@ -1115,6 +2543,50 @@ func (t *Table) Draw(screen tcell.Screen) {
 		}
 	}
 
+	// Append the columns fixed on the right.
+	if len(rightColumns) > 0 {
+		netWidth += rightTableWidth
+		columns = append(columns, rightColumns...)
+		widths = append(widths, rightWidths...)
+		expansions = append(expansions, rightExpansions...)
+		tableWidth += rightTableWidth
+		expansionTotal += rightExpansionTotal
+	}
+
+	// Widen the columns spanned by cells whose text doesn't fit into them. The
+	// last spanned column without a fixed width receives the extra width.
+	for _, row := range rows {
+		for columnIndex, column := range columns {
+			cell := t.content.GetCell(row, column)
+			if cell == nil || cell.ColSpan < 2 || tableWidth >= netWidth {
+				continue
+			}
+			spanWidth, widen := widths[columnIndex], -1
+			if _, fixedWidth := t.columnWidths[column]; !fixedWidth {
+				widen = columnIndex
+			}
+			for index := columnIndex + 1; index < columnIndex+cell.ColSpan && index < len(columns) && columns[index] == column+index-columnIndex; index++ {
+				spanWidth += widths[index] + 1
+				if _, fixedWidth := t.columnWidths[columns[index]]; !fixedWidth {
+					widen = index
+				}
+			}
+			cellWidth := TaggedStringWidth(t.cellText(row, column, cell))
+			if cellMaxWidth := t.cellMaxWidth(column, cell); cellMaxWidth > 0 && cellMaxWidth < cellWidth {
+				cellWidth = cellMaxWidth
+			}
+			if widen < 0 || cellWidth <= spanWidth {
+				continue
+			}
+			extra := cellWidth - spanWidth
+			if tableWidth+extra > netWidth {
+				extra = netWidth - tableWidth
+			}
+			widths[widen] += extra
+			tableWidth += extra
+		}
+	}
+
 	// If we have space left, distribute it.
 	if tableWidth < netWidth {
 		toDistribute := netWidth - tableWidth
@@ -1123,19 +2595,89 @@ func (t *Table) Draw(screen tcell.Screen) {
 				break
 			}
 			expWidth := toDistribute * expansion / expansionTotal
+			if limits := t.columnLimits[columns[index]]; limits[1] > 0 && widths[index]+expWidth > limits[1] {
+				expWidth = limits[1] - widths[index]
+				if expWidth < 0 {
+					expWidth = 0
+				}
+			}
 			widths[index] += expWidth
 			toDistribute -= expWidth
 			expansionTotal -= expansion
 		}
 	}
 
+	// Determine the areas of visible cells which span multiple rows or
+	// columns, as indices into "rows" and "columns". Every grid position of
+	// such an area, including the spanning cell's own, is mapped to the area.
+	type tableSpan struct {
+		rowIndex, columnIndex, rows, columns int
+	}
+	var spans map[[2]int]*tableSpan
+	t.maxRowSpan, t.maxColSpan = 1, 1
+	for rowIndex, row := range rows {
+		for columnIndex, column := range columns {
+			cell := t.content.GetCell(row, column)
+			if cell == nil || spans[[2]int{rowIndex, columnIndex}] != nil {
+				continue
+			}
+			if cell.RowSpan > t.maxRowSpan {
+				t.maxRowSpan = cell.RowSpan
+			}
+			if cell.ColSpan > t.maxColSpan {
+				t.maxColSpan = cell.ColSpan
+			}
+			spanRows, spanColumns := 1, 1
+			for spanColumns < cell.ColSpan && columnIndex+spanColumns < len(columns) && columns[columnIndex+spanColumns] == column+spanColumns {
+				spanColumns++
+			}
+			for spanRows < cell.RowSpan && rowIndex+spanRows < len(rows) && rows[rowIndex+spanRows] == row+spanRows {
+				spanRows++
+			}
+			if spanRows == 1 && spanColumns == 1 {
+				continue
+			}
+			if spans == nil {
+				spans = make(map[[2]int]*tableSpan)
+			}
+			span := &tableSpan{rowIndex, columnIndex, spanRows, spanColumns}
+			for r := rowIndex; r < rowIndex+spanRows; r++ {
+				for c := columnIndex; c < columnIndex+spanColumns; c++ {
+					spans[[2]int{r, c}] = span
+				}
+			}
+		}
+	}
+	covered := func(rowIndex, columnIndex int) bool { // Whether a grid position is covered by another cell.
+		span := spans[[2]int{rowIndex, columnIndex}]
+		return span != nil && (span.rowIndex != rowIndex || span.columnIndex != columnIndex)
+	}
+	noVertical := func(rowIndex, columnIndex int) bool { // Whether there is no vertical border left of a grid position.
+		span := spans[[2]int{rowIndex, columnIndex}]
+		return span != nil && span.columnIndex < columnIndex
+	}
+	noHorizontal := func(rowIndex, columnIndex int) bool { // Whether there is no horizontal border above a grid position.
+		span := spans[[2]int{rowIndex, columnIndex}]
+		return span != nil && span.rowIndex < rowIndex
+	}
+	spanWidth := func(rowIndex, columnIndex int) int { // The width of the cell at a grid position, including any spanned columns.
+		width := widths[columnIndex]
+		if span := spans[[2]int{rowIndex, columnIndex}]; span != nil {
+			for c := columnIndex + 1; c < columnIndex+span.columns; c++ {
+				width += widths[c] + 1
+			}
+		}
+		return width
+	}
+
 	// Helper function which draws border runes.
 	borderStyle := tcell.StyleDefault.Background(t.backgroundColor).Foreground(t.bordersColor)
 	drawBorder := func(colX, rowY int, ch rune) {
 		screen.SetContent(x+colX, y+rowY, ch, nil, borderStyle)
 	}
 
-	// Draw the cells (and borders).
+	// Draw the cells (and borders). Cells are styled only once per draw.
+	styledCells := make(map[[2]int]*TableCell)
 	var columnX int
 	if t.borders {
 		columnX++
@@ -1143,14 +2685,24 @@ func (t *Table) Draw(screen tcell.Screen) {
 	for columnIndex, column := range columns {
 		columnWidth := widths[columnIndex]
 		for rowY, row := range rows {
+			rowIndex := rowY
 			if t.borders {
 				// Draw borders.
 				rowY *= 2
-				for pos := 0; pos < columnWidth && columnX+pos < width; pos++ {
-					drawBorder(columnX+pos, rowY, Borders.Horizontal)
+				if !noHorizontal(rowIndex, columnIndex) {
+					for pos := 0; pos < columnWidth && columnX+pos < width; pos++ {
+						drawBorder(columnX+pos, rowY, Borders.Horizontal)
+					}
 				}
 				ch := Borders.Cross
-				if row == 0 {
+				if spans != nil {
+					ch = borderJunction(
+						row != 0 && (rowIndex == 0 || !noVertical(rowIndex-1, columnIndex)),
+						!noVertical(rowIndex, columnIndex),
+						column != 0 && (columnIndex == 0 || !noHorizontal(rowIndex, columnIndex-1)),
+						!noHorizontal(rowIndex, columnIndex),
+					)
+				} else if row == 0 {
 					if column == 0 {
 						ch = Borders.TopLeft
 					} else {
@@ -1164,27 +2716,47 @@ func (t *Table) Draw(screen tcell.Screen) {
 				if rowY >= height || y+rowY >= totalHeight {
 					break // No space for the text anymore.
 				}
-				drawBorder(columnX-1, rowY, Borders.Vertical)
-			} else if columnIndex < len(columns)-1 {
+				if !noVertical(rowIndex, columnIndex) {
+					drawBorder(columnX-1, rowY, Borders.Vertical)
+				}
+			} else if columnIndex < len(columns)-1 && !noVertical(rowIndex, columnIndex+1) {
 				// Draw separator.
 				drawBorder(columnX+columnWidth, rowY, t.separator)
 			}
 
 			// Get the cell.
 			cell := t.content.GetCell(row, column)
-			if cell == nil {
+			if cell == nil || covered(rowIndex, columnIndex) {
 				continue
 			}
 
 			// Draw text.
-			finalWidth := columnWidth
-			if columnX+columnWidth >= width {
+			cellWidth := spanWidth(rowIndex, columnIndex)
+			finalWidth := cellWidth
+			if columnX+cellWidth >= width {
 				finalWidth = width - columnX
 			}
 			cell.x, cell.y, cell.width = x+columnX, y+rowY, finalWidth
-			start, end, _ := printWithStyle(screen, cell.Text, x+columnX, y+rowY, 0, finalWidth, cell.Align, tcell.StyleDefault.Foreground(cell.Color).Attributes(cell.Attributes), true)
+			if t.editor != nil && row == t.editRow && column == t.editColumn {
+				t.editX, t.editY, t.editWidth = cell.x, cell.y, cell.width
+			}
+			text := t.cellText(row, column, cell)
+			styled := t.styledCell(row, column, cell)
+			styledCells[[2]int{row, column}] = styled
+			cellStyle := tcell.StyleDefault.Foreground(styled.Color).Attributes(styled.Attributes)
+			truncation := TableTruncateEllipsis
+			if descriptor := t.columnDescriptors[column]; descriptor != nil {
+				truncation = descriptor.Truncation
+			}
+			if textWidth := TaggedStringWidth(text); truncation == TableTruncateStart && textWidth > finalWidth && finalWidth > 1 {
+				// Cut off the beginning.
+				printWithStyle(screen, text, x+columnX+1, y+rowY, textWidth-finalWidth+1, finalWidth-1, AlignLeft, cellStyle, true)
+				printWithStyle(screen, string(SemigraphicsHorizontalEllipsis), x+columnX, y+rowY, 0, 1, AlignLeft, cellStyle, true)
+				continue
+			}
+			start, end, _ := printWithStyle(screen, text, x+columnX, y+rowY, 0, finalWidth, t.cellAlign(column, cell), cellStyle, true)
 			printed := end - start
-			if TaggedStringWidth(cell.Text)-printed > 0 && printed > 0 {
+			if truncation != TableTruncateClip && TaggedStringWidth(text)-printed > 0 && printed > 0 {
 				_, _, style, _ := screen.GetContent(x+columnX+finalWidth-1, y+rowY)
 				printWithStyle(screen, string(SemigraphicsHorizontalEllipsis), x+columnX+finalWidth-1, y+rowY, 0, 1, AlignLeft, style, false)
 			}
@@ -1196,7 +2768,14 @@ func (t *Table) Draw(screen tcell.Screen) {
 				drawBorder(columnX+pos, rowY, Borders.Horizontal)
 			}
 			ch := Borders.Cross
-			if rows[len(rows)-1] == rowCount-1 {
+			if spans != nil {
+				ch = borderJunction(
+					!noVertical(len(rows)-1, columnIndex),
+					rows[len(rows)-1] != rowAt(displayCount-1),
+					column != 0,
+					true,
+				)
+			} else if rows[len(rows)-1] == rowAt(displayCount-1) {
 				if column == 0 {
 					ch = Borders.BottomLeft
 				} else {
@@ -1221,7 +2800,9 @@ func (t *Table) Draw(screen tcell.Screen) {
 				drawBorder(columnX, rowY+1, Borders.Vertical)
 			}
 			ch := Borders.Cross
-			if rowY == 0 {
+			if spans != nil {
+				ch = borderJunction(rowY != 0, true, !noHorizontal(rowY/2, len(columns)-1), !lastColumn)
+			} else if rowY == 0 {
 				if lastColumn {
 					ch = Borders.TopRight
 				} else {
@@ -1277,7 +2858,10 @@ func (t *Table) Draw(screen tcell.Screen) {
 		x, y, w, h int
 		cell       *TableCell
 		selected   bool
+		inRange    bool
 	}
+	fromRow, fromColumn, toRow, toColumn := t.GetSelectionRange()
+	hasRange := t.rowsSelectable && t.columnsSelectable && t.rangeRow >= 0 && t.rangeColumn >= 0
 	cellsByBackgroundColor := make(map[tcell.Color][]*cellInfo)
 	var backgroundColors []tcell.Color
 	for rowY, row := range rows {
@@ -1286,17 +2870,26 @@ func (t *Table) Draw(screen tcell.Screen) {
 		for columnIndex, column := range columns {
 			columnWidth := widths[columnIndex]
 			cell := t.content.GetCell(row, column)
-			if cell == nil {
+			if cell == nil || covered(rowY, columnIndex) {
+				columnX += columnWidth + 1
 				continue
 			}
-			bx, by, bw, bh := x+columnX, y+rowY, columnWidth+1, 1
+			if styled := styledCells[[2]int{row, column}]; styled != nil {
+				cell = styled
+			}
+			cellHeight := 1
+			if span := spans[[2]int{rowY, columnIndex}]; span != nil {
+				cellHeight = span.rows
+			}
+			bx, by, bw, bh := x+columnX, y+rowY, spanWidth(rowY, columnIndex)+1, cellHeight
 			if t.borders {
 				by = y + rowY*2
 				bw++
-				bh = 3
+				bh = 2*cellHeight + 1
 			}
 			columnSelected := t.columnsSelectable && !t.rowsSelectable && column == t.selectedColumn
 			cellSelected := !cell.NotSelectable && (columnSelected || rowSelected || t.rowsSelectable && t.columnsSelectable && column == t.selectedColumn && row == t.selectedRow)
+			cellInRange := hasRange && !cellSelected && row >= fromRow && row <= toRow && column >= fromColumn && column <= toColumn
 			entries, ok := cellsByBackgroundColor[cell.BackgroundColor]
 			cellsByBackgroundColor[cell.BackgroundColor] = append(entries, &cellInfo{
 				x:        bx,
@@ -1305,6 +2898,7 @@ func (t *Table) Draw(screen tcell.Screen) {
 				h:        bh,
 				cell:     cell,
 				selected: cellSelected,
+				inRange:  cellInRange,
 			})
 			if !ok {
 				backgroundColors = append(backgroundColors, cell.BackgroundColor)
@@ -1323,6 +2917,7 @@ func (t *Table) Draw(screen tcell.Screen) {
 		return li < lj
 	})
 	selFg, selBg, selAttr := t.selectedStyle.Decompose()
+	rangeFg, rangeBg, rangeAttr := t.rangeStyle.Decompose()
 	for _, bgColor := range backgroundColors {
 		entries := cellsByBackgroundColor[bgColor]
 		for _, info := range entries {
@@ -1332,19 +2927,54 @@ func (t *Table) Draw(screen tcell.Screen) {
 				} else {
 					defer colorBackground(info.x, info.y, info.w, info.h, bgColor, info.cell.Color, false, false, 0, true)
 				}
+			} else if info.inRange {
+				defer colorBackground(info.x, info.y, info.w, info.h, rangeBg, rangeFg, false, false, rangeAttr, false)
 			} else {
 				colorBackground(info.x, info.y, info.w, info.h, bgColor, info.cell.Color, info.cell.Transparent, true, 0, false)
 			}
 		}
 	}
 
-	// Remember column infos.
+	// Draw the scroll bars.
+	if t.verticalScrollBar {
+		visible := len(rows) - t.fixedRows
+		if visible < 0 {
+			visible = 0
+		}
+		t.verticalBar = scrollBar{x: x + width, y: y, length: height, total: displayCount - t.fixedRows, visible: visible, vertical: true}
+		t.verticalBar.draw(screen, t.rowOffset, t.scrollBarStyle)
+	}
+	if t.horizontalScrollBar {
+		visible := len(columns) - t.fixedColumns - len(rightColumns)
+		if visible < 0 {
+			visible = 0
+		}
+		t.horizontalBar = scrollBar{x: x, y: y + height, length: width, total: scrollEnd - t.fixedColumns, visible: visible}
+		t.horizontalBar.draw(screen, t.columnOffset, t.scrollBarStyle)
+	}
+
+	// Notify about changed offsets.
+	if t.scrollChanged != nil && (t.rowOffset != t.reportedRowOffset || t.columnOffset != t.reportedColumnOffset) {
+		t.reportedRowOffset, t.reportedColumnOffset = t.rowOffset, t.columnOffset
+		t.scrollChanged(t.rowOffset, t.columnOffset)
+	}
+
+	// Remember row and column infos.
+	t.visibleRowIndices = rows
 	t.visibleColumnIndices, t.visibleColumnWidths = columns, widths
 }
 
 // InputHandler returns the handler for this primitive.
 func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		// Forward key events to the cell editor.
+		if t.editor != nil {
+			if handler := t.editor.InputHandler(); handler != nil {
+				handler(event, setFocus)
+			}
+			return
+		}
+
 		key := event.Key()
 
 		if (!t.rowsSelectable && !t.columnsSelectable && key == tcell.KeyEnter) ||
@@ -1357,6 +2987,16 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 			return
 		}
 
+		// Switch pages.
+		if t.pageLoader != nil && key == tcell.KeyRune && (event.Rune() == ']' || event.Rune() == '[') {
+			if event.Rune() == ']' {
+				t.SetPage(t.page + 1)
+			} else {
+				t.SetPage(t.page - 1)
+			}
+			return
+		}
+
 		// Movement functions.
 		previouslySelectedRow, previouslySelectedColumn := t.selectedRow, t.selectedColumn
 		lastColumn := t.content.GetColumnCount() - 1
@@ -1371,8 +3011,13 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 				row, column := t.selectedRow, t.selectedColumn
 				for {
 					// Stop if the current selection is fine.
-					cell := t.content.GetCell(row, column)
-					if cell != nil && !cell.NotSelectable {
+					if anchorRow, anchorColumn, ok := t.spanAnchor(row, column); ok {
+						// Covered cells select the spanning cell unless we're moving away from it or it's not selectable.
+						if cell := t.content.GetCell(anchorRow, anchorColumn); !cell.NotSelectable && (anchorRow != previouslySelectedRow || anchorColumn != previouslySelectedColumn) && !t.rowHidden(anchorRow) {
+							t.selectedRow, t.selectedColumn = anchorRow, anchorColumn
+							return true
+						}
+					} else if cell := t.content.GetCell(row, column); cell != nil && !cell.NotSelectable && !t.rowHidden(row) {
 						t.selectedRow, t.selectedColumn = row, column
 						return true
 					}
@@ -1400,8 +3045,13 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 				row, column := t.selectedRow, t.selectedColumn
 				for {
 					// Stop if the current selection is fine.
-					cell := t.content.GetCell(row, column)
-					if cell != nil && !cell.NotSelectable {
+					if anchorRow, anchorColumn, ok := t.spanAnchor(row, column); ok {
+						// Covered cells select the spanning cell unless we're moving away from it or it's not selectable.
+						if cell := t.content.GetCell(anchorRow, anchorColumn); !cell.NotSelectable && (anchorRow != previouslySelectedRow || anchorColumn != previouslySelectedColumn) && !t.rowHidden(anchorRow) {
+							t.selectedRow, t.selectedColumn = anchorRow, anchorColumn
+							return true
+						}
+					} else if cell := t.content.GetCell(row, column); cell != nil && !cell.NotSelectable && !t.rowHidden(row) {
 						t.selectedRow, t.selectedColumn = row, column
 						return true
 					}
@@ -1452,12 +3102,20 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 				if t.rowsSelectable {
 					row, column := t.selectedRow, t.selectedColumn
 					t.selectedRow++
+					for t.selectedRow < rowCount { // Skip the rows spanned by the selected cell.
+						if anchorRow, anchorColumn, ok := t.spanAnchor(t.selectedRow, t.selectedColumn); !ok || anchorRow != row || anchorColumn != column {
+							break
+						}
+						t.selectedRow++
+					}
 					if t.selectedRow >= rowCount {
-						if t.wrapVertically {
-							t.selectedRow = 0
-						} else {
-							t.selectedRow = rowCount - 1
+						if !t.wrapVertically {
+							// The selected cell already reaches the last row.
+							t.selectedRow = row
+							t.clampToSelection = true
+							return
 						}
+						t.selectedRow = 0
 					}
 					finalRow, finalColumn := rowCount-1, lastColumn
 					if t.wrapVertically {
@@ -1578,7 +3236,7 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 				}
 				if t.rowsSelectable {
 					row, column := t.selectedRow, t.selectedColumn
-					t.selectedRow += offsetAmount
+					t.selectedRow = t.shiftRow(t.selectedRow, offsetAmount, rowCount)
 					if t.selectedRow >= rowCount {
 						t.selectedRow = rowCount - 1
 					}
@@ -1599,7 +3257,7 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 				}
 				if t.rowsSelectable {
 					row, column := t.selectedRow, t.selectedColumn
-					t.selectedRow -= offsetAmount
+					t.selectedRow = t.shiftRow(t.selectedRow, -offsetAmount, rowCount)
 					if t.selectedRow < 0 {
 						t.selectedRow = 0
 					}
@@ -1615,6 +3273,22 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 			}
 		)
 
+		// Holding Shift while moving the selection selects a range of cells.
+		if t.rowsSelectable && t.columnsSelectable {
+			switch key {
+			case tcell.KeyUp, tcell.KeyDown, tcell.KeyLeft, tcell.KeyRight, tcell.KeyHome, tcell.KeyEnd, tcell.KeyPgUp, tcell.KeyPgDn:
+				if event.Modifiers()&tcell.ModShift == 0 {
+					t.rangeRow, t.rangeColumn = -1, -1
+				} else if t.rangeRow < 0 || t.rangeColumn < 0 {
+					t.rangeRow, t.rangeColumn = t.selectedRow, t.selectedColumn
+				}
+			case tcell.KeyRune:
+				if strings.ContainsRune("ghjklG", event.Rune()) {
+					t.rangeRow, t.rangeColumn = -1, -1
+				}
+			}
+		}
+
 		switch key {
 		case tcell.KeyRune:
 			switch event.Rune() {
@@ -1647,8 +3321,14 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 			pageDown()
 		case tcell.KeyPgUp, tcell.KeyCtrlB:
 			pageUp()
+		case tcell.KeyCtrlQ:
+			if t.copyToClipboard != nil && (t.rowsSelectable || t.columnsSelectable) {
+				t.CopySelection(TableExportTSV)
+			}
 		case tcell.KeyEnter:
-			if (t.rowsSelectable || t.columnsSelectable) && t.selected != nil {
+			if t.rowsSelectable && t.columnsSelectable && t.startEditing(t.selectedRow, t.selectedColumn) {
+				setFocus(t) // Hands the focus to the editor.
+			} else if (t.rowsSelectable || t.columnsSelectable) && t.selected != nil {
 				t.selected(t.selectedRow, t.selectedColumn)
 			}
 		}
@@ -1665,18 +3345,149 @@ func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p Primi
 // MouseHandler returns the mouse handler for this primitive.
 func (t *Table) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		// Forward mouse events to the cell editor.
+		if t.editor != nil {
+			if handler := t.editor.MouseHandler(); handler != nil {
+				if consumed, capture = handler(action, event, setFocus); consumed {
+					return
+				}
+			}
+		}
+
+		// Drag a scroll bar.
 		x, y := event.Position()
+		if t.scrollBarDragging != tableScrollBarNone {
+			switch action {
+			case MouseMove:
+				if t.scrollBarDragging == tableScrollBarVertical {
+					t.rowOffset = t.verticalBar.offset(x, y)
+					t.trackEnd = false
+				} else {
+					t.columnOffset = t.horizontalBar.offset(x, y)
+				}
+				return true, t
+			case MouseLeftUp:
+				t.scrollBarDragging = tableScrollBarNone
+				return true, nil
+			}
+		}
+
+		// Resize a column by dragging its separator.
+		if t.resizeColumn >= 0 {
+			switch action {
+			case MouseMove:
+				width := t.resizeWidth + x - t.resizeX
+				if width < 1 {
+					width = 1
+				}
+				t.SetColumnWidth(t.resizeColumn, width)
+				return true, t
+			case MouseLeftUp:
+				t.resizeColumn = -1
+				return true, nil
+			}
+		}
+
+		// Select a range of cells by dragging the mouse.
+		if t.rangeDragging {
+			switch action {
+			case MouseMove:
+				row, column := t.cellAt(x, y)
+				if row >= 0 && column >= 0 && (row != t.selectedRow || column != t.selectedColumn) {
+					t.selectedRow, t.selectedColumn = row, column
+					t.clampToSelection = true
+					if t.selectionChanged != nil {
+						t.selectionChanged(row, column)
+					}
+				}
+				return true, t
+			case MouseLeftUp:
+				t.rangeDragging = false
+				if t.rangeRow == t.selectedRow && t.rangeColumn == t.selectedColumn {
+					t.rangeRow, t.rangeColumn = -1, -1 // Not a range.
+				}
+				return true, nil
+			}
+		}
+
 		if !t.InRect(x, y) {
 			return false, nil
 		}
 
+		// Clicking elsewhere confirms the edited value.
+		if t.editor != nil {
+			if action == MouseLeftDown {
+				if _, ok := t.editor.(*Checkbox); ok || t.commitEditing() {
+					t.editor = nil
+					setFocus(t)
+				}
+			}
+			return true, nil
+		}
+
+		// Clicks on the scroll bars scroll the table.
+		if action == MouseLeftDown || action == MouseLeftClick || action == MouseLeftDoubleClick {
+			bar := tableScrollBarNone
+			if t.verticalScrollBar && t.verticalBar.contains(x, y) {
+				bar = tableScrollBarVertical
+			} else if t.horizontalScrollBar && t.horizontalBar.contains(x, y) {
+				bar = tableScrollBarHorizontal
+			}
+			if bar != tableScrollBarNone {
+				if action != MouseLeftDown {
+					return true, nil
+				}
+				setFocus(t)
+				if bar == tableScrollBarVertical {
+					t.rowOffset = t.verticalBar.offset(x, y)
+					t.trackEnd = false
+				} else {
+					t.columnOffset = t.horizontalBar.offset(x, y)
+				}
+				t.scrollBarDragging = bar
+				return true, t
+			}
+		}
+
+		// Clicks on column separators resize columns.
+		if index := t.separatorAt(x, y); index >= 0 {
+			switch action {
+			case MouseLeftDown:
+				setFocus(t)
+				t.resizeColumn, t.resizeX, t.resizeWidth = t.visibleColumnIndices[index], x, t.visibleColumnWidths[index]
+				return true, t
+			case MouseLeftDoubleClick:
+				t.fitColumn(t.visibleColumnIndices[index])
+				return true, nil
+			}
+		}
+
 		switch action {
 		case MouseLeftDown:
 			setFocus(t)
 			consumed = true
+			if t.rowsSelectable && t.columnsSelectable {
+				if row, column := t.cellAt(x, y); row >= 0 && column >= 0 {
+					if cell := t.content.GetCell(row, column); cell != nil && !cell.NotSelectable {
+						t.rangeRow, t.rangeColumn = row, column
+						t.rangeDragging = true
+						capture = t
+					}
+				}
+			}
 		case MouseLeftClick:
 			selectEvent := true
 			row, column := t.cellAt(x, y)
+			if t.sortable && row >= 0 && row < t.fixedRows && column >= 0 {
+				// Sort by the clicked column.
+				ascending := true
+				if column == t.sortColumn {
+					ascending = !t.sortAscending
+				}
+				t.SortByColumn(column, ascending)
+				consumed = true
+				break
+			}
 			cell := t.content.GetCell(row, column)
 			if cell != nil && cell.Clicked != nil {
 				if noSelect := cell.Clicked(); noSelect {
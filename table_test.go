@@ -0,0 +1,106 @@
+package tview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// TestTableDownRowSpan tests that moving down from a cell spanning the last
+// rows keeps the selection.
+func TestTableDownRowSpan(t *testing.T) {
+	table := NewTable().SetSelectable(true, true)
+	for row := 0; row < 3; row++ {
+		for column := 0; column < 2; column++ {
+			table.SetCellSimple(row, column, "x")
+		}
+	}
+	table.GetCell(1, 0).SetRowSpan(2)
+	table.Select(1, 0)
+	drawPrimitive(t, table, 10, 3)
+	table.InputHandler()(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone), func(Primitive) {})
+	if row, column := table.GetSelection(); row != 1 || column != 0 {
+		t.Errorf("selection is %d,%d, expected 1,0", row, column)
+	}
+}
+
+// TestTableColSpanWidth tests that the text of a cell spanning multiple
+// columns widens these columns.
+func TestTableColSpanWidth(t *testing.T) {
+	table := NewTable()
+	table.SetCell(0, 0, NewTableCell("Group header").SetColSpan(2))
+	table.SetCellSimple(1, 0, "a")
+	table.SetCellSimple(1, 1, "b")
+	rows := drawPrimitive(t, table, 20, 2)
+	if !strings.HasPrefix(rows[0], "Group header") {
+		t.Errorf("header row is %q", rows[0])
+	}
+}
+
+// testTableContent is a virtual, read-only table content.
+type testTableContent struct {
+	TableContentReadOnly
+}
+
+func (c testTableContent) GetCell(row, column int) *TableCell {
+	return NewTableCell(string(rune('z' - row)))
+}
+
+func (c testTableContent) GetRowCount() int {
+	return 5
+}
+
+func (c testTableContent) GetColumnCount() int {
+	return 1
+}
+
+// TestTableSortByColumn tests that sorting rearranges the rows of the default
+// content but leaves the rows and the selection of other content alone.
+func TestTableSortByColumn(t *testing.T) {
+	table := NewTable().SetSelectable(true, false)
+	for row, text := range []string{"c", "a", "b"} {
+		table.SetCellSimple(row, 0, text)
+	}
+	table.Select(1, 0)
+	table.SortByColumn(0, true)
+	if text := table.GetCell(0, 0).Text; text != "a" {
+		t.Errorf("first row is %q, expected %q", text, "a")
+	}
+	if row, _ := table.GetSelection(); row != 0 {
+		t.Errorf("selected row is %d, expected 0", row)
+	}
+
+	var sorted bool
+	table = NewTable().SetContent(testTableContent{}).SetSelectable(true, false)
+	table.SetSortedFunc(func(column int, ascending bool) {
+		sorted = true
+	})
+	table.Select(1, 0)
+	table.SortByColumn(0, true)
+	if row, _ := table.GetSelection(); row != 1 || !sorted {
+		t.Errorf("selected row is %d (sorted: %t), expected 1 (sorted: true)", row, sorted)
+	}
+}
+
+// TestTableSetPage tests that pages are clamped to the total number of rows
+// returned by the page loader when loading the page.
+func TestTableSetPage(t *testing.T) {
+	total := 10
+	table := NewTable().SetPageSize(10).SetPageLoader(func(page int) ([][]string, int) {
+		if page*10 >= total {
+			return nil, total
+		}
+		return [][]string{{"row"}}, total
+	})
+	total = 30
+	table.SetPage(2)
+	if page, pageCount := table.GetPage(); page != 2 || pageCount != 3 {
+		t.Errorf("page is %d of %d, expected 2 of 3", page, pageCount)
+	}
+	total = 10
+	table.SetPage(2)
+	if page, pageCount := table.GetPage(); page != 0 || pageCount != 1 {
+		t.Errorf("page is %d of %d, expected 0 of 1", page, pageCount)
+	}
+}
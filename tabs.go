@@ -0,0 +1,510 @@
+package tview
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// tab is one tab of a Tabs object.
+type tab struct {
+	name  string    // The tab's name which identifies it.
+	label string    // The text shown in the tab strip.
+	item  Primitive // The tab's primitive.
+
+	// Temporary member variables.
+	x, width int // The position and visible width of the tab when last drawn.
+}
+
+// Tabs is a container which shows one of several primitives at a time, along
+// with a strip of tabs at the top or bottom (see [Tabs.SetTabPosition]) to
+// switch between them. Each tab is identified by a name.
+//
+// The following keys are available, regardless of which tab's primitive has
+// focus:
+//
+//   - Ctrl+Page down / Ctrl+Page up: Switch to the next / previous tab.
+//   - Ctrl+Shift+Page down / Ctrl+Shift+Page up: Move the current tab to the
+//     right / left.
+//
+// Clicking on a tab switches to it, dragging it moves it to another position.
+// If close buttons are enabled (see [Tabs.SetClosable]), clicking on them
+// closes the tab. When the tabs don't fit into the strip, arrows are shown at
+// its ends which scroll the strip when clicked, as does the mouse wheel.
+type Tabs struct {
+	*Box
+
+	// The tabs, in the order they are shown.
+	tabs []*tab
+
+	// The index of the current tab.
+	current int
+
+	// The index of the first tab shown in the strip.
+	offset int
+
+	// Whether the strip needs to be scrolled to show the current tab.
+	scrollToCurrent bool
+
+	// Whether the strip is drawn below the primitives (AlignBottom) or above
+	// them (AlignTop).
+	position int
+
+	// Whether the tabs show close buttons.
+	closable bool
+
+	// The styles of the tabs, of the current tab, and of the scroll arrows.
+	tabStyle, activeTabStyle, arrowStyle tcell.Style
+
+	// The strip's position and whether arrows were shown when last drawn.
+	stripX, stripY, stripWidth int
+	arrows                     bool
+
+	// The name of the tab being dragged, if any.
+	dragging string
+
+	// We keep a reference to the function which allows us to set the focus to
+	// the primitive of a newly selected tab.
+	setFocus func(p Primitive)
+
+	// An optional handler which is called when the current tab changes.
+	changed func(name string)
+
+	// An optional handler which is called before a tab is closed.
+	closing func(name string) bool
+}
+
+// NewTabs returns a new Tabs object without any tabs.
+func NewTabs() *Tabs {
+	return &Tabs{
+		Box:            NewBox(),
+		position:       AlignTop,
+		tabStyle:       tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		activeTabStyle: tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.ContrastBackgroundColor),
+		arrowStyle:     tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
+	}
+}
+
+// AddTab adds a tab with the given name, label, and primitive to the right
+// end of the strip. If there was previously a tab with the same name, it is
+// replaced. The first tab becomes the current tab.
+func (t *Tabs) AddTab(name, label string, item Primitive) *Tabs {
+	if index := t.indexOf(name); index >= 0 {
+		hasFocus := t.tabs[index].item.HasFocus()
+		t.tabs[index].label, t.tabs[index].item = label, item
+		if hasFocus && t.setFocus != nil {
+			t.setFocus(item)
+		}
+		return t
+	}
+	t.tabs = append(t.tabs, &tab{name: name, label: label, item: item})
+	if len(t.tabs) == 1 {
+		t.current = 0
+		if t.HasFocus() && t.setFocus != nil {
+			t.setFocus(item)
+		}
+	}
+	return t
+}
+
+// RemoveTab removes the tab with the given name. If it was the current tab,
+// the tab to its right (or the last tab) becomes the current tab.
+func (t *Tabs) RemoveTab(name string) *Tabs {
+	index := t.indexOf(name)
+	if index < 0 {
+		return t
+	}
+	hasFocus, wasCurrent := t.HasFocus(), index == t.current
+	t.tabs = append(t.tabs[:index], t.tabs[index+1:]...)
+	if index < t.current || t.current >= len(t.tabs) {
+		t.current--
+	}
+	if t.current < 0 {
+		t.current = 0
+	}
+	if t.offset > t.current {
+		t.offset = t.current
+	}
+	t.scrollToCurrent = true
+	if wasCurrent && len(t.tabs) > 0 && t.changed != nil {
+		t.changed(t.tabs[t.current].name)
+	}
+	if hasFocus && t.setFocus != nil {
+		t.Focus(t.setFocus)
+	}
+	return t
+}
+
+// HasTab returns true if a tab with the given name exists.
+func (t *Tabs) HasTab(name string) bool {
+	return t.indexOf(name) >= 0
+}
+
+// GetTabCount returns the number of tabs.
+func (t *Tabs) GetTabCount() int {
+	return len(t.tabs)
+}
+
+// SetTabLabel changes the label of the tab with the given name.
+func (t *Tabs) SetTabLabel(name, label string) *Tabs {
+	if index := t.indexOf(name); index >= 0 {
+		t.tabs[index].label = label
+	}
+	return t
+}
+
+// SwitchToTab makes the tab with the given name the current tab. If the tabs
+// had focus, the tab's primitive receives focus.
+func (t *Tabs) SwitchToTab(name string) *Tabs {
+	if index := t.indexOf(name); index >= 0 {
+		t.switchTo(index)
+	}
+	return t
+}
+
+// GetCurrentTab returns the name and the primitive of the current tab or an
+// empty name and nil if there are no tabs.
+func (t *Tabs) GetCurrentTab() (name string, item Primitive) {
+	if t.current >= len(t.tabs) {
+		return "", nil
+	}
+	return t.tabs[t.current].name, t.tabs[t.current].item
+}
+
+// MoveTab moves the tab with the given name to the given position in the
+// strip, starting at 0. Positions outside the strip are moved into it.
+func (t *Tabs) MoveTab(name string, position int) *Tabs {
+	index := t.indexOf(name)
+	if index < 0 {
+		return t
+	}
+	if position < 0 {
+		position = 0
+	} else if position >= len(t.tabs) {
+		position = len(t.tabs) - 1
+	}
+	current := t.tabs[t.current]
+	moved := t.tabs[index]
+	t.tabs = append(t.tabs[:index], t.tabs[index+1:]...)
+	t.tabs = append(t.tabs[:position], append([]*tab{moved}, t.tabs[position:]...)...)
+	t.current = t.indexOf(current.name)
+	t.scrollToCurrent = true
+	return t
+}
+
+// SetTabPosition sets whether the tab strip is drawn above the primitives
+// ([AlignTop], the default) or below them ([AlignBottom]).
+func (t *Tabs) SetTabPosition(position int) *Tabs {
+	t.position = position
+	return t
+}
+
+// SetClosable sets whether the tabs show close buttons.
+func (t *Tabs) SetClosable(closable bool) *Tabs {
+	t.closable = closable
+	return t
+}
+
+// SetStyles sets the styles of the tabs, of the current tab, and of the arrows
+// shown when the tabs don't fit into the strip.
+func (t *Tabs) SetStyles(tab, activeTab, arrows tcell.Style) *Tabs {
+	t.tabStyle, t.activeTabStyle, t.arrowStyle = tab, activeTab, arrows
+	return t
+}
+
+// SetChangedFunc sets a handler which is called with the name of the current
+// tab whenever it changes.
+func (t *Tabs) SetChangedFunc(handler func(name string)) *Tabs {
+	t.changed = handler
+	return t
+}
+
+// SetClosingFunc sets a handler which is called with the name of a tab whose
+// close button was clicked. If it returns false, the tab is not closed.
+func (t *Tabs) SetClosingFunc(handler func(name string) bool) *Tabs {
+	t.closing = handler
+	return t
+}
+
+// indexOf returns the index of the tab with the given name or -1 if there is
+// no such tab.
+func (t *Tabs) indexOf(name string) int {
+	for index, tab := range t.tabs {
+		if tab.name == name {
+			return index
+		}
+	}
+	return -1
+}
+
+// switchTo makes the tab with the given index the current tab.
+func (t *Tabs) switchTo(index int) {
+	if index < 0 || index >= len(t.tabs) {
+		return
+	}
+	hasFocus := t.HasFocus()
+	changed := index != t.current
+	t.current = index
+	t.scrollToCurrent = true
+	if hasFocus && t.setFocus != nil {
+		t.setFocus(t.tabs[index].item)
+	}
+	if changed && t.changed != nil {
+		t.changed(t.tabs[index].name)
+	}
+}
+
+// close closes the tab with the given index unless the "closing" handler
+// prevents it.
+func (t *Tabs) close(index int) {
+	name := t.tabs[index].name
+	if t.closing != nil && !t.closing(name) {
+		return
+	}
+	t.RemoveTab(name)
+}
+
+// tabWidth returns the full width of the given tab in the strip.
+func (t *Tabs) tabWidth(tab *tab) int {
+	width := TaggedStringWidth(tab.label) + 2 // Padding.
+	if t.closable {
+		width += 2 // Close button.
+	}
+	return width
+}
+
+// Focus is called when this primitive receives focus.
+func (t *Tabs) Focus(delegate func(p Primitive)) {
+	if delegate == nil {
+		return // We cannot delegate so we cannot focus.
+	}
+	t.setFocus = delegate
+	if t.current < len(t.tabs) {
+		delegate(t.tabs[t.current].item)
+	} else {
+		t.Box.Focus(delegate)
+	}
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (t *Tabs) HasFocus() bool {
+	for _, tab := range t.tabs {
+		if tab.item.HasFocus() {
+			return true
+		}
+	}
+	return t.Box.HasFocus()
+}
+
+// Draw draws this primitive onto the screen.
+func (t *Tabs) Draw(screen tcell.Screen) {
+	t.Box.DrawForSubclass(screen, t)
+
+	x, y, width, height := t.GetInnerRect()
+	for _, tab := range t.tabs {
+		tab.width = 0
+	}
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// Draw the current tab's primitive.
+	t.stripX, t.stripY, t.stripWidth = x, y, width
+	if t.position == AlignBottom {
+		t.stripY = y + height - 1
+	} else {
+		y++
+	}
+	if t.current < len(t.tabs) && height > 1 {
+		item := t.tabs[t.current].item
+		item.SetRect(x, y, width, height-1)
+		item.Draw(screen)
+	}
+
+	// Determine the visible tabs.
+	var total int
+	for index, tab := range t.tabs {
+		if index > 0 {
+			total++ // Gap between tabs.
+		}
+		total += t.tabWidth(tab)
+	}
+	stripX, stripWidth := t.stripX, t.stripWidth
+	t.arrows = total > width && width > 2
+	if t.arrows {
+		stripX, stripWidth = stripX+1, stripWidth-2
+		if t.scrollToCurrent {
+			if t.current < t.offset {
+				t.offset = t.current
+			}
+			for t.offset < t.current {
+				needed := -1
+				for index := t.offset; index <= t.current; index++ {
+					needed += t.tabWidth(t.tabs[index]) + 1
+				}
+				if needed <= stripWidth {
+					break
+				}
+				t.offset++
+			}
+		}
+		for t.offset > 0 {
+			needed := -1 // Don't leave space at the end.
+			for index := t.offset - 1; index < len(t.tabs); index++ {
+				needed += t.tabWidth(t.tabs[index]) + 1
+			}
+			if needed > stripWidth {
+				break
+			}
+			t.offset--
+		}
+	} else {
+		t.offset = 0
+	}
+	t.scrollToCurrent = false
+
+	// Draw the tabs.
+	tabX := stripX
+	for index := t.offset; index < len(t.tabs) && tabX < stripX+stripWidth; index++ {
+		tab := t.tabs[index]
+		style := t.tabStyle
+		if index == t.current {
+			style = t.activeTabStyle
+		}
+		tab.x, tab.width = tabX, t.tabWidth(tab)
+		if tab.x+tab.width > stripX+stripWidth {
+			tab.width = stripX + stripWidth - tab.x
+		}
+		for offset := 0; offset < tab.width; offset++ {
+			screen.SetContent(tab.x+offset, t.stripY, ' ', nil, style)
+		}
+		printWithStyle(screen, tab.label, tab.x+1, t.stripY, 0, tab.width-1, AlignLeft, style, false)
+		if t.closable && tab.width == t.tabWidth(tab) {
+			screen.SetContent(tab.x+tab.width-2, t.stripY, '×', nil, style)
+		}
+		tabX += tab.width + 1
+	}
+
+	// Draw the arrows.
+	if t.arrows {
+		if t.offset > 0 {
+			screen.SetContent(t.stripX, t.stripY, '◀', nil, t.arrowStyle)
+		}
+		last := t.tabs[len(t.tabs)-1]
+		if last.width < t.tabWidth(last) {
+			screen.SetContent(t.stripX+t.stripWidth-1, t.stripY, '▶', nil, t.arrowStyle)
+		}
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (t *Tabs) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if len(t.tabs) == 0 {
+			return
+		}
+
+		// Keys for the tab strip.
+		if key := event.Key(); (key == tcell.KeyPgDn || key == tcell.KeyPgUp) && event.Modifiers()&tcell.ModCtrl != 0 {
+			direction := 1
+			if key == tcell.KeyPgUp {
+				direction = -1
+			}
+			if event.Modifiers()&tcell.ModShift != 0 {
+				t.MoveTab(t.tabs[t.current].name, t.current+direction)
+			} else {
+				t.switchTo((t.current + direction + len(t.tabs)) % len(t.tabs))
+			}
+			return
+		}
+
+		// Pass other keys on to the current tab's primitive.
+		if item := t.tabs[t.current].item; item.HasFocus() {
+			if handler := item.InputHandler(); handler != nil {
+				handler(event, setFocus)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (t *Tabs) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		if t.dragging != "" {
+			capture = t // Keep receiving events while dragging a tab.
+		}
+		if !t.InRect(x, y) && t.dragging == "" {
+			return false, nil
+		}
+
+		// Find the tab under the mouse.
+		onStrip := y == t.stripY && x >= t.stripX && x < t.stripX+t.stripWidth
+		index := -1
+		if onStrip {
+			for tabIndex, tab := range t.tabs {
+				if tab.width > 0 && x >= tab.x && x < tab.x+tab.width {
+					index = tabIndex
+					break
+				}
+			}
+		}
+
+		switch action {
+		case MouseLeftDown:
+			if index >= 0 {
+				t.setFocus = setFocus
+				if !t.closable || x != t.tabs[index].x+t.tabs[index].width-2 {
+					t.dragging = t.tabs[index].name
+					capture = t
+					t.switchTo(index)
+					setFocus(t.tabs[index].item)
+				}
+				return true, capture
+			}
+		case MouseLeftClick:
+			if t.arrows && onStrip && x == t.stripX && t.offset > 0 {
+				t.offset--
+				return true, nil
+			} else if t.arrows && onStrip && x == t.stripX+t.stripWidth-1 && t.offset < len(t.tabs)-1 {
+				t.offset++
+				return true, nil
+			} else if index >= 0 && t.closable && x == t.tabs[index].x+t.tabs[index].width-2 && t.tabs[index].width == t.tabWidth(t.tabs[index]) {
+				t.close(index)
+				return true, nil
+			} else if onStrip {
+				return true, nil
+			}
+		case MouseMove:
+			if t.dragging != "" {
+				if index >= 0 && t.tabs[index].name != t.dragging {
+					t.MoveTab(t.dragging, index)
+				}
+				return true, capture
+			}
+		case MouseLeftUp:
+			if t.dragging != "" {
+				t.dragging = ""
+				return true, nil
+			}
+		case MouseScrollUp, MouseScrollLeft:
+			if onStrip {
+				if t.offset > 0 {
+					t.offset--
+				}
+				return true, nil
+			}
+		case MouseScrollDown, MouseScrollRight:
+			if onStrip {
+				if t.arrows && t.offset < len(t.tabs)-1 {
+					t.offset++
+				}
+				return true, nil
+			}
+		}
+
+		// Pass other events on to the current tab's primitive.
+		if onStrip || t.current >= len(t.tabs) {
+			return onStrip, capture
+		}
+		return t.tabs[t.current].item.MouseHandler()(action, event, setFocus)
+	})
+}
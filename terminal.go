@@ -0,0 +1,1287 @@
+package tview
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/uniseg"
+)
+
+// States of the terminal's escape sequence parser.
+const (
+	terminalGround = iota
+	terminalEscape
+	terminalCharset
+	terminalIgnoreNext
+	terminalCSI
+	terminalOSC
+	terminalOSCEscape
+	terminalString
+	terminalStringEscape
+)
+
+// Mouse tracking modes of a terminal, as requested by the running program.
+const (
+	terminalMouseOff = iota
+	terminalMouseButtons
+	terminalMouseDrag
+	terminalMouseAll
+)
+
+// terminalGraphics maps characters to the DEC special graphics character set,
+// used by programs to draw lines.
+var terminalGraphics = map[rune]rune{
+	'`': '◆', 'a': '▒', 'f': '°', 'g': '±', 'j': '┘', 'k': '┐', 'l': '┌',
+	'm': '└', 'n': '┼', 'o': '⎺', 'p': '⎻', 'q': '─', 'r': '⎼', 's': '⎽',
+	't': '├', 'u': '┤', 'v': '┴', 'w': '┬', 'x': '│', 'y': '≤', 'z': '≥',
+	'{': 'π', '|': '≠', '}': '£', '~': '·',
+}
+
+// terminalCell is one character cell of a terminal's screen.
+type terminalCell struct {
+	text  string // The cell's character, including combining characters. Empty for a blank cell.
+	style tcell.Style
+	width int // 2 for wide characters, 0 for the cell to the right of a wide character.
+}
+
+// Terminal runs a command on a pseudo terminal (PTY) and shows its output. It
+// understands the escape sequences of the commonly used subset of VT100 and
+// xterm: cursor movement, scroll regions, colors (including 256 colors and
+// true colors) and text attributes, the alternate screen, line drawing
+// characters, and mouse tracking. The TERM environment variable of the
+// command is set to "xterm-256color" unless set otherwise.
+//
+//	terminal := tview.NewTerminal().SetApplication(app)
+//	if err := terminal.Start(exec.Command("htop")); err != nil {
+//		panic(err)
+//	}
+//
+// When the terminal has focus, all keys are sent to the command. Use
+// [Box.SetInputCapture] to intercept keys which should leave the terminal.
+// Mouse events are sent to the command if it requested them. Otherwise, the
+// mouse wheel scrolls through the lines which have scrolled off the top of the
+// screen.
+//
+// The size of the PTY follows the size of the terminal's inner rectangle.
+// Commands can be started on Linux, macOS, FreeBSD, and NetBSD. On other
+// platforms (e.g. Windows or OpenBSD), [Terminal.Start] returns an error.
+// Output may also be written to the terminal directly using [Terminal.Write],
+// without running a command.
+type Terminal struct {
+	*Box
+
+	// Protects all fields below which may be changed by the goroutine
+	// reading the command's output.
+	mu sync.Mutex
+
+	// The screen size in cells.
+	columns, rows int
+
+	// The screen's lines. When the alternate screen is active, the main
+	// screen's lines are kept in mainLines.
+	lines, mainLines [][]terminalCell
+	alternate        bool
+
+	// Lines which have scrolled off the top of the main screen, the oldest
+	// first, and the maximum number of lines kept.
+	scrollback    [][]terminalCell
+	maxScrollback int
+
+	// The number of scrollback lines the user scrolled back.
+	scrollOffset int
+
+	// The cursor position and whether the next character wraps to the next
+	// line.
+	cursorX, cursorY int
+	wrapPending      bool
+
+	// The saved cursor position and style.
+	savedX, savedY int
+	savedStyle     tcell.Style
+
+	// The style of printed characters.
+	style tcell.Style
+
+	// The scroll region, from the top to the bottom row (inclusive).
+	top, bottom int
+
+	// Terminal modes.
+	autoWrap, insert, cursorVisible, applicationCursor bool
+
+	// The mouse tracking mode and whether mouse events use the SGR encoding.
+	mouseMode int
+	sgrMouse  bool
+
+	// Whether the G0 and G1 character sets are the line drawing set, which
+	// set is active, and which set is being designated.
+	graphics      [2]bool
+	charset       int
+	designatedSet int
+
+	// The most recently printed character, for repeating it.
+	lastPrinted rune
+
+	// The escape sequence parser's state.
+	state        int
+	params       []int
+	private      byte
+	intermediate bool
+	osc          []byte
+	partial      []byte
+
+	// Replies to the command's queries, sent after parsing output.
+	replies []byte
+
+	// The window title set by the command and whether it changed since the
+	// title handler was last called.
+	title        string
+	titlePending bool
+
+	// The running command and its PTY, nil if there is none.
+	cmd *exec.Cmd
+	pty *os.File
+
+	// The application which is redrawn when output arrives, if any.
+	app *Application
+
+	// An optional function which is called when the command exits.
+	exited func(err error)
+
+	// An optional function which is called when the window title changes.
+	titleChanged func(title string)
+}
+
+// NewTerminal returns a new terminal which doesn't run any command yet. Use
+// [Terminal.Start] to run one.
+func NewTerminal() *Terminal {
+	t := &Terminal{
+		Box:           NewBox(),
+		maxScrollback: 1000,
+	}
+	t.reset(80, 24)
+	return t
+}
+
+// reset resets the terminal to its initial state with the given size.
+func (t *Terminal) reset(columns, rows int) {
+	t.columns, t.rows = columns, rows
+	t.style = tcell.StyleDefault
+	t.lines = make([][]terminalCell, rows)
+	for index := range t.lines {
+		t.lines[index] = t.blankLine()
+	}
+	t.mainLines, t.alternate = nil, false
+	t.cursorX, t.cursorY, t.wrapPending = 0, 0, false
+	t.savedX, t.savedY, t.savedStyle = 0, 0, tcell.StyleDefault
+	t.top, t.bottom = 0, rows-1
+	t.autoWrap, t.insert, t.cursorVisible, t.applicationCursor = true, false, true, false
+	t.mouseMode, t.sgrMouse = terminalMouseOff, false
+	t.graphics, t.charset = [2]bool{}, 0
+	t.state = terminalGround
+}
+
+// SetApplication sets the application which redraws the screen when the
+// command produces output. Set to nil to disable automatic redraws.
+func (t *Terminal) SetApplication(app *Application) *Terminal {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.app = app
+	return t
+}
+
+// SetScrollback sets the maximum number of lines which are kept after they
+// have scrolled off the top of the screen. The default is 1000.
+func (t *Terminal) SetScrollback(lines int) *Terminal {
+	if lines < 0 {
+		lines = 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.maxScrollback = lines
+	if len(t.scrollback) > lines {
+		t.scrollback = t.scrollback[len(t.scrollback)-lines:]
+	}
+	if t.scrollOffset > len(t.scrollback) {
+		t.scrollOffset = len(t.scrollback)
+	}
+	return t
+}
+
+// SetExitedFunc sets a handler which is called with the result of
+// [exec.Cmd.Wait] when the command exits. It is called from the goroutine
+// which reads the command's output, use [Application.QueueUpdateDraw] to
+// change primitives from it.
+func (t *Terminal) SetExitedFunc(handler func(err error)) *Terminal {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.exited = handler
+	return t
+}
+
+// SetTitleChangedFunc sets a handler which is called when the command sets
+// the window title. Like the handler set with [Terminal.SetExitedFunc], it is
+// called from the goroutine which reads the command's output.
+func (t *Terminal) SetTitleChangedFunc(handler func(title string)) *Terminal {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.titleChanged = handler
+	return t
+}
+
+// GetTerminalTitle returns the window title most recently set by the
+// command.
+func (t *Terminal) GetTerminalTitle() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.title
+}
+
+// Start runs the given command on a new PTY. Its standard input, output, and
+// error are connected to the PTY, any previous values are ignored. The output
+// is read in a separate goroutine. Only one command may run at a time.
+func (t *Terminal) Start(cmd *exec.Cmd) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cmd != nil {
+		return errors.New("terminal is already running a command")
+	}
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	hasTerm := false
+	for _, variable := range cmd.Env {
+		if strings.HasPrefix(variable, "TERM=") {
+			hasTerm = true
+			break
+		}
+	}
+	if !hasTerm {
+		cmd.Env = append(cmd.Env, "TERM=xterm-256color")
+	}
+	pty, err := startTerminalPty(cmd, t.columns, t.rows)
+	if err != nil {
+		return err
+	}
+	t.cmd, t.pty = cmd, pty
+	go t.run(cmd, pty)
+	return nil
+}
+
+// run reads the command's output until the command exits.
+func (t *Terminal) run(cmd *exec.Cmd, pty *os.File) {
+	buffer := make([]byte, 32*1024)
+	for {
+		n, err := pty.Read(buffer)
+		if n > 0 {
+			t.Write(buffer[:n])
+		}
+		if err != nil {
+			break
+		}
+	}
+	err := cmd.Wait()
+	pty.Close()
+
+	t.mu.Lock()
+	t.cmd, t.pty = nil, nil
+	app, exited := t.app, t.exited
+	t.mu.Unlock()
+	if app != nil {
+		app.requestDraw()
+	}
+	if exited != nil {
+		exited(err)
+	}
+}
+
+// IsRunning returns whether a command is running.
+func (t *Terminal) IsRunning() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cmd != nil
+}
+
+// Stop kills the running command. Nothing happens if no command is running.
+func (t *Terminal) Stop() error {
+	t.mu.Lock()
+	cmd := t.cmd
+	t.mu.Unlock()
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
+
+// Send sends the given bytes to the running command as if they were typed.
+func (t *Terminal) Send(data []byte) error {
+	t.mu.Lock()
+	pty := t.pty
+	t.scrollOffset = 0
+	t.mu.Unlock()
+	if pty == nil {
+		return errors.New("terminal is not running a command")
+	}
+	_, err := pty.Write(data)
+	return err
+}
+
+// Write processes the given output as if the running command had written it.
+// It implements the [io.Writer] interface and may be called from any
+// goroutine.
+func (t *Terminal) Write(p []byte) (n int, err error) {
+	t.mu.Lock()
+	for _, b := range p {
+		t.parse(b)
+	}
+	replies, pty, app := t.replies, t.pty, t.app
+	t.replies = nil
+	var titleChanged func(title string)
+	if t.titlePending {
+		titleChanged = t.titleChanged
+		t.titlePending = false
+	}
+	title := t.title
+	t.mu.Unlock()
+
+	if len(replies) > 0 && pty != nil {
+		pty.Write(replies)
+	}
+	if titleChanged != nil {
+		titleChanged(title)
+	}
+	if app != nil {
+		app.requestDraw()
+	}
+	return len(p), nil
+}
+
+// blankLine returns a line of blank cells with the current background color.
+func (t *Terminal) blankLine() []terminalCell {
+	line := make([]terminalCell, t.columns)
+	t.erase(line)
+	return line
+}
+
+// erase blanks the given cells using the current background color.
+func (t *Terminal) erase(cells []terminalCell) {
+	_, background, _ := t.style.Decompose()
+	style := tcell.StyleDefault.Background(background)
+	for index := range cells {
+		cells[index] = terminalCell{style: style, width: 1}
+	}
+}
+
+// parse processes one byte of output.
+func (t *Terminal) parse(b byte) {
+	// Strings (OSC, DCS, etc.) are only terminated by BEL or ST.
+	switch t.state {
+	case terminalOSC:
+		switch b {
+		case 0x07:
+			t.oscDispatch()
+			t.state = terminalGround
+		case 0x1b:
+			t.state = terminalOSCEscape
+		default:
+			if len(t.osc) < 4096 {
+				t.osc = append(t.osc, b)
+			}
+		}
+		return
+	case terminalOSCEscape:
+		t.oscDispatch()
+		t.state = terminalGround
+		if b != '\\' {
+			t.parse(b)
+		}
+		return
+	case terminalString:
+		if b == 0x1b {
+			t.state = terminalStringEscape
+		} else if b == 0x07 {
+			t.state = terminalGround
+		}
+		return
+	case terminalStringEscape:
+		if b == '\\' {
+			t.state = terminalGround
+		} else {
+			t.state = terminalString
+		}
+		return
+	}
+
+	// Control characters are executed in all other states.
+	if b < 0x20 || b == 0x7f {
+		t.execute(b)
+		return
+	}
+
+	switch t.state {
+	case terminalGround:
+		t.printByte(b)
+	case terminalEscape:
+		t.escapeDispatch(b)
+	case terminalCharset:
+		t.graphics[t.designatedSet] = b == '0'
+		t.state = terminalGround
+	case terminalIgnoreNext:
+		t.state = terminalGround
+	case terminalCSI:
+		switch {
+		case b >= '0' && b <= '9':
+			if len(t.params) == 0 {
+				t.params = append(t.params, 0)
+			}
+			last := len(t.params) - 1
+			if t.params[last] < 10000 {
+				t.params[last] = t.params[last]*10 + int(b-'0')
+			}
+		case b == ';' || b == ':':
+			if len(t.params) == 0 {
+				t.params = append(t.params, 0)
+			}
+			if len(t.params) < 32 {
+				t.params = append(t.params, 0)
+			}
+		case b >= '<' && b <= '?':
+			t.private = b
+		case b >= 0x20 && b <= 0x2f:
+			t.intermediate = true
+		case b >= 0x40 && b <= 0x7e:
+			t.state = terminalGround
+			if !t.intermediate {
+				t.csiDispatch(b)
+			}
+		}
+	}
+}
+
+// execute executes a control character.
+func (t *Terminal) execute(b byte) {
+	switch b {
+	case 0x08: // Backspace.
+		if t.cursorX > 0 {
+			t.cursorX--
+		}
+		t.wrapPending = false
+	case 0x09: // Horizontal tab.
+		t.cursorX = (t.cursorX/8 + 1) * 8
+		if t.cursorX >= t.columns {
+			t.cursorX = t.columns - 1
+		}
+		t.wrapPending = false
+	case 0x0a, 0x0b, 0x0c: // Line feed.
+		t.lineFeed()
+	case 0x0d: // Carriage return.
+		t.cursorX, t.wrapPending = 0, false
+	case 0x0e: // Shift out.
+		t.charset = 1
+	case 0x0f: // Shift in.
+		t.charset = 0
+	case 0x18, 0x1a: // Cancel.
+		t.state = terminalGround
+	case 0x1b:
+		t.state = terminalEscape
+		t.params, t.private, t.intermediate = t.params[:0], 0, false
+	}
+}
+
+// printByte collects the bytes of UTF-8 encoded characters and prints them.
+func (t *Terminal) printByte(b byte) {
+	if b < 0x80 && len(t.partial) == 0 {
+		t.print(rune(b))
+		return
+	}
+	t.partial = append(t.partial, b)
+	if !utf8.FullRune(t.partial) {
+		return
+	}
+	r, _ := utf8.DecodeRune(t.partial)
+	t.partial = t.partial[:0]
+	t.print(r)
+}
+
+// print prints a character at the cursor position.
+func (t *Terminal) print(r rune) {
+	if t.graphics[t.charset] {
+		if graphic, ok := terminalGraphics[r]; ok {
+			r = graphic
+		}
+	}
+	t.lastPrinted = r
+	width := uniseg.StringWidth(string(r))
+
+	// Combining characters are added to the previous cell.
+	if width == 0 {
+		x := t.cursorX
+		if !t.wrapPending {
+			x--
+		}
+		line := t.lines[t.cursorY]
+		if x >= 0 && line[x].width == 0 && x > 0 {
+			x--
+		}
+		if x >= 0 && line[x].text != "" {
+			line[x].text += string(r)
+		}
+		return
+	}
+
+	if t.wrapPending || t.cursorX+width > t.columns {
+		if !t.autoWrap {
+			if t.cursorX+width > t.columns {
+				return
+			}
+		} else {
+			t.cursorX = 0
+			t.lineFeed()
+		}
+	}
+	t.wrapPending = false
+	line := t.lines[t.cursorY]
+	if t.insert {
+		copy(line[t.cursorX+width:], line[t.cursorX:])
+	}
+
+	// Don't leave halves of wide characters.
+	if t.cursorX > 0 && line[t.cursorX].width == 0 {
+		t.erase(line[t.cursorX-1 : t.cursorX])
+	}
+	if end := t.cursorX + width; end < t.columns && line[end].width == 0 {
+		t.erase(line[end : end+1])
+	}
+
+	line[t.cursorX] = terminalCell{text: string(r), style: t.style, width: width}
+	if width == 2 {
+		line[t.cursorX+1] = terminalCell{style: t.style}
+	}
+	t.cursorX += width
+	if t.cursorX >= t.columns {
+		t.cursorX = t.columns - 1
+		t.wrapPending = true
+	}
+}
+
+// lineFeed moves the cursor down, scrolling if it is at the bottom of the
+// scroll region.
+func (t *Terminal) lineFeed() {
+	t.wrapPending = false
+	if t.cursorY == t.bottom {
+		t.scrollUp(1)
+	} else if t.cursorY < t.rows-1 {
+		t.cursorY++
+	}
+}
+
+// scrollUp scrolls the scroll region up by the given number of lines. Lines
+// scrolling off the top of the main screen are added to the scrollback.
+func (t *Terminal) scrollUp(count int) {
+	if count > t.bottom-t.top+1 {
+		count = t.bottom - t.top + 1
+	}
+	for index := 0; index < count; index++ {
+		if t.top == 0 && !t.alternate && t.maxScrollback > 0 {
+			t.scrollback = append(t.scrollback, t.lines[0])
+			if len(t.scrollback) > t.maxScrollback {
+				t.scrollback = t.scrollback[len(t.scrollback)-t.maxScrollback:]
+			} else if t.scrollOffset > 0 {
+				t.scrollOffset++ // Keep the user's view in place.
+			}
+		}
+		copy(t.lines[t.top:t.bottom], t.lines[t.top+1:t.bottom+1])
+		t.lines[t.bottom] = t.blankLine()
+	}
+}
+
+// scrollDown scrolls the scroll region down by the given number of lines.
+func (t *Terminal) scrollDown(count int) {
+	if count > t.bottom-t.top+1 {
+		count = t.bottom - t.top + 1
+	}
+	for index := 0; index < count; index++ {
+		copy(t.lines[t.top+1:t.bottom+1], t.lines[t.top:t.bottom])
+		t.lines[t.top] = t.blankLine()
+	}
+}
+
+// escapeDispatch executes the escape sequence ending with the given byte.
+func (t *Terminal) escapeDispatch(b byte) {
+	t.state = terminalGround
+	switch b {
+	case '[':
+		t.state = terminalCSI
+	case ']':
+		t.state = terminalOSC
+		t.osc = t.osc[:0]
+	case 'P', 'X', '^', '_':
+		t.state = terminalString
+	case '(', ')':
+		t.state = terminalCharset
+		t.designatedSet = 0
+		if b == ')' {
+			t.designatedSet = 1
+		}
+	case '*', '+', '#', '%', ' ':
+		t.state = terminalIgnoreNext
+	case '7':
+		t.saveCursor()
+	case '8':
+		t.restoreCursor()
+	case 'D':
+		t.lineFeed()
+	case 'E':
+		t.cursorX = 0
+		t.lineFeed()
+	case 'M':
+		t.wrapPending = false
+		if t.cursorY == t.top {
+			t.scrollDown(1)
+		} else if t.cursorY > 0 {
+			t.cursorY--
+		}
+	case 'c':
+		t.reset(t.columns, t.rows)
+	}
+}
+
+// oscDispatch executes the collected operating system command.
+func (t *Terminal) oscDispatch() {
+	command := string(t.osc)
+	if strings.HasPrefix(command, "0;") || strings.HasPrefix(command, "2;") {
+		t.title = command[2:]
+		t.titlePending = true
+	}
+}
+
+// saveCursor saves the cursor position and style.
+func (t *Terminal) saveCursor() {
+	t.savedX, t.savedY, t.savedStyle = t.cursorX, t.cursorY, t.style
+}
+
+// restoreCursor restores the saved cursor position and style.
+func (t *Terminal) restoreCursor() {
+	t.cursorX, t.cursorY, t.style = t.savedX, t.savedY, t.savedStyle
+	t.wrapPending = false
+	t.clampCursor()
+}
+
+// clampCursor moves the cursor back onto the screen.
+func (t *Terminal) clampCursor() {
+	if t.cursorX >= t.columns {
+		t.cursorX = t.columns - 1
+	}
+	if t.cursorX < 0 {
+		t.cursorX = 0
+	}
+	if t.cursorY >= t.rows {
+		t.cursorY = t.rows - 1
+	}
+	if t.cursorY < 0 {
+		t.cursorY = 0
+	}
+}
+
+// param returns the parameter with the given index of the current CSI
+// sequence or the given default if it is missing or 0.
+func (t *Terminal) param(index, def int) int {
+	if index >= len(t.params) || t.params[index] == 0 {
+		return def
+	}
+	return t.params[index]
+}
+
+// csiDispatch executes the CSI sequence ending with the given byte.
+func (t *Terminal) csiDispatch(b byte) {
+	if t.private == '?' {
+		switch b {
+		case 'h', 'l':
+			for index := range t.params {
+				t.setPrivateMode(t.params[index], b == 'h')
+			}
+		}
+		return
+	}
+	if t.private == '>' {
+		if b == 'c' {
+			t.replies = append(t.replies, "\x1b[>0;10;1c"...)
+		}
+		return
+	}
+	if t.private != 0 {
+		return
+	}
+
+	n := t.param(0, 1)
+	line := t.lines[t.cursorY]
+	if b != 'b' {
+		t.wrapPending = false
+	}
+	switch b {
+	case '@': // Insert characters.
+		if n > t.columns-t.cursorX {
+			n = t.columns - t.cursorX
+		}
+		copy(line[t.cursorX+n:], line[t.cursorX:])
+		t.erase(line[t.cursorX : t.cursorX+n])
+	case 'A': // Cursor up.
+		t.cursorY -= n
+		if t.cursorY < t.top && t.cursorY+n >= t.top {
+			t.cursorY = t.top
+		}
+	case 'B', 'e': // Cursor down.
+		t.cursorY += n
+		if t.cursorY > t.bottom && t.cursorY-n <= t.bottom {
+			t.cursorY = t.bottom
+		}
+	case 'C', 'a': // Cursor forward.
+		t.cursorX += n
+	case 'D': // Cursor backward.
+		t.cursorX -= n
+	case 'E': // Cursor to next line.
+		t.cursorX, t.cursorY = 0, t.cursorY+n
+	case 'F': // Cursor to previous line.
+		t.cursorX, t.cursorY = 0, t.cursorY-n
+	case 'G', '`': // Cursor to column.
+		t.cursorX = n - 1
+	case 'H', 'f': // Cursor position.
+		t.cursorY, t.cursorX = n-1, t.param(1, 1)-1
+	case 'I': // Tab forward.
+		for ; n > 0; n-- {
+			t.execute(0x09)
+		}
+	case 'J': // Erase in display.
+		switch t.param(0, 0) {
+		case 0:
+			t.erase(line[t.cursorX:])
+			for _, line := range t.lines[t.cursorY+1:] {
+				t.erase(line)
+			}
+		case 1:
+			t.erase(line[:t.cursorX+1])
+			for _, line := range t.lines[:t.cursorY] {
+				t.erase(line)
+			}
+		case 2:
+			for _, line := range t.lines {
+				t.erase(line)
+			}
+		case 3:
+			t.scrollback, t.scrollOffset = nil, 0
+		}
+	case 'K': // Erase in line.
+		switch t.param(0, 0) {
+		case 0:
+			t.erase(line[t.cursorX:])
+		case 1:
+			t.erase(line[:t.cursorX+1])
+		case 2:
+			t.erase(line)
+		}
+	case 'L', 'M': // Insert or delete lines.
+		if t.cursorY >= t.top && t.cursorY <= t.bottom {
+			top := t.top
+			t.top = t.cursorY
+			if b == 'L' {
+				t.scrollDown(n)
+			} else {
+				alternate := t.alternate
+				t.alternate = true // Deleted lines don't go to the scrollback.
+				t.scrollUp(n)
+				t.alternate = alternate
+			}
+			t.top = top
+			t.cursorX = 0
+		}
+	case 'P': // Delete characters.
+		if n > t.columns-t.cursorX {
+			n = t.columns - t.cursorX
+		}
+		copy(line[t.cursorX:], line[t.cursorX+n:])
+		t.erase(line[t.columns-n:])
+	case 'S': // Scroll up.
+		t.scrollUp(n)
+	case 'T': // Scroll down.
+		t.scrollDown(n)
+	case 'X': // Erase characters.
+		if n > t.columns-t.cursorX {
+			n = t.columns - t.cursorX
+		}
+		t.erase(line[t.cursorX : t.cursorX+n])
+	case 'Z': // Tab backward.
+		for ; n > 0 && t.cursorX > 0; n-- {
+			t.cursorX = (t.cursorX - 1) / 8 * 8
+		}
+	case 'b': // Repeat the last character.
+		if t.lastPrinted != 0 {
+			for ; n > 0; n-- {
+				t.print(t.lastPrinted)
+			}
+		}
+	case 'c': // Device attributes.
+		if t.param(0, 0) == 0 {
+			t.replies = append(t.replies, "\x1b[?62;22c"...)
+		}
+	case 'd': // Cursor to row.
+		t.cursorY = n - 1
+	case 'h', 'l': // Set or reset mode.
+		for _, mode := range t.params {
+			if mode == 4 {
+				t.insert = b == 'h'
+			}
+		}
+	case 'm': // Character attributes.
+		t.selectGraphicRendition()
+	case 'n': // Device status report.
+		switch t.param(0, 0) {
+		case 5:
+			t.replies = append(t.replies, "\x1b[0n"...)
+		case 6:
+			t.replies = append(t.replies, fmt.Sprintf("\x1b[%d;%dR", t.cursorY+1, t.cursorX+1)...)
+		}
+	case 'r': // Scroll region.
+		top, bottom := t.param(0, 1)-1, t.param(1, t.rows)-1
+		if bottom >= t.rows {
+			bottom = t.rows - 1
+		}
+		if top < bottom {
+			t.top, t.bottom = top, bottom
+			t.cursorX, t.cursorY = 0, 0
+		}
+	case 's':
+		t.saveCursor()
+	case 'u':
+		t.restoreCursor()
+	}
+	t.clampCursor()
+}
+
+// setPrivateMode sets or resets a DEC private mode.
+func (t *Terminal) setPrivateMode(mode int, set bool) {
+	switch mode {
+	case 1:
+		t.applicationCursor = set
+	case 7:
+		t.autoWrap = set
+	case 25:
+		t.cursorVisible = set
+	case 47, 1047, 1049:
+		if set == t.alternate {
+			return
+		}
+		if mode == 1049 && set {
+			t.saveCursor()
+		}
+		if set {
+			t.mainLines = t.lines
+			t.lines = make([][]terminalCell, t.rows)
+			for index := range t.lines {
+				t.lines[index] = t.blankLine()
+			}
+		} else {
+			t.lines, t.mainLines = t.mainLines, nil
+		}
+		t.alternate = set
+		t.scrollOffset = 0
+		if mode == 1049 && !set {
+			t.restoreCursor()
+		}
+	case 1000:
+		t.setMouseMode(terminalMouseButtons, set)
+	case 1002:
+		t.setMouseMode(terminalMouseDrag, set)
+	case 1003:
+		t.setMouseMode(terminalMouseAll, set)
+	case 1006:
+		t.sgrMouse = set
+	}
+}
+
+// setMouseMode enables or disables the given mouse tracking mode.
+func (t *Terminal) setMouseMode(mode int, set bool) {
+	if set {
+		t.mouseMode = mode
+	} else if t.mouseMode == mode {
+		t.mouseMode = terminalMouseOff
+	}
+}
+
+// selectGraphicRendition changes the current style according to the
+// parameters of an SGR sequence.
+func (t *Terminal) selectGraphicRendition() {
+	params := t.params
+	if len(params) == 0 {
+		params = []int{0}
+	}
+	for index := 0; index < len(params); index++ {
+		switch p := params[index]; {
+		case p == 0:
+			t.style = tcell.StyleDefault
+		case p == 1:
+			t.style = t.style.Bold(true)
+		case p == 2:
+			t.style = t.style.Dim(true)
+		case p == 3:
+			t.style = t.style.Italic(true)
+		case p == 4:
+			t.style = t.style.Underline(true)
+		case p == 5 || p == 6:
+			t.style = t.style.Blink(true)
+		case p == 7:
+			t.style = t.style.Reverse(true)
+		case p == 9:
+			t.style = t.style.StrikeThrough(true)
+		case p == 21 || p == 24:
+			t.style = t.style.Underline(false)
+		case p == 22:
+			t.style = t.style.Bold(false).Dim(false)
+		case p == 23:
+			t.style = t.style.Italic(false)
+		case p == 25:
+			t.style = t.style.Blink(false)
+		case p == 27:
+			t.style = t.style.Reverse(false)
+		case p == 29:
+			t.style = t.style.StrikeThrough(false)
+		case p >= 30 && p <= 37:
+			t.style = t.style.Foreground(tcell.PaletteColor(p - 30))
+		case p == 39:
+			t.style = t.style.Foreground(tcell.ColorDefault)
+		case p >= 40 && p <= 47:
+			t.style = t.style.Background(tcell.PaletteColor(p - 40))
+		case p == 49:
+			t.style = t.style.Background(tcell.ColorDefault)
+		case p >= 90 && p <= 97:
+			t.style = t.style.Foreground(tcell.PaletteColor(p - 90 + 8))
+		case p >= 100 && p <= 107:
+			t.style = t.style.Background(tcell.PaletteColor(p - 100 + 8))
+		case p == 38 || p == 48:
+			var color tcell.Color
+			if index+2 < len(params) && params[index+1] == 5 {
+				color = tcell.PaletteColor(params[index+2] & 0xff)
+				index += 2
+			} else if index+4 < len(params) && params[index+1] == 2 {
+				color = tcell.NewRGBColor(int32(params[index+2]&0xff), int32(params[index+3]&0xff), int32(params[index+4]&0xff))
+				index += 4
+			} else {
+				return
+			}
+			if p == 38 {
+				t.style = t.style.Foreground(color)
+			} else {
+				t.style = t.style.Background(color)
+			}
+		}
+	}
+}
+
+// resize changes the screen size. Lines which don't fit anymore because the
+// cursor would be below the bottom of the screen are moved to the scrollback.
+func (t *Terminal) resize(columns, rows int) {
+	resizeLines := func(lines [][]terminalCell, scroll bool) [][]terminalCell {
+		for index, line := range lines {
+			if len(line) > columns {
+				line = line[:columns]
+				if columns > 0 && line[columns-1].width == 2 {
+					t.erase(line[columns-1:])
+				}
+			} else if len(line) < columns {
+				extension := make([]terminalCell, columns-len(line))
+				t.erase(extension)
+				line = append(line, extension...)
+			}
+			lines[index] = line
+		}
+		if excess := t.cursorY - rows + 1; scroll && excess > 0 {
+			if !t.alternate && t.maxScrollback > 0 {
+				t.scrollback = append(t.scrollback, lines[:excess]...)
+				if len(t.scrollback) > t.maxScrollback {
+					t.scrollback = t.scrollback[len(t.scrollback)-t.maxScrollback:]
+				}
+			}
+			lines = lines[excess:]
+			t.cursorY -= excess
+		}
+		if len(lines) > rows {
+			lines = lines[:rows]
+		}
+		for len(lines) < rows {
+			line := make([]terminalCell, columns)
+			t.erase(line)
+			lines = append(lines, line)
+		}
+		return lines
+	}
+	t.lines = resizeLines(t.lines, true)
+	if t.mainLines != nil {
+		t.mainLines = resizeLines(t.mainLines, false)
+	}
+	t.columns, t.rows = columns, rows
+	t.top, t.bottom = 0, rows-1
+	t.wrapPending = false
+	t.clampCursor()
+	t.savedX, t.savedY = t.cursorX, t.cursorY
+}
+
+// Draw draws this primitive onto the screen.
+func (t *Terminal) Draw(screen tcell.Screen) {
+	t.Box.DrawForSubclass(screen, t)
+
+	x, y, width, height := t.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Resize the screen and the PTY if the size changed.
+	if width != t.columns || height != t.rows {
+		t.resize(width, height)
+		if t.pty != nil {
+			setTerminalPtySize(t.pty, width, height)
+		}
+	}
+
+	// Draw the lines, including scrolled back lines.
+	background := t.GetBackgroundColor()
+	for row := 0; row < t.rows; row++ {
+		var line []terminalCell
+		if index := len(t.scrollback) - t.scrollOffset + row; index < len(t.scrollback) {
+			line = t.scrollback[index]
+		} else {
+			line = t.lines[row-t.scrollOffset]
+		}
+		for column := 0; column < t.columns && column < len(line); column++ {
+			cell := line[column]
+			if cell.width == 0 {
+				continue // Right half of a wide character.
+			}
+			style := cell.style
+			foreground, cellBackground, _ := style.Decompose()
+			if foreground == tcell.ColorDefault {
+				style = style.Foreground(Styles.PrimaryTextColor)
+			}
+			if cellBackground == tcell.ColorDefault {
+				style = style.Background(background)
+			}
+			main, combining := ' ', []rune(nil)
+			if cell.text != "" {
+				runes := []rune(cell.text)
+				main, combining = runes[0], runes[1:]
+			}
+			screen.SetContent(x+column, y+row, main, combining, style)
+		}
+	}
+
+	// Show the cursor.
+	if t.HasFocus() && t.cursorVisible && t.scrollOffset == 0 {
+		screen.ShowCursor(x+t.cursorX, y+t.cursorY)
+	}
+}
+
+// keySequence returns the bytes to send to the command for the given key
+// event.
+func (t *Terminal) keySequence(event *tcell.EventKey) []byte {
+	var modifier int
+	if event.Modifiers()&tcell.ModShift != 0 {
+		modifier |= 1
+	}
+	if event.Modifiers()&tcell.ModAlt != 0 {
+		modifier |= 2
+	}
+	if event.Modifiers()&tcell.ModCtrl != 0 {
+		modifier |= 4
+	}
+	letter := func(final byte) []byte {
+		if modifier != 0 {
+			return []byte(fmt.Sprintf("\x1b[1;%d%c", modifier+1, final))
+		}
+		if t.applicationCursor {
+			return []byte{0x1b, 'O', final}
+		}
+		return []byte{0x1b, '[', final}
+	}
+	tilde := func(code int) []byte {
+		if modifier != 0 {
+			return []byte(fmt.Sprintf("\x1b[%d;%d~", code, modifier+1))
+		}
+		return []byte(fmt.Sprintf("\x1b[%d~", code))
+	}
+	function := func(final byte) []byte {
+		if modifier != 0 {
+			return []byte(fmt.Sprintf("\x1b[1;%d%c", modifier+1, final))
+		}
+		return []byte{0x1b, 'O', final}
+	}
+
+	switch key := event.Key(); key {
+	case tcell.KeyRune:
+		text := []byte(string(event.Rune()))
+		if modifier&2 != 0 {
+			text = append([]byte{0x1b}, text...)
+		}
+		return text
+	case tcell.KeyUp:
+		return letter('A')
+	case tcell.KeyDown:
+		return letter('B')
+	case tcell.KeyRight:
+		return letter('C')
+	case tcell.KeyLeft:
+		return letter('D')
+	case tcell.KeyHome:
+		return letter('H')
+	case tcell.KeyEnd:
+		return letter('F')
+	case tcell.KeyInsert:
+		return tilde(2)
+	case tcell.KeyDelete:
+		return tilde(3)
+	case tcell.KeyPgUp:
+		return tilde(5)
+	case tcell.KeyPgDn:
+		return tilde(6)
+	case tcell.KeyF1, tcell.KeyF2, tcell.KeyF3, tcell.KeyF4:
+		return function(byte('P' + key - tcell.KeyF1))
+	case tcell.KeyF5:
+		return tilde(15)
+	case tcell.KeyF6, tcell.KeyF7, tcell.KeyF8, tcell.KeyF9, tcell.KeyF10:
+		return tilde(17 + int(key-tcell.KeyF6))
+	case tcell.KeyF11, tcell.KeyF12:
+		return tilde(23 + int(key-tcell.KeyF11))
+	case tcell.KeyBacktab:
+		return []byte("\x1b[Z")
+	default:
+		if key < 0x20 || key == 0x7f {
+			if modifier&2 != 0 {
+				return []byte{0x1b, byte(key)}
+			}
+			return []byte{byte(key)}
+		}
+	}
+	return nil
+}
+
+// InputHandler returns the handler for this primitive.
+func (t *Terminal) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if sequence := t.keySequence(event); len(sequence) > 0 {
+			t.Send(sequence)
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (t *Terminal) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		x, y := event.Position()
+		rectX, rectY, width, height := t.GetInnerRect()
+		inside := x >= rectX && x < rectX+width && y >= rectY && y < rectY+height
+		if !t.InRect(x, y) {
+			return false, nil
+		}
+		if action == MouseLeftDown || action == MouseMiddleDown || action == MouseRightDown {
+			setFocus(t)
+		}
+
+		t.mu.Lock()
+		mouseMode, sgr, running := t.mouseMode, t.sgrMouse, t.pty != nil
+		t.mu.Unlock()
+
+		// Forward mouse events to the command if it wants them.
+		if mouseMode != terminalMouseOff && running {
+			if !inside {
+				return true, nil
+			}
+			button, release := -1, false
+			switch action {
+			case MouseLeftDown:
+				button = 0
+			case MouseMiddleDown:
+				button = 1
+			case MouseRightDown:
+				button = 2
+			case MouseLeftUp:
+				button, release = 0, true
+			case MouseMiddleUp:
+				button, release = 1, true
+			case MouseRightUp:
+				button, release = 2, true
+			case MouseScrollUp:
+				button = 64
+			case MouseScrollDown:
+				button = 65
+			case MouseMove:
+				buttons := event.Buttons()
+				switch {
+				case buttons&tcell.Button1 != 0:
+					button = 32
+				case buttons&tcell.Button3 != 0:
+					button = 33
+				case buttons&tcell.Button2 != 0:
+					button = 34
+				case mouseMode == terminalMouseAll:
+					button = 35
+				}
+				if button >= 0 && button != 35 && mouseMode == terminalMouseButtons {
+					button = -1 // Motion is not requested.
+				}
+			}
+			if button < 0 {
+				return true, nil
+			}
+			if event.Modifiers()&tcell.ModShift != 0 {
+				button |= 4
+			}
+			if event.Modifiers()&tcell.ModAlt != 0 {
+				button |= 8
+			}
+			if event.Modifiers()&tcell.ModCtrl != 0 {
+				button |= 16
+			}
+			column, row := x-rectX+1, y-rectY+1
+			if sgr {
+				final := 'M'
+				if release {
+					final = 'm'
+				}
+				t.Send([]byte(fmt.Sprintf("\x1b[<%d;%d;%d%c", button, column, row, final)))
+			} else if column <= 223 && row <= 223 {
+				if release {
+					button = button&^3 | 3
+				}
+				t.Send([]byte{0x1b, '[', 'M', byte(32 + button), byte(32 + column), byte(32 + row)})
+			}
+			if action == MouseLeftDown || action == MouseMiddleDown || action == MouseRightDown {
+				return true, t // Capture the mouse until the button is released.
+			}
+			return true, nil
+		}
+
+		// Otherwise, the mouse wheel scrolls through the scrollback.
+		switch action {
+		case MouseScrollUp:
+			t.mu.Lock()
+			t.scrollOffset += 3
+			if t.scrollOffset > len(t.scrollback) {
+				t.scrollOffset = len(t.scrollback)
+			}
+			t.mu.Unlock()
+		case MouseScrollDown:
+			t.mu.Lock()
+			t.scrollOffset -= 3
+			if t.scrollOffset < 0 {
+				t.scrollOffset = 0
+			}
+			t.mu.Unlock()
+		}
+		return true, nil
+	})
+}
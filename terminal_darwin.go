@@ -0,0 +1,41 @@
+package tview
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// openTerminalPty opens the master side of a new PTY, grants access to its
+// slave side, and unlocks it. It returns the master side and the path of the
+// slave side.
+func openTerminalPty() (*os.File, string, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	fd := int(master.Fd())
+	if err := unix.IoctlSetInt(fd, unix.TIOCPTYGRANT, 0); err != nil {
+		master.Close()
+		return nil, "", err
+	}
+	if err := unix.IoctlSetInt(fd, unix.TIOCPTYUNLK, 0); err != nil {
+		master.Close()
+		return nil, "", err
+	}
+
+	// The name is written to a buffer of 128 bytes, which the ioctl helpers of
+	// the unix package don't provide.
+	var name [128]byte
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(unix.TIOCPTYGNAME), uintptr(unsafe.Pointer(&name[0]))); errno != 0 {
+		master.Close()
+		return nil, "", errno
+	}
+	if end := bytes.IndexByte(name[:], 0); end >= 0 {
+		return master, string(name[:end]), nil
+	}
+	return master, string(name[:]), nil
+}
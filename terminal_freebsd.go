@@ -0,0 +1,25 @@
+package tview
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// openTerminalPty opens the master side of a new PTY. It returns the master
+// side and the path of the slave side. On FreeBSD, slave sides don't need to
+// be granted or unlocked.
+func openTerminalPty() (*os.File, string, error) {
+	fd, _, errno := unix.Syscall(unix.SYS_POSIX_OPENPT, uintptr(unix.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC), 0, 0)
+	if errno != 0 {
+		return nil, "", errno
+	}
+	master := os.NewFile(fd, "/dev/ptmx")
+	number, err := unix.IoctlGetInt(int(fd), unix.TIOCGPTN)
+	if err != nil {
+		master.Close()
+		return nil, "", err
+	}
+	return master, fmt.Sprintf("/dev/pts/%d", number), nil
+}
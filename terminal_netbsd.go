@@ -0,0 +1,32 @@
+package tview
+
+import (
+	"bytes"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// openTerminalPty opens the master side of a new PTY and grants access to its
+// slave side. It returns the master side and the path of the slave side. On
+// NetBSD, slave sides don't need to be unlocked.
+func openTerminalPty() (*os.File, string, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	fd := int(master.Fd())
+	if err := unix.IoctlSetInt(fd, unix.TIOCGRANTPT, 0); err != nil {
+		master.Close()
+		return nil, "", err
+	}
+	ptm, err := unix.IoctlGetPtmget(fd, unix.TIOCPTSNAME)
+	if err != nil {
+		master.Close()
+		return nil, "", err
+	}
+	if end := bytes.IndexByte(ptm.Sn[:], 0); end >= 0 {
+		return master, string(ptm.Sn[:end]), nil
+	}
+	return master, string(ptm.Sn[:]), nil
+}
@@ -0,0 +1,20 @@
+//go:build !linux && !darwin && !freebsd && !netbsd
+
+package tview
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// startTerminalPty returns an error because PTYs are not supported on this
+// platform.
+func startTerminalPty(cmd *exec.Cmd, columns, rows int) (*os.File, error) {
+	return nil, errors.New("terminal commands are not supported on this platform")
+}
+
+// setTerminalPtySize does nothing on this platform.
+func setTerminalPtySize(pty *os.File, columns, rows int) error {
+	return nil
+}
@@ -0,0 +1,52 @@
+//go:build linux || darwin || freebsd || netbsd
+
+package tview
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// startTerminalPty opens a new PTY with the given size and starts the command
+// on it. It returns the PTY's master side.
+func startTerminalPty(cmd *exec.Cmd, columns, rows int) (*os.File, error) {
+	master, name, err := openTerminalPty()
+	if err != nil {
+		return nil, err
+	}
+	slave, err := os.OpenFile(name, os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, err
+	}
+	defer slave.Close()
+	if err := setTerminalPtySize(master, columns, rows); err != nil {
+		master.Close()
+		return nil, err
+	}
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 0 // Standard input.
+	if err := cmd.Start(); err != nil {
+		master.Close()
+		return nil, err
+	}
+	return master, nil
+}
+
+// setTerminalPtySize sets the size of the given PTY. The command running on it
+// is notified with a SIGWINCH signal.
+func setTerminalPtySize(pty *os.File, columns, rows int) error {
+	return unix.IoctlSetWinsize(int(pty.Fd()), unix.TIOCSWINSZ, &unix.Winsize{
+		Row: uint16(rows),
+		Col: uint16(columns),
+	})
+}
@@ -1,6 +1,9 @@
 package tview
 
 import (
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -20,6 +23,10 @@ const (
 	// could be longer but it would be highly unusual.
 	maxGraphemeClusterSize = 40
 
+	// The maximum size in bytes of the spans an initial text is split into.
+	// Smaller spans make line lookups faster but the piece chain longer.
+	initialSpanMaxSize = 1 << 16
+
 	// The default value for the [TextArea.minCursorPrefix] variable.
 	minCursorPrefixDefault = 5
 
@@ -38,6 +45,17 @@ const (
 	taActionDelete                // Deleting the next character.
 )
 
+// TextAreaMode is the editing mode of a [TextArea] with modal editing enabled.
+// See [TextArea.SetModalEditing] for details.
+type TextAreaMode int
+
+// Editing modes of a text area.
+const (
+	TextAreaModeInsert TextAreaMode = iota // Keys edit the text as usual.
+	TextAreaModeNormal                     // Keys are interpreted as commands.
+	TextAreaModeVisual                     // Keys are interpreted as commands which extend or operate on the selection.
+)
+
 // NewLine is the string sequence to be inserted when hitting the Enter key in a
 // TextArea. The default is "\n" but you may change it to "\r\n" if required.
 var NewLine = "\n"
@@ -77,29 +95,59 @@ type textAreaSpan struct {
 	// the sentinel spans (index 0 and 1), both values will be 0. Others will
 	// never have a zero length.
 	offset, length int
+
+	// The number of newline characters in this span plus one or 0 if it has
+	// not been determined yet.
+	newlines int
+}
+
+// textAreaChainEntry describes one span in [TextArea.chainIndex].
+type textAreaChainEntry struct {
+	span     int // The index of the span into [TextArea.spans].
+	start    int // The index of the span's first byte within the entire text.
+	newlines int // The number of newline characters before the span.
 }
 
 // textAreaUndoItem represents an undoable edit to the text area. It describes
 // the two spans wrapping a text change.
 type textAreaUndoItem struct {
-	before, after                 int    // The index of the copied "before" and "after" spans into the "spans" slice.
-	originalBefore, originalAfter int    // The original indices of the "before" and "after" spans.
-	pos                           [3]int // The cursor position to be assumed after applying an undo.
-	length                        int    // The total text length at the time the undo item was created.
-	continuation                  bool   // If true, this item is a continuation of the previous undo item. It is handled together with all other undo items in the same continuation sequence.
+	before, after                 int      // The index of the copied "before" and "after" spans into the "spans" slice.
+	originalBefore, originalAfter int      // The original indices of the "before" and "after" spans.
+	pos                           [3]int   // The cursor position to be assumed after applying an undo.
+	length                        int      // The total text length at the time the undo item was created.
+	readOnly                      [][2]int // The read-only regions at the time the undo item was created.
+	continuation                  bool     // If true, this item is a continuation of the previous undo item. It is handled together with all other undo items in the same continuation sequence.
+}
+
+// Line states for change markers, see [TextArea.SetBaselineText].
+const (
+	textAreaLineUnchanged byte = iota
+	textAreaLineAdded
+	textAreaLineModified
+	textAreaLineDeleted // The line is unchanged but lines before it were deleted.
+)
+
+// textAreaHighlightLine contains the cached syntax highlighting results of one
+// line of text, i.e. text up to and including a newline character.
+type textAreaHighlightLine struct {
+	start, length int          // The index of the line's first byte and its length, including the newline character.
+	spans         []StyledSpan // The styled spans with indices relative to the line start.
+	state         any          // The highlighter state after this line.
 }
 
 // TextArea implements a simple text editor for multi-line text. Multi-color
-// text is not supported. Word-wrapping is enabled by default but can be turned
-// off or be changed to character-wrapping.
+// text is not supported but syntax highlighting can be applied using
+// [TextArea.SetHighlighter]. Word-wrapping is enabled by default but can be
+// turned off or be changed to character-wrapping.
 //
 // At this point, a text area cannot be added to a [Form]. This will be added in
 // the future.
 //
 // # Navigation and Editing
 //
-// A text area is always in editing mode and no other mode exists. The following
-// keys can be used to move the cursor (subject to what the user's terminal
+// Unless modal editing is enabled with [TextArea.SetModalEditing], a text area
+// is always in editing mode and no other mode exists. The following keys can be
+// used to move the cursor (subject to what the user's terminal
 // supports and how it is configured):
 //
 //   - Left arrow: Move left.
@@ -128,9 +176,12 @@ type textAreaUndoItem struct {
 // visible area, any changes to the text will move it into the visible area. The
 // following keys can also be used to modify the text:
 //
-//   - Enter: Insert a newline character (see [NewLine]).
+//   - Enter: Insert a newline character (see [NewLine]), followed by the
+//     current line's indentation if [TextArea.SetAutoIndent] is enabled.
 //   - Tab: Insert a tab character (\t). It will be rendered like [TabSize]
-//     spaces. (This may eventually be changed to behave like regular tabs.)
+//     spaces (or as set with [TextArea.SetTabSize]). (This may eventually be
+//     changed to behave like regular tabs.) With [TextArea.SetSoftTabs],
+//     spaces up to the next multiple of the tab size are inserted instead.
 //   - Ctrl-H, Backspace: Delete one character to the left of the cursor.
 //   - Ctrl-D, Delete: Delete the character under the cursor (or the first
 //     character on the next line if the cursor is at the end of a line).
@@ -178,7 +229,21 @@ type textAreaUndoItem struct {
 //   - Ctrl-Z: Undo the last change.
 //   - Ctrl-Y: Redo the last Undo change.
 //
-// Undo does not affect the clipboard.
+// Undo does not affect the clipboard. Applications may also use
+// [TextArea.Undo] and [TextArea.Redo] to bind these functions to other keys and
+// [TextArea.SetModifiedFunc] to be notified when the text's modified state
+// changes.
+//
+// Matches of a regular expression can be highlighted and navigated using
+// [TextArea.SetSearch], [TextArea.NextMatch], and [TextArea.PreviousMatch] and
+// replaced using [TextArea.ReplaceMatch] and [TextArea.ReplaceAll]. A simple
+// search prompt is available via [TextArea.OpenSearchPrompt].
+//
+// Text can be edited at multiple positions at once by adding cursors with
+// [TextArea.AddCursorAt] or [TextArea.SelectNextOccurrence].
+//
+// Parts of the text can be protected from changes with
+// [TextArea.AddReadOnlyRegion].
 //
 // If the mouse is enabled, the following actions are available:
 //
@@ -186,7 +251,10 @@ type textAreaUndoItem struct {
 //     line if past the last character.
 //   - Left double-click: Select the word under the cursor.
 //   - Left click while holding the Shift key: Select text.
+//   - Left click while holding the Alt key: Add a cursor.
 //   - Scroll wheel: Scroll the text.
+//   - Left click on the gutter (if line numbers are shown): Invoke the handler
+//     set with [TextArea.SetGutterClickedFunc].
 //
 // [Unicode Standard Annex #29]: https://unicode.org/reports/tr29/
 type TextArea struct {
@@ -245,6 +313,16 @@ type TextArea struct {
 	// deleted from this slice.
 	spans []textAreaSpan
 
+	// The spans of the piece chain in their order, including the end sentinel
+	// span, along with their text positions. This index is built when needed
+	// and discarded when the text changes. It allows for fast lookups of text
+	// indices and lines in long texts. See [TextArea.getChainIndex].
+	chainIndex []textAreaChainEntry
+
+	// For each span in [TextArea.spans], its position in [TextArea.chainIndex]
+	// or -1 if it is not part of the piece chain.
+	chainPositions []int
+
 	// An optional function which transforms grapheme clusters. This can be used
 	// to hide characters from the screen while preserving the original text.
 	transform func(cluster, rest string, boundaries int) (newCluster string, newBoundaries int)
@@ -304,6 +382,145 @@ type TextArea struct {
 	// Set to true when the mouse is dragging to select text.
 	dragging bool
 
+	// Search related fields:
+
+	// The regular expression whose matches are highlighted. If nil, no search
+	// is active.
+	search *regexp.Regexp
+
+	// The start and end indices of all non-empty matches of the search
+	// expression. If nil, they need to be determined.
+	searchMatches [][]int
+
+	// The style of highlighted search matches.
+	matchStyle tcell.Style
+
+	// The input field of the search prompt. If nil, the prompt is not shown.
+	searchPrompt *InputField
+
+	// The text index at which the search prompt was opened.
+	searchStart int
+
+	// Syntax highlighting related fields:
+
+	// The syntax highlighter. If nil, no syntax highlighting is applied.
+	highlighter Highlighter
+
+	// The highlighting results of the first lines of the text. Lines are
+	// added as needed while drawing, they are removed when the text changes.
+	highlightLines []textAreaHighlightLine
+
+	// Gutter related fields:
+
+	// Whether or not line numbers are shown in a gutter left of the text.
+	showLineNumbers bool
+
+	// If set to true, line numbers are shown relative to the cursor's line.
+	relativeLineNumbers bool
+
+	// The style of the line numbers and of the cursor's line number.
+	lineNumberStyle, currentLineNumberStyle tcell.Style
+
+	// The number of lines (separated by newline characters) in the text. If 0,
+	// it needs to be determined.
+	lineCount int
+
+	// The width of the gutter the last time the text area was drawn.
+	gutterWidth int
+
+	// The line (starting at 0) each visible row belongs to the last time the
+	// text area was drawn or -1 if the row is a wrapped continuation of the
+	// line before it.
+	gutterLines []int
+
+	// An optional function which is called when the user clicks on the gutter.
+	gutterClicked func(line int)
+
+	// The lines of the text which changes are determined against or nil if
+	// change markers are not shown.
+	baseline []string
+
+	// The change state of each line of the current text. It is determined
+	// when needed and discarded when the text changes.
+	lineChanges []byte
+
+	// The styles of the markers for added, modified, and deleted lines.
+	addedStyle, modifiedStyle, deletedStyle tcell.Style
+
+	// Modal editing related fields:
+
+	// Whether or not modal editing is enabled.
+	modal bool
+
+	// The current editing mode.
+	mode TextAreaMode
+
+	// The count entered in front of a command in normal or visual mode. 0 if
+	// no count was entered.
+	modeCount int
+
+	// The keys of an incomplete command, e.g. "d" or "g".
+	modePending string
+
+	// Whether the clipboard text was copied as entire lines.
+	linewiseClipboard bool
+
+	// An optional function which is called when the editing mode has changed.
+	modeChanged func(mode TextAreaMode)
+
+	// If set to true, rows continuing a wrapped line are indented like the
+	// start of the line.
+	hangingIndent bool
+
+	// The rune shown at the start of rows continuing a wrapped line (0 for
+	// none) and its style.
+	wrapIndicator      rune
+	wrapIndicatorStyle tcell.Style
+
+	// Indentation related fields:
+
+	// If set to true, Enter copies the current line's indentation.
+	autoIndent bool
+
+	// The screen width of a tab character. If 0, [TabSize] is used.
+	tabSize int
+
+	// If set to true, Tab inserts spaces instead of a tab character.
+	softTabs bool
+
+	// Bracket related fields:
+
+	// Whether or not the bracket at the cursor and its matching bracket are
+	// highlighted.
+	matchBrackets bool
+
+	// The opening and closing brackets considered for bracket matching.
+	bracketPairs [][2]rune
+
+	// The style of matching brackets.
+	bracketMatchStyle tcell.Style
+
+	// The opening and closing characters which are closed automatically.
+	autoClosePairs [][2]rune
+
+	// Read-only regions related fields:
+
+	// The read-only regions as index positions of their start and end (a
+	// half-open interval) within the entire text, sorted by position. This
+	// slice is never modified, only replaced, so undo items can refer to it.
+	readOnly [][2]int
+
+	// An optional function which is called when an edit was rejected because
+	// it would have changed text in a read-only region.
+	editRejected func(start, end int)
+
+	// Multiple cursors related fields:
+
+	// Additional cursors besides the primary one, each given as the index
+	// positions of its selection start and of the cursor itself within the
+	// entire text.
+	extraCursors [][2]int
+
 	// Clipboard related fields:
 
 	// The internal clipboard.
@@ -332,11 +549,38 @@ type TextArea struct {
 	// been performed yet, this is the same as len(undoStack).
 	nextUndo int
 
+	// The maximum number of undo steps kept on the undo stack. If 0, there is
+	// no limit.
+	undoLimit int
+
+	// The nesting depth of [TextArea.GroupUndo] calls. If greater than 0, all
+	// undo items but the first of the group are marked as continuations.
+	undoGroupDepth int
+
+	// Whether the current undo group already started with an undo item.
+	undoGroupStarted bool
+
+	// Whether the changes made in insert mode continue the undo step of the
+	// text deleted by a modal "c" command.
+	modalChange bool
+
+	// The value of nextUndo at which the text is considered unmodified. If
+	// negative, the unmodified state cannot be reached via undo/redo anymore.
+	unmodifiedUndo int
+
+	// The modified state reported the last time the "modified" event was
+	// triggered.
+	modified bool
+
 	// Event handlers:
 
 	// An optional function which is called when the input has changed.
 	changed func()
 
+	// An optional function which is called when the text's modified state has
+	// changed.
+	modifiedFunc func(modified bool)
+
 	// An optional function which is called when the position of the cursor or
 	// the selection has changed.
 	moved func()
@@ -350,17 +594,25 @@ type TextArea struct {
 // initial text.
 func NewTextArea() *TextArea {
 	t := &TextArea{
-		Box:              NewBox(),
-		wrap:             true,
-		wordWrap:         true,
-		placeholderStyle: tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(Styles.TertiaryTextColor),
-		labelStyle:       tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
-		textStyle:        tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(Styles.PrimaryTextColor),
-		selectedStyle:    tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.PrimitiveBackgroundColor),
-		spans:            make([]textAreaSpan, 2, pieceChainMinCap), // We reserve some space to avoid reallocations right when editing starts.
-		lastAction:       taActionOther,
-		minCursorPrefix:  minCursorPrefixDefault,
-		minCursorSuffix:  minCursorSuffixDefault,
+		Box:                    NewBox(),
+		wrap:                   true,
+		wordWrap:               true,
+		placeholderStyle:       tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(Styles.TertiaryTextColor),
+		labelStyle:             tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
+		textStyle:              tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		selectedStyle:          tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.PrimitiveBackgroundColor),
+		matchStyle:             tcell.StyleDefault.Background(Styles.SecondaryTextColor).Foreground(Styles.PrimitiveBackgroundColor),
+		lineNumberStyle:        tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(Styles.TertiaryTextColor),
+		currentLineNumberStyle: tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		addedStyle:             tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(tcell.ColorGreen),
+		modifiedStyle:          tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(tcell.ColorYellow),
+		deletedStyle:           tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(tcell.ColorRed),
+		bracketPairs:           [][2]rune{{'(', ')'}, {'[', ']'}, {'{', '}'}},
+		bracketMatchStyle:      tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		spans:                  make([]textAreaSpan, 2, pieceChainMinCap), // We reserve some space to avoid reallocations right when editing starts.
+		lastAction:             taActionOther,
+		minCursorPrefix:        minCursorPrefixDefault,
+		minCursorSuffix:        minCursorSuffixDefault,
 	}
 	t.editText.Grow(editBufferMinCap)
 	t.spans[0] = textAreaSpan{previous: -1, next: 1}
@@ -388,6 +640,9 @@ func NewTextArea() *TextArea {
 func (t *TextArea) SetText(text string, cursorAtTheEnd bool) *TextArea {
 	t.spans = t.spans[:2]
 	t.initialText = text
+	t.extraCursors = nil
+	t.readOnly = nil
+	t.textChanged(0)
 	t.editText.Reset()
 	t.lineStarts = nil
 	t.length = len(text)
@@ -398,16 +653,40 @@ func (t *TextArea) SetText(text string, cursorAtTheEnd bool) *TextArea {
 	t.cursor.pos = [3]int{1, 0, -1}
 	t.undoStack = t.undoStack[:0]
 	t.nextUndo = 0
+	t.unmodifiedUndo = 0
+	t.modalChange = false
+	defer t.updateModified()
 
 	if len(text) > 0 {
-		t.spans = append(t.spans, textAreaSpan{
-			previous: 0,
-			next:     1,
-			offset:   0,
-			length:   -len(text),
-		})
+		// Long texts are split into multiple spans, preferably after newline
+		// characters, to speed up line lookups.
+		for offset := 0; offset < len(text); {
+			length := len(text) - offset
+			if length > initialSpanMaxSize {
+				length = initialSpanMaxSize
+				if newline := strings.LastIndexByte(text[offset:offset+length], '\n'); newline >= 0 {
+					length = newline + 1
+				} else {
+					for length > 0 && !utf8.RuneStart(text[offset+length]) {
+						length--
+					}
+					if length == 0 {
+						length = initialSpanMaxSize // Invalid UTF-8.
+					}
+				}
+			}
+			t.spans = append(t.spans, textAreaSpan{
+				previous: len(t.spans) - 1,
+				next:     len(t.spans) + 1,
+				offset:   offset,
+				length:   -length,
+			})
+			offset += length
+		}
+		t.spans[2].previous = 0
+		t.spans[len(t.spans)-1].next = 1
 		t.spans[0].next = 2
-		t.spans[1].previous = 2
+		t.spans[1].previous = len(t.spans) - 1
 		if cursorAtTheEnd {
 			t.cursor.row = -1
 			if t.lastWidth > 0 {
@@ -673,6 +952,14 @@ func (t *TextArea) Select(start, end int) *TextArea {
 		start, end = end, start
 	}
 
+	// Without a layout, only the span positions are known. Rows and columns
+	// are determined when the text area is drawn.
+	if t.lastWidth <= 0 {
+		t.selectionStart.row, t.selectionStart.pos = -1, t.positionOf(start)
+		t.cursor.row, t.cursor.pos = -1, t.positionOf(end)
+		return t
+	}
+
 	// Find the cursor positions.
 	var row, index int
 	t.cursor.row, t.cursor.pos = -1, [3]int{1, 0, -1}
@@ -741,11 +1028,14 @@ RowLoop:
 			index += len(cluster)
 			column += width
 		}
+		row++
 	}
 
 	if t.cursor.row < 0 {
 		t.findCursor(false, 0) // This only happens if we couldn't find the locations above.
-		t.selectionStart = t.cursor
+		if t.selectionStart.row < 0 {
+			t.selectionStart = t.cursor
+		}
 	}
 
 	return t
@@ -777,6 +1067,33 @@ func (t *TextArea) SetWordWrap(wrapOnWords bool) *TextArea {
 	return t
 }
 
+// SetHangingIndent sets the flag that, if true, causes rows which continue a
+// wrapped line to be indented by the width of the whitespace at the start of
+// that line (but by no more than half of the available width), as is common
+// for code or nested lists. This flag is ignored if the flag set with
+// [TextArea.SetWrap] is false.
+func (t *TextArea) SetHangingIndent(indent bool) *TextArea {
+	if t.hangingIndent != indent {
+		t.hangingIndent = indent
+		t.reset()
+	}
+	return t
+}
+
+// SetWrapIndicator sets a rune which is shown in the given style at the start
+// of each row that continues a wrapped line, e.g. '↪'. The text of these rows
+// is shifted to the right to make room for it. Set the rune to 0 to remove the
+// indicator (the default). The indicator is only shown if the flag set with
+// [TextArea.SetWrap] is true.
+func (t *TextArea) SetWrapIndicator(indicator rune, style tcell.Style) *TextArea {
+	t.wrapIndicatorStyle = style
+	if t.wrapIndicator != indicator {
+		t.wrapIndicator = indicator
+		t.reset()
+	}
+	return t
+}
+
 // SetPlaceholder sets the text to be displayed when the text area is empty.
 func (t *TextArea) SetPlaceholder(placeholder string) *TextArea {
 	t.placeholder = placeholder
@@ -911,124 +1228,911 @@ func (t *TextArea) SetOffset(row, column int) *TextArea {
 	return t
 }
 
-// SetClipboard allows you to implement your own clipboard by providing a
-// function that is called when the user wishes to store text in the clipboard
-// (copyToClipboard) and a function that is called when the user wishes to
-// retrieve text from the clipboard (pasteFromClipboard).
-//
-// Providing nil values will cause the default clipboard implementation to be
-// used.
-func (t *TextArea) SetClipboard(copyToClipboard func(string), pasteFromClipboard func() string) *TextArea {
-	t.copyToClipboard = copyToClipboard
-	if t.copyToClipboard == nil {
-		t.copyToClipboard = func(text string) {
-			t.clipboard = text
+// SetMatchStyle sets the style of text matching the search expression set with
+// [TextArea.SetSearch].
+func (t *TextArea) SetMatchStyle(style tcell.Style) *TextArea {
+	t.matchStyle = style
+	return t
+}
+
+// GetMatchStyle returns the style of text matching the search expression.
+func (t *TextArea) GetMatchStyle() tcell.Style {
+	return t.matchStyle
+}
+
+// Find returns the start and end index (as a half-open interval) of the first
+// non-empty match of the given regular expression which starts at or after
+// the index "from". If there is no such match, the search continues at the
+// start of the text. If there is no match at all, -1 is returned for both
+// values. Use [regexp.QuoteMeta] to search for literal text.
+func (t *TextArea) Find(pattern *regexp.Regexp, from int) (start, end int) {
+	matches := t.FindAll(pattern)
+	if len(matches) == 0 {
+		return -1, -1
+	}
+	for _, match := range matches {
+		if match[0] >= from {
+			return match[0], match[1]
 		}
 	}
+	return matches[0][0], matches[0][1]
+}
 
-	t.pasteFromClipboard = pasteFromClipboard
-	if t.pasteFromClipboard == nil {
-		t.pasteFromClipboard = func() string {
-			return t.clipboard
+// FindAll returns the start and end indices (as half-open intervals) of all
+// non-empty matches of the given regular expression in the text area's text.
+// Note that this function needs to allocate the entire text.
+func (t *TextArea) FindAll(pattern *regexp.Regexp) [][]int {
+	var matches [][]int
+	for _, match := range pattern.FindAllStringIndex(t.GetText(), -1) {
+		if match[0] < match[1] {
+			matches = append(matches, match)
 		}
 	}
+	return matches
+}
 
+// SetSearch sets a regular expression whose matches are highlighted with the
+// style set with [TextArea.SetMatchStyle]. Use [TextArea.NextMatch] and
+// [TextArea.PreviousMatch] to navigate the matches. Providing nil removes any
+// highlighting. Use [regexp.QuoteMeta] to search for literal text.
+func (t *TextArea) SetSearch(pattern *regexp.Regexp) *TextArea {
+	t.search = pattern
+	t.searchMatches = nil
 	return t
 }
 
-// SetChangedFunc sets a handler which is called whenever the text of the text
-// area has changed.
-func (t *TextArea) SetChangedFunc(handler func()) *TextArea {
-	t.changed = handler
+// GetSearch returns the regular expression set with [TextArea.SetSearch].
+func (t *TextArea) GetSearch() *regexp.Regexp {
+	return t.search
+}
+
+// GetMatchCount returns the number of matches of the search expression set
+// with [TextArea.SetSearch].
+func (t *TextArea) GetMatchCount() int {
+	return len(t.getMatches())
+}
+
+// NextMatch selects the next match of the search expression after the cursor
+// (or after the start of the current selection), wrapping around at the end of
+// the text. The text is scrolled such that the match is visible. Nothing
+// happens if there are no matches.
+func (t *TextArea) NextMatch() *TextArea {
+	_, start, end := t.GetSelection()
+	if start != end {
+		start++
+	}
+	t.selectMatch(start, false)
 	return t
 }
 
-// SetMovedFunc sets a handler which is called whenever the cursor position or
-// the text selection has changed.
-func (t *TextArea) SetMovedFunc(handler func()) *TextArea {
-	t.moved = handler
+// PreviousMatch selects the previous match of the search expression before the
+// cursor (or before the start of the current selection), wrapping around at
+// the start of the text. The text is scrolled such that the match is visible.
+// Nothing happens if there are no matches.
+func (t *TextArea) PreviousMatch() *TextArea {
+	_, start, _ := t.GetSelection()
+	t.selectMatch(start, true)
 	return t
 }
 
-// SetFinishedFunc sets a callback invoked when the user leaves this form item.
-func (t *TextArea) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
-	t.finished = handler
+// ReplaceMatch replaces the selected text with the replacement text if it is a
+// match of the search expression and then selects the next match. If the
+// selection is not a match, only the next match is selected. Inside the
+// replacement text, $ signs are interpreted as in [regexp.Regexp.Expand], e.g.
+// $1 stands for the text of the first submatch.
+func (t *TextArea) ReplaceMatch(replacement string) *TextArea {
+	if t.search == nil {
+		return t
+	}
+	text := t.GetText()
+	_, start, end := t.GetSelection()
+	for _, match := range t.search.FindAllStringSubmatchIndex(text, -1) {
+		if match[0] == start && match[1] == end && start < end {
+			expanded := t.search.ExpandString(nil, replacement, text, match)
+			t.Replace(start, end, string(expanded))
+			break
+		}
+	}
+	return t.NextMatch()
+}
+
+// ReplaceAll replaces all matches of the search expression with the
+// replacement text (see [TextArea.ReplaceMatch] for its format) and returns
+// the number of replacements. All replacements are undone in a single step.
+func (t *TextArea) ReplaceAll(replacement string) int {
+	if t.search == nil {
+		return 0
+	}
+
+	// Build the new text.
+	var (
+		newText          strings.Builder
+		last, count      int
+		cursor, newIndex int
+		matches          [][2]int
+		expansions       []string
+	)
+	text := t.GetText()
+	_, cursor, _ = t.GetSelection()
+	newIndex = cursor
+	for _, match := range t.search.FindAllStringSubmatchIndex(text, -1) {
+		if match[0] == match[1] || t.isReadOnly(match[0], match[1]) {
+			continue
+		}
+		expanded := t.search.ExpandString(nil, replacement, text, match)
+		matches = append(matches, [2]int{match[0], match[1]})
+		expansions = append(expansions, string(expanded))
+		newText.WriteString(text[last:match[0]])
+		newText.Write(expanded)
+		if match[1] <= cursor {
+			newIndex += len(expanded) - match[1] + match[0]
+		}
+		last = match[1]
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	newText.WriteString(text[last:])
+
+	// Replace the entire text, then restore the cursor. Read-only regions
+	// require each match to be replaced individually.
+	if len(t.readOnly) == 0 {
+		t.Replace(0, t.length, newText.String())
+	} else {
+		t.GroupUndo(func() {
+			for index := len(matches) - 1; index >= 0; index-- {
+				t.Replace(matches[index][0], matches[index][1], expansions[index])
+			}
+		})
+	}
+	t.Select(newIndex, newIndex)
+	t.findCursor(true, t.cursor.row)
+	return count
+}
+
+// OpenSearchPrompt opens a one-line prompt at the bottom of the text area in
+// which the user can enter text to search for (ignoring case). Matches are
+// highlighted while typing. While the prompt is open, the following keys are
+// available:
+//
+//   - Enter, Down arrow: Select the next match.
+//   - Up arrow: Select the previous match.
+//   - Escape: Close the prompt and remove the highlighting.
+//
+// No key opens the prompt by default. Applications can bind it to a key using
+// [Box.SetInputCapture].
+func (t *TextArea) OpenSearchPrompt() *TextArea {
+	if t.searchPrompt != nil {
+		return t
+	}
+	_, t.searchStart, _ = t.GetSelection()
+	t.searchPrompt = NewInputField().SetLabel("Search: ")
+	t.searchPrompt.SetChangedFunc(func(text string) {
+		if text == "" {
+			t.SetSearch(nil)
+			return
+		}
+		t.SetSearch(regexp.MustCompile("(?i)" + regexp.QuoteMeta(text)))
+		t.selectMatch(t.searchStart, false)
+	})
+	t.searchPrompt.Focus(nil)
 	return t
 }
 
-// Focus is called when this primitive receives focus.
-func (t *TextArea) Focus(delegate func(p Primitive)) {
-	// If we're part of a form and this item is disabled, there's nothing the
-	// user can do here so we're finished.
-	if t.finished != nil && t.disabled {
-		t.finished(-1)
-		return
+// CloseSearchPrompt closes the prompt opened with
+// [TextArea.OpenSearchPrompt] and removes the highlighting of search matches.
+func (t *TextArea) CloseSearchPrompt() *TextArea {
+	if t.searchPrompt != nil {
+		t.searchPrompt = nil
+		t.SetSearch(nil)
 	}
+	return t
+}
 
-	t.Box.Focus(delegate)
+// SetHighlighter sets a syntax highlighter which determines the styles of the
+// text (see [Highlighter]). Text which is not styled by the highlighter as well
+// as highlighted text whose style has no background color uses the style set
+// with [TextArea.SetTextStyle]. Selected text and search matches take
+// precedence over syntax highlighting. Provide nil to remove the highlighter.
+//
+// Lines are highlighted lazily while they are drawn. Highlighting results are
+// cached and only the lines from the first changed line onwards are
+// highlighted again after the text was edited.
+func (t *TextArea) SetHighlighter(highlighter Highlighter) *TextArea {
+	t.highlighter = highlighter
+	t.highlightLines = nil
+	return t
 }
 
-// SetFormAttributes sets attributes shared by all form items.
-func (t *TextArea) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
-	t.labelWidth = labelWidth
-	t.backgroundColor = bgColor
-	t.labelStyle = t.labelStyle.Foreground(labelColor)
-	t.textStyle = tcell.StyleDefault.Foreground(fieldTextColor).Background(fieldBgColor)
+// SetShowLineNumbers sets whether or not line numbers are shown in a gutter
+// left of the text. Line numbers count lines separated by newline characters,
+// i.e. a line wrapped onto multiple rows only has a number on its first row.
+// The gutter's width adjusts to the number of lines.
+func (t *TextArea) SetShowLineNumbers(show bool) *TextArea {
+	t.showLineNumbers = show
 	return t
 }
 
-// replace deletes a range of text and inserts the given text at that position.
-// If the resulting text would exceed the maximum length, the function does not
-// do anything. The function returns the end position of the deleted/inserted
-// range.
+// SetRelativeLineNumbers sets whether line numbers (see
+// [TextArea.SetShowLineNumbers]) are shown relative to the cursor's line. The
+// cursor's line will still show its absolute line number.
+func (t *TextArea) SetRelativeLineNumbers(relative bool) *TextArea {
+	t.relativeLineNumbers = relative
+	return t
+}
+
+// SetLineNumberStyle sets the style of the line numbers in the gutter and the
+// style of the line number of the line the cursor is in.
+func (t *TextArea) SetLineNumberStyle(style, current tcell.Style) *TextArea {
+	t.lineNumberStyle = style
+	t.currentLineNumberStyle = current
+	return t
+}
+
+// SetGutterClickedFunc sets a handler which is called when the user clicks on
+// the gutter, e.g. to set a breakpoint or a mark. The handler receives the line
+// (starting at 0) next to which the gutter was clicked.
+func (t *TextArea) SetGutterClickedFunc(handler func(line int)) *TextArea {
+	t.gutterClicked = handler
+	return t
+}
+
+// SetBaselineText sets a text which the text area's text is compared to, line
+// by line, e.g. the last saved version of a file. Lines which were added since
+// are then marked with a "+" in the gutter, modified lines with a "~", and
+// lines before which lines were deleted with a "-". Use
+// [TextArea.NextChange] and [TextArea.PreviousChange] to move between
+// changes.
 //
-// The function can hang if "deleteStart" is located after "deleteEnd".
+// Very large changes are not analyzed in detail. All lines in such a change
+// are then marked as modified.
+func (t *TextArea) SetBaselineText(text string) *TextArea {
+	t.baseline = strings.Split(text, "\n")
+	t.lineChanges = nil
+	return t
+}
+
+// ClearBaselineText removes the text set with [TextArea.SetBaselineText] and
+// with it the change markers.
+func (t *TextArea) ClearBaselineText() *TextArea {
+	t.baseline = nil
+	t.lineChanges = nil
+	return t
+}
+
+// SetChangeMarkerStyles sets the styles of the gutter markers for added,
+// modified, and deleted lines (see [TextArea.SetBaselineText]).
+func (t *TextArea) SetChangeMarkerStyles(added, modified, deleted tcell.Style) *TextArea {
+	t.addedStyle, t.modifiedStyle, t.deletedStyle = added, modified, deleted
+	return t
+}
+
+// NextChange moves the cursor to the start of the first changed line after the
+// current block of changed lines (see [TextArea.SetBaselineText]). Nothing
+// happens if there is no such line.
+func (t *TextArea) NextChange() *TextArea {
+	changes := t.getLineChanges()
+	for line := t.countNewlines(t.cursor.pos) + 1; line < len(changes); line++ {
+		if changes[line] != textAreaLineUnchanged && changes[line] != changes[line-1] {
+			t.moveToLine(line)
+			break
+		}
+	}
+	return t
+}
+
+// PreviousChange moves the cursor to the start of the closest block of changed
+// lines before the cursor (see [TextArea.SetBaselineText]). Nothing happens if
+// there is no such block.
+func (t *TextArea) PreviousChange() *TextArea {
+	changes := t.getLineChanges()
+	line := t.countNewlines(t.cursor.pos) - 1
+	if line >= len(changes) {
+		line = len(changes) - 1
+	}
+	for ; line >= 0; line-- {
+		if changes[line] != textAreaLineUnchanged && (line == 0 || changes[line] != changes[line-1]) {
+			t.moveToLine(line)
+			break
+		}
+	}
+	return t
+}
+
+// SetModalEditing enables or disables modal editing, similar to the "vi" text
+// editor. When enabled, the text area starts in normal mode where keys are
+// interpreted as commands. The following commands are available in normal
+// mode, most of them can be preceded by a count (e.g. "3w"):
 //
-// Undo events are always generated unless continuation is true and text is
-// either appended to the end of a span or a span is shortened at the beginning
-// or the end (and nothing else).
+//   - h, l, Left arrow, Right arrow: Move left or right within the line.
+//   - j, k, Down arrow, Up arrow: Move down or up one row.
+//   - w, b, e: Move to the start of the next word, the start of the current
+//     or previous word, or the end of the current or next word.
+//   - 0, $, Home, End: Move to the start or the end of the line.
+//   - gg, G: Move to the first or the last line or, with a count, to the
+//     line with that number.
+//   - d, y, c followed by a motion: Delete, copy (yank), or change the text
+//     covered by the motion. Doubling the operator (dd, yy, cc) operates on
+//     entire lines.
+//   - D, C, Y: Same as d$, c$, and yy.
+//   - x: Delete the character under the cursor.
+//   - p, P: Paste the clipboard text after or before the cursor (or the
+//     current line if entire lines were copied).
+//   - u, Ctrl-R: Undo or redo.
+//   - i, a, I, A, o, O: Switch to insert mode before or after the cursor, at
+//     the start or the end of the line, or on a new line below or above the
+//     current line.
+//   - v: Switch to visual mode.
 //
-// This function only modifies [TextArea.lineStarts] to update span references
-// but does not change it to reflect the new layout.
+// In visual mode, motions extend the selection and d, x, y, and c operate on
+// the selected text (including the character under the cursor). Escape or v
+// return to normal mode. In insert mode, the text area behaves as usual except
+// that Escape returns to normal mode.
 //
-// A "changed" event will be triggered.
-func (t *TextArea) replace(deleteStart, deleteEnd [3]int, insert string, continuation bool) [3]int {
-	// Maybe nothing needs to be done?
-	if deleteStart == deleteEnd && insert == "" || t.maxLength > 0 && len(insert) > 0 && t.length+len(insert) >= t.maxLength {
-		return deleteEnd
+// Lines are separated by newline characters. The j and k motions move by rows,
+// i.e. they honor wrapped lines. Disabling modal editing switches to insert
+// mode.
+func (t *TextArea) SetModalEditing(enabled bool) *TextArea {
+	t.modal = enabled
+	if enabled {
+		t.setMode(TextAreaModeNormal)
+	} else {
+		t.setMode(TextAreaModeInsert)
 	}
+	return t
+}
 
-	// Notify at the end.
-	if t.changed != nil {
-		defer t.changed()
+// SetMode sets the current editing mode. This has no effect if modal editing is
+// not enabled (see [TextArea.SetModalEditing]).
+func (t *TextArea) SetMode(mode TextAreaMode) *TextArea {
+	if t.modal {
+		t.setMode(mode)
 	}
+	return t
+}
 
-	// Handle a few cases where we don't put anything onto the undo stack for
-	// increased efficiency.
-	if continuation {
-		// Same action as the one before. An undo item was already generated for
-		// this block of (same) actions. We're also only changing one character.
-		switch {
-		case insert == "" && deleteStart[1] != 0 && deleteEnd[1] == 0:
-			// Simple backspace. Just shorten this span.
-			length := t.spans[deleteStart[0]].length
-			if length < 0 {
-				t.length -= -length - deleteStart[1]
-				length = -deleteStart[1]
-			} else {
-				t.length -= length - deleteStart[1]
-				length = deleteStart[1]
-			}
-			t.spans[deleteStart[0]].length = length
-			return deleteEnd
-		case insert == "" && deleteStart[1] == 0 && deleteEnd[1] != 0:
-			// Simple delete. Just clip the beginning of this span.
-			t.spans[deleteEnd[0]].offset += deleteEnd[1]
-			if t.spans[deleteEnd[0]].length < 0 {
-				t.spans[deleteEnd[0]].length += deleteEnd[1]
-			} else {
-				t.spans[deleteEnd[0]].length -= deleteEnd[1]
-			}
+// GetMode returns the current editing mode. This is always
+// [TextAreaModeInsert] if modal editing is not enabled.
+func (t *TextArea) GetMode() TextAreaMode {
+	return t.mode
+}
+
+// SetModeChangedFunc sets a handler which is called whenever the editing mode
+// has changed, e.g. to show the current mode in a status bar.
+func (t *TextArea) SetModeChangedFunc(handler func(mode TextAreaMode)) *TextArea {
+	t.modeChanged = handler
+	return t
+}
+
+// AddCursorAt adds an additional cursor at the given index position within the
+// entire text. While there are additional cursors, typing, deleting, and
+// pasting text applies to all cursors at the same time. Other keys (e.g.
+// navigation keys), Escape, and mouse clicks remove the additional cursors.
+// The user may also add cursors by clicking on the text while holding the Alt
+// key.
+//
+// Nothing happens if there already is a cursor at the given position.
+func (t *TextArea) AddCursorAt(index int) *TextArea {
+	if index < 0 {
+		index = 0
+	} else if index > t.length {
+		index = t.length
+	}
+	t.addCursor(index, index)
+	return t
+}
+
+// SelectNextOccurrence selects the word at the cursor position if no text is
+// selected. Otherwise, it looks for the next occurrence of the selected text
+// after all cursors, wrapping around at the end of the text, and selects it
+// with a new cursor which then becomes the primary cursor. The previous
+// selection is kept as an additional cursor. Calling this function repeatedly
+// and then typing replaces all occurrences at once.
+//
+// See [TextArea.AddCursorAt] for more information on additional cursors.
+func (t *TextArea) SelectNextOccurrence() *TextArea {
+	selected, start, end := t.GetSelection()
+	text := t.GetText()
+
+	// Select the current word.
+	if selected == "" {
+		isWord := func(r rune) bool {
+			return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || r == '_'
+		}
+		for start > 0 {
+			r, size := utf8.DecodeLastRuneInString(text[:start])
+			if !isWord(r) {
+				break
+			}
+			start -= size
+		}
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if !isWord(r) {
+				break
+			}
+			end += size
+		}
+		if start < end {
+			t.Select(start, end)
+			t.findCursor(true, t.cursor.row)
+		}
+		return t
+	}
+
+	// Find the next free occurrence.
+	last := end
+	occupied := func(index int) bool {
+		if index == start {
+			return true
+		}
+		for _, cursor := range t.extraCursors {
+			from := cursor[0]
+			if cursor[1] < from {
+				from = cursor[1]
+			}
+			if from == index {
+				return true
+			}
+		}
+		return false
+	}
+	for _, cursor := range t.extraCursors {
+		if cursor[0] > last {
+			last = cursor[0]
+		}
+		if cursor[1] > last {
+			last = cursor[1]
+		}
+	}
+	next := -1
+	for index := 0; index < len(text); {
+		found := strings.Index(text[index:], selected)
+		if found < 0 {
+			break
+		}
+		found += index
+		if !occupied(found) && (next < 0 || next < last && found >= last) {
+			next = found
+		}
+		index = found + len(selected)
+	}
+	if next < 0 {
+		return t // No other occurrences.
+	}
+
+	// Select it.
+	selectionStart, cursor := t.indexOf(t.selectionStart.pos), t.indexOf(t.cursor.pos)
+	t.Select(next, next+len(selected))
+	t.findCursor(true, t.cursor.row)
+	t.addCursor(selectionStart, cursor)
+	return t
+}
+
+// GetCursors returns the index positions of the selection start and of the
+// cursor within the entire text for each cursor, starting with the primary
+// cursor followed by any additional cursors (see [TextArea.AddCursorAt]). Both
+// index positions are the same if a cursor has no selection.
+func (t *TextArea) GetCursors() [][2]int {
+	cursors := make([][2]int, 0, len(t.extraCursors)+1)
+	cursors = append(cursors, [2]int{t.indexOf(t.selectionStart.pos), t.indexOf(t.cursor.pos)})
+	return append(cursors, t.extraCursors...)
+}
+
+// ClearCursors removes all additional cursors, leaving only the primary
+// cursor.
+func (t *TextArea) ClearCursors() *TextArea {
+	t.extraCursors = nil
+	return t
+}
+
+// SetAutoIndent sets the flag that, if true, causes newlines entered by the
+// user to be followed by the whitespace at the start of the current line, i.e.
+// new lines keep the indentation of the line before them.
+func (t *TextArea) SetAutoIndent(autoIndent bool) *TextArea {
+	t.autoIndent = autoIndent
+	return t
+}
+
+// SetTabSize sets the number of screen columns a tab character occupies. A
+// value of 0 (the default) causes the global [TabSize] to be used.
+func (t *TextArea) SetTabSize(size int) *TextArea {
+	if size < 0 {
+		size = 0
+	}
+	if t.tabSize != size {
+		t.tabSize = size
+		t.reset()
+	}
+	return t
+}
+
+// SetSoftTabs sets the flag that, if true, causes the Tab key to insert spaces
+// up to the next multiple of the tab size (see [TextArea.SetTabSize]) instead
+// of a tab character.
+func (t *TextArea) SetSoftTabs(softTabs bool) *TextArea {
+	t.softTabs = softTabs
+	return t
+}
+
+// SetMatchBrackets sets whether or not the bracket at the cursor (or, if there
+// is none, the bracket before the cursor) and its matching bracket are
+// highlighted using the style set with [TextArea.SetBracketMatchStyle]. Only
+// brackets in the visible part of the text are matched. The brackets are
+// defined with [TextArea.SetBracketPairs].
+func (t *TextArea) SetMatchBrackets(match bool) *TextArea {
+	t.matchBrackets = match
+	return t
+}
+
+// SetBracketPairs sets the brackets considered for bracket matching (see
+// [TextArea.SetMatchBrackets]). Each pair is a string consisting of the
+// opening and the closing bracket, e.g. "()". Pairs which don't consist of two
+// different characters are ignored. The default pairs are "()", "[]", and
+// "{}".
+func (t *TextArea) SetBracketPairs(pairs ...string) *TextArea {
+	t.bracketPairs = t.bracketPairs[:0]
+	for _, pair := range pairs {
+		if runes := []rune(pair); len(runes) == 2 && runes[0] != runes[1] {
+			t.bracketPairs = append(t.bracketPairs, [2]rune{runes[0], runes[1]})
+		}
+	}
+	return t
+}
+
+// SetBracketMatchStyle sets the style of matching brackets (see
+// [TextArea.SetMatchBrackets]).
+func (t *TextArea) SetBracketMatchStyle(style tcell.Style) *TextArea {
+	t.bracketMatchStyle = style
+	return t
+}
+
+// SetAutoClosePairs sets the pairs of characters for which the closing
+// character is inserted automatically when the user types the opening
+// character. Each pair is a string consisting of the opening and the closing
+// character, e.g. "()" or `""`. Other strings are ignored. If text is
+// selected, it is enclosed in the pair instead.
+//
+// Typing a closing character when it is already located at the cursor moves
+// the cursor past it. Backspace between an opening and its closing character
+// deletes both. Pairs with identical characters (quotes) are not closed
+// automatically after letters or digits.
+//
+// Automatic closing is disabled by default. Call this function without
+// arguments to disable it again.
+func (t *TextArea) SetAutoClosePairs(pairs ...string) *TextArea {
+	t.autoClosePairs = nil
+	for _, pair := range pairs {
+		if runes := []rune(pair); len(runes) == 2 {
+			t.autoClosePairs = append(t.autoClosePairs, [2]rune{runes[0], runes[1]})
+		}
+	}
+	return t
+}
+
+// AddReadOnlyRegion marks the text between the given index positions within
+// the entire text (a half-open interval) as read-only, e.g. to protect the
+// prompt of a REPL. Edits which would change text inside read-only regions
+// are rejected, regardless of whether they were made by the user or with
+// [TextArea.Replace]. Text can still be inserted at the boundaries of a
+// region. Regions move along with edits before them. Overlapping regions are
+// merged.
+//
+// As earlier changes could otherwise be undone into a read-only region, the
+// undo history is cleared. [TextArea.SetText] removes all read-only regions.
+func (t *TextArea) AddReadOnlyRegion(start, end int) *TextArea {
+	if start < 0 {
+		start = 0
+	}
+	if end > t.length {
+		end = t.length
+	}
+	if start >= end {
+		return t
+	}
+
+	// Insert the region, merging overlapping ones.
+	regions := make([][2]int, 0, len(t.readOnly)+1)
+	for _, region := range t.readOnly {
+		if region[1] <= start || region[0] >= end {
+			regions = append(regions, region)
+			continue
+		}
+		if region[0] < start {
+			start = region[0]
+		}
+		if region[1] > end {
+			end = region[1]
+		}
+	}
+	regions = append(regions, [2]int{start, end})
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i][0] < regions[j][0]
+	})
+	t.readOnly = regions
+
+	// Clear the undo history.
+	t.undoStack = t.undoStack[:0]
+	t.nextUndo = 0
+	if t.IsModified() {
+		t.unmodifiedUndo = -1
+	} else {
+		t.unmodifiedUndo = 0
+	}
+
+	return t
+}
+
+// GetReadOnlyRegions returns the start and end index positions (a half-open
+// interval) of all read-only regions, sorted by position. See
+// [TextArea.AddReadOnlyRegion].
+func (t *TextArea) GetReadOnlyRegions() [][2]int {
+	return append([][2]int(nil), t.readOnly...)
+}
+
+// ClearReadOnlyRegions removes all read-only regions. They are also removed
+// from the undo history so undoing or redoing changes will not restore them.
+func (t *TextArea) ClearReadOnlyRegions() *TextArea {
+	t.readOnly = nil
+	for index := range t.undoStack {
+		t.undoStack[index].readOnly = nil
+	}
+	return t
+}
+
+// SetEditRejectedFunc sets a handler which is called when an edit was rejected
+// because it would have changed text in a read-only region (see
+// [TextArea.AddReadOnlyRegion]). The handler receives the index positions of
+// the text that was to be replaced.
+func (t *TextArea) SetEditRejectedFunc(handler func(start, end int)) *TextArea {
+	t.editRejected = handler
+	return t
+}
+
+// SetClipboard allows you to implement your own clipboard by providing a
+// function that is called when the user wishes to store text in the clipboard
+// (copyToClipboard) and a function that is called when the user wishes to
+// retrieve text from the clipboard (pasteFromClipboard).
+//
+// Providing nil values will cause the default clipboard implementation to be
+// used.
+func (t *TextArea) SetClipboard(copyToClipboard func(string), pasteFromClipboard func() string) *TextArea {
+	t.copyToClipboard = copyToClipboard
+	if t.copyToClipboard == nil {
+		t.copyToClipboard = func(text string) {
+			t.clipboard = text
+		}
+	}
+
+	t.pasteFromClipboard = pasteFromClipboard
+	if t.pasteFromClipboard == nil {
+		t.pasteFromClipboard = func() string {
+			return t.clipboard
+		}
+	}
+
+	return t
+}
+
+// SetUndoLimit sets the maximum number of undo steps kept by the text area. A
+// value of 0 (the default) means there is no limit. If there are more undo
+// steps, the oldest ones are discarded.
+func (t *TextArea) SetUndoLimit(limit int) *TextArea {
+	t.undoLimit = limit
+	t.limitUndo()
+	return t
+}
+
+// Undo reverts the last change to the text, if there is one. Consecutive
+// changes of the same kind (e.g. typing a word) and changes made within
+// [TextArea.GroupUndo] are reverted together. This is the same as the user
+// pressing Ctrl-Z.
+func (t *TextArea) Undo() *TextArea {
+	if t.undo() && t.moved != nil {
+		t.moved()
+	}
+	return t
+}
+
+// Redo reapplies the last change reverted by [TextArea.Undo], if there is one.
+// This is the same as the user pressing Ctrl-Y.
+func (t *TextArea) Redo() *TextArea {
+	if t.redo() && t.moved != nil {
+		t.moved()
+	}
+	return t
+}
+
+// CanUndo returns true if there is a change which can be reverted with
+// [TextArea.Undo].
+func (t *TextArea) CanUndo() bool {
+	return t.nextUndo > 0
+}
+
+// CanRedo returns true if there is a change which can be reapplied with
+// [TextArea.Redo].
+func (t *TextArea) CanRedo() bool {
+	return t.nextUndo < len(t.undoStack)
+}
+
+// GroupUndo calls the provided function and combines all changes it makes to
+// the text (e.g. using [TextArea.Replace]) into a single undo step. Calls may
+// be nested, in which case the outermost call determines the undo step.
+func (t *TextArea) GroupUndo(changes func()) *TextArea {
+	if t.undoGroupDepth == 0 {
+		t.undoGroupStarted = false
+	}
+	t.undoGroupDepth++
+	defer func() {
+		t.undoGroupDepth--
+	}()
+	changes()
+	return t
+}
+
+// IsModified returns whether the text was changed since it was set with
+// [TextArea.SetText] or since [TextArea.SetModified] was last called with
+// "false". Undoing all changes makes the text unmodified again.
+func (t *TextArea) IsModified() bool {
+	return t.nextUndo != t.unmodifiedUndo
+}
+
+// SetModified sets the text's modified state. Setting it to false marks the
+// current text as unmodified, e.g. after it was saved to a file. Setting it to
+// true marks the text as modified until this function is called with "false"
+// again, regardless of any undos or redos. A "modified" event is triggered if
+// the state has changed.
+func (t *TextArea) SetModified(modified bool) *TextArea {
+	if modified {
+		t.unmodifiedUndo = -1
+	} else {
+		t.unmodifiedUndo = t.nextUndo
+	}
+	t.updateModified()
+	return t
+}
+
+// SetModifiedFunc sets a handler which is called whenever the text's modified
+// state (see [TextArea.IsModified]) has changed. It receives the new state.
+func (t *TextArea) SetModifiedFunc(handler func(modified bool)) *TextArea {
+	t.modifiedFunc = handler
+	return t
+}
+
+// SetChangedFunc sets a handler which is called whenever the text of the text
+// area has changed.
+func (t *TextArea) SetChangedFunc(handler func()) *TextArea {
+	t.changed = handler
+	return t
+}
+
+// SetMovedFunc sets a handler which is called whenever the cursor position or
+// the text selection has changed.
+func (t *TextArea) SetMovedFunc(handler func()) *TextArea {
+	t.moved = handler
+	return t
+}
+
+// SetFinishedFunc sets a callback invoked when the user leaves this form item.
+func (t *TextArea) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
+	t.finished = handler
+	return t
+}
+
+// Focus is called when this primitive receives focus.
+func (t *TextArea) Focus(delegate func(p Primitive)) {
+	// If we're part of a form and this item is disabled, there's nothing the
+	// user can do here so we're finished.
+	if t.finished != nil && t.disabled {
+		t.finished(-1)
+		return
+	}
+
+	t.Box.Focus(delegate)
+}
+
+// SetFormAttributes sets attributes shared by all form items.
+func (t *TextArea) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
+	t.labelWidth = labelWidth
+	t.backgroundColor = bgColor
+	t.labelStyle = t.labelStyle.Foreground(labelColor)
+	t.textStyle = tcell.StyleDefault.Foreground(fieldTextColor).Background(fieldBgColor)
+	return t
+}
+
+// replace deletes a range of text and inserts the given text at that position.
+// If the resulting text would exceed the maximum length, the function does not
+// do anything. The function returns the end position of the deleted/inserted
+// range.
+//
+// The function can hang if "deleteStart" is located after "deleteEnd".
+//
+// Undo events are always generated unless continuation is true and text is
+// either appended to the end of a span or a span is shortened at the beginning
+// or the end (and nothing else).
+//
+// This function only modifies [TextArea.lineStarts] to update span references
+// but does not change it to reflect the new layout.
+//
+// A "changed" event will be triggered.
+func (t *TextArea) replace(deleteStart, deleteEnd [3]int, insert string, continuation bool) [3]int {
+	// Maybe nothing needs to be done?
+	if deleteStart == deleteEnd && insert == "" || t.maxLength > 0 && len(insert) > 0 && t.length+len(insert) >= t.maxLength {
+		return deleteEnd
+	}
+
+	// Read-only regions must not be changed. The others need to be moved.
+	readOnly := t.readOnly
+	if len(readOnly) > 0 {
+		start, end := t.indexOf(deleteStart), t.indexOf(deleteEnd)
+		if t.isReadOnly(start, end) {
+			if t.editRejected != nil {
+				t.editRejected(start, end)
+			}
+			return deleteEnd
+		}
+		shift := len(insert) - end + start
+		t.readOnly = make([][2]int, len(readOnly))
+		for index, region := range readOnly {
+			if region[0] >= end {
+				region[0] += shift
+				region[1] += shift
+			}
+			t.readOnly[index] = region
+		}
+	}
+
+	// Notify at the end.
+	if t.changed != nil {
+		defer t.changed()
+	}
+	defer t.updateModified()
+	var changeIndex int
+	if len(t.highlightLines) > 0 {
+		changeIndex = t.indexOf(deleteStart) // Only needed to keep some highlighting results.
+	}
+	t.textChanged(changeIndex)
+
+	// Text inserted after a modal "c" command is part of the same change.
+	if t.modalChange {
+		continuation = true
+	}
+
+	// The first change after the unmodified state always starts a new undo
+	// step. Otherwise, we could not return to the unmodified state.
+	if t.nextUndo == t.unmodifiedUndo {
+		continuation = false
+	}
+
+	// Handle a few cases where we don't put anything onto the undo stack for
+	// increased efficiency.
+	if continuation {
+		// Same action as the one before. An undo item was already generated for
+		// this block of (same) actions. We're also only changing one character.
+		switch {
+		case insert == "" && deleteStart[1] != 0 && deleteEnd[1] == 0:
+			// Simple backspace. Just shorten this span.
+			length := t.spans[deleteStart[0]].length
+			if length < 0 {
+				t.length -= -length - deleteStart[1]
+				length = -deleteStart[1]
+			} else {
+				t.length -= length - deleteStart[1]
+				length = deleteStart[1]
+			}
+			t.spans[deleteStart[0]].length = length
+			t.spans[deleteStart[0]].newlines = 0
+			return deleteEnd
+		case insert == "" && deleteStart[1] == 0 && deleteEnd[1] != 0:
+			// Simple delete. Just clip the beginning of this span.
+			t.spans[deleteEnd[0]].offset += deleteEnd[1]
+			if t.spans[deleteEnd[0]].length < 0 {
+				t.spans[deleteEnd[0]].length += deleteEnd[1]
+			} else {
+				t.spans[deleteEnd[0]].length -= deleteEnd[1]
+			}
+			t.spans[deleteEnd[0]].newlines = 0
 			t.length -= deleteEnd[1]
 			deleteEnd[1] = 0
 			return deleteEnd
@@ -1039,111 +2143,781 @@ func (t *TextArea) replace(deleteStart, deleteEnd [3]int, insert string, continu
 				// Typing individual characters. Simply extend the edit buffer.
 				length, _ := t.editText.WriteString(insert)
 				t.spans[previous].length += length
+				t.spans[previous].newlines = 0
 				t.length += length
 				return deleteEnd
 			}
 		}
 	}
 
-	// All other cases generate an undo item.
-	before := t.spans[deleteStart[0]].previous
-	after := deleteEnd[0]
-	if deleteEnd[1] > 0 {
-		after = t.spans[deleteEnd[0]].next
+	// All other cases generate an undo item.
+	before := t.spans[deleteStart[0]].previous
+	after := deleteEnd[0]
+	if deleteEnd[1] > 0 {
+		after = t.spans[deleteEnd[0]].next
+	}
+	if t.undoGroupDepth > 0 {
+		// Changes in an undo group are undone together.
+		if t.undoGroupStarted {
+			continuation = true
+		}
+		t.undoGroupStarted = true
+	}
+	if t.unmodifiedUndo > t.nextUndo {
+		t.unmodifiedUndo = -1 // The unmodified state was in the redo part which we discard now.
+	}
+	t.undoStack = t.undoStack[:t.nextUndo]
+	t.undoStack = append(t.undoStack, textAreaUndoItem{
+		before:         len(t.spans),
+		after:          len(t.spans) + 1,
+		originalBefore: before,
+		originalAfter:  after,
+		length:         t.length,
+		readOnly:       readOnly,
+		pos:            t.cursor.pos,
+		continuation:   continuation,
+	})
+	t.spans = append(t.spans, t.spans[before])
+	t.spans = append(t.spans, t.spans[after])
+	t.nextUndo++
+	t.limitUndo()
+
+	// Adjust total text length by subtracting everything between "before" and
+	// "after". Inserted spans will be added back.
+	for index := deleteStart[0]; index != after; index = t.spans[index].next {
+		if t.spans[index].length < 0 {
+			t.length += t.spans[index].length
+		} else {
+			t.length -= t.spans[index].length
+		}
+	}
+	t.spans[before].next = after
+	t.spans[after].previous = before
+
+	// We go from left to right, connecting new spans as needed. We update
+	// "before" as the span to connect new spans to.
+
+	// If we start deleting in the middle of a span, connect a partial span.
+	if deleteStart[1] != 0 {
+		span := textAreaSpan{
+			previous: before,
+			next:     after,
+			offset:   t.spans[deleteStart[0]].offset,
+			length:   deleteStart[1],
+		}
+		if t.spans[deleteStart[0]].length < 0 {
+			span.length = -span.length
+		}
+		t.length += deleteStart[1] // This was previously subtracted.
+		t.spans[before].next = len(t.spans)
+		t.spans[after].previous = len(t.spans)
+		before = len(t.spans)
+		for row, lineStart := range t.lineStarts { // Also redirect line starts until the end of this new span.
+			if lineStart[0] == deleteStart[0] {
+				if lineStart[1] >= deleteStart[1] {
+					t.lineStarts = t.lineStarts[:row] // Everything else is unknown at this point.
+					break
+				}
+				t.lineStarts[row][0] = len(t.spans)
+			}
+		}
+		t.spans = append(t.spans, span)
+	}
+
+	// If we insert text, connect a new span.
+	if insert != "" {
+		span := textAreaSpan{
+			previous: before,
+			next:     after,
+			offset:   t.editText.Len(),
+		}
+		span.length, _ = t.editText.WriteString(insert)
+		t.length += span.length
+		t.spans[before].next = len(t.spans)
+		t.spans[after].previous = len(t.spans)
+		before = len(t.spans)
+		t.spans = append(t.spans, span)
+	}
+
+	// If we stop deleting in the middle of a span, connect a partial span.
+	if deleteEnd[1] != 0 {
+		span := textAreaSpan{
+			previous: before,
+			next:     after,
+			offset:   t.spans[deleteEnd[0]].offset + deleteEnd[1],
+		}
+		length := t.spans[deleteEnd[0]].length
+		if length < 0 {
+			span.length = length + deleteEnd[1]
+			t.length -= span.length // This was previously subtracted.
+		} else {
+			span.length = length - deleteEnd[1]
+			t.length += span.length // This was previously subtracted.
+		}
+		t.spans[before].next = len(t.spans)
+		t.spans[after].previous = len(t.spans)
+		deleteEnd[0], deleteEnd[1] = len(t.spans), 0
+		t.spans = append(t.spans, span)
+	}
+
+	return deleteEnd
+}
+
+// undo reverts the last undo step, if there is one. It returns true if the
+// text was changed. A "changed" event will be triggered in that case.
+func (t *TextArea) undo() bool {
+	t.modalChange = false
+	if t.nextUndo <= 0 {
+		return false
+	}
+	var changed []int
+	for t.nextUndo > 0 {
+		t.nextUndo--
+		undo := t.undoStack[t.nextUndo]
+		changed = append(changed, undo.originalBefore)
+		t.spans[undo.originalBefore], t.spans[undo.before] = t.spans[undo.before], t.spans[undo.originalBefore]
+		t.spans[undo.originalAfter], t.spans[undo.after] = t.spans[undo.after], t.spans[undo.originalAfter]
+		t.cursor.pos, t.undoStack[t.nextUndo].pos = undo.pos, t.cursor.pos
+		t.length, t.undoStack[t.nextUndo].length = undo.length, t.length
+		t.readOnly, t.undoStack[t.nextUndo].readOnly = undo.readOnly, t.readOnly
+		if !undo.continuation {
+			break
+		}
+	}
+	t.textChanged(0)
+	t.cursor.row = -1
+	t.truncateChangedLines(changed)
+	t.findCursor(true, 0)
+	t.selectionStart = t.cursor
+	t.updateModified()
+	if t.changed != nil {
+		t.changed()
+	}
+	return true
+}
+
+// redo reapplies the last undo step reverted by [TextArea.undo], if there is
+// one. It returns true if the text was changed. A "changed" event will be
+// triggered in that case.
+func (t *TextArea) redo() bool {
+	t.modalChange = false
+	if t.nextUndo >= len(t.undoStack) {
+		return false
+	}
+	var changed []int
+	for t.nextUndo < len(t.undoStack) {
+		undo := t.undoStack[t.nextUndo]
+		changed = append(changed, undo.originalBefore)
+		t.spans[undo.originalBefore], t.spans[undo.before] = t.spans[undo.before], t.spans[undo.originalBefore]
+		t.spans[undo.originalAfter], t.spans[undo.after] = t.spans[undo.after], t.spans[undo.originalAfter]
+		t.cursor.pos, t.undoStack[t.nextUndo].pos = undo.pos, t.cursor.pos
+		t.length, t.undoStack[t.nextUndo].length = undo.length, t.length
+		t.readOnly, t.undoStack[t.nextUndo].readOnly = undo.readOnly, t.readOnly
+		t.nextUndo++
+		if t.nextUndo < len(t.undoStack) && !t.undoStack[t.nextUndo].continuation {
+			break
+		}
+	}
+	t.textChanged(0)
+	t.cursor.row = -1
+	t.truncateChangedLines(changed)
+	t.findCursor(true, 0)
+	t.selectionStart = t.cursor
+	t.updateModified()
+	if t.changed != nil {
+		t.changed()
+	}
+	return true
+}
+
+// limitUndo removes the oldest undo steps from the undo stack such that no
+// more than [TextArea.undoLimit] steps can be undone. The copied spans of
+// removed items remain in the piece chain.
+func (t *TextArea) limitUndo() {
+	if t.undoLimit <= 0 {
+		return
+	}
+
+	// Find the first item of each undo step.
+	var starts []int
+	for index := 0; index < t.nextUndo; index++ {
+		if index == 0 || !t.undoStack[index].continuation {
+			starts = append(starts, index)
+		}
+	}
+	if len(starts) <= t.undoLimit {
+		return
+	}
+
+	// Remove the oldest steps.
+	remove := starts[len(starts)-t.undoLimit]
+	t.undoStack = append(t.undoStack[:0], t.undoStack[remove:]...)
+	t.nextUndo -= remove
+	if t.unmodifiedUndo >= 0 {
+		t.unmodifiedUndo -= remove
+		if t.unmodifiedUndo < 0 {
+			t.unmodifiedUndo = -1 // The unmodified state is gone.
+		}
+	}
+}
+
+// getMatches returns the start and end indices of all non-empty matches of the
+// search expression. The result is cached until the text changes.
+func (t *TextArea) getMatches() [][]int {
+	if t.search == nil {
+		return nil
+	}
+	if t.searchMatches == nil {
+		t.searchMatches = t.FindAll(t.search)
+		if t.searchMatches == nil {
+			t.searchMatches = [][]int{} // Don't search again.
+		}
+	}
+	return t.searchMatches
+}
+
+// selectMatch selects the first match of the search expression which starts
+// at or after the given index (or the last one which starts before it if
+// backwards is true), wrapping around if necessary, and scrolls it into view.
+func (t *TextArea) selectMatch(index int, backwards bool) {
+	matches := t.getMatches()
+	if len(matches) == 0 {
+		return
+	}
+	match := matches[0]
+	if backwards {
+		match = matches[len(matches)-1]
+		for i := len(matches) - 1; i >= 0; i-- {
+			if matches[i][0] < index {
+				match = matches[i]
+				break
+			}
+		}
+	} else {
+		for _, m := range matches {
+			if m[0] >= index {
+				match = m
+				break
+			}
+		}
+	}
+	t.Select(match[0], match[1])
+	t.findCursor(true, t.cursor.row)
+}
+
+// textChanged discards any cached information which depends on the text
+// starting at the given text index.
+func (t *TextArea) textChanged(index int) {
+	t.chainIndex = nil
+	t.lineChanges = nil
+	t.searchMatches = nil
+	t.lineCount = 0
+	t.truncateHighlighting(index)
+}
+
+// runeAt returns the rune starting at the given index position within the
+// entire text and its size in bytes. It returns utf8.RuneError and a size of 0
+// at the end of the text.
+func (t *TextArea) runeAt(index int) (rune, int) {
+	if index < 0 || index >= t.length {
+		return utf8.RuneError, 0
+	}
+	pos := t.positionOf(index)
+	return utf8.DecodeRuneInString(t.spanText(pos[0])[pos[1]:])
+}
+
+// runeBefore returns the rune ending at the given index position within the
+// entire text and its size in bytes. It returns utf8.RuneError and a size of 0
+// at the start of the text.
+func (t *TextArea) runeBefore(index int) (rune, int) {
+	if index <= 0 || index > t.length {
+		return utf8.RuneError, 0
+	}
+	pos := t.positionOf(index)
+	if pos[1] == 0 {
+		return utf8.DecodeLastRuneInString(t.spanText(t.spans[pos[0]].previous))
+	}
+	return utf8.DecodeLastRuneInString(t.spanText(pos[0])[:pos[1]])
+}
+
+// findMatchingBracket returns the index positions of the bracket at the cursor
+// (or before it) and of its matching bracket. The matching bracket is only
+// searched for between the given index positions. If no matching bracket is
+// found, -1 is returned for both.
+func (t *TextArea) findMatchingBracket(from, to int) (bracket, match int) {
+	cursor := t.indexOf(t.cursor.pos)
+	for _, before := range []bool{false, true} {
+		r, size := t.runeAt(cursor)
+		bracket = cursor
+		if before {
+			r, size = t.runeBefore(cursor)
+			bracket = cursor - size
+		}
+		if size == 0 {
+			continue
+		}
+		for _, pair := range t.bracketPairs {
+			var depth int
+			if r == pair[0] {
+				// Search forward.
+				for index := bracket; index < to; {
+					r, size := t.runeAt(index)
+					if r == pair[0] {
+						depth++
+					} else if r == pair[1] {
+						depth--
+						if depth == 0 {
+							return bracket, index
+						}
+					}
+					index += size
+				}
+				return -1, -1
+			} else if r == pair[1] {
+				// Search backward.
+				for index := bracket + size; index > from; {
+					r, size := t.runeBefore(index)
+					index -= size
+					if r == pair[1] {
+						depth++
+					} else if r == pair[0] {
+						depth--
+						if depth == 0 {
+							return bracket, index
+						}
+					}
+				}
+				return -1, -1
+			}
+		}
+	}
+	return -1, -1
+}
+
+// autoClose handles the typing of the given rune if it is part of one of the
+// pairs set with [TextArea.SetAutoClosePairs]. It returns true if it was
+// handled.
+func (t *TextArea) autoClose(r rune) bool {
+	_, from, to := t.GetSelection()
+	for _, pair := range t.autoClosePairs {
+		// Skip over closing characters.
+		if next, _ := t.runeAt(to); r == pair[1] && from == to && next == pair[1] {
+			t.Select(to+utf8.RuneLen(next), to+utf8.RuneLen(next))
+			t.findCursor(true, t.cursor.row)
+			return true
+		}
+		if r != pair[0] {
+			continue
+		}
+
+		// Insert the pair.
+		if previous, _ := t.runeBefore(from); pair[0] == pair[1] && from == to && (unicode.IsLetter(previous) || unicode.IsDigit(previous)) {
+			return false
+		}
+		selected := t.getSelectedText()
+		opening := string(pair[0])
+		t.Replace(from, to, opening+selected+string(pair[1]))
+		t.Select(from+len(opening), from+len(opening)+len(selected))
+		t.findCursor(true, t.cursor.row)
+		return true
+	}
+	return false
+}
+
+// moveToLine places the cursor at the start of the line (separated by newline
+// characters) with the given number and removes any selection.
+func (t *TextArea) moveToLine(line int) {
+	index := t.indexOf(t.lineStartPos(line))
+	t.Select(index, index)
+	t.findCursor(true, t.cursor.row)
+}
+
+// getLineChanges returns [TextArea.lineChanges], determining it first if
+// needed. It returns nil if there is no baseline text.
+func (t *TextArea) getLineChanges() []byte {
+	if t.baseline == nil || t.lineChanges != nil {
+		return t.lineChanges
+	}
+	t.lineChanges = diffLines(t.baseline, strings.Split(t.GetText(), "\n"))
+	return t.lineChanges
+}
+
+// diffLines compares two texts, given as their lines, and returns the change
+// state of each new line (one of the textAreaLine constants).
+func diffLines(oldLines, newLines []string) []byte {
+	changes := make([]byte, len(newLines))
+
+	// Skip common lines at the start and at the end.
+	var prefix, suffix int
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix && oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+	oldLines, newLines = oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix]
+	mark := func(line int, state byte) {
+		if line >= len(changes) {
+			line = len(changes) - 1 // Deletions at the end are shown on the last line.
+		}
+		if line >= 0 && changes[line] == textAreaLineUnchanged {
+			changes[line] = state
+		}
+	}
+
+	// Changes which are too large are not analyzed further.
+	if len(oldLines)*len(newLines) > 1<<20 {
+		for line := range newLines {
+			mark(prefix+line, textAreaLineModified)
+		}
+		return changes
+	}
+
+	// Determine the lengths of the longest common subsequences of all suffixes.
+	columns := len(newLines) + 1
+	lengths := make([]int32, (len(oldLines)+1)*columns)
+	for o := len(oldLines) - 1; o >= 0; o-- {
+		for n := len(newLines) - 1; n >= 0; n-- {
+			if oldLines[o] == newLines[n] {
+				lengths[o*columns+n] = lengths[(o+1)*columns+n+1] + 1
+			} else if lengths[(o+1)*columns+n] >= lengths[o*columns+n+1] {
+				lengths[o*columns+n] = lengths[(o+1)*columns+n]
+			} else {
+				lengths[o*columns+n] = lengths[o*columns+n+1]
+			}
+		}
+	}
+
+	// Walk along the longest common subsequence, classifying the changes in
+	// between.
+	var o, n, deleted, inserted int
+	flush := func() {
+		switch {
+		case deleted > 0 && inserted > 0:
+			for line := n - inserted; line < n; line++ {
+				mark(prefix+line, textAreaLineModified)
+			}
+		case inserted > 0:
+			for line := n - inserted; line < n; line++ {
+				mark(prefix+line, textAreaLineAdded)
+			}
+		case deleted > 0:
+			mark(prefix+n, textAreaLineDeleted)
+		}
+		deleted, inserted = 0, 0
 	}
-	t.undoStack = t.undoStack[:t.nextUndo]
-	t.undoStack = append(t.undoStack, textAreaUndoItem{
-		before:         len(t.spans),
-		after:          len(t.spans) + 1,
-		originalBefore: before,
-		originalAfter:  after,
-		length:         t.length,
-		pos:            t.cursor.pos,
-		continuation:   continuation,
-	})
-	t.spans = append(t.spans, t.spans[before])
-	t.spans = append(t.spans, t.spans[after])
-	t.nextUndo++
+	for o < len(oldLines) || n < len(newLines) {
+		switch {
+		case o < len(oldLines) && n < len(newLines) && oldLines[o] == newLines[n]:
+			flush()
+			o++
+			n++
+		case n >= len(newLines) || o < len(oldLines) && lengths[(o+1)*columns+n] >= lengths[o*columns+n+1]:
+			deleted++
+			o++
+		default:
+			inserted++
+			n++
+		}
+	}
+	flush()
 
-	// Adjust total text length by subtracting everything between "before" and
-	// "after". Inserted spans will be added back.
-	for index := deleteStart[0]; index != after; index = t.spans[index].next {
-		if t.spans[index].length < 0 {
-			t.length += t.spans[index].length
-		} else {
-			t.length -= t.spans[index].length
+	return changes
+}
+
+// getTabSize returns the screen width of a tab character.
+func (t *TextArea) getTabSize() int {
+	if t.tabSize > 0 {
+		return t.tabSize
+	}
+	return TabSize
+}
+
+// lineBefore returns the text between the start of the line (separated by
+// newline characters) containing the given span position and that position.
+func (t *TextArea) lineBefore(pos [3]int) string {
+	start := t.indexOf(t.lineStartPos(t.countNewlines(pos)))
+	line, _ := t.getLine(start)
+	if length := t.indexOf(pos) - start; length < len(line) {
+		line = line[:length]
+	}
+	return line
+}
+
+// indentation returns the text to be inserted when the user presses Enter,
+// given the text between the start of the current line and the cursor.
+func (t *TextArea) indentation(lineBefore string) string {
+	if !t.autoIndent {
+		return NewLine
+	}
+	return NewLine + lineBefore[:len(lineBefore)-len(strings.TrimLeft(lineBefore, " \t"))]
+}
+
+// tab returns the text to be inserted when the user presses Tab, given the text
+// between the start of the current line and the cursor.
+func (t *TextArea) tab(lineBefore string) string {
+	if !t.softTabs {
+		return "\t"
+	}
+	size := t.getTabSize()
+	if size <= 0 {
+		size = 1
+	}
+	width := uniseg.StringWidth(lineBefore) + strings.Count(lineBefore, "\t")*size
+	return strings.Repeat(" ", size-width%size)
+}
+
+// rowIndent returns the number of screen columns by which the text of the given
+// row is shifted to the right because it continues a wrapped line. See
+// [TextArea.SetHangingIndent] and [TextArea.SetWrapIndicator].
+func (t *TextArea) rowIndent(row int) int {
+	if !t.wrap || !t.hangingIndent && t.wrapIndicator == 0 || row <= 0 || row >= len(t.lineStarts) || t.afterNewline(t.lineStarts[row]) {
+		return 0
+	}
+
+	// Measure the whitespace at the start of the line.
+	var indent int
+	if t.hangingIndent {
+		first := row - 1
+		for first > 0 && !t.afterNewline(t.lineStarts[first]) {
+			first--
+		}
+		pos := t.lineStarts[first]
+		endPos := pos
+		var (
+			cluster, text string
+			width         int
+		)
+		for pos[0] != 1 && pos != t.lineStarts[row] {
+			cluster, text, _, width, pos, endPos = t.step(text, pos, endPos)
+			if cluster != " " && cluster != "\t" {
+				break
+			}
+			indent += width
 		}
 	}
-	t.spans[before].next = after
-	t.spans[after].previous = before
 
-	// We go from left to right, connecting new spans as needed. We update
-	// "before" as the span to connect new spans to.
+	if t.wrapIndicator != 0 {
+		indent += uniseg.StringWidth(string(t.wrapIndicator))
+	}
+	if indent > t.lastWidth/2 {
+		indent = t.lastWidth / 2
+	}
+	return indent
+}
 
-	// If we start deleting in the middle of a span, connect a partial span.
-	if deleteStart[1] != 0 {
-		span := textAreaSpan{
-			previous: before,
-			next:     after,
-			offset:   t.spans[deleteStart[0]].offset,
-			length:   deleteStart[1],
+// isReadOnly returns whether replacing the text between the given index
+// positions would change text in a read-only region.
+func (t *TextArea) isReadOnly(start, end int) bool {
+	for _, region := range t.readOnly {
+		if start < region[1] && end > region[0] || start == end && start > region[0] && start < region[1] {
+			return true
 		}
-		if t.spans[deleteStart[0]].length < 0 {
-			span.length = -span.length
+	}
+	return false
+}
+
+// countNewlines returns the number of newline characters before the given span
+// position.
+func (t *TextArea) countNewlines(pos [3]int) int {
+	entry := t.getChainEntry(pos[0])
+	if entry.span == 1 {
+		return entry.newlines
+	}
+	return entry.newlines + strings.Count(t.spanText(pos[0])[:pos[1]], "\n")
+}
+
+// afterNewline returns whether the given span position is at the start of the
+// text or immediately after a newline character.
+func (t *TextArea) afterNewline(pos [3]int) bool {
+	if pos[1] > 0 {
+		return t.spanText(pos[0])[pos[1]-1] == '\n'
+	}
+	previous := t.spans[pos[0]].previous
+	if previous <= 0 {
+		return true
+	}
+	text := t.spanText(previous)
+	return text[len(text)-1] == '\n'
+}
+
+// getHighlightLine returns the index into [TextArea.highlightLines] of the line
+// which contains the text index, highlighting lines as needed. It returns -1 if
+// the index is at or beyond the end of the text.
+func (t *TextArea) getHighlightLine(index int) int {
+	// Highlight more lines if needed.
+	for len(t.highlightLines) == 0 || index >= t.highlightLines[len(t.highlightLines)-1].start+t.highlightLines[len(t.highlightLines)-1].length {
+		var (
+			start int
+			state any
+		)
+		if len(t.highlightLines) > 0 {
+			last := t.highlightLines[len(t.highlightLines)-1]
+			start, state = last.start+last.length, last.state
 		}
-		t.length += deleteStart[1] // This was previously subtracted.
-		t.spans[before].next = len(t.spans)
-		t.spans[after].previous = len(t.spans)
-		before = len(t.spans)
-		for row, lineStart := range t.lineStarts { // Also redirect line starts until the end of this new span.
-			if lineStart[0] == deleteStart[0] {
-				if lineStart[1] >= deleteStart[1] {
-					t.lineStarts = t.lineStarts[:row] // Everything else is unknown at this point.
-					break
-				}
-				t.lineStarts[row][0] = len(t.spans)
-			}
+		if start >= t.length {
+			return -1
 		}
-		t.spans = append(t.spans, span)
+		line, length := t.getLine(start)
+		spans, state := t.highlighter.Highlight(line, state)
+		t.highlightLines = append(t.highlightLines, textAreaHighlightLine{
+			start:  start,
+			length: length,
+			spans:  spans,
+			state:  state,
+		})
 	}
 
-	// If we insert text, connect a new span.
-	if insert != "" {
-		span := textAreaSpan{
-			previous: before,
-			next:     after,
-			offset:   t.editText.Len(),
+	// Find the line.
+	low, high := 0, len(t.highlightLines)-1
+	for low < high {
+		middle := (low + high + 1) / 2
+		if t.highlightLines[middle].start <= index {
+			low = middle
+		} else {
+			high = middle - 1
 		}
-		span.length, _ = t.editText.WriteString(insert)
-		t.length += span.length
-		t.spans[before].next = len(t.spans)
-		t.spans[after].previous = len(t.spans)
-		before = len(t.spans)
-		t.spans = append(t.spans, span)
 	}
+	return low
+}
+
+// truncateHighlighting removes the cached highlighting results of the line
+// containing the given text index and of all lines after it.
+func (t *TextArea) truncateHighlighting(index int) {
+	for len(t.highlightLines) > 0 && t.highlightLines[len(t.highlightLines)-1].start+t.highlightLines[len(t.highlightLines)-1].length >= index {
+		t.highlightLines = t.highlightLines[:len(t.highlightLines)-1]
+	}
+}
 
-	// If we stop deleting in the middle of a span, connect a partial span.
-	if deleteEnd[1] != 0 {
-		span := textAreaSpan{
-			previous: before,
-			next:     after,
-			offset:   t.spans[deleteEnd[0]].offset + deleteEnd[1],
+// getLine returns the text starting at the given text index up until the next
+// newline character (exclusive, and without any trailing carriage return) as
+// well as the length of that text including the newline character.
+func (t *TextArea) getLine(start int) (line string, length int) {
+	var text strings.Builder
+	pos := t.positionOf(start)
+	for span := pos[0]; span != 1; span = t.spans[span].next {
+		spanText := t.spanText(span)
+		if span == pos[0] {
+			spanText = spanText[pos[1]:]
 		}
-		length := t.spans[deleteEnd[0]].length
-		if length < 0 {
-			span.length = length + deleteEnd[1]
-			t.length -= span.length // This was previously subtracted.
+		if newline := strings.IndexByte(spanText, '\n'); newline >= 0 {
+			text.WriteString(spanText[:newline])
+			length = text.Len() + 1
+			return strings.TrimSuffix(text.String(), "\r"), length
+		}
+		text.WriteString(spanText)
+	}
+	return text.String(), text.Len()
+}
+
+// spanText returns the text referenced by the span with the given index.
+func (t *TextArea) spanText(span int) string {
+	s := t.spans[span]
+	if s.length < 0 {
+		return t.initialText[s.offset : s.offset-s.length]
+	}
+	return t.editText.String()[s.offset : s.offset+s.length]
+}
+
+// indexOf returns the index of the given span position within the entire
+// text.
+func (t *TextArea) indexOf(pos [3]int) int {
+	return t.getChainEntry(pos[0]).start + pos[1]
+}
+
+// positionOf returns the span position (with an unknown state) of the given
+// index within the entire text. Indices at or beyond the end of the text
+// result in the end position.
+func (t *TextArea) positionOf(index int) [3]int {
+	if index >= t.length {
+		return [3]int{1, 0, -1}
+	}
+	if index < 0 {
+		index = 0
+	}
+	chain := t.getChainIndex()
+	low, high := 0, len(chain)-2 // The last entry is the end sentinel.
+	for low < high {
+		middle := (low + high + 1) / 2
+		if chain[middle].start <= index {
+			low = middle
 		} else {
-			span.length = length - deleteEnd[1]
-			t.length += span.length // This was previously subtracted.
+			high = middle - 1
 		}
-		t.spans[before].next = len(t.spans)
-		t.spans[after].previous = len(t.spans)
-		deleteEnd[0], deleteEnd[1] = len(t.spans), 0
-		t.spans = append(t.spans, span)
+	}
+	return [3]int{chain[low].span, index - chain[low].start, -1}
+}
+
+// distance returns the number of bytes between two span positions where "to"
+// must not be located before "from".
+func (t *TextArea) distance(from, to [3]int) int {
+	return t.indexOf(to) - t.indexOf(from)
+}
+
+// getChainIndex returns [TextArea.chainIndex], building it first if needed.
+// This requires iterating over the piece chain but newline characters only
+// need to be counted for new spans.
+func (t *TextArea) getChainIndex() []textAreaChainEntry {
+	if t.chainIndex != nil {
+		return t.chainIndex
 	}
 
-	return deleteEnd
+	if cap(t.chainPositions) < len(t.spans) {
+		t.chainPositions = make([]int, len(t.spans), cap(t.spans))
+	}
+	t.chainPositions = t.chainPositions[:len(t.spans)]
+	for index := range t.chainPositions {
+		t.chainPositions[index] = -1
+	}
+
+	var index, newlines int
+	for span := t.spans[0].next; span != 1; span = t.spans[span].next {
+		t.chainPositions[span] = len(t.chainIndex)
+		t.chainIndex = append(t.chainIndex, textAreaChainEntry{
+			span:     span,
+			start:    index,
+			newlines: newlines,
+		})
+		if t.spans[span].newlines == 0 {
+			t.spans[span].newlines = strings.Count(t.spanText(span), "\n") + 1
+		}
+		length := t.spans[span].length
+		if length < 0 {
+			length = -length
+		}
+		index += length
+		newlines += t.spans[span].newlines - 1
+	}
+	t.chainPositions[1] = len(t.chainIndex)
+	t.chainIndex = append(t.chainIndex, textAreaChainEntry{
+		span:     1,
+		start:    index,
+		newlines: newlines,
+	})
+
+	return t.chainIndex
+}
+
+// getChainEntry returns the [TextArea.chainIndex] entry of the span with the
+// given index. Spans which are not part of the piece chain result in the entry
+// of the end sentinel span.
+func (t *TextArea) getChainEntry(span int) textAreaChainEntry {
+	chain := t.getChainIndex()
+	if span < 0 || span >= len(t.chainPositions) || t.chainPositions[span] < 0 {
+		return chain[len(chain)-1]
+	}
+	return chain[t.chainPositions[span]]
+}
+
+// updateModified triggers a "modified" event if the text's modified state
+// differs from the one last reported.
+func (t *TextArea) updateModified() {
+	if modified := t.IsModified(); modified != t.modified {
+		t.modified = modified
+		if t.modifiedFunc != nil {
+			t.modifiedFunc(modified)
+		}
+	}
 }
 
 // Draw draws this primitive onto the screen.
@@ -1188,6 +2962,72 @@ func (t *TextArea) Draw(screen tcell.Screen) {
 		return // No space left for the text area.
 	}
 
+	// The search prompt occupies the last row.
+	if t.searchPrompt != nil && height > 1 {
+		height--
+		t.searchPrompt.SetRect(x, y+height, width, 1)
+		t.searchPrompt.Draw(screen)
+	}
+
+	// Make room for the gutter.
+	t.gutterWidth = 0
+	t.gutterLines = t.gutterLines[:0]
+	var cursorLine int
+	lineChanges := t.getLineChanges()
+	if t.showLineNumbers || t.baseline != nil {
+		t.gutterWidth = 1 // The last column is for change markers.
+		if t.showLineNumbers {
+			if t.lineCount == 0 {
+				t.lineCount = t.countNewlines([3]int{1, 0, -1}) + 1
+			}
+			t.gutterWidth += len(strconv.Itoa(t.lineCount))
+		}
+		if t.gutterWidth >= width {
+			t.gutterWidth = 0
+		} else {
+			x += t.gutterWidth
+			width -= t.gutterWidth
+			for row := 0; row < height; row++ {
+				for column := 0; column < t.gutterWidth; column++ {
+					screen.SetContent(x-t.gutterWidth+column, y+row, ' ', nil, t.lineNumberStyle)
+				}
+			}
+			cursorLine = t.countNewlines(t.cursor.pos)
+		}
+	}
+	drawLineNumber := func(row, line int) {
+		if t.gutterWidth == 0 {
+			return
+		}
+		t.gutterLines = append(t.gutterLines, line)
+		if line < 0 {
+			return
+		}
+		if line < len(lineChanges) {
+			switch lineChanges[line] {
+			case textAreaLineAdded:
+				screen.SetContent(x-1, y+row, '+', nil, t.addedStyle)
+			case textAreaLineModified:
+				screen.SetContent(x-1, y+row, '~', nil, t.modifiedStyle)
+			case textAreaLineDeleted:
+				screen.SetContent(x-1, y+row, '-', nil, t.deletedStyle)
+			}
+		}
+		if !t.showLineNumbers {
+			return
+		}
+		number, style := line+1, t.lineNumberStyle
+		if line == cursorLine {
+			style = t.currentLineNumberStyle
+		} else if t.relativeLineNumbers {
+			number = line - cursorLine
+			if number < 0 {
+				number = -number
+			}
+		}
+		printWithStyle(screen, strconv.Itoa(number), x-t.gutterWidth, y+row, 0, t.gutterWidth-1, AlignRight, style, false)
+	}
+
 	// Draw the input element if necessary.
 	_, bg, _ := t.textStyle.Decompose()
 	if t.disabled {
@@ -1203,11 +3043,11 @@ func (t *TextArea) Draw(screen tcell.Screen) {
 
 	// Show/hide the cursor at the end.
 	defer func() {
-		if t.HasFocus() {
-			row, column := t.cursor.row, t.cursor.actualColumn
+		if t.HasFocus() && t.searchPrompt == nil {
+			row, column := t.cursor.row, t.cursor.actualColumn+t.rowIndent(t.cursor.row)
 			if t.length > 0 && t.wrap && column >= t.lastWidth { // This happens when a row has text all the way until the end, pushing the cursor outside the viewport.
 				row++
-				column = 0
+				column = t.rowIndent(row)
 			}
 			if row >= 0 &&
 				row-t.rowOffset >= 0 && row-t.rowOffset < height &&
@@ -1224,6 +3064,7 @@ func (t *TextArea) Draw(screen tcell.Screen) {
 		t.lastHeight, t.lastWidth = height, width
 		t.cursor.row, t.cursor.column, t.cursor.actualColumn, t.cursor.pos = 0, 0, 0, [3]int{1, 0, -1}
 		t.rowOffset, t.columnOffset = 0, 0
+		drawLineNumber(0, 0)
 		if len(t.placeholder) > 0 {
 			t.drawPlaceholder(screen, x, y, width, height)
 		}
@@ -1244,6 +3085,9 @@ func (t *TextArea) Draw(screen tcell.Screen) {
 	// If the cursor position is unknown, find it. This usually only happens
 	// before the screen is drawn for the first time.
 	if t.cursor.row < 0 {
+		if t.selectionStart.row < 0 && t.selectionStart.pos != t.cursor.pos {
+			t.Select(t.indexOf(t.selectionStart.pos), t.indexOf(t.cursor.pos)) // Selected before the first drawing.
+		}
 		t.findCursor(true, 0)
 		if t.selectionStart.row < 0 {
 			t.selectionStart = t.cursor
@@ -1259,8 +3103,64 @@ func (t *TextArea) Draw(screen tcell.Screen) {
 	pos := t.lineStarts[line]
 	endPos := pos
 	posX, posY := 0, 0
+	var (
+		index                int
+		matches              [][]int
+		highlightLine        *textAreaHighlightLine
+		highlightSpans       []StyledSpan
+		_, textBackground, _ = t.textStyle.Decompose()
+		trackIndex           = t.search != nil || t.highlighter != nil || len(t.extraCursors) > 0 || t.matchBrackets
+		extraCursors         [][3]int // Selection from, to, and cursor.
+		bracket, match       = -1, -1
+	)
+	if t.matchBrackets && len(t.bracketPairs) > 0 {
+		visibleEnd := t.length
+		if t.rowOffset+height < len(t.lineStarts) {
+			visibleEnd = t.indexOf(t.lineStarts[t.rowOffset+height])
+		}
+		bracket, match = t.findMatchingBracket(t.indexOf(pos), visibleEnd)
+	}
+	for _, cursor := range t.extraCursors {
+		from, to := cursor[0], cursor[1]
+		if from > to {
+			from, to = to, from
+		}
+		extraCursors = append(extraCursors, [3]int{from, to, cursor[1]})
+	}
+	sort.Slice(extraCursors, func(i, j int) bool {
+		return extraCursors[i][0] < extraCursors[j][0]
+	})
+	if trackIndex {
+		index = t.indexOf(pos)
+	}
+	if t.search != nil {
+		matches = t.getMatches()
+	}
+	if t.highlighter != nil {
+		if line := t.getHighlightLine(index); line >= 0 {
+			highlightLine = &t.highlightLines[line]
+			highlightSpans = highlightLine.spans
+		}
+	}
+	var lineNumber int
+	if t.gutterWidth > 0 {
+		lineNumber = t.countNewlines(pos)
+		if t.afterNewline(pos) {
+			drawLineNumber(0, lineNumber)
+		} else {
+			drawLineNumber(0, -1)
+		}
+	}
+	indent := t.rowIndent(line)
+	drawWrapIndicator := func() {
+		if indent > 0 && t.wrapIndicator != 0 {
+			screen.SetContent(x, y+posY, t.wrapIndicator, nil, t.wrapIndicatorStyle)
+		}
+	}
+	drawWrapIndicator()
 	for pos[0] != 1 {
 		var clusterWidth int
+		oldPos := pos
 		cluster, text, _, clusterWidth, pos, endPos = t.step(text, pos, endPos)
 
 		// Prepare drawing.
@@ -1276,14 +3176,60 @@ func (t *TextArea) Draw(screen tcell.Screen) {
 			fromRow > line ||
 			fromRow == line && fromColumn > posX {
 			style = t.textStyle
+			if highlightLine != nil {
+				for highlightLine != nil && index >= highlightLine.start+highlightLine.length {
+					// Move on to the next line.
+					highlightLine = nil
+					highlightSpans = nil
+					if line := t.getHighlightLine(index); line >= 0 {
+						highlightLine = &t.highlightLines[line]
+						highlightSpans = highlightLine.spans
+					}
+				}
+				for len(highlightSpans) > 0 && highlightLine.start+highlightSpans[0].End <= index {
+					highlightSpans = highlightSpans[1:]
+				}
+				if len(highlightSpans) > 0 && highlightLine.start+highlightSpans[0].Start <= index {
+					style = highlightSpans[0].Style
+					if _, bg, _ := style.Decompose(); bg == tcell.ColorDefault {
+						style = style.Background(textBackground)
+					}
+				}
+			}
 			if t.disabled {
 				style = style.Background(t.backgroundColor)
 			}
+			for len(matches) > 0 && matches[0][1] <= index {
+				matches = matches[1:]
+			}
+			if len(matches) > 0 && matches[0][0] <= index {
+				style = t.matchStyle
+			}
+			if index == bracket || index == match {
+				style = t.bracketMatchStyle
+			}
+		}
+		for len(extraCursors) > 0 && extraCursors[0][1] < index {
+			extraCursors = extraCursors[1:]
+		}
+		if len(extraCursors) > 0 {
+			if extraCursors[0][2] == index {
+				// Additional cursors are shown in reverse.
+				style = style.Reverse(true)
+				if clusterWidth == 0 && posX+indent-columnOffset >= 0 && posX+indent-columnOffset < width {
+					screen.SetContent(x+posX+indent-columnOffset, y+posY, ' ', nil, style)
+				}
+			} else if extraCursors[0][0] <= index && index < extraCursors[0][1] {
+				style = t.selectedStyle
+			}
+		}
+		if trackIndex {
+			index += t.distance(oldPos, pos)
 		}
 
 		// Draw character.
-		if posX+clusterWidth-columnOffset <= width && posX-columnOffset >= 0 && clusterWidth > 0 {
-			screen.SetContent(x+posX-columnOffset, y+posY, runes[0], runes[1:], style)
+		if posX+indent+clusterWidth-columnOffset <= width && posX+indent-columnOffset >= 0 && clusterWidth > 0 {
+			screen.SetContent(x+posX+indent-columnOffset, y+posY, runes[0], runes[1:], style)
 		}
 
 		// Advance.
@@ -1296,6 +3242,23 @@ func (t *TextArea) Draw(screen tcell.Screen) {
 			}
 			posX = 0
 			line++
+			indent = t.rowIndent(line)
+			drawWrapIndicator()
+			if uniseg.HasTrailingLineBreakInString(cluster) {
+				lineNumber++
+				drawLineNumber(posY, lineNumber)
+			} else {
+				drawLineNumber(posY, -1)
+			}
+		}
+	}
+
+	// An additional cursor may be located at the end of the text.
+	if pos[0] == 1 && posY < height && posX+indent-columnOffset >= 0 && posX+indent-columnOffset < width {
+		for _, cursor := range extraCursors {
+			if cursor[2] == t.length {
+				screen.SetContent(x+posX+indent-columnOffset, y+posY, ' ', nil, t.textStyle.Reverse(true))
+			}
 		}
 	}
 }
@@ -1355,6 +3318,7 @@ func (t *TextArea) extendLines(width, maxLines int) {
 		lastGraphemeBreak, lastLineBreak    [3]int
 		widthSinceLineBreak                 int
 	)
+	indent, lineIndent := t.rowIndent(len(t.lineStarts)-1), -1 // The indent of the current row and of continuation rows (-1 if unknown).
 	for pos[0] != 1 {
 		// Get the next grapheme cluster.
 		cluster, text, boundaries, clusterWidth, pos, endPos = t.step(text, pos, endPos)
@@ -1362,7 +3326,7 @@ func (t *TextArea) extendLines(width, maxLines int) {
 		widthSinceLineBreak += clusterWidth
 
 		// Any line breaks?
-		if !t.wrap || lineWidth <= width {
+		if !t.wrap || lineWidth <= width-indent {
 			if boundaries&uniseg.MaskLine == uniseg.LineMustBreak && (len(text) > 0 || uniseg.HasTrailingLineBreakInString(cluster)) {
 				// We must break over.
 				t.lineStarts = append(t.lineStarts, pos)
@@ -1370,6 +3334,7 @@ func (t *TextArea) extendLines(width, maxLines int) {
 					t.widestLine = lineWidth
 				}
 				lineWidth = 0
+				indent, lineIndent = 0, -1
 				lastGraphemeBreak = [3]int{}
 				lastLineBreak = [3]int{}
 				widthSinceLineBreak = 0
@@ -1378,7 +3343,7 @@ func (t *TextArea) extendLines(width, maxLines int) {
 				}
 				continue
 			}
-		} else { // t.wrap && lineWidth > width
+		} else { // t.wrap && lineWidth > width-indent
 			if !t.wordWrap || lastLineBreak == [3]int{} {
 				if lastGraphemeBreak != [3]int{} { // We have at least one character on each line.
 					// Break after last grapheme.
@@ -1388,6 +3353,10 @@ func (t *TextArea) extendLines(width, maxLines int) {
 					}
 					lineWidth = clusterWidth
 					lastLineBreak = [3]int{}
+					if lineIndent < 0 {
+						lineIndent = t.rowIndent(len(t.lineStarts) - 1)
+					}
+					indent = lineIndent
 				}
 			} else { // t.wordWrap && lastLineBreak != [3]int{}
 				// Break after last line break opportunity.
@@ -1397,6 +3366,10 @@ func (t *TextArea) extendLines(width, maxLines int) {
 				}
 				lineWidth = widthSinceLineBreak
 				lastLineBreak = [3]int{}
+				if lineIndent < 0 {
+					lineIndent = t.rowIndent(len(t.lineStarts) - 1)
+				}
+				indent = lineIndent
 			}
 		}
 
@@ -1431,6 +3404,31 @@ func (t *TextArea) truncateLines(fromLine int) {
 	}
 }
 
+// truncateChangedLines truncates [TextArea.lineStarts] after changes were
+// undone or redone. The given spans are the "before" spans of the undo items.
+// Only rows starting in spans which precede them in the piece chain are kept,
+// minus the last of those rows whose layout may have changed, too.
+func (t *TextArea) truncateChangedLines(before []int) {
+	t.getChainIndex()
+	boundary := len(t.chainIndex)
+	for _, span := range before {
+		position := t.chainPositions[span]
+		if position < 0 {
+			t.truncateLines(0) // Also the case for the start sentinel span.
+			return
+		}
+		if position < boundary {
+			boundary = position
+		}
+	}
+	for row, lineStart := range t.lineStarts {
+		if position := t.chainPositions[lineStart[0]]; position < 0 || position >= boundary {
+			t.truncateLines(row - 1)
+			return
+		}
+	}
+}
+
 // findCursor determines the cursor position if its "row" value is < 0
 // (=unknown) but only its span position ("pos" value) is known. If the cursor
 // position is already known (row >= 0), it can also be used to modify row and
@@ -1455,7 +3453,7 @@ func (t *TextArea) findCursor(clamp bool, startRow int) {
 	// Clamp to viewport.
 	if clamp && t.cursor.row >= 0 {
 		cursorRow := t.cursor.row
-		if t.wrap && t.cursor.actualColumn >= t.lastWidth {
+		if t.wrap && t.cursor.actualColumn+t.rowIndent(cursorRow) >= t.lastWidth {
 			cursorRow++ // A row can push the cursor just outside the viewport. It will wrap onto the next line.
 		}
 		if cursorRow < t.rowOffset {
@@ -1633,7 +3631,7 @@ func (t *TextArea) step(text string, pos, endPos [3]int) (cluster, rest string,
 			}
 			text += moreText
 			endPos[1] += len(moreText)
-			if endPos[1] >= endSpan.length {
+			if endPos[1] >= endSpan.length && endPos[1] >= -endSpan.length {
 				endPos[0], endPos[1] = endSpan.next, 0
 			}
 		}
@@ -1657,7 +3655,7 @@ func (t *TextArea) step(text string, pos, endPos [3]int) (cluster, rest string,
 	}
 
 	if cluster == "\t" {
-		width = TabSize
+		width = t.getTabSize()
 	} else {
 		width = boundaries >> uniseg.ShiftWidth
 	}
@@ -1927,6 +3925,663 @@ func (t *TextArea) getSelectedText() string {
 	return text.String()
 }
 
+// setMode changes the editing mode and resets any incomplete commands. Entering
+// or leaving visual mode removes the selection. A "mode changed" event is
+// triggered if the mode has changed.
+func (t *TextArea) setMode(mode TextAreaMode) {
+	t.modeCount, t.modePending = 0, ""
+	if mode == t.mode {
+		return
+	}
+	t.modalChange = false
+	if mode == TextAreaModeVisual || t.mode == TextAreaModeVisual {
+		t.selectionStart = t.cursor
+	}
+	if mode != TextAreaModeInsert {
+		t.extraCursors = nil
+	}
+	t.mode = mode
+	if t.modeChanged != nil {
+		t.modeChanged(mode)
+	}
+}
+
+// handleModalKey processes a key event in normal or visual mode. See
+// [TextArea.SetModalEditing] for the available commands.
+func (t *TextArea) handleModalKey(event *tcell.EventKey) {
+	// Translate special keys into commands.
+	r := event.Rune()
+	switch key := event.Key(); key {
+	case tcell.KeyRune:
+		if event.Modifiers()&tcell.ModAlt != 0 {
+			return
+		}
+
+		// Collect counts.
+		if r >= '1' && r <= '9' || r == '0' && t.modeCount > 0 {
+			t.modeCount = t.modeCount*10 + int(r-'0')
+			return
+		}
+	case tcell.KeyLeft, tcell.KeyBackspace, tcell.KeyBackspace2:
+		r = 'h'
+	case tcell.KeyRight:
+		r = 'l'
+	case tcell.KeyDown, tcell.KeyEnter:
+		r = 'j'
+	case tcell.KeyUp:
+		r = 'k'
+	case tcell.KeyHome:
+		r = '0'
+	case tcell.KeyEnd:
+		r = '$'
+	case tcell.KeyDelete:
+		r = 'x'
+	case tcell.KeyPgDn, tcell.KeyCtrlF, tcell.KeyPgUp, tcell.KeyCtrlB:
+		rows := t.lastHeight
+		if key == tcell.KeyPgUp || key == tcell.KeyCtrlB {
+			rows = -rows
+		}
+		column := t.cursor.column
+		t.moveCursor(t.cursor.row+rows, column)
+		t.cursor.column = column
+		if t.mode != TextAreaModeVisual {
+			t.selectionStart = t.cursor
+		}
+		return
+	case tcell.KeyCtrlR:
+		t.modeCount, t.modePending = 0, ""
+		t.redo()
+		return
+	case tcell.KeyEscape:
+		if t.modePending != "" || t.modeCount > 0 {
+			t.modeCount, t.modePending = 0, ""
+		} else if t.mode == TextAreaModeVisual {
+			t.setMode(TextAreaModeNormal)
+		} else if t.finished != nil {
+			t.finished(key)
+		}
+		return
+	case tcell.KeyTab, tcell.KeyBacktab:
+		if t.finished != nil {
+			t.finished(key)
+		}
+		return
+	default:
+		return
+	}
+
+	// Determine the command.
+	count, explicitCount := t.modeCount, t.modeCount > 0
+	if count == 0 {
+		count = 1
+	}
+	command := t.modePending + string(r)
+	t.modePending = ""
+	visual := t.mode == TextAreaModeVisual
+	var operator byte
+	if len(command) > 1 && strings.IndexByte("dyc", command[0]) >= 0 {
+		operator, command = command[0], command[1:]
+	}
+
+	// Incomplete commands.
+	if command == "g" || operator == 0 && !visual && (command == "d" || command == "y" || command == "c") {
+		if operator != 0 {
+			t.modePending = string(operator)
+		}
+		t.modePending += command
+		return // Keep the count.
+	}
+	t.modeCount = 0
+
+	// Operations on entire lines.
+	if operator != 0 && command == string(operator) {
+		from := t.indexOf(t.cursor.pos)
+		text := t.GetText()
+		to := from
+		for line := 1; line < count; line++ {
+			next := strings.IndexByte(text[to:], '\n')
+			if next < 0 {
+				break
+			}
+			to += next + 1
+		}
+		t.modalOperate(operator, from, to, true)
+		return
+	}
+
+	// Motions.
+	if operator == 'c' && command == "w" && t.cursor.pos[0] != 1 {
+		if cluster, _, _, _, _, _ := t.step("", t.cursor.pos, t.cursor.pos); !unicode.IsSpace([]rune(cluster)[0]) {
+			command = "e" // Like vi, "cw" does not change the space after the word.
+		}
+	}
+	before := t.cursor
+	if linewise, inclusive, ok := t.modalMotion(command, count, explicitCount); ok {
+		if operator != 0 {
+			from, to := t.indexOf(before.pos), t.indexOf(t.cursor.pos)
+			if from > to {
+				from, to = to, from
+			}
+			if inclusive && to < t.length {
+				_, _, _, _, next, _ := t.step("", t.cursor.pos, t.cursor.pos)
+				to += t.distance(t.cursor.pos, next)
+			}
+			t.modalOperate(operator, from, to, linewise)
+		} else {
+			if command == "$" && operator == 0 && t.cursor.actualColumn > 0 {
+				t.modalMotion("h", 1, false) // Place the cursor on the last character.
+			}
+			if !visual {
+				t.selectionStart = t.cursor
+			}
+		}
+		return
+	}
+	if operator != 0 {
+		return // Unknown motion.
+	}
+
+	// Operations on the selection.
+	if visual {
+		switch command {
+		case "d", "x", "y", "c":
+			if command == "x" {
+				command = "d"
+			}
+			_, from, to := t.GetSelection()
+			if to < t.length {
+				_, _, _, _, next, _ := t.step("", t.cursor.pos, t.cursor.pos)
+				if t.indexOf(t.cursor.pos) < to {
+					_, _, _, _, next, _ = t.step("", t.selectionStart.pos, t.selectionStart.pos)
+					to += t.distance(t.selectionStart.pos, next)
+				} else {
+					to += t.distance(t.cursor.pos, next)
+				}
+			}
+			t.modalOperate(command[0], from, to, false)
+		case "v":
+			t.setMode(TextAreaModeNormal)
+		}
+		return
+	}
+
+	// Other commands in normal mode.
+	switch command {
+	case "x", "D", "C":
+		from := t.indexOf(t.cursor.pos)
+		text := t.GetText()
+		to := from
+		if command == "x" {
+			for count > 0 && to < len(text) && text[to] != '\n' {
+				cluster, _, _, _ := uniseg.FirstGraphemeClusterInString(text[to:], -1)
+				to += len(cluster)
+				count--
+			}
+		} else if end := strings.IndexByte(text[to:], '\n'); end >= 0 {
+			to += end
+		} else {
+			to = len(text)
+		}
+		operator := byte('d')
+		if command == "C" {
+			operator = 'c'
+		}
+		t.modalOperate(operator, from, to, false)
+	case "Y":
+		from := t.indexOf(t.cursor.pos)
+		t.modalOperate('y', from, from, true)
+	case "p", "P":
+		t.modalPaste(command == "p", count)
+	case "u":
+		for ; count > 0; count-- {
+			t.undo()
+		}
+	case "i":
+		t.setMode(TextAreaModeInsert)
+	case "a", "I", "A":
+		motion := map[string]string{"a": "l", "I": "0", "A": "$"}[command]
+		t.modalMotion(motion, 1, false)
+		t.selectionStart = t.cursor
+		t.setMode(TextAreaModeInsert)
+	case "o", "O":
+		index := t.indexOf(t.cursor.pos)
+		text := t.GetText()
+		if command == "o" {
+			if end := strings.IndexByte(text[index:], '\n'); end >= 0 {
+				index += end
+			} else {
+				index = len(text)
+			}
+			t.Replace(index, index, NewLine)
+		} else {
+			index = strings.LastIndexByte(text[:index], '\n') + 1
+			t.Replace(index, index, NewLine)
+			t.Select(index, index)
+		}
+		t.setMode(TextAreaModeInsert)
+	case "v":
+		t.setMode(TextAreaModeVisual)
+	}
+}
+
+// modalMotion moves the cursor according to the given motion command which is
+// to be repeated "count" times. The function returns whether the motion covers
+// entire lines, whether it includes the character under the cursor (when used
+// with an operator), and whether the command was a motion command at all.
+func (t *TextArea) modalMotion(command string, count int, explicitCount bool) (linewise, inclusive, ok bool) {
+	switch command {
+	case "h":
+		for ; count > 0 && t.cursor.actualColumn > 0; count-- {
+			t.moveCursor(t.cursor.row, t.cursor.actualColumn-1)
+		}
+	case "l":
+		for ; count > 0 && t.cursor.pos[0] != 1; count-- {
+			cluster, _, _, clusterWidth, next, _ := t.step("", t.cursor.pos, t.cursor.pos)
+			if uniseg.HasTrailingLineBreakInString(cluster) {
+				break // Don't leave the line.
+			}
+			if len(t.lineStarts) <= t.cursor.row+1 {
+				t.extendLines(t.lastWidth, t.cursor.row+1)
+			}
+			if t.cursor.row+1 < len(t.lineStarts) && t.lineStarts[t.cursor.row+1] == next {
+				t.moveCursor(t.cursor.row+1, 0) // A wrapped line.
+			} else {
+				t.moveCursor(t.cursor.row, t.cursor.actualColumn+clusterWidth)
+			}
+		}
+	case "j", "k":
+		if command == "k" {
+			count = -count
+		}
+		column := t.cursor.column
+		t.moveCursor(t.cursor.row+count, column)
+		t.cursor.column = column
+		linewise = true
+	case "w":
+		for ; count > 0; count-- {
+			t.moveWordStart()
+		}
+	case "b":
+		for ; count > 0; count-- {
+			t.moveWordLeft(true)
+		}
+	case "e":
+		for ; count > 0; count-- {
+			t.moveWordRight(false, true)
+		}
+		inclusive = true
+	case "0":
+		first, _ := t.cursorLineRows()
+		t.moveCursor(first, 0)
+	case "$":
+		_, last := t.cursorLineRows()
+		t.moveCursor(last, -1)
+	case "G", "gg":
+		line := count - 1
+		if !explicitCount && command == "G" {
+			line = t.countNewlines([3]int{1, 0, -1})
+		}
+		t.cursor.row = -1
+		t.cursor.pos = t.lineStartPos(line)
+		t.findCursor(true, 0)
+		linewise = true
+	default:
+		return false, false, false
+	}
+	return linewise, inclusive, true
+}
+
+// modalOperate deletes ('d'), copies ('y'), or changes ('c') the text between
+// the given text indices. If linewise is true, the range is extended to cover
+// entire lines. Deleted text is also copied to the clipboard.
+func (t *TextArea) modalOperate(operator byte, from, to int, linewise bool) {
+	text := t.GetText()
+	if linewise {
+		from = strings.LastIndexByte(text[:from], '\n') + 1
+		if end := strings.IndexByte(text[to:], '\n'); end >= 0 {
+			to += end + 1
+		} else {
+			to = len(text)
+		}
+	}
+	copied := text[from:to]
+	if linewise && !strings.HasSuffix(copied, "\n") {
+		copied += "\n"
+	}
+	t.copyToClipboard(copied)
+	t.linewiseClipboard = linewise
+
+	switch operator {
+	case 'y':
+		t.Select(from, from)
+		t.findCursor(true, t.cursor.row)
+		t.setMode(TextAreaModeNormal)
+	case 'd':
+		if linewise && to == len(text) && from > 0 && !strings.HasSuffix(text, "\n") {
+			from-- // Deleting the last line also deletes the newline before it.
+		}
+		t.Replace(from, to, "")
+		t.setMode(TextAreaModeNormal)
+	case 'c':
+		if linewise && strings.HasSuffix(text[from:to], "\n") {
+			to-- // Keep the line.
+		}
+		t.Replace(from, to, "")
+		t.setMode(TextAreaModeInsert)
+		t.modalChange = t.GetTextLength() != len(text)
+	}
+}
+
+// modalPaste inserts the clipboard text "count" times after or before the
+// cursor. If the clipboard text was copied as entire lines, it is inserted
+// after or before the current line.
+func (t *TextArea) modalPaste(after bool, count int) {
+	paste := strings.Repeat(t.pasteFromClipboard(), count)
+	if paste == "" {
+		return
+	}
+	index := t.indexOf(t.cursor.pos)
+	text := t.GetText()
+	if !t.linewiseClipboard {
+		if after && index < len(text) && text[index] != '\n' {
+			cluster, _, _, _ := uniseg.FirstGraphemeClusterInString(text[index:], -1)
+			index += len(cluster)
+		}
+		t.Replace(index, index, paste)
+		return
+	}
+	if !after {
+		index = strings.LastIndexByte(text[:index], '\n') + 1
+	} else if end := strings.IndexByte(text[index:], '\n'); end >= 0 {
+		index += end + 1
+	} else {
+		index = len(text)
+		paste = "\n" + strings.TrimSuffix(paste, "\n")
+		t.Replace(index, index, paste)
+		t.Select(index+1, index+1)
+		t.findCursor(true, t.cursor.row)
+		return
+	}
+	t.Replace(index, index, paste)
+	t.Select(index, index)
+	t.findCursor(true, t.cursor.row)
+}
+
+// moveWordStart moves the cursor to the start of the next word where words are
+// sequences of letters, digits, and underscores or sequences of other
+// non-space characters.
+func (t *TextArea) moveWordStart() {
+	class := func(cluster string) int {
+		r, _ := utf8.DecodeRuneInString(cluster)
+		switch {
+		case unicode.IsSpace(r):
+			return 0
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			return 1
+		}
+		return 2
+	}
+	pos := t.cursor.pos
+	endPos := pos
+	var cluster, text string
+	wordClass := -1
+	for pos[0] != 1 {
+		oldPos := pos
+		cluster, text, _, _, pos, endPos = t.step(text, pos, endPos)
+		if c := class(cluster); wordClass < 0 {
+			wordClass = c
+		} else if c == 0 {
+			wordClass = 0 // Any non-space character after a space starts a new word.
+		} else if c != wordClass {
+			pos = oldPos
+			break
+		}
+	}
+	row := t.cursor.row
+	t.cursor.row, t.cursor.column, t.cursor.actualColumn = -1, 0, 0
+	t.cursor.pos = pos
+	t.findCursor(true, row)
+}
+
+// cursorLineRows returns the first and the last row of the line (separated by
+// newline characters) the cursor is in.
+func (t *TextArea) cursorLineRows() (first, last int) {
+	if t.cursor.row+1 >= len(t.lineStarts) {
+		t.extendLines(t.lastWidth, t.cursor.row+1)
+	}
+	if len(t.lineStarts) == 0 {
+		return 0, 0
+	}
+	first = t.cursor.row
+	if first >= len(t.lineStarts) {
+		first = len(t.lineStarts) - 1
+	}
+	last = first
+	for first > 0 && !t.afterNewline(t.lineStarts[first]) {
+		first--
+	}
+	for {
+		if last+1 >= len(t.lineStarts) {
+			t.extendLines(t.lastWidth, last+1)
+		}
+		if last+1 >= len(t.lineStarts) || t.afterNewline(t.lineStarts[last+1]) {
+			break
+		}
+		last++
+	}
+	return
+}
+
+// lineStartPos returns the span position of the start of the line (separated
+// by newline characters) with the given number, starting at 0. If there are
+// fewer lines, the start of the last line is returned.
+func (t *TextArea) lineStartPos(line int) [3]int {
+	chain := t.getChainIndex()
+	if newlines := chain[len(chain)-1].newlines; line > newlines {
+		line = newlines
+	}
+	if line <= 0 {
+		return [3]int{t.spans[0].next, 0, -1}
+	}
+
+	// Find the span with the newline character ending the previous line.
+	low, high := 0, len(chain)-1
+	for low < high {
+		middle := (low + high + 1) / 2
+		if chain[middle].newlines < line {
+			low = middle
+		} else {
+			high = middle - 1
+		}
+	}
+
+	// Find the newline character.
+	entry := chain[low]
+	text := t.spanText(entry.span)
+	var offset int
+	for newlines := line - entry.newlines; newlines > 0; newlines-- {
+		offset += strings.IndexByte(text[offset:], '\n') + 1
+	}
+	if offset >= len(text) {
+		return [3]int{t.spans[entry.span].next, 0, -1}
+	}
+	return [3]int{entry.span, offset, -1}
+}
+
+// addCursor adds an additional cursor with the given selection start and
+// cursor index positions unless there already is a cursor at that position.
+func (t *TextArea) addCursor(selectionStart, cursor int) {
+	if cursor == t.indexOf(t.cursor.pos) {
+		return
+	}
+	for _, extraCursor := range t.extraCursors {
+		if extraCursor[1] == cursor {
+			return
+		}
+	}
+	t.extraCursors = append(t.extraCursors, [2]int{selectionStart, cursor})
+}
+
+// editCursors applies an edit to all cursors as one undo step. The edit
+// function is called for each cursor in the order of their positions. It
+// receives the entire text, the cursor's number in that order, and its
+// selected range (which is empty if there is no selection) and returns the
+// range to be replaced and the replacement text. Ranges overlapping the ones
+// of previous cursors are clipped. Afterwards, each cursor is placed after its
+// replacement text and selections are removed.
+func (t *TextArea) editCursors(edit func(text string, number, from, to int) (start, end int, insert string)) {
+	type change struct {
+		start, end int
+		insert     string
+		primary    bool
+	}
+
+	// Determine the changes.
+	text := t.GetText()
+	cursors := t.GetCursors()
+	changes := make([]change, 0, len(cursors))
+	for index, cursor := range cursors {
+		from, to := cursor[0], cursor[1]
+		if from > to {
+			from, to = to, from
+		}
+		if to > len(text) {
+			to = len(text)
+		}
+		if from > to {
+			from = to
+		}
+		changes = append(changes, change{start: from, end: to, primary: index == 0})
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].start < changes[j].start
+	})
+	for index := range changes {
+		c := &changes[index]
+		c.start, c.end, c.insert = edit(text, index, c.start, c.end)
+		if index > 0 && c.start < changes[index-1].end {
+			c.start = changes[index-1].end
+			if c.end < c.start {
+				c.end = c.start
+			}
+		}
+	}
+
+	// Apply them from the end so the indices of earlier changes remain valid.
+	t.GroupUndo(func() {
+		for index := len(changes) - 1; index >= 0; index-- {
+			if c := changes[index]; c.start != c.end || c.insert != "" {
+				t.Replace(c.start, c.end, c.insert)
+			}
+		}
+	})
+
+	// Place the cursors.
+	var shift, primary int
+	t.extraCursors = t.extraCursors[:0]
+	for _, c := range changes {
+		pos := c.start + shift + len(c.insert)
+		shift += len(c.insert) - c.end + c.start
+		if c.primary {
+			primary = pos
+		} else if len(t.extraCursors) == 0 || t.extraCursors[len(t.extraCursors)-1][1] != pos {
+			t.extraCursors = append(t.extraCursors, [2]int{pos, pos})
+		}
+	}
+	for index := 0; index < len(t.extraCursors); index++ {
+		if t.extraCursors[index][1] == primary {
+			t.extraCursors = append(t.extraCursors[:index], t.extraCursors[index+1:]...)
+			break
+		}
+	}
+	t.Select(primary, primary)
+	t.findCursor(true, t.cursor.row)
+}
+
+// handleCursorsKey processes a key event while there are additional cursors
+// and returns true if it was handled. Otherwise, the additional cursors are
+// removed and the key event should be processed as usual.
+func (t *TextArea) handleCursorsKey(event *tcell.EventKey) bool {
+	insert := func(insert string) {
+		t.editCursors(func(text string, number, from, to int) (int, int, string) {
+			return from, to, insert
+		})
+	}
+	switch event.Key() {
+	case tcell.KeyRune:
+		if event.Modifiers()&tcell.ModAlt == 0 {
+			insert(string(event.Rune()))
+			return true
+		}
+	case tcell.KeyEnter, tcell.KeyTab:
+		if event.Key() == tcell.KeyTab && t.finished != nil {
+			break
+		}
+		t.editCursors(func(text string, number, from, to int) (int, int, string) {
+			lineBefore := text[strings.LastIndexByte(text[:from], '\n')+1 : from]
+			if event.Key() == tcell.KeyTab {
+				return from, to, t.tab(lineBefore)
+			}
+			return from, to, t.indentation(lineBefore)
+		})
+		return true
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		t.editCursors(func(text string, number, from, to int) (int, int, string) {
+			if from == to && from > 0 {
+				// Find the start of the grapheme cluster before the cursor.
+				lineStart := strings.LastIndexByte(text[:from-1], '\n') + 1
+				for index := lineStart; index < to; {
+					cluster, _, _, _ := uniseg.FirstGraphemeClusterInString(text[index:to], -1)
+					from = index
+					index += len(cluster)
+				}
+			}
+			return from, to, ""
+		})
+		return true
+	case tcell.KeyDelete, tcell.KeyCtrlD:
+		t.editCursors(func(text string, number, from, to int) (int, int, string) {
+			if from == to && to < len(text) {
+				cluster, _, _, _ := uniseg.FirstGraphemeClusterInString(text[to:], -1)
+				to += len(cluster)
+			}
+			return from, to, ""
+		})
+		return true
+	case tcell.KeyCtrlQ, tcell.KeyCtrlX: // Copy or cut the selections, one per line.
+		var selections []string
+		cut := event.Key() == tcell.KeyCtrlX
+		t.editCursors(func(text string, number, from, to int) (int, int, string) {
+			if from < to {
+				selections = append(selections, text[from:to])
+			}
+			if !cut {
+				from = to
+			}
+			return from, to, ""
+		})
+		if len(selections) > 0 {
+			t.copyToClipboard(strings.Join(selections, "\n"))
+		}
+		return true
+	case tcell.KeyCtrlV: // Paste, one line per cursor if the line count matches.
+		paste := t.pasteFromClipboard()
+		lines := strings.Split(strings.TrimSuffix(paste, "\n"), "\n")
+		t.editCursors(func(text string, number, from, to int) (int, int, string) {
+			if len(lines) == len(t.extraCursors)+1 {
+				return from, to, lines[number]
+			}
+			return from, to, paste
+		})
+		return true
+	case tcell.KeyEscape:
+		t.extraCursors = nil
+		return true
+	}
+	t.extraCursors = nil
+	return false
+}
+
 // InputHandler returns the handler for this primitive.
 func (t *TextArea) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
@@ -1934,6 +4589,21 @@ func (t *TextArea) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			return
 		}
 
+		// The search prompt receives all key events while it is open.
+		if t.searchPrompt != nil {
+			switch event.Key() {
+			case tcell.KeyEscape:
+				t.CloseSearchPrompt()
+			case tcell.KeyEnter, tcell.KeyDown:
+				t.NextMatch()
+			case tcell.KeyUp:
+				t.PreviousMatch()
+			default:
+				t.searchPrompt.InputHandler()(event, setFocus)
+			}
+			return
+		}
+
 		// All actions except a few specific ones are "other" actions.
 		newLastAction := taActionOther
 		defer func() {
@@ -1950,6 +4620,24 @@ func (t *TextArea) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			}()
 		}
 
+		// Modal editing.
+		if t.modal {
+			if t.mode != TextAreaModeInsert {
+				t.handleModalKey(event)
+				return
+			} else if event.Key() == tcell.KeyEscape {
+				t.setMode(TextAreaModeNormal)
+				t.modalMotion("h", 1, false) // Like vi, place the cursor on the last inserted character.
+				t.selectionStart = t.cursor
+				return
+			}
+		}
+
+		// Multiple cursors.
+		if len(t.extraCursors) > 0 && t.handleCursorsKey(event) {
+			return
+		}
+
 		// Process the different key events.
 		switch key := event.Key(); key {
 		case tcell.KeyLeft: // Move one grapheme cluster to the left.
@@ -2092,13 +4780,17 @@ func (t *TextArea) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			}
 		case tcell.KeyEnter: // Insert a newline.
 			from, to, row := t.getSelection()
-			t.cursor.pos = t.replace(from, to, NewLine, t.lastAction == taActionTypeSpace)
+			newLine := NewLine
+			if t.autoIndent {
+				newLine = t.indentation(t.lineBefore(from))
+			}
+			t.cursor.pos = t.replace(from, to, newLine, t.lastAction == taActionTypeSpace)
 			t.cursor.row = -1
 			t.truncateLines(row - 1)
 			t.findCursor(true, row)
 			t.selectionStart = t.cursor
 			newLastAction = taActionTypeSpace
-		case tcell.KeyTab: // Insert a tab character. It will be rendered as TabSize spaces.
+		case tcell.KeyTab: // Insert a tab character (or spaces). It will be rendered as TabSize spaces.
 			// But forwarding takes precedence.
 			if t.finished != nil {
 				t.finished(key)
@@ -2106,7 +4798,11 @@ func (t *TextArea) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			}
 
 			from, to, row := t.getSelection()
-			t.cursor.pos = t.replace(from, to, "\t", t.lastAction == taActionTypeSpace)
+			tab := "\t"
+			if t.softTabs {
+				tab = t.tab(t.lineBefore(from))
+			}
+			t.cursor.pos = t.replace(from, to, tab, t.lastAction == taActionTypeSpace)
 			t.cursor.row = -1
 			t.truncateLines(row - 1)
 			t.findCursor(true, row)
@@ -2137,6 +4833,9 @@ func (t *TextArea) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			} else {
 				// Other keys are simply accepted as regular characters.
 				r := event.Rune()
+				if len(t.autoClosePairs) > 0 && t.autoClose(r) {
+					break
+				}
 				from, to, row := t.getSelection()
 				newLastAction = taActionTypeNonSpace
 				if unicode.IsSpace(r) {
@@ -2160,6 +4859,25 @@ func (t *TextArea) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 				break
 			}
 
+			// Delete empty auto-closed pairs.
+			if event.Modifiers()&tcell.ModAlt == 0 && len(t.autoClosePairs) > 0 {
+				cursor := t.indexOf(t.cursor.pos)
+				previous, previousSize := t.runeBefore(cursor)
+				next, nextSize := t.runeAt(cursor)
+				var deleted bool
+				for _, pair := range t.autoClosePairs {
+					if previousSize > 0 && nextSize > 0 && previous == pair[0] && next == pair[1] {
+						t.Replace(cursor-previousSize, cursor+nextSize, "")
+						t.findCursor(true, t.cursor.row)
+						deleted = true
+						break
+					}
+				}
+				if deleted {
+					break
+				}
+			}
+
 			beforeCursor := t.cursor
 			if event.Modifiers()&tcell.ModAlt == 0 {
 				// Move the cursor back by one grapheme cluster.
@@ -2271,49 +4989,9 @@ func (t *TextArea) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			t.findCursor(true, row)
 			t.selectionStart = t.cursor
 		case tcell.KeyCtrlZ: // Undo.
-			if t.nextUndo <= 0 {
-				break
-			}
-			for t.nextUndo > 0 {
-				t.nextUndo--
-				undo := t.undoStack[t.nextUndo]
-				t.spans[undo.originalBefore], t.spans[undo.before] = t.spans[undo.before], t.spans[undo.originalBefore]
-				t.spans[undo.originalAfter], t.spans[undo.after] = t.spans[undo.after], t.spans[undo.originalAfter]
-				t.cursor.pos, t.undoStack[t.nextUndo].pos = undo.pos, t.cursor.pos
-				t.length, t.undoStack[t.nextUndo].length = undo.length, t.length
-				if !undo.continuation {
-					break
-				}
-			}
-			t.cursor.row = -1
-			t.truncateLines(0) // This is why Undo is expensive for large texts. (t.lineStarts can get largely unusable after an undo.)
-			t.findCursor(true, 0)
-			t.selectionStart = t.cursor
-			if t.changed != nil {
-				defer t.changed()
-			}
+			t.undo()
 		case tcell.KeyCtrlY: // Redo.
-			if t.nextUndo >= len(t.undoStack) {
-				break
-			}
-			for t.nextUndo < len(t.undoStack) {
-				undo := t.undoStack[t.nextUndo]
-				t.spans[undo.originalBefore], t.spans[undo.before] = t.spans[undo.before], t.spans[undo.originalBefore]
-				t.spans[undo.originalAfter], t.spans[undo.after] = t.spans[undo.after], t.spans[undo.originalAfter]
-				t.cursor.pos, t.undoStack[t.nextUndo].pos = undo.pos, t.cursor.pos
-				t.length, t.undoStack[t.nextUndo].length = undo.length, t.length
-				t.nextUndo++
-				if t.nextUndo < len(t.undoStack) && !t.undoStack[t.nextUndo].continuation {
-					break
-				}
-			}
-			t.cursor.row = -1
-			t.truncateLines(0) // This is why Redo is expensive for large texts. (t.lineStarts can get largely unusable after an undo.)
-			t.findCursor(true, 0)
-			t.selectionStart = t.cursor
-			if t.changed != nil {
-				defer t.changed()
-			}
+			t.redo()
 		}
 	})
 }
@@ -2346,16 +5024,42 @@ func (t *TextArea) MouseHandler() func(action MouseAction, event *tcell.EventMou
 		if labelWidth == 0 && t.label != "" {
 			labelWidth = TaggedStringWidth(t.label)
 		}
-		column := x - rectX - labelWidth
+		column := x - rectX - labelWidth - t.gutterWidth
 		row := y - rectY
+
+		// Did the user click on the gutter?
+		if column < 0 && column >= -t.gutterWidth {
+			if action == MouseLeftClick && t.gutterClicked != nil {
+				for r := row; r >= 0 && r < len(t.gutterLines); r-- {
+					if t.gutterLines[r] >= 0 {
+						t.gutterClicked(t.gutterLines[r])
+						break
+					}
+				}
+			}
+			column = 0
+		}
+
 		if !t.wrap {
 			column += t.columnOffset
 		}
 		row += t.rowOffset
+		if indent := t.rowIndent(row); indent > 0 {
+			column -= indent
+			if column < 0 {
+				column = 0
+			}
+		}
 
 		// Process mouse actions.
 		switch action {
 		case MouseLeftDown:
+			if event.Modifiers()&tcell.ModAlt != 0 {
+				// Add a cursor.
+				t.addCursor(t.indexOf(t.selectionStart.pos), t.indexOf(t.cursor.pos))
+			} else {
+				t.extraCursors = nil
+			}
 			t.moveCursor(row, column)
 			if event.Modifiers()&tcell.ModShift == 0 {
 				t.selectionStart = t.cursor
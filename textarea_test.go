@@ -0,0 +1,57 @@
+package tview
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// typeKeys sends the given runes to the primitive, with 'E' standing for the
+// Escape key.
+func typeKeys(p Primitive, keys string) {
+	handler := p.InputHandler()
+	for _, r := range keys {
+		if r == 'E' {
+			handler(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone), func(Primitive) {})
+			continue
+		}
+		handler(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone), func(Primitive) {})
+	}
+}
+
+// TestTextAreaModalChangeUndo tests that a modal "c" command and the text typed
+// after it are undone in one step.
+func TestTextAreaModalChangeUndo(t *testing.T) {
+	for _, test := range []struct {
+		keys, changed string
+	}{
+		{"cwXXE", "XX two three"},
+		{"ccXE", "X"},
+	} {
+		textArea := NewTextArea().SetText("one two three", false).SetModalEditing(true)
+		drawPrimitive(t, textArea, 20, 3)
+		typeKeys(textArea, test.keys)
+		if text := textArea.GetText(); text != test.changed {
+			t.Errorf("%q: text is %q, expected %q", test.keys, text, test.changed)
+		}
+		typeKeys(textArea, "u")
+		if text := textArea.GetText(); text != "one two three" {
+			t.Errorf("%q: text after undo is %q, expected the original text", test.keys, text)
+		}
+	}
+}
+
+// TestTextAreaReplaceBeforeDraw tests that text can be replaced before the
+// text area is drawn for the first time.
+func TestTextAreaReplaceBeforeDraw(t *testing.T) {
+	textArea := NewTextArea().SetText("hello", false)
+	textArea.Replace(0, textArea.GetTextLength(), "abc")
+	textArea.Replace(1, 2, "x")
+	if text := textArea.GetText(); text != "axc" {
+		t.Errorf("text is %q, expected %q", text, "axc")
+	}
+	textArea.Undo()
+	if text := textArea.GetText(); text != "abc" {
+		t.Errorf("text after undo is %q, expected %q", text, "abc")
+	}
+}
@@ -1,8 +1,12 @@
 package tview
 
 import (
+	"errors"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	colorful "github.com/lucasb-eyer/go-colorful"
@@ -18,6 +22,7 @@ type textViewLine struct {
 	length  int               // The string length (in bytes) of this line.
 	state   *stepState        // The parser state at the beginning of the line, before parsing the first character.
 	regions map[string][2]int // The start and end columns of all regions in this line. Only valid for visible lines. May be nil.
+	links   map[string][2]int // The start and end columns of all hyperlinks in this line, mapped by URL. Only valid for visible lines. May be nil.
 }
 
 // TextViewWriter is a writer that can be used to write to and clear a TextView
@@ -50,6 +55,94 @@ func (w TextViewWriter) HasFocus() bool {
 	return w.t.hasFocus
 }
 
+// TextViewStreamWriter is a thread-safe writer which collects text written to
+// it and forwards it to a TextView in batches, at a limited rate. Don't
+// instantiate this class directly but use the TextView's StreamWriter method
+// instead.
+type TextViewStreamWriter struct {
+	sync.Mutex
+	t *TextView
+
+	// The minimum time between two writes to the text view.
+	interval time.Duration
+
+	// The maximum number of bytes held back before writes block.
+	bufferSize int
+
+	// Text not yet written to the text view.
+	buffer []byte
+
+	// Whether a flush of the buffer has been scheduled.
+	scheduled bool
+
+	// The time of the last flush.
+	lastFlush time.Time
+
+	// Whether the writer was closed.
+	closed bool
+
+	// Signals that the buffer was flushed.
+	flushed *sync.Cond
+
+	// Ensures that flushed text arrives at the text view in order.
+	flushing sync.Mutex
+}
+
+// Write implements the io.Writer interface. The text is appended to the
+// writer's buffer and forwarded to the text view later. If the buffer is full,
+// Write blocks until the buffer has been forwarded.
+func (w *TextViewStreamWriter) Write(p []byte) (n int, err error) {
+	w.Lock()
+	defer w.Unlock()
+
+	for !w.closed && len(w.buffer) > 0 && len(w.buffer)+len(p) > w.bufferSize {
+		w.flushed.Wait() // Backpressure.
+	}
+	if w.closed {
+		return 0, errors.New("write to closed stream writer")
+	}
+	w.buffer = append(w.buffer, p...)
+
+	if !w.scheduled {
+		w.scheduled = true
+		time.AfterFunc(time.Until(w.lastFlush.Add(w.interval)), w.flush)
+	}
+
+	return len(p), nil
+}
+
+// Close implements io.Closer for the writer. Any text in the buffer is
+// forwarded to the text view immediately. Subsequent writes will fail.
+func (w *TextViewStreamWriter) Close() error {
+	w.Lock()
+	if w.closed {
+		w.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.Unlock()
+	w.flush()
+	return nil
+}
+
+// flush forwards the writer's buffer to the text view.
+func (w *TextViewStreamWriter) flush() {
+	w.flushing.Lock()
+	defer w.flushing.Unlock()
+
+	w.Lock()
+	text := w.buffer
+	w.buffer = nil
+	w.scheduled = false
+	w.lastFlush = time.Now()
+	w.flushed.Broadcast()
+	w.Unlock()
+
+	if len(text) > 0 {
+		w.t.Write(text)
+	}
+}
+
 // TextView is a component to display read-only text. While the text to be
 // displayed can be changed or appended to, there is no functionality that
 // allows the user to edit it. For that, [TextArea] should be used.
@@ -57,7 +150,9 @@ func (w TextViewWriter) HasFocus() bool {
 // TextView implements the io.Writer interface so you can stream text to it,
 // appending to the existing text. This does not trigger a redraw automatically
 // but if a handler is installed via [TextView.SetChangedFunc], you can cause it
-// to be redrawn. (See [TextView.SetChangedFunc] for more details.)
+// to be redrawn. (See [TextView.SetChangedFunc] for more details.) To stream
+// large amounts of text without redrawing too often, use
+// [TextView.StreamWriter].
 //
 // Tab characters advance the text to the next tab stop at every [TabSize]
 // screen columns, but only if the text is left-aligned. If the text is centered
@@ -94,6 +189,9 @@ func (w TextViewWriter) HasFocus() bool {
 // works the same way as anywhere else. See the package documentation for more
 // information.
 //
+// Alternatively, the text may be a Markdown document which is then rendered
+// with the appropriate styles. See [TextView.SetMarkdown] for details.
+//
 // # Regions and Highlights
 //
 // If regions are enabled via [TextView.SetRegions], you can define text regions
@@ -116,6 +214,8 @@ func (w TextViewWriter) HasFocus() bool {
 // The [TextView.ScrollToHighlight] function can be used to jump to the
 // currently highlighted region once when the text view is drawn the next time.
 //
+// Text can also be searched without defining regions, using [TextView.Search].
+//
 // # Large Texts
 //
 // The text view can handle reasonably large texts. It will parse the text as
@@ -131,6 +231,9 @@ func (w TextViewWriter) HasFocus() bool {
 // (using [TextView.SetScrollable]). This will cause the text view to discard
 // lines moving out of the visible area at the top.
 //
+// If the text is available elsewhere, [TextView.SetContentProvider] lets the
+// text view request only the lines it displays.
+//
 // See https://github.com/rivo/tview/wiki/TextView for an example.
 type TextView struct {
 	sync.Mutex
@@ -143,6 +246,20 @@ type TextView struct {
 	// The text buffer.
 	text strings.Builder
 
+	// The index of the first byte of the text buffer which has not been
+	// discarded (see [TextView.SetMaxLines]). Indices into the text buffer, e.g.
+	// of the line index, include the discarded text.
+	textStart int
+
+	// If set, functions which provide the text line by line. The text buffer
+	// then only contains the visible lines.
+	lineCount func() int
+	line      func(index int) string
+
+	// The index of the first line in the line index which was drawn the last
+	// time, if lines are provided by the functions above.
+	drawnLine int
+
 	// The line index. It is valid at any time but may not contain trailing
 	// lines which are not visible.
 	lineIndex []*textViewLine
@@ -182,6 +299,10 @@ type TextView struct {
 	// content when text is added.
 	trackEnd bool
 
+	// If set to true, trackEnd is turned on again when the user scrolls to the
+	// end of the content.
+	follow bool
+
 	// The width of the characters to be skipped on each line (not used in wrap
 	// mode).
 	columnOffset int
@@ -190,6 +311,10 @@ type TextView struct {
 	// latest word-wrapped lines. Ignored if 0.
 	maxLines int
 
+	// An optional function which is called when lines are discarded from the
+	// start of the text.
+	evicted func(lines int, text string)
+
 	// If set to true, the text view will keep a buffer of text which can be
 	// navigated when the text is longer than what fits into the box.
 	scrollable bool
@@ -213,6 +338,14 @@ type TextView struct {
 	// Whether or not region tags are used.
 	regionTags bool
 
+	// Whether or not the text is a Markdown document.
+	markdown bool
+
+	// The Markdown document rendered with style tags and the width it was
+	// rendered for, or -1 if it needs to be rendered again.
+	markdownText  string
+	markdownWidth int
+
 	// A temporary flag which, when true, will automatically bring the current
 	// highlight(s) into the visible screen the next time the text view is
 	// drawn.
@@ -222,6 +355,30 @@ type TextView struct {
 	// operation.
 	toggleHighlights bool
 
+	// If true, the region under the mouse pointer is underlined.
+	hoverHighlights bool
+
+	// The ID of the region under the mouse pointer or an empty string.
+	hoveredRegion string
+
+	// The current search expression or nil if there is no search.
+	search *regexp.Regexp
+
+	// The matches of the search expression as half-open intervals of indices
+	// into the displayed text (see [TextView.content]). It is nil if the
+	// matches need to be determined again.
+	searchMatches [][2]int
+
+	// The index of the current match in searchMatches or -1 if there is none.
+	currentMatch int
+
+	// A temporary flag which, when true, will bring the current match into the
+	// visible screen the next time the text view is drawn.
+	scrollToMatch bool
+
+	// The styles of search matches and of the current match.
+	matchStyle, currentMatchStyle tcell.Style
+
 	// An optional function which is called when the content of the text view
 	// has changed.
 	changed func()
@@ -234,6 +391,21 @@ type TextView struct {
 	// highlighted.
 	highlighted func(added, removed, remaining []string)
 
+	// An optional function which is called when the user clicks on a
+	// hyperlink.
+	linkClicked func(url string)
+
+	// An optional function which is called when the user clicks on a region.
+	regionClicked func(regionID string)
+
+	// An optional function which is called when the mouse pointer moves onto
+	// a region or leaves it.
+	regionHovered func(regionID string)
+
+	// An optional function which is called when following the end of the text
+	// is paused or resumed.
+	followChanged func(following bool)
+
 	// A callback function set by the Form class and called when the user leaves
 	// this form item.
 	finished func(tcell.Key)
@@ -242,17 +414,20 @@ type TextView struct {
 // NewTextView returns a new text view.
 func NewTextView() *TextView {
 	return &TextView{
-		Box:        NewBox(),
-		labelStyle: tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
-		highlights: make(map[string]struct{}),
-		lineOffset: -1,
-		scrollable: true,
-		align:      AlignLeft,
-		wrap:       true,
-		wordWrap:   true,
-		textStyle:  tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(Styles.PrimaryTextColor),
-		regionTags: false,
-		styleTags:  false,
+		Box:               NewBox(),
+		labelStyle:        tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
+		highlights:        make(map[string]struct{}),
+		lineOffset:        -1,
+		currentMatch:      -1,
+		matchStyle:        tcell.StyleDefault.Background(Styles.SecondaryTextColor).Foreground(Styles.PrimitiveBackgroundColor),
+		currentMatchStyle: tcell.StyleDefault.Background(Styles.TertiaryTextColor).Foreground(Styles.PrimitiveBackgroundColor),
+		scrollable:        true,
+		align:             AlignLeft,
+		wrap:              true,
+		wordWrap:          true,
+		textStyle:         tcell.StyleDefault.Background(Styles.PrimitiveBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		regionTags:        false,
+		styleTags:         false,
 	}
 }
 
@@ -334,8 +509,11 @@ func (t *TextView) SetWordWrap(wrapOnWords bool) *TextView {
 }
 
 // SetMaxLines sets the maximum number of lines for this text view. Lines at the
-// beginning of the text will be discarded when the text view is drawn, so as to
-// remain below this value. Only lines above the first visible line are removed.
+// beginning of the text will be discarded when text is written to the text
+// view (once it has been drawn) and when it is drawn, so as to remain below
+// this value. Discarding lines does not copy the remaining text every time so
+// this is suitable for long-running streams such as logs. Use
+// [TextView.SetEvictedFunc] to be notified of discarded lines.
 //
 // Broken-over lines via word/character wrapping are counted individually.
 //
@@ -347,6 +525,18 @@ func (t *TextView) SetMaxLines(maxLines int) *TextView {
 	return t
 }
 
+// SetEvictedFunc sets a handler which is called when lines are discarded from
+// the start of the text because of [TextView.SetMaxLines] or because the text
+// view is not scrollable (see [TextView.SetScrollable]). It receives the number
+// of discarded (possibly wrapped) lines and their text, including any tags.
+//
+// The handler is called while the text view is locked, i.e. it must not call
+// any of the text view's functions which acquire the lock.
+func (t *TextView) SetEvictedFunc(handler func(lines int, text string)) *TextView {
+	t.evicted = handler
+	return t
+}
+
 // SetTextAlign sets the text alignment within the text view. This must be
 // either AlignLeft, AlignCenter, or AlignRight.
 func (t *TextView) SetTextAlign(align int) *TextView {
@@ -387,6 +577,7 @@ func (t *TextView) SetText(text string) *TextView {
 	t.Lock()
 	defer t.Unlock()
 	t.text.Reset()
+	t.textStart = 0
 	t.text.WriteString(text)
 	t.resetIndex()
 	if t.changed != nil {
@@ -398,18 +589,18 @@ func (t *TextView) SetText(text string) *TextView {
 // GetText returns the current text of this text view. If "stripAllTags" is set
 // to true, any region/style tags are stripped from the text.
 func (t *TextView) GetText(stripAllTags bool) string {
-	if !stripAllTags || (!t.styleTags && !t.regionTags) {
-		return t.text.String()
+	if !stripAllTags || (!t.styleTags && !t.regionTags && !t.markdown) {
+		return t.text.String()[t.textStart:]
 	}
 
 	var (
 		str   strings.Builder
 		state *stepState
-		text  = t.text.String()
+		text  = t.content()[t.textStart:]
 		opts  stepOptions
 		ch    string
 	)
-	if t.styleTags {
+	if t.styleTags || t.markdown {
 		opts = stepOptionsStyle
 	}
 	if t.regionTags {
@@ -432,7 +623,7 @@ func (t *TextView) GetOriginalLineCount() int {
 
 	var (
 		state *stepState
-		str       = t.text.String()
+		str       = t.text.String()[t.textStart:]
 		lines int = 1
 	)
 	for len(str) > 0 {
@@ -465,6 +656,115 @@ func (t *TextView) SetRegions(regions bool) *TextView {
 	return t
 }
 
+// SetContentProvider sets functions which provide the text of the text view
+// line by line instead of the text buffer: lineCount returns the number of
+// lines and line returns the line with the given index (starting at 0, without
+// a trailing newline). Only the lines which are visible are requested when the
+// text view is drawn so memory usage does not depend on the total number of
+// lines. This is useful for very large texts such as log files which are
+// indexed elsewhere. Provide nil for both functions to return to using the
+// text buffer.
+//
+// In this mode, the text view's row offset (see [TextView.ScrollTo] and
+// [TextView.GetScrollOffset]) refers to lines, not screen rows, each line
+// starts with the default text style, and the text buffer as well as regions,
+// search matches, and [TextView.GetText] only cover the visible lines.
+// Writing to the text view has no lasting effect. Call [Application.Draw] to
+// update the screen when the provided lines change.
+//
+// The functions are called while drawing the text view, i.e. in the
+// application's main goroutine.
+func (t *TextView) SetContentProvider(lineCount func() int, line func(index int) string) *TextView {
+	t.Lock()
+	defer t.Unlock()
+	t.lineCount, t.line = lineCount, line
+	if lineCount == nil || line == nil {
+		t.lineCount, t.line = nil, nil
+	}
+	t.text.Reset()
+	t.textStart = 0
+	t.resetIndex()
+	return t
+}
+
+// loadLines replaces the text buffer with the lines from the content provider
+// (see [TextView.SetContentProvider]) which are visible in a text area of the
+// given height. It returns the index of the first loaded line. The line offset
+// is changed to the first row of the requested top line.
+func (t *TextView) loadLines(height int) int {
+	// Which lines do we need?
+	count := t.lineCount()
+	top := t.lineOffset
+	if t.trackEnd || top >= count {
+		top = count - 1
+	}
+	if top < 0 {
+		top = 0
+	}
+	from := top // Earlier lines may be needed to fill the text area.
+	if from > count-height {
+		from = count - height
+	}
+	if from < 0 {
+		from = 0
+	}
+
+	// Load them.
+	t.text.Reset()
+	t.textStart = 0
+	var topOffset int
+	for index := from; index < count && index < top+height; index++ {
+		if index > from {
+			t.text.WriteByte('\n')
+		}
+		if index == top {
+			topOffset = t.text.Len()
+		}
+		t.text.WriteString(t.line(index))
+	}
+	t.resetIndex()
+
+	// Scroll to the top line.
+	if !t.trackEnd {
+		t.parseAhead(t.lastWidth, func(lineNumber int, line *textViewLine) bool {
+			return line.offset >= topOffset
+		})
+		t.lineOffset = sort.Search(len(t.lineIndex), func(index int) bool {
+			return t.lineIndex[index].offset >= topOffset
+		})
+	}
+	return from
+}
+
+// SetMarkdown sets the flag that causes the text to be interpreted as a
+// Markdown document. It is then displayed with styled headings, emphasis, code
+// spans and blocks, ordered and unordered lists (including task lists), block
+// quotes, horizontal rules, links, and tables. Paragraphs, list items, and
+// table cells are reflowed to fit the available width.
+//
+// Tags in the document are shown as they are unless dynamic colors or regions
+// are enabled (see [TextView.SetDynamicColors] and [TextView.SetRegions]), in
+// which case they are interpreted. [TextView.GetText] returns the Markdown
+// document unless tags are stripped.
+//
+// The document is rendered again whenever it changes, which makes this mode
+// unsuitable for streaming very large texts. [TextView.SetMaxLines] and
+// non-scrollable text views don't discard any text in this mode.
+func (t *TextView) SetMarkdown(markdown bool) *TextView {
+	if t.markdown != markdown {
+		t.resetIndex() // This invalidates the entire index.
+	}
+	if markdown && t.textStart > 0 {
+		// The document is rendered as a whole, discard purged text for good.
+		text := t.text.String()[t.textStart:]
+		t.text.Reset()
+		t.text.WriteString(text)
+		t.textStart = 0
+	}
+	t.markdown = markdown
+	return t
+}
+
 // SetChangedFunc sets a handler function which is called when the text of the
 // text view has changed. This is useful when text is written to this
 // [io.Writer] in a separate goroutine. Doing so does not automatically cause
@@ -507,6 +807,43 @@ func (t *TextView) SetHighlightedFunc(handler func(added, removed, remaining []s
 	return t
 }
 
+// SetLinkClickedFunc sets a handler which is called when the user clicks on a
+// hyperlink with the mouse. The handler receives the hyperlink's URL. Hyperlinks
+// are defined with style tags (see package documentation) so dynamic colors must
+// be enabled (see [TextView.SetDynamicColors]).
+//
+// Clicks on hyperlinks are not processed any further, i.e. regions under the
+// hyperlink will not be highlighted.
+func (t *TextView) SetLinkClickedFunc(handler func(url string)) *TextView {
+	t.linkClicked = handler
+	return t
+}
+
+// SetRegionClickedFunc sets a handler which is called when the user clicks on a
+// region with the mouse (see [TextView.SetRegions]). The handler receives the
+// region's ID. It is called after the region was highlighted as described in
+// [TextView.Highlight].
+func (t *TextView) SetRegionClickedFunc(handler func(regionID string)) *TextView {
+	t.regionClicked = handler
+	return t
+}
+
+// SetRegionHoveredFunc sets a handler which is called when the mouse pointer
+// moves onto a region or leaves it. The handler receives the region's ID or an
+// empty string when the mouse pointer left the region.
+func (t *TextView) SetRegionHoveredFunc(handler func(regionID string)) *TextView {
+	t.regionHovered = handler
+	return t
+}
+
+// SetHoverHighlights sets the flag that, if true, causes the region under the
+// mouse pointer to be underlined. Together with [TextView.SetRegionClickedFunc],
+// this allows the text view to be used as a simple hypertext widget.
+func (t *TextView) SetHoverHighlights(hover bool) *TextView {
+	t.hoverHighlights = hover
+	return t
+}
+
 // SetFinishedFunc sets a callback invoked when the user leaves this form item.
 func (t *TextView) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
 	t.finished = handler
@@ -558,6 +895,66 @@ func (t *TextView) ScrollToEnd() *TextView {
 	return t
 }
 
+// SetFollow sets the flag that, if true, keeps the text view scrolled to the
+// end of the text as new text is added, e.g. to follow a log file. Following is
+// paused when the user scrolls up and resumed when the user scrolls back to
+// the end of the text (or presses "G" or the End key). Use
+// [TextView.SetFollowChangedFunc] to be notified of these changes, e.g. to
+// display an indicator.
+//
+// Nothing happens if the text view is not scrollable.
+func (t *TextView) SetFollow(follow bool) *TextView {
+	t.follow = follow
+	if follow && t.scrollable {
+		t.trackEnd = true
+		t.columnOffset = 0
+	}
+	return t
+}
+
+// IsFollowing returns true if following was enabled with [TextView.SetFollow]
+// and is not currently paused.
+func (t *TextView) IsFollowing() bool {
+	return t.follow && t.trackEnd
+}
+
+// SetFollowChangedFunc sets a handler which is called when following the end
+// of the text (see [TextView.SetFollow]) is paused or resumed by the user. The
+// handler receives true if following was resumed and false if it was paused.
+func (t *TextView) SetFollowChangedFunc(handler func(following bool)) *TextView {
+	t.followChanged = handler
+	return t
+}
+
+// updateFollow resumes following the end of the text if it was paused and the
+// text view is now scrolled to the end of the text. It then notifies the
+// handler set with [TextView.SetFollowChangedFunc] if the following state is
+// different from the given previous state.
+func (t *TextView) updateFollow(wasFollowing bool) {
+	if !t.follow {
+		return
+	}
+	if !t.trackEnd {
+		t.Lock()
+		var lines int
+		if t.lineCount != nil {
+			lines = t.lineCount()
+		} else {
+			t.parseAhead(t.lastWidth, func(lineNumber int, line *textViewLine) bool {
+				return false
+			})
+			lines = len(t.lineIndex)
+		}
+		if t.lineOffset >= lines-t.pageSize {
+			t.trackEnd = true
+		}
+		t.Unlock()
+	}
+	if t.trackEnd != wasFollowing && t.followChanged != nil {
+		t.followChanged(t.trackEnd)
+	}
+}
+
 // GetScrollOffset returns the number of rows and columns that are skipped at
 // the top left corner when the text view has been scrolled.
 func (t *TextView) GetScrollOffset() (row, column int) {
@@ -579,6 +976,7 @@ func (t *TextView) Clear() *TextView {
 // and anywhere that we need to perform a write without locking the buffer.
 func (t *TextView) clear() {
 	t.text.Reset()
+	t.textStart = 0
 	t.resetIndex()
 }
 
@@ -728,13 +1126,13 @@ func (t *TextView) GetRegionText(regionID string) string {
 	// Extract text from region.
 	var (
 		line       = t.lineIndex[lineNumber]
-		text       = t.text.String()[line.offset:]
+		text       = t.content()[line.offset:]
 		st         = *line.state
 		state      = &st
 		options    = stepOptionsRegion
 		regionText strings.Builder
 	)
-	if t.styleTags {
+	if t.styleTags || t.markdown {
 		options |= stepOptionsStyle
 	}
 	for len(text) > 0 {
@@ -750,6 +1148,157 @@ func (t *TextView) GetRegionText(regionID string) string {
 	return regionText.String()
 }
 
+// Search highlights all matches of the given pattern in the text (with any tags
+// removed) and makes the first match at or after the top of the visible area
+// the current match, scrolling it into view. The pattern is a regular
+// expression if isRegexp is true, otherwise it is searched for literally. An
+// empty pattern ends the search. It returns the number of matches and an error
+// if the regular expression is invalid.
+//
+// Use [TextView.NextMatch] and [TextView.PreviousMatch] to navigate the
+// matches. Matches are updated as text is added to the text view.
+func (t *TextView) Search(pattern string, isRegexp bool) (matches int, err error) {
+	t.search, t.searchMatches, t.currentMatch = nil, nil, -1
+	if pattern == "" {
+		return 0, nil
+	}
+	if !isRegexp {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if t.search, err = regexp.Compile(pattern); err != nil {
+		return 0, err
+	}
+
+	// Find the first match in the visible area.
+	all := t.getMatches()
+	if len(all) == 0 {
+		return 0, nil
+	}
+	var top int
+	if t.lineOffset >= 0 && t.lineOffset < len(t.lineIndex) {
+		top = t.lineIndex[t.lineOffset].offset
+	}
+	t.currentMatch = sort.Search(len(all), func(index int) bool {
+		return all[index][0] >= top
+	}) % len(all)
+	t.scrollToMatch = t.scrollable
+	t.trackEnd = false
+	return len(all), nil
+}
+
+// GetMatchCount returns the number of matches of the current search (see
+// [TextView.Search]).
+func (t *TextView) GetMatchCount() int {
+	return len(t.getMatches())
+}
+
+// GetCurrentMatch returns the index of the current match of the current search
+// (see [TextView.Search]), starting with 0, or -1 if there is no current match.
+func (t *TextView) GetCurrentMatch() int {
+	if t.currentMatch >= len(t.getMatches()) {
+		t.currentMatch = -1
+	}
+	return t.currentMatch
+}
+
+// NextMatch makes the next match of the current search the current match,
+// wrapping around at the end of the text, and scrolls it into view the next
+// time the text view is drawn. Nothing happens if there are no matches.
+func (t *TextView) NextMatch() *TextView {
+	if matches := t.getMatches(); len(matches) > 0 {
+		t.currentMatch = (t.GetCurrentMatch() + 1) % len(matches)
+		t.scrollToMatch = t.scrollable
+		t.trackEnd = false
+	}
+	return t
+}
+
+// PreviousMatch makes the previous match of the current search the current
+// match, wrapping around at the start of the text, and scrolls it into view the
+// next time the text view is drawn. Nothing happens if there are no matches.
+func (t *TextView) PreviousMatch() *TextView {
+	if matches := t.getMatches(); len(matches) > 0 {
+		current := t.GetCurrentMatch()
+		if current <= 0 {
+			current = len(matches)
+		}
+		t.currentMatch = current - 1
+		t.scrollToMatch = t.scrollable
+		t.trackEnd = false
+	}
+	return t
+}
+
+// SetMatchStyle sets the styles of search matches and of the current match
+// (see [TextView.Search]).
+func (t *TextView) SetMatchStyle(match, current tcell.Style) *TextView {
+	t.matchStyle, t.currentMatchStyle = match, current
+	return t
+}
+
+// getMatches returns [TextView.searchMatches], determining them first if
+// needed.
+func (t *TextView) getMatches() [][2]int {
+	if t.search == nil || t.searchMatches != nil {
+		return t.searchMatches
+	}
+	t.searchMatches = [][2]int{}
+	text := t.content()[t.textStart:]
+	var options stepOptions
+	if t.styleTags || t.markdown {
+		options |= stepOptionsStyle
+	}
+	if t.regionTags {
+		options |= stepOptionsRegion
+	}
+	if options == 0 {
+		for _, match := range t.search.FindAllStringIndex(text, -1) {
+			if match[0] < match[1] {
+				t.searchMatches = append(t.searchMatches, [2]int{t.textStart + match[0], t.textStart + match[1]})
+			}
+		}
+		return t.searchMatches
+	}
+
+	// Search in the text without tags.
+	var (
+		stripped strings.Builder
+		state    *stepState
+		str      = text
+		ch       string
+	)
+	for len(str) > 0 {
+		ch, str, state = step(str, state, options)
+		stripped.WriteString(ch)
+	}
+	var boundaries []int
+	for _, match := range t.search.FindAllStringIndex(stripped.String(), -1) {
+		if match[0] < match[1] {
+			boundaries = append(boundaries, match[0], match[1]-1)
+		}
+	}
+
+	// Translate the match boundaries to indices into the original text.
+	var strippedIndex, boundary int
+	index := t.textStart
+	str, state = text, nil
+	for len(str) > 0 && boundary < len(boundaries) {
+		ch, str, state = step(str, state, options)
+		start := index + state.GrossLength() - len(ch) // Skip tags.
+		for boundary < len(boundaries) && boundaries[boundary] < strippedIndex+len(ch) {
+			boundaries[boundary] = start + boundaries[boundary] - strippedIndex
+			boundary++
+		}
+		strippedIndex += len(ch)
+		index += state.GrossLength()
+	}
+	for index := 0; index+1 < len(boundaries); index += 2 {
+		t.searchMatches = append(t.searchMatches, [2]int{boundaries[index], boundaries[index+1] + 1})
+	}
+
+	return t.searchMatches
+}
+
 // Focus is called when this primitive receives focus.
 func (t *TextView) Focus(delegate func(p Primitive)) {
 	// Implemented here with locking because this is used by layout primitives.
@@ -796,7 +1345,26 @@ func (t *TextView) write(p []byte) (n int, err error) {
 		}()
 	}
 
-	return t.text.Write(p)
+	if t.markdown {
+		t.resetIndex() // Added text may change how previous text is rendered.
+	}
+	t.searchMatches = nil
+	if n, err = t.text.Write(p); err != nil {
+		return
+	}
+
+	// Discard old lines if we have too many. This requires a previous Draw()
+	// to know the width.
+	if t.maxLines > 0 && t.lastWidth > 0 && !t.markdown && t.lineCount == nil {
+		t.parseAhead(t.lastWidth, func(lineNumber int, line *textViewLine) bool {
+			return false
+		})
+		if len(t.lineIndex) > t.maxLines {
+			t.purge(len(t.lineIndex) - t.maxLines)
+		}
+	}
+
+	return
 }
 
 // BatchWriter returns a new writer that can be used to write into the buffer
@@ -822,11 +1390,61 @@ func (t *TextView) BatchWriter() TextViewWriter {
 	}
 }
 
+// StreamWriter returns a new writer which can be used to stream large amounts
+// of text into the text view, e.g. the output of a chatty subprocess. The
+// writer may be used from any goroutine. Concurrent writes are collected and
+// forwarded to the text view in batches, at most "maxWrites" times per second
+// (10 if 0 or less). Because the "changed" callback (see
+// [TextView.SetChangedFunc]) is only invoked once per batch, this also limits
+// the number of redraws triggered by it.
+//
+// At most "bufferSize" bytes (64 KB if 0 or less) are held back. Writes block
+// while the buffer is full so that fast producers are slowed down to the rate
+// at which the text view can take the text.
+//
+// Close the writer to forward any remaining text immediately. Example:
+//
+//	w := textView.StreamWriter(20, 0)
+//	cmd.Stdout = w
+//	cmd.Run()
+//	w.Close()
+func (t *TextView) StreamWriter(maxWrites, bufferSize int) *TextViewStreamWriter {
+	if maxWrites <= 0 {
+		maxWrites = 10
+	}
+	if bufferSize <= 0 {
+		bufferSize = 64 * 1024
+	}
+	w := &TextViewStreamWriter{
+		t:          t,
+		interval:   time.Second / time.Duration(maxWrites),
+		bufferSize: bufferSize,
+	}
+	w.flushed = sync.NewCond(w)
+	return w
+}
+
 // resetIndex resets all indexed data, including the line index.
 func (t *TextView) resetIndex() {
 	t.lineIndex = nil
 	t.regions = make(map[string]int)
 	t.longestLine = 0
+	t.markdownWidth = -1
+	t.searchMatches = nil
+}
+
+// content returns the text which is parsed and displayed. This is the text
+// buffer itself unless it contains a Markdown document, in which case the
+// rendered document is returned for the last drawn width.
+func (t *TextView) content() string {
+	if !t.markdown {
+		return t.text.String()
+	}
+	if t.markdownWidth != t.lastWidth {
+		t.markdownText = renderMarkdown(t.text.String(), t.lastWidth, !t.styleTags && !t.regionTags)
+		t.markdownWidth = t.lastWidth
+	}
+	return t.markdownText
 }
 
 // parseAhead parses the text buffer starting at the last line in
@@ -845,7 +1463,7 @@ func (t *TextView) parseAhead(width int, stop func(lineNumber int, line *textVie
 
 	// What kind of tags do we scan for?
 	var options stepOptions
-	if t.styleTags {
+	if t.styleTags || t.markdown {
 		options |= stepOptionsStyle
 	}
 	if t.regionTags {
@@ -854,16 +1472,18 @@ func (t *TextView) parseAhead(width int, stop func(lineNumber int, line *textVie
 
 	// Start parsing at the last line in the index.
 	var lastLine *textViewLine
-	str := t.text.String()
+	str := t.content()
 	if len(t.lineIndex) == 0 {
 		// Insert the first line.
 		lastLine = &textViewLine{
+			offset: t.textStart,
 			state: &stepState{
 				unisegState: -1,
 				style:       t.textStyle,
 			},
 		}
 		t.lineIndex = append(t.lineIndex, lastLine)
+		str = str[t.textStart:]
 	} else {
 		// Reset the last line.
 		lastLine = t.lineIndex[len(t.lineIndex)-1]
@@ -1022,14 +1642,27 @@ func (t *TextView) Draw(screen tcell.Screen) {
 	}
 
 	// If the width has changed, we need to reindex.
-	if width != t.lastWidth && t.wrap {
+	if width != t.lastWidth && (t.wrap || t.markdown) {
 		t.resetIndex()
 	}
 	t.lastWidth = width
 
+	// Load the visible lines from the content provider. Afterwards, the line
+	// offset needs to refer to provided lines again.
+	if t.lineCount != nil {
+		start := t.loadLines(height)
+		defer func() {
+			t.drawnLine = t.lineOffset
+			if t.lineOffset < len(t.lineIndex) {
+				start += strings.Count(t.text.String()[:t.lineIndex[t.lineOffset].offset], "\n")
+			}
+			t.lineOffset = start
+		}()
+	}
+
 	// What are our parse options?
 	var options stepOptions
-	if t.styleTags {
+	if t.styleTags || t.markdown {
 		options |= stepOptionsStyle
 	}
 	if t.regionTags {
@@ -1070,7 +1703,7 @@ func (t *TextView) Draw(screen tcell.Screen) {
 				line := t.lineIndex[fromHighlight]
 				st := *line.state
 				state := &st
-				str := t.text.String()[line.offset:]
+				str := t.content()[line.offset:]
 				var posHighlight int
 				for len(str) > 0 && posHighlight < line.width && state.region != firstRegion {
 					_, str, state = step(str, state, options)
@@ -1090,6 +1723,12 @@ func (t *TextView) Draw(screen tcell.Screen) {
 	}
 	t.scrollToHighlights = false
 
+	// Scroll to the current match.
+	if t.scrollToMatch && t.GetCurrentMatch() >= 0 {
+		t.scrollToMatchLine(width, height)
+	}
+	t.scrollToMatch = false
+
 	// Make sure our index has enough lines.
 	t.parseAhead(width, func(lineNumber int, line *textViewLine) bool {
 		return lineNumber >= t.lineOffset+height
@@ -1140,6 +1779,7 @@ func (t *TextView) Draw(screen tcell.Screen) {
 
 		info := t.lineIndex[line]
 		info.regions = nil
+		info.links = nil
 
 		// Determine starting point of the text and the screen.
 		var skipWidth, xPos int
@@ -1165,10 +1805,14 @@ func (t *TextView) Draw(screen tcell.Screen) {
 		}
 
 		// Draw the line text.
-		str := t.text.String()[info.offset:]
+		str := t.content()[info.offset:]
 		st := *info.state
 		state := &st
 		var processed int
+		matches := t.getMatches()
+		match := sort.Search(len(matches), func(index int) bool {
+			return matches[index][1] > info.offset
+		})
 		for len(str) > 0 && xPos < width && processed < info.length {
 			var ch string
 			ch, str, state = step(str, state, options)
@@ -1180,6 +1824,7 @@ func (t *TextView) Draw(screen tcell.Screen) {
 					w = TabSize
 				}
 			}
+			position := info.offset + processed + state.GrossLength() - len(ch)
 			processed += state.GrossLength()
 
 			// Don't draw anything while we skip characters.
@@ -1214,6 +1859,23 @@ func (t *TextView) Draw(screen tcell.Screen) {
 					style = style.Background(fg).Foreground(bg)
 				}
 
+				// Is the mouse pointer over this region?
+				if t.hoverHighlights && state.region != "" && state.region == t.hoveredRegion {
+					style = style.Underline(true)
+				}
+
+				// Is this character part of a search match?
+				for match < len(matches) && matches[match][1] <= position {
+					match++
+				}
+				if match < len(matches) && matches[match][0] <= position {
+					if match == t.currentMatch {
+						style = t.currentMatchStyle
+					} else {
+						style = t.matchStyle
+					}
+				}
+
 				// Paint on screen.
 				for offset := w - 1; offset >= 0; offset-- {
 					runes := []rune(ch)
@@ -1242,6 +1904,20 @@ func (t *TextView) Draw(screen tcell.Screen) {
 					}
 					info.regions[state.region] = fromTo
 				}
+
+				// Register this hyperlink.
+				if state.url != "" {
+					if info.links == nil {
+						info.links = make(map[string][2]int)
+					}
+					fromTo, ok := info.links[state.url]
+					if !ok {
+						fromTo = [2]int{xPos, xPos + w}
+					} else if xPos+w > fromTo[1] {
+						fromTo[1] = xPos + w
+					}
+					info.links[state.url] = fromTo
+				}
 			}
 
 			xPos += w
@@ -1251,6 +1927,9 @@ func (t *TextView) Draw(screen tcell.Screen) {
 	// If this view is not scrollable, we'll purge the buffer of lines that have
 	// scrolled out of view.
 	var purgeStart int
+	if t.lineCount != nil {
+		return // The buffer only contains the visible lines anyway.
+	}
 	if !t.scrollable && t.lineOffset > 0 {
 		purgeStart = t.lineOffset
 	}
@@ -1261,20 +1940,166 @@ func (t *TextView) Draw(screen tcell.Screen) {
 		purgeStart = len(t.lineIndex) - t.maxLines
 	}
 
-	// Purge.
-	if purgeStart > 0 && purgeStart < len(t.lineIndex) {
-		newText := t.text.String()[t.lineIndex[purgeStart].offset:]
-		t.text.Reset()
-		t.text.WriteString(newText)
-		t.resetIndex()
+	t.purge(purgeStart)
+}
+
+// purge discards the given number of lines from the start of the line index
+// and the corresponding text. The text buffer is only compacted when more than
+// half of it was discarded.
+func (t *TextView) purge(lines int) {
+	if lines <= 0 || lines >= len(t.lineIndex) || t.markdown || t.lineCount != nil {
+		return
+	}
+	start := t.lineIndex[lines].offset
+	if t.evicted != nil {
+		t.evicted(lines, t.text.String()[t.textStart:start])
+	}
+	t.textStart = start
+
+	// Adjust what refers to line numbers.
+	t.lineIndex = t.lineIndex[lines:]
+	t.lineOffset -= lines
+	if t.lineOffset < 0 {
 		t.lineOffset = 0
 	}
+	t.longestLine = 0
+	for _, line := range t.lineIndex {
+		if line.width > t.longestLine {
+			t.longestLine = line.width
+		}
+	}
+	for regionID, line := range t.regions {
+		if line < lines {
+			delete(t.regions, regionID)
+		} else {
+			t.regions[regionID] = line - lines
+		}
+	}
+
+	// Remove discarded search matches.
+	if t.searchMatches != nil {
+		discarded := sort.Search(len(t.searchMatches), func(index int) bool {
+			return t.searchMatches[index][0] >= start
+		})
+		t.searchMatches = t.searchMatches[discarded:]
+		if t.currentMatch -= discarded; t.currentMatch < 0 {
+			t.currentMatch = -1
+		}
+	}
+
+	// Compact the buffer.
+	if t.textStart > t.text.Len()/2 {
+		text := t.text.String()[t.textStart:]
+		t.text.Reset()
+		t.text.WriteString(text)
+		for _, line := range t.lineIndex {
+			line.offset -= t.textStart
+		}
+		for index := range t.searchMatches {
+			t.searchMatches[index][0] -= t.textStart
+			t.searchMatches[index][1] -= t.textStart
+		}
+		t.textStart = 0
+	}
+}
+
+// scrollToMatchLine adjusts the line and column offsets such that the current
+// search match is visible in a text area of the given size.
+func (t *TextView) scrollToMatchLine(width, height int) {
+	start := t.getMatches()[t.currentMatch][0]
+	t.parseAhead(width, func(lineNumber int, line *textViewLine) bool {
+		return line.offset > start
+	})
+	line := sort.Search(len(t.lineIndex), func(index int) bool {
+		return t.lineIndex[index].offset > start
+	}) - 1
+	if line < 0 {
+		return
+	}
+	if line < t.lineOffset || line >= t.lineOffset+height {
+		t.lineOffset = line - height/2
+	}
+
+	// Without wrapping, the match may be off-screen horizontally.
+	if t.wrap || t.align != AlignLeft {
+		return
+	}
+	var options stepOptions
+	if t.styleTags || t.markdown {
+		options |= stepOptionsStyle
+	}
+	if t.regionTags {
+		options |= stepOptionsRegion
+	}
+	info := t.lineIndex[line]
+	st := *info.state
+	state := &st
+	str := t.content()[info.offset:start]
+	var column int
+	for len(str) > 0 {
+		var ch string
+		ch, str, state = step(str, state, options)
+		if ch == "\t" {
+			column += TabSize - column%TabSize
+		} else {
+			column += state.Width()
+		}
+	}
+	if column < t.columnOffset || column >= t.columnOffset+width {
+		t.columnOffset = column - width/4
+	}
+}
+
+// indexLine returns the index into [TextView.lineIndex] of the line drawn in
+// the given row of the text area.
+func (t *TextView) indexLine(row int) int {
+	if t.lineCount != nil {
+		return t.drawnLine + row
+	}
+	return t.lineOffset + row
+}
+
+// regionAt returns the ID of the region drawn at the given screen coordinates
+// or an empty string if there is no region.
+func (t *TextView) regionAt(x, y int) string {
+	rectX, rectY, _, _ := t.GetInnerRect()
+	x -= rectX
+	y -= rectY
+	line := t.indexLine(y)
+	if y < 0 || line < 0 || line >= len(t.lineIndex) {
+		return ""
+	}
+	for regionID, fromTo := range t.lineIndex[line].regions {
+		if x >= fromTo[0] && x < fromTo[1] {
+			return regionID
+		}
+	}
+	return ""
+}
+
+// linkAt returns the URL of the hyperlink drawn at the given screen
+// coordinates or an empty string if there is no hyperlink.
+func (t *TextView) linkAt(x, y int) string {
+	rectX, rectY, _, _ := t.GetInnerRect()
+	x -= rectX
+	y -= rectY
+	line := t.indexLine(y)
+	if y < 0 || line < 0 || line >= len(t.lineIndex) {
+		return ""
+	}
+	for url, fromTo := range t.lineIndex[line].links {
+		if x >= fromTo[0] && x < fromTo[1] {
+			return url
+		}
+	}
+	return ""
 }
 
 // InputHandler returns the handler for this primitive.
 func (t *TextView) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
 	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
 		key := event.Key()
+		defer t.updateFollow(t.IsFollowing())
 
 		if key == tcell.KeyEscape || key == tcell.KeyEnter || key == tcell.KeyTab || key == tcell.KeyBacktab {
 			if t.done != nil {
@@ -1339,32 +2164,49 @@ func (t *TextView) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 func (t *TextView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 		x, y := event.Position()
+		if action == MouseMove && t.regionTags {
+			// Keep track of the region under the mouse pointer. While there is
+			// one, we capture the mouse to notice when the pointer leaves.
+			var regionID string
+			if t.InRect(x, y) {
+				regionID = t.regionAt(x, y)
+			}
+			if regionID != t.hoveredRegion {
+				t.hoveredRegion = regionID
+				if t.regionHovered != nil {
+					t.regionHovered(regionID)
+				}
+				consumed = true
+			}
+			if regionID != "" {
+				capture = t
+			}
+			return
+		}
 		if !t.InRect(x, y) {
 			return false, nil
 		}
+		defer t.updateFollow(t.IsFollowing())
 
 		switch action {
 		case MouseLeftDown:
 			setFocus(t)
 			consumed = true
 		case MouseLeftClick:
+			if t.linkClicked != nil {
+				if url := t.linkAt(x, y); url != "" {
+					t.linkClicked(url)
+					consumed = true
+					break
+				}
+			}
 			if t.regionTags {
 				// Find a region to highlight.
-				rectX, rectY, _, _ := t.GetInnerRect()
-				x -= rectX
-				y -= rectY
-				var highlightedID string
-				if y+t.lineOffset < len(t.lineIndex) {
-					line := t.lineIndex[y+t.lineOffset]
-					for regionID, fromTo := range line.regions {
-						if x >= fromTo[0] && x < fromTo[1] {
-							highlightedID = regionID
-							break
-						}
-					}
-				}
-				if highlightedID != "" {
+				if highlightedID := t.regionAt(x, y); highlightedID != "" {
 					t.Highlight(highlightedID)
+					if t.regionClicked != nil {
+						t.regionClicked(highlightedID)
+					}
 				} else if !t.toggleHighlights {
 					t.Highlight()
 				}
@@ -0,0 +1,23 @@
+package tview
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestTextViewPurgeLongestLine tests that the column offset is clamped to the
+// remaining lines after the longest line was discarded.
+func TestTextViewPurgeLongestLine(t *testing.T) {
+	textView := NewTextView().SetWrap(false).SetMaxLines(2)
+	drawPrimitive(t, textView, 10, 2)
+	fmt.Fprintln(textView, strings.Repeat("x", 50))
+	fmt.Fprintln(textView, "short")
+	fmt.Fprintln(textView, "short")
+	fmt.Fprint(textView, "short")
+	textView.ScrollTo(0, 30)
+	drawPrimitive(t, textView, 10, 2)
+	if _, column := textView.GetScrollOffset(); column != 0 {
+		t.Errorf("column offset is %d, expected 0", column)
+	}
+}
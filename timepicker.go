@@ -0,0 +1,610 @@
+package tview
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// The editable segments of a time picker.
+const (
+	timeSegmentHours = iota
+	timeSegmentMinutes
+	timeSegmentSeconds
+	timeSegmentPeriod // AM or PM.
+)
+
+// TimePicker is a form item for entering a time of day, e.g. "14:30", or a
+// duration (see [NewDurationPicker]). The value is shown in segments (hours,
+// minutes, and optionally seconds, see [TimePicker.ShowSeconds]) which are
+// edited one at a time. Times of day are shown in 24-hour format or, with
+// [TimePicker.SetHour12], in 12-hour format with an AM/PM segment.
+//
+// The following keys are available:
+//
+//   - Left arrow / right arrow: Move to the previous / next segment.
+//   - Up arrow / down arrow: Increase / decrease the current segment.
+//   - Home / end: Move to the first / last segment.
+//   - 0-9: Type the current segment's value. The next segment is selected
+//     when no further digit can follow.
+//   - a / p: Switch to AM / PM in 12-hour format.
+//   - Backspace: Set the current segment to zero.
+//
+// Values which are out of range for a segment cannot be entered. Clicking on a
+// segment selects it, the mouse wheel changes it.
+type TimePicker struct {
+	*Box
+
+	// Whether or not this time picker is disabled/read-only.
+	disabled bool
+
+	// Whether this is a duration picker rather than a time of day picker.
+	duration bool
+
+	// The value in seconds (since midnight for a time of day).
+	value int
+
+	// The largest duration in seconds, for duration pickers only.
+	maxDuration int
+
+	// Whether times of day are shown in 12-hour format.
+	hour12 bool
+
+	// Whether the seconds segment is shown.
+	showSeconds bool
+
+	// The segment which is currently being edited.
+	segment int
+
+	// The digits typed into the current segment so far.
+	typed string
+
+	// The text to be displayed before the field.
+	label string
+
+	// The screen width of the label area. A value of 0 means use the width of
+	// the label text.
+	labelWidth int
+
+	// The label style.
+	labelStyle tcell.Style
+
+	// The style of the field.
+	fieldStyle tcell.Style
+
+	// The style of the current segment when the field has focus.
+	focusStyle tcell.Style
+
+	// An optional function which is called when the value was changed.
+	changed func(value time.Duration)
+
+	// An optional function which is called when the user indicated that they
+	// are done entering the value. The key which was pressed is provided (tab,
+	// shift-tab, enter, or escape).
+	done func(tcell.Key)
+
+	// A callback function set by the Form class and called when the user leaves
+	// this form item.
+	finished func(tcell.Key)
+}
+
+// NewTimePicker returns a new time picker for a time of day, set to midnight.
+// It uses the 24-hour format and shows hours and minutes.
+func NewTimePicker() *TimePicker {
+	return &TimePicker{
+		Box:        NewBox(),
+		labelStyle: tcell.StyleDefault.Foreground(Styles.SecondaryTextColor),
+		fieldStyle: tcell.StyleDefault.Background(Styles.ContrastBackgroundColor).Foreground(Styles.PrimaryTextColor),
+		focusStyle: tcell.StyleDefault.Background(Styles.PrimaryTextColor).Foreground(Styles.ContrastBackgroundColor),
+	}
+}
+
+// NewDurationPicker returns a new time picker for a duration of up to 99 hours,
+// 59 minutes, and 59 seconds (see [TimePicker.SetMaxDuration]), set to zero. It
+// shows hours, minutes, and seconds.
+func NewDurationPicker() *TimePicker {
+	t := NewTimePicker()
+	t.duration = true
+	t.showSeconds = true
+	t.maxDuration = 99*3600 + 59*60 + 59
+	return t
+}
+
+// SetTime sets a time of day. Values out of range are wrapped around, e.g. 25
+// hours result in 1 o'clock. For duration pickers, the value is clamped to the
+// maximum duration instead. This also triggers the "changed" callback if the
+// value changes with this call.
+func (t *TimePicker) SetTime(hour, minute, second int) *TimePicker {
+	t.setValue(hour*3600 + minute*60 + second)
+	return t
+}
+
+// GetTime returns the hour, minute, and second of the value. For duration
+// pickers, the hour may exceed 23.
+func (t *TimePicker) GetTime() (hour, minute, second int) {
+	return t.value / 3600, t.value / 60 % 60, t.value % 60
+}
+
+// SetDuration sets the value as a duration, i.e. the time since midnight for a
+// time of day. Fractions of seconds are dropped. See [TimePicker.SetTime] for
+// values out of range.
+func (t *TimePicker) SetDuration(duration time.Duration) *TimePicker {
+	t.setValue(int(duration / time.Second))
+	return t
+}
+
+// GetDuration returns the value as a duration, i.e. the time since midnight
+// for a time of day.
+func (t *TimePicker) GetDuration() time.Duration {
+	return time.Duration(t.value) * time.Second
+}
+
+// GetText returns the value as it is shown in the field.
+func (t *TimePicker) GetText() string {
+	var text string
+	for index, segment := range t.segments() {
+		text += t.separator(index) + t.segmentText(segment)
+	}
+	return text
+}
+
+// SetMaxDuration sets the largest value of a duration picker. It has no effect
+// on time of day pickers. The current value is clamped to the new maximum.
+func (t *TimePicker) SetMaxDuration(max time.Duration) *TimePicker {
+	if !t.duration {
+		return t
+	}
+	if max < 0 {
+		max = 0
+	}
+	t.maxDuration = int(max / time.Second)
+	t.setValue(t.value)
+	return t
+}
+
+// SetHour12 sets whether a time of day is shown in 12-hour format with an
+// AM/PM segment instead of the 24-hour format. It has no effect on duration
+// pickers.
+func (t *TimePicker) SetHour12(hour12 bool) *TimePicker {
+	t.hour12 = hour12 && !t.duration
+	t.segment, t.typed = timeSegmentHours, ""
+	return t
+}
+
+// ShowSeconds sets whether the seconds segment is shown. If it is hidden, the
+// seconds are not changed by the user.
+func (t *TimePicker) ShowSeconds(show bool) *TimePicker {
+	t.showSeconds = show
+	t.segment, t.typed = timeSegmentHours, ""
+	return t
+}
+
+// SetLabel sets the text to be displayed before the field.
+func (t *TimePicker) SetLabel(label string) *TimePicker {
+	t.label = label
+	return t
+}
+
+// GetLabel returns the text to be displayed before the field.
+func (t *TimePicker) GetLabel() string {
+	return t.label
+}
+
+// SetLabelWidth sets the screen width of the label. A value of 0 will cause the
+// primitive to use the width of the label string.
+func (t *TimePicker) SetLabelWidth(width int) *TimePicker {
+	t.labelWidth = width
+	return t
+}
+
+// SetLabelStyle sets the style of the label.
+func (t *TimePicker) SetLabelStyle(style tcell.Style) *TimePicker {
+	t.labelStyle = style
+	return t
+}
+
+// SetFieldStyle sets the style of the field.
+func (t *TimePicker) SetFieldStyle(style tcell.Style) *TimePicker {
+	t.fieldStyle = style
+	return t
+}
+
+// SetActivatedStyle sets the style of the current segment when the field has
+// focus.
+func (t *TimePicker) SetActivatedStyle(style tcell.Style) *TimePicker {
+	t.focusStyle = style
+	return t
+}
+
+// SetChangedFunc sets a handler which is called when the value was changed.
+// The handler receives the value as a duration, i.e. the time since midnight
+// for a time of day.
+func (t *TimePicker) SetChangedFunc(handler func(value time.Duration)) *TimePicker {
+	t.changed = handler
+	return t
+}
+
+// SetDoneFunc sets a handler which is called when the user is done entering
+// the value. The callback function is provided with the key that was pressed,
+// which is one of the following:
+//
+//   - KeyEnter: Done entering the value.
+//   - KeyEscape: Abort.
+//   - KeyTab: Move to the next field.
+//   - KeyBacktab: Move to the previous field.
+func (t *TimePicker) SetDoneFunc(handler func(key tcell.Key)) *TimePicker {
+	t.done = handler
+	return t
+}
+
+// SetFinishedFunc sets a callback invoked when the user leaves this form item.
+func (t *TimePicker) SetFinishedFunc(handler func(key tcell.Key)) FormItem {
+	t.finished = handler
+	return t
+}
+
+// SetFormAttributes sets attributes shared by all form items.
+func (t *TimePicker) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) FormItem {
+	t.labelWidth = labelWidth
+	t.labelStyle = t.labelStyle.Foreground(labelColor)
+	t.backgroundColor = bgColor
+	t.fieldStyle = t.fieldStyle.Foreground(fieldTextColor).Background(fieldBgColor)
+	t.focusStyle = t.focusStyle.Foreground(fieldBgColor).Background(fieldTextColor)
+	return t
+}
+
+// GetFieldWidth returns this primitive's field width.
+func (t *TimePicker) GetFieldWidth() int {
+	return TaggedStringWidth(Escape(t.GetText())) + 1
+}
+
+// GetFieldHeight returns this primitive's field height.
+func (t *TimePicker) GetFieldHeight() int {
+	return 1
+}
+
+// SetDisabled sets whether or not the item is disabled / read-only.
+func (t *TimePicker) SetDisabled(disabled bool) FormItem {
+	t.disabled = disabled
+	if t.finished != nil {
+		t.finished(-1)
+	}
+	return t
+}
+
+// Focus is called when this primitive receives focus.
+func (t *TimePicker) Focus(delegate func(p Primitive)) {
+	// If we're part of a form and this item is disabled, there's nothing the
+	// user can do here so we're finished.
+	if t.finished != nil && t.disabled {
+		t.finished(-1)
+		return
+	}
+
+	t.typed = ""
+	t.Box.Focus(delegate)
+}
+
+// setValue sets the value in seconds, wrapped around for times of day and
+// clamped for durations, and triggers the "changed" callback if it changed.
+func (t *TimePicker) setValue(value int) {
+	if t.duration {
+		if value > t.maxDuration {
+			value = t.maxDuration
+		}
+		if value < 0 {
+			value = 0
+		}
+	} else {
+		value = (value%86400 + 86400) % 86400
+	}
+	if value == t.value {
+		return
+	}
+	t.value = value
+	if t.changed != nil {
+		t.changed(t.GetDuration())
+	}
+}
+
+// segments returns the segments which are shown, in order.
+func (t *TimePicker) segments() []int {
+	segments := []int{timeSegmentHours, timeSegmentMinutes}
+	if t.showSeconds {
+		segments = append(segments, timeSegmentSeconds)
+	}
+	if t.hour12 {
+		segments = append(segments, timeSegmentPeriod)
+	}
+	return segments
+}
+
+// separator returns the text placed before the segment at the given position.
+func (t *TimePicker) separator(index int) string {
+	switch {
+	case index == 0:
+		return ""
+	case t.segments()[index] == timeSegmentPeriod:
+		return " "
+	}
+	return ":"
+}
+
+// hourDigits returns the number of digits of the hours segment.
+func (t *TimePicker) hourDigits() int {
+	if digits := len(strconv.Itoa(t.maxDuration / 3600)); t.duration && digits > 2 {
+		return digits
+	}
+	return 2
+}
+
+// segmentText returns the text of the given segment.
+func (t *TimePicker) segmentText(segment int) string {
+	hour, minute, second := t.GetTime()
+	switch segment {
+	case timeSegmentHours:
+		if t.hour12 {
+			if hour = hour % 12; hour == 0 {
+				hour = 12
+			}
+		}
+		return fmt.Sprintf("%0*d", t.hourDigits(), hour)
+	case timeSegmentMinutes:
+		return fmt.Sprintf("%02d", minute)
+	case timeSegmentSeconds:
+		return fmt.Sprintf("%02d", second)
+	}
+	if hour < 12 {
+		return "AM"
+	}
+	return "PM"
+}
+
+// segmentMax returns the largest value which can be typed into the given
+// segment.
+func (t *TimePicker) segmentMax(segment int) int {
+	switch {
+	case segment != timeSegmentHours:
+		return 59
+	case t.duration:
+		return t.maxDuration / 3600
+	case t.hour12:
+		return 12
+	}
+	return 23
+}
+
+// adjust changes the given segment by the given amount. Minutes and seconds
+// wrap around without changing the other segments.
+func (t *TimePicker) adjust(segment, delta int) {
+	hour, minute, second := t.GetTime()
+	switch segment {
+	case timeSegmentHours:
+		hour += delta
+		if t.duration && hour < 0 {
+			hour = 0
+		}
+	case timeSegmentMinutes:
+		minute = ((minute+delta)%60 + 60) % 60
+	case timeSegmentSeconds:
+		second = ((second+delta)%60 + 60) % 60
+	case timeSegmentPeriod:
+		hour += 12
+	}
+	t.SetTime(hour, minute, second)
+}
+
+// setSegment sets the given segment to a value typed by the user.
+func (t *TimePicker) setSegment(segment, value int) {
+	hour, minute, second := t.GetTime()
+	switch segment {
+	case timeSegmentHours:
+		if t.hour12 {
+			value = value%12 + hour/12*12
+		}
+		hour = value
+	case timeSegmentMinutes:
+		minute = value
+	case timeSegmentSeconds:
+		second = value
+	}
+	t.SetTime(hour, minute, second)
+}
+
+// typeDigit processes a digit typed by the user.
+func (t *TimePicker) typeDigit(digit rune) {
+	typed := t.typed + string(digit)
+	max := t.segmentMax(t.segment)
+	value, _ := strconv.Atoi(typed)
+	if value > max {
+		typed = string(digit) // Start over.
+		value = int(digit - '0')
+	}
+	if value > 0 || !t.hour12 || t.segment != timeSegmentHours {
+		t.setSegment(t.segment, value)
+	}
+
+	// Advance to the next segment if no more digits can follow.
+	digits := 2
+	if t.segment == timeSegmentHours {
+		digits = t.hourDigits()
+	}
+	if len(typed) >= digits || value*10 > max {
+		t.typed = ""
+		t.moveSegment(1)
+		return
+	}
+	t.typed = typed
+}
+
+// moveSegment selects the segment at the given distance from the current one.
+func (t *TimePicker) moveSegment(delta int) {
+	segments := t.segments()
+	for index, segment := range segments {
+		if segment == t.segment {
+			index += delta
+			if index < 0 {
+				index = 0
+			} else if index >= len(segments) {
+				index = len(segments) - 1
+			}
+			t.segment = segments[index]
+			break
+		}
+	}
+	t.typed = ""
+}
+
+// fieldX returns the screen x-coordinate at which the field starts.
+func (t *TimePicker) fieldX() int {
+	x, _, width, _ := t.GetInnerRect()
+	labelWidth := t.labelWidth
+	if labelWidth <= 0 {
+		labelWidth = TaggedStringWidth(t.label)
+	}
+	if labelWidth > width {
+		labelWidth = width
+	}
+	return x + labelWidth
+}
+
+// segmentAt returns the segment at the given screen x-coordinate or -1 if
+// there is none.
+func (t *TimePicker) segmentAt(x int) int {
+	position := t.fieldX()
+	for index, segment := range t.segments() {
+		position += len(t.separator(index))
+		width := len(t.segmentText(segment))
+		if x >= position && x < position+width {
+			return segment
+		}
+		position += width
+	}
+	return -1
+}
+
+// Draw draws this primitive onto the screen.
+func (t *TimePicker) Draw(screen tcell.Screen) {
+	t.Box.DrawForSubclass(screen, t)
+
+	// Prepare.
+	x, y, width, height := t.GetInnerRect()
+	rightLimit := x + width
+	if height < 1 || rightLimit <= x {
+		return
+	}
+
+	// Draw label.
+	_, labelBg, _ := t.labelStyle.Decompose()
+	printWithStyle(screen, t.label, x, y, 0, t.fieldX()-x, AlignLeft, t.labelStyle, labelBg == tcell.ColorDefault)
+	x = t.fieldX()
+
+	// Draw the field background.
+	fieldWidth := t.GetFieldWidth()
+	if fieldWidth > rightLimit-x {
+		fieldWidth = rightLimit - x
+	}
+	for index := 0; index < fieldWidth; index++ {
+		screen.SetContent(x+index, y, ' ', nil, t.fieldStyle)
+	}
+
+	// Draw the segments.
+	for index, segment := range t.segments() {
+		if x >= rightLimit {
+			break
+		}
+		_, _, printed := printWithStyle(screen, t.separator(index), x, y, 0, rightLimit-x, AlignLeft, t.fieldStyle, false)
+		x += printed
+		style := t.fieldStyle
+		if segment == t.segment && t.HasFocus() && !t.disabled {
+			style = t.focusStyle
+		}
+		_, _, printed = printWithStyle(screen, t.segmentText(segment), x, y, 0, rightLimit-x, AlignLeft, style, false)
+		x += printed
+	}
+}
+
+// InputHandler returns the handler for this primitive.
+func (t *TimePicker) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return t.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if t.disabled {
+			return
+		}
+
+		// Process key event.
+		switch key := event.Key(); key {
+		case tcell.KeyLeft:
+			t.moveSegment(-1)
+		case tcell.KeyRight:
+			t.moveSegment(1)
+		case tcell.KeyHome:
+			t.moveSegment(-len(t.segments()))
+		case tcell.KeyEnd:
+			t.moveSegment(len(t.segments()))
+		case tcell.KeyUp:
+			t.typed = ""
+			t.adjust(t.segment, 1)
+		case tcell.KeyDown:
+			t.typed = ""
+			t.adjust(t.segment, -1)
+		case tcell.KeyBackspace, tcell.KeyBackspace2, tcell.KeyDelete:
+			t.typed = ""
+			if t.segment != timeSegmentPeriod {
+				t.setSegment(t.segment, 0)
+			}
+		case tcell.KeyRune:
+			r := event.Rune()
+			switch {
+			case r >= '0' && r <= '9' && t.segment != timeSegmentPeriod:
+				t.typeDigit(r)
+			case t.hour12 && (r == 'a' || r == 'A') && t.value >= 12*3600,
+				t.hour12 && (r == 'p' || r == 'P') && t.value < 12*3600:
+				t.adjust(timeSegmentPeriod, 1)
+			}
+		case tcell.KeyEnter, tcell.KeyTab, tcell.KeyBacktab, tcell.KeyEscape: // We're done.
+			t.typed = ""
+			if t.done != nil {
+				t.done(key)
+			}
+			if t.finished != nil {
+				t.finished(key)
+			}
+		}
+	})
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (t *TimePicker) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if t.disabled {
+			return false, nil
+		}
+
+		x, y := event.Position()
+		if !t.InRect(x, y) {
+			return false, nil
+		}
+
+		// Process mouse event.
+		switch action {
+		case MouseLeftDown:
+			setFocus(t)
+			if segment := t.segmentAt(x); segment >= 0 {
+				t.segment, t.typed = segment, ""
+			}
+			consumed = true
+		case MouseLeftClick:
+			consumed = true
+		case MouseScrollUp:
+			t.adjust(t.segment, 1)
+			consumed = true
+		case MouseScrollDown:
+			t.adjust(t.segment, -1)
+			consumed = true
+		}
+
+		return
+	})
+}
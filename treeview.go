@@ -1,6 +1,10 @@
 package tview
 
 import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -15,6 +19,54 @@ const (
 	treeScroll // Move without changing the selection, even when off screen.
 )
 
+// TreeLines defines the runes used to draw the lines connecting tree nodes.
+type TreeLines struct {
+	// The line drawn next to the descendants of nodes which have further
+	// siblings below them.
+	Vertical rune
+
+	// The line leading from the branch to the node's text.
+	Horizontal rune
+
+	// The branch of a node which has further siblings below it.
+	Branch rune
+
+	// The branch of a node which is the last child of its parent.
+	LastBranch rune
+}
+
+// Predefined tree lines.
+var (
+	TreeLinesUnicode = TreeLines{
+		Vertical:   BoxDrawingsLightVertical,
+		Horizontal: BoxDrawingsLightHorizontal,
+		Branch:     BoxDrawingsLightVerticalAndRight,
+		LastBranch: BoxDrawingsLightUpAndRight,
+	}
+	TreeLinesRounded = TreeLines{
+		Vertical:   BoxDrawingsLightVertical,
+		Horizontal: BoxDrawingsLightHorizontal,
+		Branch:     BoxDrawingsLightVerticalAndRight,
+		LastBranch: BoxDrawingsLightArcUpAndRight,
+	}
+	TreeLinesDouble = TreeLines{
+		Vertical:   BoxDrawingsDoubleVertical,
+		Horizontal: BoxDrawingsDoubleHorizontal,
+		Branch:     BoxDrawingsDoubleVerticalAndRight,
+		LastBranch: BoxDrawingsDoubleUpAndRight,
+	}
+	TreeLinesASCII = TreeLines{
+		Vertical:   '|',
+		Horizontal: '-',
+		Branch:     '|',
+		LastBranch: '`',
+	}
+)
+
+
+// The frames of the animation shown while a node's children are loaded.
+var treeLoadingFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
 // TreeNode represents one node in a tree view.
 type TreeNode struct {
 	// The reference object.
@@ -41,17 +93,67 @@ type TreeNode struct {
 	// An optional function which is called when the user selects this node.
 	selected func()
 
+	// Whether this node's children are yet to be loaded by the tree view's
+	// node loader.
+	lazy bool
+
+	// Whether this node's children are currently being loaded.
+	loading bool
+
+	// Whether the last attempt to load this node's children failed. Reset when
+	// the node is collapsed.
+	loadFailed bool
+
+	// Whether this node is a placeholder created by the tree view.
+	placeholder bool
+
+	// The state of this node's checkbox.
+	checkState CheckState
+
 	// The hierarchy level (0 for the root, 1 for its children, and so on). This
 	// is only up to date immediately after a call to process() (e.g. via
 	// Draw()).
 	level int
 
+	// The node which this node was added to as a child (nil if none). Unlike
+	// "parent", this is kept up to date when children are added or removed.
+	owner *TreeNode
+
+	// Incremented whenever the structure (or the text) of the subtree starting
+	// at this node changes. Tree views compare it to the value at the time they
+	// last flattened their tree to find out if their list of visible nodes is
+	// stale.
+	generation uint64
+
 	// Temporary member variables.
 	parent    *TreeNode // The parent node (nil for the root).
+	lastChild *TreeNode // The last visible child node.
 	graphicsX int       // The x-coordinate of the left-most graphics rune.
 	textX     int       // The x-coordinate of the first rune of the text.
 }
 
+// treeChanged marks the structure (or the text) of the subtree starting at
+// this node and thus of all subtrees containing it as changed.
+func (n *TreeNode) treeChanged() {
+	for node := n; node != nil; node = node.owner {
+		node.generation++
+	}
+}
+
+// setChildren replaces this node's child nodes.
+func (n *TreeNode) setChildren(childNodes []*TreeNode) {
+	for _, child := range n.children {
+		if child.owner == n {
+			child.owner = nil
+		}
+	}
+	n.children = childNodes
+	for _, child := range childNodes {
+		child.owner = n
+	}
+	n.treeChanged()
+}
+
 // NewTreeNode returns a new tree node.
 func NewTreeNode(text string) *TreeNode {
 	return &TreeNode{
@@ -69,21 +171,20 @@ func NewTreeNode(text string) *TreeNode {
 // The callback returns whether traversal should continue with the traversed
 // node's child nodes (true) or not recurse any deeper (false).
 func (n *TreeNode) Walk(callback func(node, parent *TreeNode) bool) *TreeNode {
-	n.parent = nil
-	nodes := []*TreeNode{n}
+	nodes, parents := []*TreeNode{n}, []*TreeNode{nil}
 	for len(nodes) > 0 {
 		// Pop the top node and process it.
-		node := nodes[len(nodes)-1]
-		nodes = nodes[:len(nodes)-1]
-		if !callback(node, node.parent) {
+		node, parent := nodes[len(nodes)-1], parents[len(parents)-1]
+		nodes, parents = nodes[:len(nodes)-1], parents[:len(parents)-1]
+		if !callback(node, parent) {
 			// Don't add any children.
 			continue
 		}
 
 		// Add children in reverse order.
 		for index := len(node.children) - 1; index >= 0; index-- {
-			node.children[index].parent = node
 			nodes = append(nodes, node.children[index])
+			parents = append(parents, node)
 		}
 	}
 
@@ -105,7 +206,7 @@ func (n *TreeNode) GetReference() interface{} {
 
 // SetChildren sets this node's child nodes.
 func (n *TreeNode) SetChildren(childNodes []*TreeNode) *TreeNode {
-	n.children = childNodes
+	n.setChildren(childNodes)
 	return n
 }
 
@@ -121,13 +222,15 @@ func (n *TreeNode) GetChildren() []*TreeNode {
 
 // ClearChildren removes all child nodes from this node.
 func (n *TreeNode) ClearChildren() *TreeNode {
-	n.children = nil
+	n.setChildren(nil)
 	return n
 }
 
 // AddChild adds a new child node to this node.
 func (n *TreeNode) AddChild(node *TreeNode) *TreeNode {
 	n.children = append(n.children, node)
+	node.owner = n
+	n.treeChanged()
 	return n
 }
 
@@ -137,6 +240,10 @@ func (n *TreeNode) RemoveChild(node *TreeNode) *TreeNode {
 	for index, child := range n.children {
 		if child == node {
 			n.children = append(n.children[:index], n.children[index+1:]...)
+			if node.owner == n {
+				node.owner = nil
+			}
+			n.treeChanged()
 			break
 		}
 	}
@@ -147,6 +254,7 @@ func (n *TreeNode) RemoveChild(node *TreeNode) *TreeNode {
 // the user.
 func (n *TreeNode) SetSelectable(selectable bool) *TreeNode {
 	n.selectable = selectable
+	n.treeChanged()
 	return n
 }
 
@@ -159,28 +267,63 @@ func (n *TreeNode) SetSelectedFunc(handler func()) *TreeNode {
 
 // SetExpanded sets whether or not this node's child nodes should be displayed.
 func (n *TreeNode) SetExpanded(expanded bool) *TreeNode {
-	n.expanded = expanded
-	return n
+	if !expanded {
+		return n.Collapse()
+	}
+	return n.Expand()
 }
 
 // Expand makes the child nodes of this node appear.
 func (n *TreeNode) Expand() *TreeNode {
 	n.expanded = true
+	n.treeChanged()
 	return n
 }
 
 // Collapse makes the child nodes of this node disappear.
 func (n *TreeNode) Collapse() *TreeNode {
 	n.expanded = false
+	n.loadFailed = false
+	n.treeChanged()
 	return n
 }
 
+// SetLazy sets whether this node's children are yet to be loaded. Lazy nodes
+// are collapsed. When a lazy node is expanded, the tree view calls its node
+// loader (see TreeView.SetNodeLoader()) to retrieve the node's children, after
+// which the node is no longer lazy.
+func (n *TreeNode) SetLazy(lazy bool) *TreeNode {
+	n.lazy = lazy
+	if lazy {
+		n.expanded = false
+	}
+	n.treeChanged()
+	return n
+}
+
+// IsLazy returns whether this node's children are yet to be loaded.
+func (n *TreeNode) IsLazy() bool {
+	return n.lazy
+}
+
+// IsLoading returns whether this node's children are currently being loaded.
+func (n *TreeNode) IsLoading() bool {
+	return n.loading
+}
+
+// GetCheckState returns the state of this node's checkbox. See
+// TreeView.SetCheckboxes() for details.
+func (n *TreeNode) GetCheckState() CheckState {
+	return n.checkState
+}
+
 // ExpandAll expands this node and all descendent nodes.
 func (n *TreeNode) ExpandAll() *TreeNode {
 	n.Walk(func(node, parent *TreeNode) bool {
 		node.expanded = true
 		return true
 	})
+	n.treeChanged()
 	return n
 }
 
@@ -190,6 +333,7 @@ func (n *TreeNode) CollapseAll() *TreeNode {
 		node.expanded = false
 		return true
 	})
+	n.treeChanged()
 	return n
 }
 
@@ -201,6 +345,7 @@ func (n *TreeNode) IsExpanded() bool {
 // SetText sets the node's text which is displayed.
 func (n *TreeNode) SetText(text string) *TreeNode {
 	n.text = text
+	n.treeChanged()
 	return n
 }
 
@@ -220,6 +365,7 @@ func (n *TreeNode) SetColor(color tcell.Color) *TreeNode {
 // value greater than that moves the text to the right.
 func (n *TreeNode) SetIndent(indent int) *TreeNode {
 	n.indent = indent
+	n.treeChanged()
 	return n
 }
 
@@ -252,6 +398,19 @@ func (n *TreeNode) GetLevel() int {
 //   - Ctrl-F, page down: Move (the selection) down by one page.
 //   - Ctrl-B, page up: Move (the selection) up by one page.
 //
+// If searching is enabled (see SetSearchable()), the following keys are also
+// available:
+//
+//   - /: Start typing a search text. The selection jumps to the first
+//     visible node whose text contains the search text (case-insensitive).
+//     Enter stops typing, Escape cancels the search.
+//   - n: Move the selection to the next node matching the search text.
+//   - N: Move the selection to the previous node matching the search text.
+//
+// If editing is enabled (see SetEditable()), F2 replaces the current node's
+// text with an input field in which the user may change the text. Enter
+// confirms the new text, Escape cancels editing. See also EditNode().
+//
 // Selected nodes can trigger the "selected" callback when the user hits Enter.
 //
 // The root node corresponds to level 0, its children correspond to level 1,
@@ -259,10 +418,25 @@ func (n *TreeNode) GetLevel() int {
 // displayed is 0, i.e. the root node. You can call SetTopLevel() to hide
 // levels.
 //
+// Nodes may be displayed with tri-state checkboxes, see SetCheckboxes(). If
+// checkboxes are enabled, the space key toggles the current node's checkbox
+// instead of selecting the node.
+//
+// If dragging is enabled (see SetDraggable()), the user may move a node to a
+// new parent node by dragging it onto that node with the mouse.
+//
+// A filter function may be provided with SetFilterFunc() to hide all nodes that
+// don't match the filter and that have no matching descendants.
+//
+// Children of nodes may be loaded when the nodes are expanded for the first
+// time. See SetNodeLoader() and TreeNode.SetLazy() for details.
+//
 // If graphics are turned on (see SetGraphics()), lines indicate the tree's
-// hierarchy. Alternative (or additionally), you can set different prefixes
-// using SetPrefixes() for different levels, for example to display hierarchical
-// bullet point lists.
+// hierarchy. The runes used for the lines may be changed with SetLines() and
+// their styles with SetLevelLineStyles(). Expanders which show whether a node
+// is expanded or collapsed can be added with SetExpanders(). Alternative (or
+// additionally), you can set different prefixes using SetPrefixes() for
+// different levels, for example to display hierarchical bullet point lists.
 //
 // See https://github.com/rivo/tview/wiki/TreeView for an example.
 type TreeView struct {
@@ -304,6 +478,22 @@ type TreeView struct {
 	// The color of the lines.
 	graphicsColor tcell.Color
 
+	// The runes used to draw the lines. If nil, they are taken from the
+	// global Borders variable.
+	lines *TreeLines
+
+	// The styles of the lines, per level. If empty, the lines are drawn in
+	// the graphics color.
+	levelLineStyles []tcell.Style
+
+	// The indentation of the nodes' texts, per level. If empty, the nodes'
+	// own indentation is used.
+	levelIndents []int
+
+	// The runes drawn before collapsed and expanded nodes with children. If
+	// both are 0, no expanders are drawn.
+	collapsedExpander, expandedExpander rune
+
 	// An optional function which is called when the user has navigated to a new
 	// tree node.
 	changed func(node *TreeNode)
@@ -321,20 +511,103 @@ type TreeView struct {
 	// Temporarily set to true while we know that the tree has not changed and
 	// therefore does not need to be reprocessed.
 	stableNodes bool
+
+	// Whether the "nodes" slice reflects the tree view's settings and the tree
+	// structure at the time of "nodesGeneration" (see TreeNode.generation).
+	nodesValid      bool
+	nodesGeneration uint64
+
+	// The index of the current node in the "nodes" slice as of the last call
+	// to process(). Used to avoid searching for the current node.
+	currentIndex int
+
+	// An optional function which loads the children of lazy nodes.
+	loader func(node *TreeNode) ([]*TreeNode, error)
+
+	// The application used to load children in the background. If nil,
+	// children are loaded synchronously.
+	loaderApp *Application
+
+	// The text of the placeholder node shown while children are loaded.
+	loadingText string
+
+	// An optional function which determines the nodes to be shown.
+	filter func(node *TreeNode) bool
+
+	// Whether the user may search nodes by typing.
+	searchable bool
+
+	// Whether the user is currently typing a search text.
+	searching bool
+
+	// The current search text.
+	searchText string
+
+	// The style of the search prompt.
+	searchStyle tcell.Style
+
+	// Whether checkboxes are drawn before the nodes' texts.
+	checkboxes bool
+
+	// The strings drawn for checked, unchecked, and partially checked nodes.
+	checkedString, uncheckedString, partialString string
+
+	// An optional function which is called when the user toggles a checkbox.
+	checkedFunc func(node *TreeNode, state CheckState)
+
+	// Whether the user may drag nodes onto new parent nodes.
+	draggable bool
+
+	// The node being dragged, if any.
+	dragNode *TreeNode
+
+	// The node onto which the dragged node would be dropped, if any.
+	dropTarget *TreeNode
+
+	// The style of the drop target.
+	dropStyle tcell.Style
+
+	// An optional function which determines whether a node may be dropped
+	// onto a target node.
+	canDrop func(node, target *TreeNode) bool
+
+	// An optional function which is called when the user moved a node.
+	moved func(node, oldParent, newParent *TreeNode)
+
+	// Whether the user may edit node texts by pressing F2.
+	editable bool
+
+	// The node currently being edited, if any, and the input field used to
+	// edit it.
+	editNode  *TreeNode
+	editField *InputField
+
+	// Whether the input field was drawn during the last call to Draw().
+	editVisible bool
+
+	// An optional function which validates edited texts.
+	edited func(node *TreeNode, text string) bool
 }
 
 // NewTreeView returns a new tree view.
 func NewTreeView() *TreeView {
 	return &TreeView{
-		Box:           NewBox(),
-		graphics:      true,
-		graphicsColor: Styles.GraphicsColor,
+		Box:             NewBox(),
+		graphics:        true,
+		graphicsColor:   Styles.GraphicsColor,
+		loadingText:     "Loading...",
+		searchStyle:     tcell.StyleDefault.Foreground(Styles.SecondaryTextColor).Background(Styles.PrimitiveBackgroundColor),
+		checkedString:   "[x] ",
+		uncheckedString: "[ ] ",
+		partialString:   "[-] ",
+		dropStyle:       tcell.StyleDefault.Foreground(Styles.PrimaryTextColor).Background(Styles.MoreContrastBackgroundColor),
 	}
 }
 
 // SetRoot sets the root node of the tree.
 func (t *TreeView) SetRoot(root *TreeNode) *TreeView {
 	t.root = root
+	t.nodesValid = false
 	return t
 }
 
@@ -396,6 +669,7 @@ func (t *TreeView) GetPath(node *TreeNode) []*TreeNode {
 // not displayed.
 func (t *TreeView) SetTopLevel(topLevel int) *TreeView {
 	t.topLevel = topLevel
+	t.nodesValid = false
 	return t
 }
 
@@ -420,6 +694,7 @@ func (t *TreeView) SetPrefixes(prefixes []string) *TreeView {
 // If set to false, they will indent with the hierarchy.
 func (t *TreeView) SetAlign(align bool) *TreeView {
 	t.align = align
+	t.nodesValid = false
 	return t
 }
 
@@ -427,6 +702,7 @@ func (t *TreeView) SetAlign(align bool) *TreeView {
 // drawn to illustrate the tree's hierarchy.
 func (t *TreeView) SetGraphics(showGraphics bool) *TreeView {
 	t.graphics = showGraphics
+	t.nodesValid = false
 	return t
 }
 
@@ -436,6 +712,567 @@ func (t *TreeView) SetGraphicsColor(color tcell.Color) *TreeView {
 	return t
 }
 
+// SetLines sets the runes used to draw the lines connecting tree nodes when
+// graphics are turned on (see SetGraphics()). You may use one of the
+// predefined TreeLines variables, for example TreeLinesASCII for terminals
+// without support for box drawing characters. By default, the runes are taken
+// from the global Borders variable.
+func (t *TreeView) SetLines(lines TreeLines) *TreeView {
+	t.lines = &lines
+	return t
+}
+
+// SetLevelLineStyles sets the styles of the lines connecting tree nodes, per
+// hierarchy level. The first element applies to the lines of the nodes one
+// level below the top level (see SetTopLevel()), the second element to the
+// level below that, and so on. Deeper levels will cycle through the styles.
+// Provide nil to draw all lines in the graphics color (see
+// SetGraphicsColor()).
+func (t *TreeView) SetLevelLineStyles(styles []tcell.Style) *TreeView {
+	t.levelLineStyles = styles
+	return t
+}
+
+// SetLevelIndents sets the indentation of the nodes' texts per hierarchy
+// level, overriding the nodes' own indentation (see TreeNode.SetIndent()). The
+// first element applies to the top level (see SetTopLevel()), the second
+// element to the level below that, and so on. Deeper levels will cycle through
+// the indents. Provide nil to use the nodes' own indentation.
+func (t *TreeView) SetLevelIndents(indents []int) *TreeView {
+	t.levelIndents = indents
+	t.nodesValid = false
+	return t
+}
+
+// SetExpanders sets the runes drawn before the texts of collapsed and
+// expanded nodes which have children (or whose children are yet to be loaded,
+// see TreeNode.SetLazy()), for example '▸' and '▾' or '+' and '-'. Nodes
+// without children are indented accordingly. The user may click on an
+// expander to expand or collapse the node. Set both runes to 0 to remove the
+// expanders (the default).
+func (t *TreeView) SetExpanders(collapsed, expanded rune) *TreeView {
+	t.collapsedExpander = collapsed
+	t.expandedExpander = expanded
+	return t
+}
+
+// expanderString returns the expander drawn before the given node's text, or
+// an empty string if there is none.
+func (t *TreeView) expanderString(node *TreeNode) string {
+	if t.collapsedExpander == 0 && t.expandedExpander == 0 {
+		return ""
+	}
+	expander := t.collapsedExpander
+	if node.expanded {
+		expander = t.expandedExpander
+	}
+	if len(node.children) == 0 && !node.lazy {
+		width := TaggedStringWidth(Escape(string(t.collapsedExpander)))
+		if w := TaggedStringWidth(Escape(string(t.expandedExpander))); w > width {
+			width = w
+		}
+		return strings.Repeat(" ", width+1)
+	}
+	return string(expander) + " "
+}
+
+// lineStyle returns the style of the lines drawn for the given node.
+func (t *TreeView) lineStyle(node *TreeNode) tcell.Style {
+	if len(t.levelLineStyles) == 0 {
+		return tcell.StyleDefault.Background(t.backgroundColor).Foreground(t.graphicsColor)
+	}
+	level := node.level - t.topLevel - 1
+	if level < 0 {
+		level = 0
+	}
+	return t.levelLineStyles[level%len(t.levelLineStyles)]
+}
+
+// SetNodeLoader sets a function which loads the children of lazy nodes (see
+// TreeNode.SetLazy()) when they are expanded. If an application is provided,
+// the function is called in a separate goroutine and a non-selectable,
+// animated placeholder node is shown until it returns. The children are then
+// added to the node in the application's main goroutine (see
+// Application.QueueUpdateDraw()). If "app" is nil, the function is called
+// synchronously when the tree view is drawn.
+//
+// If the function returns an error, a placeholder node with the error message
+// is shown instead of the children. Loading is attempted again when the node
+// is collapsed and expanded again.
+//
+//	tree.SetNodeLoader(app, func(node *tview.TreeNode) ([]*tview.TreeNode, error) {
+//	  entries, err := os.ReadDir(node.GetReference().(string))
+//	  if err != nil {
+//	    return nil, err
+//	  }
+//	  ... // Create one node per entry.
+//	})
+func (t *TreeView) SetNodeLoader(app *Application, loader func(node *TreeNode) ([]*TreeNode, error)) *TreeView {
+	t.loaderApp = app
+	t.loader = loader
+	t.nodesValid = false
+	return t
+}
+
+// SetLoadingText sets the text of the placeholder node shown while the
+// children of a node are loaded in the background.
+func (t *TreeView) SetLoadingText(text string) *TreeView {
+	t.loadingText = text
+	return t
+}
+
+// loadChildren loads the children of the given lazy node using the node
+// loader.
+func (t *TreeView) loadChildren(node *TreeNode) {
+	finish := func(children []*TreeNode, err error) {
+		node.loading = false
+		if err != nil {
+			node.loadFailed = true
+			errorNode := NewTreeNode(Escape(err.Error())).SetColor(tcell.ColorRed).SetSelectable(false)
+			errorNode.placeholder = true
+			node.setChildren([]*TreeNode{errorNode})
+			return
+		}
+		node.lazy = false
+		node.setChildren(children)
+		if node.checkState == CheckStateChecked {
+			for _, child := range children {
+				child.Walk(func(n, parent *TreeNode) bool {
+					n.checkState = CheckStateChecked
+					return true
+				})
+			}
+		}
+	}
+
+	// Synchronous loading.
+	loader, app := t.loader, t.loaderApp
+	if app == nil {
+		finish(loader(node))
+		return
+	}
+
+	// Show a placeholder and load in the background.
+	node.loading = true
+	placeholder := NewTreeNode(treeLoadingFrames[0] + " " + t.loadingText).SetColor(Styles.TertiaryTextColor).SetSelectable(false)
+	placeholder.placeholder = true
+	node.setChildren([]*TreeNode{placeholder})
+	done := make(chan struct{})
+	go func() {
+		children, err := loader(node)
+		close(done)
+		app.QueueUpdateDraw(func() {
+			finish(children, err)
+		})
+	}()
+	go func() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		text := t.loadingText
+		for frame := 1; ; frame++ {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				frameText := treeLoadingFrames[frame%len(treeLoadingFrames)] + " " + text
+				app.QueueUpdateDraw(func() {
+					placeholder.SetText(frameText)
+				})
+			}
+		}
+	}()
+}
+
+// SetFilterFunc sets a function which determines which nodes are shown. A node
+// is shown if the function returns true for it or for any of its descendants,
+// regardless of whether its ancestors are expanded. All other nodes are
+// hidden. Children which have not been loaded yet (see TreeNode.SetLazy()) are
+// not considered. Provide nil to remove the filter.
+//
+// The function is called for all nodes of the tree whenever the tree's
+// structure or the text of one of its nodes changes.
+func (t *TreeView) SetFilterFunc(filter func(node *TreeNode) bool) *TreeView {
+	t.filter = filter
+	t.nodesValid = false
+	return t
+}
+
+// SetSearchable sets whether the user may search for nodes by typing (see the
+// TreeView documentation for the keys involved).
+func (t *TreeView) SetSearchable(searchable bool) *TreeView {
+	t.searchable = searchable
+	if !searchable {
+		t.searching = false
+	}
+	return t
+}
+
+// SetSearchText sets the search text and moves the selection to the first
+// visible node, starting at the current node, whose text contains the search
+// text (case-insensitive). If no such node exists, the selection is not
+// changed.
+func (t *TreeView) SetSearchText(text string) *TreeView {
+	t.searchText = text
+	t.process(false)
+	t.search(0)
+	return t
+}
+
+// GetSearchText returns the current search text.
+func (t *TreeView) GetSearchText() string {
+	return t.searchText
+}
+
+// SetSearchStyle sets the style of the search prompt shown while the user is
+// typing a search text.
+func (t *TreeView) SetSearchStyle(style tcell.Style) *TreeView {
+	t.searchStyle = style
+	return t
+}
+
+// search moves the selection to the next visible, selectable node whose text
+// contains the search text, starting with the current node if "direction" is
+// 0, the node after it if "direction" is 1, and the node before it if
+// "direction" is -1. The search wraps around. The visible nodes must be up to
+// date.
+func (t *TreeView) search(direction int) {
+	if t.searchText == "" || len(t.nodes) == 0 {
+		return
+	}
+	start := 0
+	for index, node := range t.nodes {
+		if node == t.currentNode {
+			start = index
+			break
+		}
+	}
+	step := direction
+	if step == 0 {
+		step = 1
+	} else {
+		start += direction
+	}
+	text := strings.ToLower(t.searchText)
+	for count := 0; count < len(t.nodes); count++ {
+		index := ((start+count*step)%len(t.nodes) + len(t.nodes)) % len(t.nodes)
+		node := t.nodes[index]
+		if node.selectable && strings.Contains(strings.ToLower(stripTags(node.text)), text) {
+			t.currentNode = node
+			return
+		}
+	}
+}
+
+// treeHeight returns the number of rows available for nodes.
+func (t *TreeView) treeHeight() int {
+	_, _, _, height := t.GetInnerRect()
+	if t.searching {
+		height-- // The search prompt.
+	}
+	return height
+}
+
+// SetCheckboxes sets whether checkboxes are drawn before the nodes' texts. A
+// node's checkbox is either checked, unchecked, or partially checked. Checking
+// or unchecking a node (see SetChecked()) also checks or unchecks all of its
+// descendants. The states of the node's ancestors are then updated: A node
+// whose children are all checked is checked, a node whose children are all
+// unchecked is unchecked, and all other nodes are partially checked.
+//
+// The user may toggle the current node's checkbox with the space key or by
+// clicking on it.
+func (t *TreeView) SetCheckboxes(show bool) *TreeView {
+	t.checkboxes = show
+	return t
+}
+
+// SetCheckboxStrings sets the strings drawn before checked, unchecked, and
+// partially checked nodes. They are printed as they are, i.e. style tags are
+// not interpreted. The defaults are "[x] ", "[ ] ", and "[-] ".
+func (t *TreeView) SetCheckboxStrings(checked, unchecked, partial string) *TreeView {
+	t.checkedString = checked
+	t.uncheckedString = unchecked
+	t.partialString = partial
+	return t
+}
+
+// SetCheckedFunc sets a function which is called when the user toggles a
+// node's checkbox. It receives the toggled node and its new state. The states
+// of its descendants and ancestors have already been updated at that point.
+func (t *TreeView) SetCheckedFunc(handler func(node *TreeNode, state CheckState)) *TreeView {
+	t.checkedFunc = handler
+	return t
+}
+
+// SetChecked checks or unchecks the given node and all of its descendants and
+// updates the states of its ancestors. The node must be part of this tree.
+// This function does not trigger the "checked" callback.
+func (t *TreeView) SetChecked(node *TreeNode, checked bool) *TreeView {
+	state := CheckStateUnchecked
+	if checked {
+		state = CheckStateChecked
+	}
+	node.Walk(func(n, parent *TreeNode) bool {
+		n.checkState = state
+		return true
+	})
+
+	// Update ancestors.
+	path := t.GetPath(node)
+	for index := len(path) - 2; index >= 0; index-- {
+		path[index].updateCheckState()
+	}
+
+	return t
+}
+
+// updateCheckState derives this node's checkbox state from that of its
+// children. Nodes without children keep their state.
+func (n *TreeNode) updateCheckState() {
+	var checked, unchecked bool
+	for _, child := range n.children {
+		if child.placeholder {
+			continue
+		}
+		switch child.checkState {
+		case CheckStateChecked:
+			checked = true
+		case CheckStateUnchecked:
+			unchecked = true
+		default:
+			checked, unchecked = true, true
+		}
+	}
+	switch {
+	case checked && unchecked:
+		n.checkState = CheckStatePartial
+	case checked:
+		n.checkState = CheckStateChecked
+	case unchecked:
+		n.checkState = CheckStateUnchecked
+	}
+}
+
+// GetCheckedNodes returns all checked nodes of the tree in depth-first,
+// pre-order. Partially checked nodes are not included.
+func (t *TreeView) GetCheckedNodes() (nodes []*TreeNode) {
+	if t.root == nil {
+		return nil
+	}
+	t.root.Walk(func(node, parent *TreeNode) bool {
+		if node.checkState == CheckStateChecked && !node.placeholder {
+			nodes = append(nodes, node)
+		}
+		return true
+	})
+	return
+}
+
+// toggleChecked toggles the checkbox of the given node, triggering the
+// "checked" callback. Partially checked nodes become checked.
+func (t *TreeView) toggleChecked(node *TreeNode) {
+	if node.placeholder {
+		return
+	}
+	t.SetChecked(node, node.checkState != CheckStateChecked)
+	if t.checkedFunc != nil {
+		t.checkedFunc(node, node.checkState)
+	}
+}
+
+// checkboxString returns the checkbox string drawn before the given node, or
+// an empty string if there is none.
+func (t *TreeView) checkboxString(node *TreeNode) string {
+	if !t.checkboxes || node.placeholder {
+		return ""
+	}
+	switch node.checkState {
+	case CheckStateChecked:
+		return t.checkedString
+	case CheckStatePartial:
+		return t.partialString
+	}
+	return t.uncheckedString
+}
+
+// prefixString returns the prefix drawn before the given node's text.
+func (t *TreeView) prefixString(node *TreeNode) string {
+	if len(t.prefixes) == 0 {
+		return ""
+	}
+	return t.prefixes[(node.level-t.topLevel)%len(t.prefixes)]
+}
+
+// SetDraggable sets whether the user may drag nodes with the mouse and drop
+// them onto other nodes, making the dragged node the last child of the target
+// node. The root node cannot be dragged and nodes cannot be dropped onto their
+// own descendants, their current parent, or nodes whose children are not
+// loaded yet (see TreeNode.SetLazy()). Use SetCanDropFunc() to restrict
+// dropping further.
+func (t *TreeView) SetDraggable(draggable bool) *TreeView {
+	t.draggable = draggable
+	if !draggable {
+		t.dragNode, t.dropTarget = nil, nil
+	}
+	return t
+}
+
+// SetCanDropFunc sets a function which is called while the user drags a node
+// over a potential target node. It returns whether the node may be dropped
+// onto the target node.
+func (t *TreeView) SetCanDropFunc(handler func(node, target *TreeNode) bool) *TreeView {
+	t.canDrop = handler
+	return t
+}
+
+// SetMovedFunc sets a function which is called after the user dropped a node
+// onto a new parent node.
+func (t *TreeView) SetMovedFunc(handler func(node, oldParent, newParent *TreeNode)) *TreeView {
+	t.moved = handler
+	return t
+}
+
+// SetDropTargetStyle sets the style of the node onto which the dragged node
+// would be dropped.
+func (t *TreeView) SetDropTargetStyle(style tcell.Style) *TreeView {
+	t.dropStyle = style
+	return t
+}
+
+// validDropTarget returns whether the dragged node may be dropped onto the
+// given target node.
+func (t *TreeView) validDropTarget(target *TreeNode) bool {
+	node := t.dragNode
+	if node == nil || target == nil || target.placeholder || target.lazy || target.loading {
+		return false
+	}
+	for _, child := range target.children {
+		if child == node {
+			return false // Already the parent.
+		}
+	}
+	for ancestor := target; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor == node {
+			return false // Can't drop onto itself or a descendant.
+		}
+	}
+	return t.canDrop == nil || t.canDrop(node, target)
+}
+
+// nodeAtPoint returns the visible node at the given screen coordinates or nil
+// if there is none.
+func (t *TreeView) nodeAtPoint(x, y int) *TreeNode {
+	rectX, rectY, width, _ := t.GetInnerRect()
+	if x < rectX || x >= rectX+width || y < rectY || y >= rectY+t.treeHeight() {
+		return nil
+	}
+	y += t.offsetY - rectY
+	if y < 0 || y >= len(t.nodes) {
+		return nil
+	}
+	return t.nodes[y]
+}
+
+// dropNode moves the dragged node to the current drop target.
+func (t *TreeView) dropNode() {
+	node, target := t.dragNode, t.dropTarget
+	t.dragNode, t.dropTarget = nil, nil
+	if node == nil || target == nil {
+		return
+	}
+	path := t.GetPath(node)
+	if len(path) < 2 {
+		return
+	}
+	oldParent := path[len(path)-2]
+	oldParent.RemoveChild(node)
+	target.AddChild(node)
+	target.Expand()
+
+	// Update checkbox states.
+	if t.checkboxes {
+		for index := len(path) - 2; index >= 0; index-- {
+			path[index].updateCheckState()
+		}
+		path = t.GetPath(node)
+		for index := len(path) - 2; index >= 0; index-- {
+			path[index].updateCheckState()
+		}
+	}
+
+	if t.moved != nil {
+		t.moved(node, oldParent, target)
+	}
+}
+
+// SetEditable sets whether the user may edit the text of the current node by
+// pressing F2. Nodes may always be edited programmatically with EditNode().
+func (t *TreeView) SetEditable(editable bool) *TreeView {
+	t.editable = editable
+	return t
+}
+
+// SetEditedFunc sets a function which is called when the user confirms the
+// edited text of a node by pressing Enter. If it returns true, the node's text
+// is replaced with the new text and editing ends. If it returns false, the new
+// text is rejected and editing continues. Without such a function, all texts
+// are accepted.
+func (t *TreeView) SetEditedFunc(handler func(node *TreeNode, text string) bool) *TreeView {
+	t.edited = handler
+	return t
+}
+
+// EditNode starts editing the given node's text. Its text is replaced with an
+// input field containing the text (including any style tags) until the user
+// confirms or cancels editing. Any previous edit is cancelled. The node
+// should be visible and the tree view should have focus for the user to be
+// able to edit the text.
+func (t *TreeView) EditNode(node *TreeNode) *TreeView {
+	t.CancelEditing()
+	if node == nil {
+		return t
+	}
+	t.editNode = node
+	t.editField = NewInputField().SetText(node.text)
+	t.editField.Focus(nil)
+	return t
+}
+
+// IsEditing returns whether a node's text is currently being edited.
+func (t *TreeView) IsEditing() bool {
+	return t.editNode != nil
+}
+
+// CancelEditing stops editing the current node's text, discarding any
+// changes.
+func (t *TreeView) CancelEditing() *TreeView {
+	if t.editField != nil {
+		t.editField.Blur()
+	}
+	t.editNode, t.editField, t.editVisible = nil, nil, false
+	return t
+}
+
+// confirmEditing validates the edited text and, if valid, applies it to the
+// edited node and stops editing.
+func (t *TreeView) confirmEditing() {
+	node, text := t.editNode, t.editField.GetText()
+	if t.edited != nil && !t.edited(node, text) {
+		return
+	}
+	node.SetText(text)
+	t.CancelEditing()
+}
+
+// Blur is called when this primitive loses focus.
+func (t *TreeView) Blur() {
+	if t.editNode != nil {
+		t.confirmEditing()
+		t.CancelEditing() // If the text was rejected.
+	}
+	t.Box.Blur()
+}
+
 // SetChangedFunc sets the function which is called when the currently selected
 // node changes, for example when the user navigates to a new tree node.
 func (t *TreeView) SetChangedFunc(handler func(node *TreeNode)) *TreeView {
@@ -489,27 +1326,60 @@ func (t *TreeView) Move(offset int) *TreeView {
 	return t
 }
 
-// process builds the visible tree, populates the "nodes" slice, and processes
-// pending movement actions. Set "drawingAfter" to true if you know that
-// [TreeView.Draw] will be called immediately after this function (to avoid
-// having [TreeView.Draw] call it again).
-func (t *TreeView) process(drawingAfter bool) {
-	t.stableNodes = drawingAfter
-	_, _, _, height := t.GetInnerRect()
-
-	// Determine visible nodes and their placement.
-	t.nodes = nil
+// flatten builds the visible tree and populates the "nodes" slice, unless
+// neither the tree view's settings nor the structure of the tree have changed
+// since the last call.
+func (t *TreeView) flatten() {
 	if t.root == nil {
+		t.nodes = t.nodes[:0]
 		return
 	}
-	parentSelectedIndex, selectedIndex, topLevelGraphicsX := -1, -1, -1
+	if t.nodesValid && t.nodesGeneration == t.root.generation {
+		return
+	}
+	t.nodesValid, t.nodesGeneration = true, t.root.generation
+
+	// Determine visible nodes and their placement.
+	t.nodes = t.nodes[:0]
+
+	// Find the nodes which pass the filter.
+	var shown map[*TreeNode]bool
+	if t.filter != nil {
+		shown = make(map[*TreeNode]bool)
+		var keep func(node *TreeNode) bool
+		keep = func(node *TreeNode) bool {
+			result := t.filter(node)
+			if !node.lazy {
+				for _, child := range node.children {
+					if keep(child) {
+						result = true
+					}
+				}
+			}
+			if result {
+				shown[node] = true
+			}
+			return result
+		}
+		keep(t.root)
+	}
+
+	topLevelGraphicsX := -1
 	var graphicsOffset, maxTextX int
 	if t.graphics {
 		graphicsOffset = 1
 	}
 	t.root.Walk(func(node, parent *TreeNode) bool {
+		if shown != nil && !shown[node] {
+			return false // Filtered out.
+		}
+
 		// Set node attributes.
 		node.parent = parent
+		node.lastChild = nil
+		if parent != nil {
+			parent.lastChild = node
+		}
 		if parent == nil {
 			node.level = 0
 			node.graphicsX = 0
@@ -517,7 +1387,11 @@ func (t *TreeView) process(drawingAfter bool) {
 		} else {
 			node.level = parent.level + 1
 			node.graphicsX = parent.textX
-			node.textX = node.graphicsX + graphicsOffset + node.indent
+			indent := node.indent
+			if len(t.levelIndents) > 0 && node.level >= t.topLevel {
+				indent = t.levelIndents[(node.level-t.topLevel)%len(t.levelIndents)]
+			}
+			node.textX = node.graphicsX + graphicsOffset + indent
 		}
 		if !t.graphics && t.align {
 			// Without graphics, we align nodes on the first column.
@@ -535,17 +1409,6 @@ func (t *TreeView) process(drawingAfter bool) {
 			if node.textX > maxTextX {
 				maxTextX = node.textX
 			}
-			if node == t.currentNode && node.selectable {
-				selectedIndex = len(t.nodes)
-
-				// Also find parent node.
-				for index := len(t.nodes) - 1; index >= 0; index-- {
-					if t.nodes[index] == parent && t.nodes[index].selectable {
-						parentSelectedIndex = index
-						break
-					}
-				}
-			}
 
 			// Maybe we want to skip this level.
 			if t.topLevel == node.level && (topLevelGraphicsX < 0 || node.graphicsX < topLevelGraphicsX) {
@@ -555,7 +1418,15 @@ func (t *TreeView) process(drawingAfter bool) {
 			t.nodes = append(t.nodes, node)
 		}
 
+		// Load lazy children.
+		if node.expanded && node.lazy && !node.loading && !node.loadFailed && t.loader != nil {
+			t.loadChildren(node)
+		}
+
 		// Recurse if desired.
+		if shown != nil {
+			return !node.lazy
+		}
 		return node.expanded
 	})
 
@@ -572,6 +1443,42 @@ func (t *TreeView) process(drawingAfter bool) {
 			node.textX -= topLevelGraphicsX
 		}
 	}
+}
+
+// process builds the visible tree (if needed, see flatten()), populates the
+// "nodes" slice, and processes pending movement actions. Set "drawingAfter" to
+// true if you know that [TreeView.Draw] will be called immediately after this
+// function (to avoid having [TreeView.Draw] call it again).
+func (t *TreeView) process(drawingAfter bool) {
+	t.stableNodes = drawingAfter
+	height := t.treeHeight()
+	t.flatten()
+	if t.root == nil {
+		return
+	}
+
+	// Find the current node and its parent node.
+	parentSelectedIndex, selectedIndex := -1, -1
+	if t.currentNode != nil && t.currentNode.selectable {
+		if t.currentIndex >= 0 && t.currentIndex < len(t.nodes) && t.nodes[t.currentIndex] == t.currentNode {
+			selectedIndex = t.currentIndex
+		} else {
+			for index, node := range t.nodes {
+				if node == t.currentNode {
+					selectedIndex = index
+					break
+				}
+			}
+		}
+		for index := selectedIndex - 1; index >= 0; index-- {
+			if t.nodes[index] == t.currentNode.parent {
+				if t.nodes[index].selectable {
+					parentSelectedIndex = index
+				}
+				break
+			}
+		}
+	}
 
 	// Process selection. (Also trigger events if necessary.)
 	if selectedIndex >= 0 {
@@ -614,6 +1521,7 @@ func (t *TreeView) process(drawingAfter bool) {
 			}
 		}
 		t.currentNode = t.nodes[selectedIndex]
+		t.currentIndex = selectedIndex
 
 		// Move selection into viewport.
 		if t.movement != treeScroll {
@@ -636,6 +1544,7 @@ func (t *TreeView) process(drawingAfter bool) {
 				if node.selectable {
 					selectedIndex = index
 					t.currentNode = node
+					t.currentIndex = index
 					break
 				}
 			}
@@ -666,9 +1575,15 @@ func (t *TreeView) Draw(screen tcell.Screen) {
 		t.stableNodes = false
 	}
 
+	// Draw the search prompt.
+	x, y, width, _ := t.GetInnerRect()
+	height := t.treeHeight()
+	if t.searching && height >= 0 {
+		printWithStyle(screen, "/"+Escape(t.searchText)+"_", x, y+height, 0, width, AlignLeft, t.searchStyle, true)
+	}
+
 	// Scroll the tree, t.movement is treeNone after process() when there is a
 	// selection, except for treeScroll, treeHome, and treeEnd.
-	x, y, width, height := t.GetInnerRect()
 	switch t.movement {
 	case treeMove, treeScroll:
 		t.offsetY += t.step
@@ -688,66 +1603,101 @@ func (t *TreeView) Draw(screen tcell.Screen) {
 	}
 
 	// Draw the tree.
+	t.editVisible = false
 	posY := y
-	lineStyle := tcell.StyleDefault.Background(t.backgroundColor).Foreground(t.graphicsColor)
-	for index, node := range t.nodes {
+	for index := t.offsetY; index < len(t.nodes); index++ {
+		node := t.nodes[index]
+
 		// Skip invisible parts.
 		if posY >= y+height+1 || posY >= totalHeight {
 			break
 		}
-		if index < t.offsetY {
-			continue
-		}
 
 		// Draw the graphics.
-		if t.graphics {
+		if t.graphics && t.lines == nil {
 			// Draw ancestor branches.
-			ancestor := node.parent
-			for ancestor != nil && ancestor.parent != nil && ancestor.parent.level >= t.topLevel {
-				if ancestor.graphicsX >= width {
-					continue
-				}
-
+			for ancestor := node.parent; ancestor != nil && ancestor.parent != nil && ancestor.parent.level >= t.topLevel; ancestor = ancestor.parent {
 				// Draw a branch if this ancestor is not a last child.
-				if ancestor.parent.children[len(ancestor.parent.children)-1] != ancestor {
+				if ancestor.graphicsX < width && ancestor.parent.lastChild != ancestor {
+					style := t.lineStyle(ancestor)
 					if posY-1 >= y && ancestor.textX > ancestor.graphicsX {
-						PrintJoinedSemigraphics(screen, x+ancestor.graphicsX, posY-1, Borders.Vertical, lineStyle)
+						PrintJoinedSemigraphics(screen, x+ancestor.graphicsX, posY-1, Borders.Vertical, style)
 					}
 					if posY < y+height {
-						screen.SetContent(x+ancestor.graphicsX, posY, Borders.Vertical, nil, lineStyle)
+						screen.SetContent(x+ancestor.graphicsX, posY, Borders.Vertical, nil, style)
 					}
 				}
-				ancestor = ancestor.parent
 			}
 
 			if node.textX > node.graphicsX && node.graphicsX < width {
+				style := t.lineStyle(node)
+
 				// Connect to the node above.
 				if posY-1 >= y && t.nodes[index-1].graphicsX <= node.graphicsX && t.nodes[index-1].textX > node.graphicsX {
-					PrintJoinedSemigraphics(screen, x+node.graphicsX, posY-1, Borders.TopLeft, lineStyle)
+					PrintJoinedSemigraphics(screen, x+node.graphicsX, posY-1, Borders.TopLeft, style)
 				}
 
 				// Join this node.
 				if posY < y+height {
-					screen.SetContent(x+node.graphicsX, posY, Borders.BottomLeft, nil, lineStyle)
+					screen.SetContent(x+node.graphicsX, posY, Borders.BottomLeft, nil, style)
 					for pos := node.graphicsX + 1; pos < node.textX && pos < width; pos++ {
-						screen.SetContent(x+pos, posY, Borders.Horizontal, nil, lineStyle)
+						screen.SetContent(x+pos, posY, Borders.Horizontal, nil, style)
 					}
 				}
 			}
+		} else if t.graphics && posY < y+height {
+			// Custom lines can't be joined, draw the branches directly instead.
+			lines := *t.lines
+			for ancestor := node.parent; ancestor != nil && ancestor.parent != nil && ancestor.parent.level >= t.topLevel; ancestor = ancestor.parent {
+				if ancestor.graphicsX < width && ancestor.parent.lastChild != ancestor {
+					screen.SetContent(x+ancestor.graphicsX, posY, lines.Vertical, nil, t.lineStyle(ancestor))
+				}
+			}
+
+			// Join this node.
+			if node.textX > node.graphicsX && node.graphicsX < width {
+				style := t.lineStyle(node)
+				branch := lines.Branch
+				if node.parent == nil || node.parent.lastChild == node {
+					branch = lines.LastBranch
+				}
+				screen.SetContent(x+node.graphicsX, posY, branch, nil, style)
+				for pos := node.graphicsX + 1; pos < node.textX && pos < width; pos++ {
+					screen.SetContent(x+pos, posY, lines.Horizontal, nil, style)
+				}
+			}
 		}
 
 		// Draw the prefix and the text.
 		if node.textX < width && posY < y+height {
-			// Prefix.
+			// Expander.
 			var prefixWidth int
-			if len(t.prefixes) > 0 {
-				_, prefixWidth = Print(screen, t.prefixes[(node.level-t.topLevel)%len(t.prefixes)], x+node.textX, posY, width-node.textX, AlignLeft, node.color)
+			if expander := t.expanderString(node); expander != "" {
+				_, prefixWidth = Print(screen, Escape(expander), x+node.textX, posY, width-node.textX, AlignLeft, node.color)
+			}
+
+			// Prefix.
+			if prefix := t.prefixString(node); prefix != "" && node.textX+prefixWidth < width {
+				_, printed := Print(screen, prefix, x+node.textX+prefixWidth, posY, width-node.textX-prefixWidth, AlignLeft, node.color)
+				prefixWidth += printed
+			}
+
+			// Checkbox.
+			if checkbox := t.checkboxString(node); checkbox != "" && node.textX+prefixWidth < width {
+				_, checkboxWidth := Print(screen, Escape(checkbox), x+node.textX+prefixWidth, posY, width-node.textX-prefixWidth, AlignLeft, node.color)
+				prefixWidth += checkboxWidth
 			}
 
 			// Text.
-			if node.textX+prefixWidth < width {
+			if node == t.editNode && node.textX+prefixWidth < width {
+				t.editField.SetRect(x+node.textX+prefixWidth, posY, width-node.textX-prefixWidth, 1)
+				t.editField.Draw(screen)
+				t.editVisible = true
+			} else if node.textX+prefixWidth < width {
 				style := tcell.StyleDefault.Background(t.backgroundColor).Foreground(node.color)
-				if node == t.currentNode {
+				if node == t.dropTarget {
+					style = t.dropStyle
+				} else if node == t.currentNode {
 					style = tcell.StyleDefault.Background(node.color).Foreground(t.backgroundColor)
 				}
 				printWithStyle(screen, node.text, x+node.textX+prefixWidth, posY, 0, width-node.textX-prefixWidth, AlignLeft, style, false)
@@ -774,6 +1724,45 @@ func (t *TreeView) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 			}
 		}
 
+		// Editing a node's text.
+		if t.editNode != nil {
+			switch event.Key() {
+			case tcell.KeyEnter:
+				t.confirmEditing()
+			case tcell.KeyEscape:
+				t.CancelEditing()
+			default:
+				if handler := t.editField.InputHandler(); handler != nil {
+					handler(event, setFocus)
+				}
+			}
+			return
+		}
+
+		// Typing a search text.
+		if t.searching {
+			switch event.Key() {
+			case tcell.KeyEscape:
+				t.searching = false
+				t.searchText = ""
+			case tcell.KeyEnter:
+				t.searching = false
+			case tcell.KeyBackspace, tcell.KeyBackspace2:
+				if t.searchText == "" {
+					t.searching = false
+				} else {
+					_, size := utf8.DecodeLastRuneInString(t.searchText)
+					t.searchText = t.searchText[:len(t.searchText)-size]
+					t.search(0)
+				}
+			case tcell.KeyRune:
+				t.searchText += string(event.Rune())
+				t.search(0)
+			}
+			t.process(true)
+			return
+		}
+
 		// Because the tree is flattened into a list only at drawing time, we also
 		// postpone the (selection) movement to drawing time.
 		switch key := event.Key(); key {
@@ -792,13 +1781,11 @@ func (t *TreeView) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 		case tcell.KeyEnd:
 			t.movement = treeEnd
 		case tcell.KeyPgDn, tcell.KeyCtrlF:
-			_, _, _, height := t.GetInnerRect()
 			t.movement = treeMove
-			t.step = height
+			t.step = t.treeHeight()
 		case tcell.KeyPgUp, tcell.KeyCtrlB:
-			_, _, _, height := t.GetInnerRect()
 			t.movement = treeMove
-			t.step = -height
+			t.step = -t.treeHeight()
 		case tcell.KeyRune:
 			switch event.Rune() {
 			case 'g':
@@ -815,11 +1802,32 @@ func (t *TreeView) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 				t.step = -1
 			case 'K':
 				t.movement = treeParent
+			case '/':
+				if t.searchable {
+					t.searching = true
+					t.searchText = ""
+				}
+			case 'n':
+				if t.searchable {
+					t.search(1)
+				}
+			case 'N':
+				if t.searchable {
+					t.search(-1)
+				}
 			case ' ':
-				selectNode()
+				if t.checkboxes && t.currentNode != nil {
+					t.toggleChecked(t.currentNode)
+				} else {
+					selectNode()
+				}
 			}
 		case tcell.KeyEnter:
 			selectNode()
+		case tcell.KeyF2:
+			if t.editable && t.currentNode != nil {
+				t.EditNode(t.currentNode)
+			}
 		}
 
 		t.process(true)
@@ -830,6 +1838,38 @@ func (t *TreeView) InputHandler() func(event *tcell.EventKey, setFocus func(p Pr
 func (t *TreeView) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 		x, y := event.Position()
+
+		// Forward mouse events to the input field while editing.
+		if t.editNode != nil && action != MouseMove {
+			if t.editVisible && t.editField.InRect(x, y) {
+				if handler := t.editField.MouseHandler(); handler != nil {
+					handler(action, event, func(p Primitive) {})
+				}
+				return true, nil
+			}
+			if action == MouseLeftDown && t.InRect(x, y) {
+				// Clicking elsewhere ends editing.
+				t.confirmEditing()
+				t.CancelEditing()
+			}
+		}
+
+		// Drag a node.
+		if t.dragNode != nil {
+			switch action {
+			case MouseMove:
+				if target := t.nodeAtPoint(x, y); t.validDropTarget(target) {
+					t.dropTarget = target
+				} else {
+					t.dropTarget = nil
+				}
+				return true, t
+			case MouseLeftUp:
+				t.dropNode()
+				return true, nil
+			}
+		}
+
 		if !t.InRect(x, y) {
 			return false, nil
 		}
@@ -838,11 +1878,34 @@ func (t *TreeView) MouseHandler() func(action MouseAction, event *tcell.EventMou
 		case MouseLeftDown:
 			setFocus(t)
 			consumed = true
+			if node := t.nodeAtPoint(x, y); t.draggable && node != nil && node != t.root && !node.placeholder {
+				t.dragNode = node
+				return true, t
+			}
 		case MouseLeftClick:
-			_, rectY, _, _ := t.GetInnerRect()
+			rectX, rectY, _, _ := t.GetInnerRect()
 			y += t.offsetY - rectY
 			if y >= 0 && y < len(t.nodes) {
 				node := t.nodes[y]
+
+				// Did we click on the expander?
+				expanderWidth := TaggedStringWidth(Escape(t.expanderString(node)))
+				if expanderWidth > 0 && x >= rectX+node.textX && x < rectX+node.textX+expanderWidth && (len(node.children) > 0 || node.lazy) {
+					node.SetExpanded(!node.expanded)
+					consumed = true
+					return
+				}
+
+				// Did we click on the checkbox?
+				if checkbox := t.checkboxString(node); checkbox != "" {
+					checkboxX := rectX + node.textX + expanderWidth + TaggedStringWidth(t.prefixString(node))
+					if x >= checkboxX && x < checkboxX+TaggedStringWidth(Escape(checkbox)) {
+						t.toggleChecked(node)
+						consumed = true
+						return
+					}
+				}
+
 				if node.selectable {
 					previousNode := t.currentNode
 					t.currentNode = node
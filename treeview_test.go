@@ -0,0 +1,101 @@
+package tview
+
+import "testing"
+
+// TestTreeViewDefaultLines tests that the default tree lines are joined with
+// the lines in the rows above them.
+func TestTreeViewDefaultLines(t *testing.T) {
+	root := NewTreeNode("root")
+	a, b := NewTreeNode("a"), NewTreeNode("b")
+	root.AddChild(a).AddChild(b)
+	a.AddChild(NewTreeNode("a1")).AddChild(NewTreeNode("a2"))
+	b.AddChild(NewTreeNode("b1"))
+	tree := NewTreeView().SetRoot(root).SetCurrentNode(root)
+
+	expected := []string{
+		"root                ",
+		"├──a                ",
+		"│  ├──a1            ",
+		"│  └──a2            ",
+		"└──b                ",
+		"   └──b1            ",
+		"                    ",
+	}
+	for index, row := range drawPrimitive(t, tree, 20, 7) {
+		if row != expected[index] {
+			t.Errorf("row %d is %q, expected %q", index, row, expected[index])
+		}
+	}
+
+	// Aligned nodes connect to their children in the row above.
+	tree.SetAlign(true)
+	a.GetChildren()[0].AddChild(NewTreeNode("x"))
+	expected = []string{
+		"root                ",
+		"├──┬─────a          ",
+		"│  ├──┬──a1         ",
+		"│  │  └──x          ",
+		"│  └─────a2         ",
+		"└──┬─────b          ",
+		"   └─────b1         ",
+	}
+	for index, row := range drawPrimitive(t, tree, 20, 7) {
+		if row != expected[index] {
+			t.Errorf("aligned row %d is %q, expected %q", index, row, expected[index])
+		}
+	}
+}
+
+// TestTreeViewLines tests that configured tree lines are drawn as given.
+func TestTreeViewLines(t *testing.T) {
+	root := NewTreeNode("root")
+	a, b := NewTreeNode("a"), NewTreeNode("b")
+	root.AddChild(a).AddChild(b)
+	a.AddChild(NewTreeNode("a1"))
+	tree := NewTreeView().SetRoot(root).SetCurrentNode(root).SetLines(TreeLinesASCII)
+
+	expected := []string{
+		"root      ",
+		"|--a      ",
+		"|  `--a1  ",
+		"`--b      ",
+	}
+	for index, row := range drawPrimitive(t, tree, 10, 4) {
+		if row != expected[index] {
+			t.Errorf("row %d is %q, expected %q", index, row, expected[index])
+		}
+	}
+}
+
+// TestTreeViewGeneration tests that tree views only flatten their tree again
+// when their own tree changes.
+func TestTreeViewGeneration(t *testing.T) {
+	rootA, rootB := NewTreeNode("a"), NewTreeNode("b")
+	a1 := NewTreeNode("a1")
+	rootA.AddChild(a1)
+	rootB.AddChild(NewTreeNode("b1"))
+	treeA, treeB := NewTreeView().SetRoot(rootA), NewTreeView().SetRoot(rootB)
+	treeA.flatten()
+	treeB.flatten()
+
+	// A change deep inside one tree invalidates that tree only.
+	a1.AddChild(NewTreeNode("a11"))
+	if treeA.nodesGeneration == rootA.generation {
+		t.Error("tree A was not invalidated by a change to its subtree")
+	}
+	if treeB.nodesGeneration != rootB.generation {
+		t.Error("tree B was invalidated by a change to tree A")
+	}
+	treeA.flatten()
+	if len(treeA.nodes) != 3 {
+		t.Errorf("tree A has %d visible nodes, expected 3", len(treeA.nodes))
+	}
+
+	// Removed nodes no longer affect their former tree.
+	rootA.RemoveChild(a1)
+	treeA.flatten()
+	a1.SetText("x")
+	if treeA.nodesGeneration != rootA.generation {
+		t.Error("tree A was invalidated by a change to a removed node")
+	}
+}
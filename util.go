@@ -19,7 +19,7 @@ const (
 
 var (
 	// Regular expression used to escape style/region tags.
-	nonEscapePattern = regexp.MustCompile(`(\[[a-zA-Z0-9_,;: \-\."#]+\[*)\]`)
+	nonEscapePattern = regexp.MustCompile(`(\[(?:[a-zA-Z0-9_,;: \-\."#]+|link=[^\[\]]*)\[*)\]`)
 
 	// The number of colors available in the terminal.
 	availableColors = 256
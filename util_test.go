@@ -0,0 +1,37 @@
+package tview
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// drawPrimitive draws the given primitive onto a simulation screen of the
+// given size and returns the screen's contents, one string per row.
+func drawPrimitive(t *testing.T, p Primitive, width, height int) []string {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	screen.SetSize(width, height)
+	p.SetRect(0, 0, width, height)
+	p.Draw(screen)
+	screen.Show()
+	cells, _, _ := screen.GetContents()
+	rows := make([]string, height)
+	for y := range rows {
+		var row strings.Builder
+		for _, cell := range cells[y*width : (y+1)*width] {
+			if len(cell.Runes) == 0 {
+				row.WriteRune(' ')
+				continue
+			}
+			row.WriteString(string(cell.Runes))
+		}
+		rows[y] = row.String()
+	}
+	return rows
+}